@@ -6,6 +6,7 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 	"strconv"
 	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/cmd/config"
 	"tek-bank/internal/dto"
 	"tek-bank/internal/i18n"
 	"tek-bank/internal/i18n/messages"
@@ -17,8 +18,16 @@ type AccountHandler interface {
 	RegisterAccount(ctx *fiber.Ctx) error
 	CreateNewAccount(ctx *fiber.Ctx) error
 	AddMoney(ctx *fiber.Ctx) error
+	Withdraw(ctx *fiber.Ctx) error
 	TransferMoney(ctx *fiber.Ctx) error
 	TransferApproval(ctx *fiber.Ctx) error
+	ApproveTransfer(ctx *fiber.Ctx) error
+	ReverseTransfer(ctx *fiber.Ctx) error
+	FreezeAccount(ctx *fiber.Ctx) error
+	SetOverdraft(ctx *fiber.Ctx) error
+	UnfreezeAccount(ctx *fiber.Ctx) error
+	CloseAccount(ctx *fiber.Ctx) error
+	GetAccountEvents(ctx *fiber.Ctx) error
 }
 
 type accountHandler struct {
@@ -38,6 +47,7 @@ func NewAccountHandler(accountService service.AccountService) AccountHandler {
 // @Tags Account
 // @Accept application/json
 // @Produce application/json
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
 // @Param registerRequest body dto.RegisterAccountRequest true "Register Request"
 // @Success 200 {object} map[string]interface{}
 // @Router /account/register [post]
@@ -60,6 +70,8 @@ func (h *accountHandler) RegisterAccount(ctx *fiber.Ctx) error {
 		var status int = fiber.StatusInternalServerError
 		if err.Error() == messages.UserAlreadyExists {
 			status = fiber.StatusConflict
+		} else if err.Error() == messages.UnsupportedCurrency || err.Error() == messages.UnsupportedCountry {
+			status = fiber.StatusBadRequest
 		}
 		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
 	}
@@ -74,6 +86,7 @@ func (h *accountHandler) RegisterAccount(ctx *fiber.Ctx) error {
 // @Tags Account
 // @Accept application/json
 // @Produce application/json
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
 // @Param createAccountRequest body dto.CreateNewAccountRequest true "Create Account Request"
 // @Success 200 {object} map[string]interface{}
 // @Router /account/create [post]
@@ -90,6 +103,8 @@ func (h *accountHandler) CreateNewAccount(ctx *fiber.Ctx) error {
 		var status int = fiber.StatusInternalServerError
 		if err.Error() == messages.UserNotFound {
 			status = fiber.StatusNotFound
+		} else if err.Error() == messages.UnsupportedCurrency || err.Error() == messages.UnsupportedCountry {
+			status = fiber.StatusBadRequest
 		}
 		return cresponse.ErrorResponse(ctx, status, err.Error())
 	}
@@ -107,6 +122,7 @@ func (h *accountHandler) CreateNewAccount(ctx *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer <token>"
 // @Param accountNumber path int true "Account Number"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
 // @Param addMoneyRequest body dto.AddMoneyRequest true "Add Money Request"
 // @Success 200 {object} dto.AddMoneyResponse
 // @Router /account/add-money/{accountNumber} [put]
@@ -136,6 +152,10 @@ func (h *accountHandler) AddMoney(ctx *fiber.Ctx) error {
 		if err.Error() == messages.AccountNotFound {
 			log.Error(err)
 			status = fiber.StatusNotFound
+		} else if err.Error() == messages.InvalidAmount {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.AccountFrozen || err.Error() == messages.AccountClosed {
+			status = fiber.StatusConflict
 		}
 
 		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
@@ -144,16 +164,74 @@ func (h *accountHandler) AddMoney(ctx *fiber.Ctx) error {
 	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
 }
 
+// Withdraw godoc
+// @Summary Withdraw money from the account
+// @Description Withdraw money from an account of the current user by providing the account number and the amount.
+// @Description You can imagine this as withdrawing money from an ATM. The daily withdrawal limit of the account can not be exceeded.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param withdrawRequest body dto.WithdrawRequest true "Withdraw Request"
+// @Success 200 {object} dto.WithdrawResponse
+// @Router /account/withdraw/{accountNumber} [put]
+func (h *accountHandler) Withdraw(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.Atoi(ctx.Params("accountNumber"))
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.WithdrawRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = int64(accountNumber)
+	request.Language = config.GetLanguage(ctx)
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).Withdraw(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.AccountNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusForbidden
+		} else if err.Error() == messages.InSufficientBalance || err.Error() == messages.InvalidAmount || err.Error() == messages.DailyWithdrawalLimitExceeded {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.AccountFrozen || err.Error() == messages.AccountClosed {
+			status = fiber.StatusConflict
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
 // TransferMoney godoc
 // @Summary Transfer money between accounts
 // @Description Transfer money between accounts by providing the account numbers and the amount to be transferred.
+// @Description The receiver can be given with its account number or its IBAN (to_iban), the IBAN must belong to an account of the bank.
+// @Description The transfer waits for the approval of the sender, the approval link is sent via e-mail and expires after one hour.
+// @Description A saved beneficiary (beneficiary_id) can be given instead of the receiver. Transfers to trusted beneficiaries up to the daily limit of the bank are executed at once without an approval.
 // @Tags Account
 // @Accept application/json
 // @Produce application/json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
 // @Param transferMoneyRequest body dto.TransferMoneyRequest true "Transfer Money Request"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} dto.TransferItem
 // @Router /account/transfer [post]
 func (h *accountHandler) TransferMoney(ctx *fiber.Ctx) error {
 	var request dto.TransferMoneyRequest
@@ -169,24 +247,33 @@ func (h *accountHandler) TransferMoney(ctx *fiber.Ctx) error {
 		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, messages.TransactionFailed)
 	}
 
-	err = h.accountService.WithTx(tx).TransferMoney(ctx.Context(), request)
+	response, err := h.accountService.WithTx(tx).TransferMoney(ctx.Context(), request)
 	if err != nil {
 		var status int = fiber.StatusInternalServerError
 		if err.Error() == messages.AccountNotFound {
 			status = fiber.StatusNotFound
-		} else if err.Error() == messages.InSufficientBalance {
+		} else if err.Error() == messages.IBANNotFound || err.Error() == messages.BeneficiaryNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusForbidden
+		} else if err.Error() == messages.InSufficientBalance || err.Error() == messages.InvalidAmount || err.Error() == messages.UnsupportedCurrency ||
+			err.Error() == messages.InvalidIBAN || err.Error() == messages.BadRequest ||
+			err.Error() == messages.TransferTransactionLimitExceeded || err.Error() == messages.TransferDailyLimitExceeded || err.Error() == messages.TransferMonthlyLimitExceeded {
 			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.AccountFrozen || err.Error() == messages.AccountClosed || err.Error() == messages.TrustedBeneficiaryLimitExceeded {
+			status = fiber.StatusConflict
 		}
 		log.Error(err.Error())
 		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
 	}
 
-	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
 }
 
 // TransferApproval godoc
 // @Summary Approve the transfer
 // @Description Approve the transfer by providing the transfer token.
+// @Description A transfer can only be approved once and before it expires. A transfer whose accounts can not make it anymore is rejected.
 // @Tags Account
 // @Accept application/json
 // @Produce application/json
@@ -206,14 +293,364 @@ func (h *accountHandler) TransferApproval(ctx *fiber.Ctx) error {
 	err = h.accountService.WithTx(tx).TransferApproval(ctx.Context(), token)
 	if err != nil {
 		var status int = fiber.StatusInternalServerError
-		if err.Error() == messages.AccountNotFound {
+		if err.Error() == messages.AccountNotFound || err.Error() == messages.TransferNotFound {
 			status = fiber.StatusNotFound
-		} else if err.Error() == messages.InSufficientBalance {
+		} else if err.Error() == messages.InSufficientBalance ||
+			err.Error() == messages.TransferTransactionLimitExceeded || err.Error() == messages.TransferDailyLimitExceeded || err.Error() == messages.TransferMonthlyLimitExceeded {
 			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.TransferNotPending {
+			status = fiber.StatusConflict
+		} else if err.Error() == messages.TransferExpired {
+			// The expiry and the rejection are recorded on the transfer
+			status = fiber.StatusGone
+			transaction.KeepChanges(ctx)
+		} else if err.Error() == messages.TransferRejected {
+			status = fiber.StatusUnprocessableEntity
+			transaction.KeepChanges(ctx)
 		}
 		log.Error(err.Error())
-		return cresponse.ErrorResponse(ctx, status, err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.TransferApproved))
+}
+
+// ApproveTransfer godoc
+// @Summary Approve the transfer with a one-time code
+// @Description Approve a transfer of the user with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.
+// @Description The code is valid for 5 minutes. After 3 wrong codes the transfer is rejected.
+// @Tags Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Transfer Id"
+// @Param approveTransferRequest body dto.ApproveTransferRequest true "Approve Transfer Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/transfers/{id}/approve [post]
+func (h *accountHandler) ApproveTransfer(ctx *fiber.Ctx) error {
+	var request dto.ApproveTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.accountService.WithTx(tx).ApproveTransfer(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.AccountNotFound || err.Error() == messages.TransferNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.InSufficientBalance ||
+			err.Error() == messages.TransferTransactionLimitExceeded || err.Error() == messages.TransferDailyLimitExceeded || err.Error() == messages.TransferMonthlyLimitExceeded {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		} else if err.Error() == messages.TransferNotPending {
+			status = fiber.StatusConflict
+		} else if err.Error() == messages.InvalidTransferCode {
+			// The wrong attempt, the expiry and the rejection are recorded on the transfer
+			status = fiber.StatusBadRequest
+			transaction.KeepChanges(ctx)
+		} else if err.Error() == messages.TransferExpired {
+			status = fiber.StatusGone
+			transaction.KeepChanges(ctx)
+		} else if err.Error() == messages.TransferRejected || err.Error() == messages.TransferCodeAttemptsExceeded {
+			status = fiber.StatusUnprocessableEntity
+			transaction.KeepChanges(ctx)
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
 	}
 
 	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.TransferApproved))
 }
+
+// ReverseTransfer godoc
+// @Summary Reverse a transfer
+// @Description Gives back the whole or a part of an executed transfer to the sender by its transfer history entry, the amount is
+// @Description in the currency of the sender and the whole remaining amount is reversed without it. The fee is refunded on request.
+// @Description A reason is required. The reversal fails if the receiver does not have the money without its overdraft and holds, unless it is forced and the policy
+// @Description of the bank allows forcing (REVERSAL_ALLOW_FORCE). Only admins can use this endpoint.
+// @Description A reference like a ticket number is required, a transfer is reversed once with a reference, so retries do not post twice.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Transfer History Id"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param reverseTransferRequest body dto.ReverseTransferRequest true "Reverse Transfer Request"
+// @Success 200 {object} dto.ReverseTransferResponse
+// @Router /admin/transfer-history/{id}/reverse [post]
+func (h *accountHandler) ReverseTransfer(ctx *fiber.Ctx) error {
+	var request dto.ReverseTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.TransferHistoryId = ctx.Params("id")
+	request.Language = config.GetLanguage(ctx)
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).ReverseTransfer(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.TransferNotFound || err.Error() == messages.AccountNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.ReversalReasonRequired || err.Error() == messages.ReversalReferenceRequired || err.Error() == messages.InvalidReversal ||
+			err.Error() == messages.InvalidReversalAmount || err.Error() == messages.InvalidFeeRefund {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.TransferAlreadyReversed || err.Error() == messages.ReversalReferenceUsed || err.Error() == messages.AccountClosed {
+			status = fiber.StatusConflict
+		} else if err.Error() == messages.ReversalForceNotAllowed {
+			status = fiber.StatusForbidden
+		} else if err.Error() == messages.ReceiverInsufficientBalance {
+			status = fiber.StatusUnprocessableEntity
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// lifecycleErrorStatus returns the http status of an error of a change of the account status
+func lifecycleErrorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.AccountReasonRequired, messages.InvalidFreeze, messages.InvalidSweepAccount, messages.UnsupportedCurrency,
+		messages.InvalidOverdraft, messages.OverdraftNotAllowed:
+		return fiber.StatusBadRequest
+	case messages.AccountClosed, messages.AccountFrozen, messages.AccountNotFrozen, messages.AccountBalanceNotZero, messages.AccountHasHolds:
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// FreezeAccount godoc
+// @Summary Freeze an account
+// @Description Blocks the debits (money going out), the credits (money coming in) or both of a customer account. Pending transfers
+// @Description of a frozen account are rejected when they are approved. A frozen account can be frozen again to change what is blocked.
+// @Description A reason is required, it is kept in the audit trail of the account. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param freezeAccountRequest body dto.FreezeAccountRequest true "Freeze Account Request"
+// @Success 200 {object} dto.AccountStatusResponse
+// @Router /admin/accounts/{accountNumber}/freeze [put]
+func (h *accountHandler) FreezeAccount(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.FreezeAccountRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).FreezeAccount(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, lifecycleErrorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// UnfreezeAccount godoc
+// @Summary Unfreeze an account
+// @Description Lets the money in and out of a frozen account again. A reason is required, it is kept in the audit trail of the account.
+// @Description Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param unfreezeAccountRequest body dto.UnfreezeAccountRequest true "Unfreeze Account Request"
+// @Success 200 {object} dto.AccountStatusResponse
+// @Router /admin/accounts/{accountNumber}/unfreeze [post]
+func (h *accountHandler) UnfreezeAccount(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.UnfreezeAccountRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).UnfreezeAccount(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, lifecycleErrorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// CloseAccount godoc
+// @Summary Close an account
+// @Description Closes an account of the current user, admins can close any customer account at /admin/accounts/{accountNumber}/close.
+// @Description The balance has to be zero or is swept to another open account of the owner (sweep_to_account_number) with the current
+// @Description exchange rate and without a fee. A closed account can not send or receive money anymore, the owner is deactivated
+// @Description when the last account is closed. A reason is required, it is kept in the audit trail of the account.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param closeAccountRequest body dto.CloseAccountRequest true "Close Account Request"
+// @Success 200 {object} dto.AccountStatusResponse
+// @Router /account/close/{accountNumber} [post]
+// @Router /admin/accounts/{accountNumber}/close [post]
+func (h *accountHandler) CloseAccount(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.CloseAccountRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).CloseAccount(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, lifecycleErrorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// SetOverdraft godoc
+// @Summary Set the overdraft of an account
+// @Description Sets the overdraft limit and the yearly overdraft rate of a current account, a zero limit removes the overdraft.
+// @Description The balance may go below zero down to the negative limit, the negative balance is charged interest every day.
+// @Description A limit below the used overdraft does not take money back, the owner is notified to pay the difference in.
+// @Description Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param setOverdraftRequest body dto.SetOverdraftRequest true "Set Overdraft Request"
+// @Success 200 {object} dto.OverdraftResponse
+// @Router /admin/accounts/{accountNumber}/overdraft [put]
+func (h *accountHandler) SetOverdraft(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.SetOverdraftRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.accountService.WithTx(tx).SetOverdraft(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, lifecycleErrorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// GetAccountEvents godoc
+// @Summary Get the audit trail of an account
+// @Description Returns the status of a customer account and its freezes, unfreezes and closure with their reasons, the oldest first.
+// @Description Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Success 200 {object} dto.GetAccountEventsResponse
+// @Router /admin/accounts/{accountNumber}/events [get]
+func (h *accountHandler) GetAccountEvents(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	response, err := h.accountService.GetAccountEvents(ctx.Context(), accountNumber)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, lifecycleErrorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
@@ -49,6 +49,8 @@ func (h *authHandler) Login(ctx *fiber.Ctx) error {
 		var status int = fiber.StatusInternalServerError
 		if err.Error() == messages.UserNotFound || err.Error() == messages.PasswordIncorrect {
 			status = fiber.StatusUnauthorized
+		} else if err.Error() == messages.UserInactive {
+			status = fiber.StatusForbidden
 		}
 		log.Error(err.Error())
 		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
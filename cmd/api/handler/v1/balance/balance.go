@@ -0,0 +1,69 @@
+package balance
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"strconv"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type BalanceHandler interface {
+	BalanceAt(ctx *fiber.Ctx) error
+}
+
+type balanceHandler struct {
+	balanceService service.BalanceService
+}
+
+func NewBalanceHandler(balanceService service.BalanceService) BalanceHandler {
+	return &balanceHandler{
+		balanceService: balanceService,
+	}
+}
+
+// BalanceAt godoc
+// @Summary Get the balance of an account at a moment
+// @Description Returns the balance the account had at the given moment, calculated from the postings of the ledger.
+// @Description A day (YYYY-MM-DD) returns the balance at the end of the day, an RFC 3339 time the balance at that moment. The current balance is returned without a time.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param at query string false "Day (YYYY-MM-DD) or time (RFC 3339)"
+// @Success 200 {object} dto.GetBalanceResponse
+// @Router /account/balance/{accountNumber} [get]
+func (h *balanceHandler) BalanceAt(ctx *fiber.Ctx) error {
+	var request dto.GetBalanceRequest
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err == nil {
+		err = ctx.QueryParser(&request)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	response, err := h.balanceService.BalanceAt(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.AccountNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.InvalidBalanceTime {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusForbidden
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
@@ -0,0 +1,272 @@
+package beneficiary
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type BeneficiaryHandler interface {
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	ApproveTrust(ctx *fiber.Ctx) error
+	TrustApproval(ctx *fiber.Ctx) error
+}
+
+type beneficiaryHandler struct {
+	beneficiaryService service.BeneficiaryService
+}
+
+func NewBeneficiaryHandler(beneficiaryService service.BeneficiaryService) BeneficiaryHandler {
+	return &beneficiaryHandler{
+		beneficiaryService: beneficiaryService,
+	}
+}
+
+// errorStatus returns the http status of an error of the beneficiaries
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound, messages.IBANNotFound, messages.BeneficiaryNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.BeneficiaryAlreadyExists, messages.AccountClosed, messages.BeneficiaryTrustNotPending:
+		return fiber.StatusConflict
+	case messages.InvalidBeneficiary, messages.BeneficiaryHolderMismatch, messages.InvalidIBAN, messages.InvalidTransferCode,
+		messages.BadRequest:
+		return fiber.StatusBadRequest
+	case messages.BeneficiaryTrustExpired:
+		return fiber.StatusGone
+	case messages.BeneficiaryTrustCodeAttemptsExceeded:
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Create godoc
+// @Summary Save a beneficiary
+// @Description Saves a receiver under a nickname, the receiver is given by an account number or by an IBAN.
+// @Description The optional holder name has to match the holder of the account. Transfers to trusted beneficiaries up to the daily limit of the bank are executed without an approval.
+// @Description A trusted beneficiary is saved untrusted, the trust is given when the user approves it like a transfer.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param createBeneficiaryRequest body dto.CreateBeneficiaryRequest true "Create Beneficiary Request"
+// @Success 201 {object} dto.BeneficiaryItem
+// @Router /profile/beneficiaries [post]
+func (h *beneficiaryHandler) Create(ctx *fiber.Ctx) error {
+	var request dto.CreateBeneficiaryRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.beneficiaryService.WithTx(tx).Create(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusCreated, response)
+}
+
+// List godoc
+// @Summary List the beneficiaries
+// @Description Lists the saved beneficiaries of the user ordered by their nicknames.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.BeneficiaryItem
+// @Router /profile/beneficiaries [get]
+func (h *beneficiaryHandler) List(ctx *fiber.Ctx) error {
+	response, err := h.beneficiaryService.List(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get a beneficiary
+// @Description Returns a saved beneficiary of the user.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Beneficiary Id"
+// @Success 200 {object} dto.BeneficiaryItem
+// @Router /profile/beneficiaries/{id} [get]
+func (h *beneficiaryHandler) Get(ctx *fiber.Ctx) error {
+	response, err := h.beneficiaryService.Get(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Update godoc
+// @Summary Update a beneficiary
+// @Description Changes the nickname or the trust of a saved beneficiary, the account can not be changed.
+// @Description Trust is given when the user approves it like a transfer, it is taken back at once.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Beneficiary Id"
+// @Param updateBeneficiaryRequest body dto.UpdateBeneficiaryRequest true "Update Beneficiary Request"
+// @Success 200 {object} dto.BeneficiaryItem
+// @Router /profile/beneficiaries/{id} [put]
+func (h *beneficiaryHandler) Update(ctx *fiber.Ctx) error {
+	var request dto.UpdateBeneficiaryRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.beneficiaryService.WithTx(tx).Update(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Delete godoc
+// @Summary Delete a beneficiary
+// @Description Removes a saved beneficiary, the transfers made to it stay in the history.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Beneficiary Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /profile/beneficiaries/{id} [delete]
+func (h *beneficiaryHandler) Delete(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.beneficiaryService.WithTx(tx).Delete(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+// ApproveTrust godoc
+// @Summary Approve the trust of a beneficiary with a one-time code
+// @Description Trusts a beneficiary with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.
+// @Description The code is valid for 5 minutes. After 3 wrong codes the beneficiary is not trusted.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Beneficiary Id"
+// @Param approveTransferRequest body dto.ApproveTransferRequest true "Approve Transfer Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /profile/beneficiaries/{id}/trust/approve [post]
+func (h *beneficiaryHandler) ApproveTrust(ctx *fiber.Ctx) error {
+	var request dto.ApproveTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.beneficiaryService.WithTx(tx).ApproveTrust(ctx.Context(), request)
+	if err != nil {
+		// The wrong attempt, the expiry and the rejection are recorded on the beneficiary
+		if err.Error() == messages.InvalidTransferCode || err.Error() == messages.BeneficiaryTrustExpired ||
+			err.Error() == messages.BeneficiaryTrustCodeAttemptsExceeded {
+			transaction.KeepChanges(ctx)
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.BeneficiaryTrustApproved))
+}
+
+// TrustApproval godoc
+// @Summary Approve the trust of a beneficiary
+// @Description Trusts a beneficiary by providing the token of the approval link. The trust can only be approved once and before it expires.
+// @Tags Beneficiary
+// @Accept application/json
+// @Produce application/json
+// @Param token query string true "Token"
+// @Success 200 {object} map[string]interface{}
+// @Router /profile/beneficiary-trust-approval [get]
+func (h *beneficiaryHandler) TrustApproval(ctx *fiber.Ctx) error {
+	token := ctx.Query("token")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.beneficiaryService.WithTx(tx).TrustApproval(ctx.Context(), token)
+	if err != nil {
+		// The expiry is recorded on the beneficiary
+		if err.Error() == messages.BeneficiaryTrustExpired {
+			transaction.KeepChanges(ctx)
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.BeneficiaryTrustApproved))
+}
@@ -0,0 +1,264 @@
+package bulktransfer
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"io"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type BulkTransferHandler interface {
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Approve(ctx *fiber.Ctx) error
+	Approval(ctx *fiber.Ctx) error
+	Cancel(ctx *fiber.Ctx) error
+}
+
+type bulkTransferHandler struct {
+	bulkTransferService service.BulkTransferService
+}
+
+func NewBulkTransferHandler(bulkTransferService service.BulkTransferService) BulkTransferHandler {
+	return &bulkTransferHandler{
+		bulkTransferService: bulkTransferService,
+	}
+}
+
+// errorStatus returns the http status of an error of the bulk transfers
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound, messages.BulkTransferNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.TransferNotPending, messages.AccountClosed, messages.AccountFrozen:
+		return fiber.StatusConflict
+	case messages.BulkTransferRowsInvalid:
+		return fiber.StatusUnprocessableEntity
+	case messages.InvalidBulkTransferFile, messages.BulkTransferTooManyRows, messages.InSufficientBalance,
+		messages.InvalidTransferCode, messages.BadRequest:
+		return fiber.StatusBadRequest
+	case messages.TransferExpired:
+		return fiber.StatusGone
+	case messages.TransferCodeAttemptsExceeded:
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Create godoc
+// @Summary Upload a bulk transfer file
+// @Description Uploads a CSV file of transfers, like a payroll, from an account of the user. The header of the file names the columns:
+// @Description to_account_number or to_iban for the receiver, amount in the currency of the account and an optional note. A file has at most 1000 rows.
+// @Description Every row is validated with the balance, the limits and the fees. If a row is invalid nothing is created and the preview with the errors of the rows is returned.
+// @Description Otherwise the money of the rows is held and the bulk transfer waits for a single approval with the approval method of the user.
+// @Description Approved rows are executed in the background, a row which fails does not stop the others and the report is sent by e-mail at the end.
+// @Tags Bulk Transfer
+// @Accept multipart/form-data
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param from_account_number formData integer true "Account Number"
+// @Param file formData file true "CSV File"
+// @Success 201 {object} dto.BulkTransferItem
+// @Failure 422 {object} dto.BulkTransferItem
+// @Router /account/bulk-transfers [post]
+func (h *bulkTransferHandler) Create(ctx *fiber.Ctx) error {
+	var request dto.CreateBulkTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.InvalidBulkTransferFile))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.InvalidBulkTransferFile))
+	}
+	defer file.Close()
+
+	request.FileName = fileHeader.Filename
+	request.File, err = io.ReadAll(file)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.InvalidBulkTransferFile))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.bulkTransferService.WithTx(tx).CreateBulkTransfer(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		// The preview shows the rows and the totals which could not be made
+		if response != nil {
+			return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()), response)
+		}
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusCreated, response)
+}
+
+// List godoc
+// @Summary List the bulk transfers
+// @Description Lists the bulk transfers of the user without their rows, the newest first.
+// @Tags Bulk Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.BulkTransferItem
+// @Router /account/bulk-transfers [get]
+func (h *bulkTransferHandler) List(ctx *fiber.Ctx) error {
+	response, err := h.bulkTransferService.ListBulkTransfers(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get a bulk transfer
+// @Description Returns the bulk transfer with the status of every row, the rows which failed have the reason of the failure.
+// @Tags Bulk Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Bulk Transfer Id"
+// @Success 200 {object} dto.BulkTransferItem
+// @Router /account/bulk-transfers/{id} [get]
+func (h *bulkTransferHandler) Get(ctx *fiber.Ctx) error {
+	response, err := h.bulkTransferService.GetBulkTransfer(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Approve godoc
+// @Summary Approve the bulk transfer with a one-time code
+// @Description Approve all transfers of a bulk transfer with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.
+// @Description The code is valid for 5 minutes. After 3 wrong codes the bulk transfer is rejected.
+// @Tags Bulk Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Bulk Transfer Id"
+// @Param approveTransferRequest body dto.ApproveTransferRequest true "Approve Transfer Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/bulk-transfers/{id}/approve [post]
+func (h *bulkTransferHandler) Approve(ctx *fiber.Ctx) error {
+	var request dto.ApproveTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.bulkTransferService.WithTx(tx).ApproveBulkTransfer(ctx.Context(), request)
+	if err != nil {
+		// The wrong attempt, the expiry and the rejection are recorded on the bulk transfer
+		if err.Error() == messages.InvalidTransferCode || err.Error() == messages.TransferExpired || err.Error() == messages.TransferCodeAttemptsExceeded {
+			transaction.KeepChanges(ctx)
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.BulkTransferApproved))
+}
+
+// Approval godoc
+// @Summary Approve the bulk transfer
+// @Description Approve all transfers of a bulk transfer by providing the token of the approval link.
+// @Description A bulk transfer can only be approved once and before it expires.
+// @Tags Bulk Transfer
+// @Accept application/json
+// @Produce application/json
+// @Param token query string true "Token"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/bulk-transfer-approval [get]
+func (h *bulkTransferHandler) Approval(ctx *fiber.Ctx) error {
+	token := ctx.Query("token")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.bulkTransferService.WithTx(tx).BulkTransferApproval(ctx.Context(), token)
+	if err != nil {
+		// The expiry is recorded on the bulk transfer
+		if err.Error() == messages.TransferExpired {
+			transaction.KeepChanges(ctx)
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.BulkTransferApproved))
+}
+
+// Cancel godoc
+// @Summary Cancel a bulk transfer
+// @Description Cancels a bulk transfer of the user which is waiting for approval, the money held for its rows is available again.
+// @Tags Bulk Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Bulk Transfer Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/bulk-transfers/{id}/cancel [post]
+func (h *bulkTransferHandler) Cancel(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.bulkTransferService.WithTx(tx).CancelBulkTransfer(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
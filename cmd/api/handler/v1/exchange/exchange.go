@@ -0,0 +1,87 @@
+package exchange
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type ExchangeHandler interface {
+	GetRates(ctx *fiber.Ctx) error
+	UpdateRates(ctx *fiber.Ctx) error
+}
+
+type exchangeHandler struct {
+	exchangeService service.ExchangeService
+}
+
+func NewExchangeHandler(exchangeService service.ExchangeService) ExchangeHandler {
+	return &exchangeHandler{
+		exchangeService: exchangeService,
+	}
+}
+
+// GetRates godoc
+// @Summary Get the exchange rates
+// @Description Every rate is the value of one unit of the currency in the base currency of the bank.
+// @Description Transfers between accounts of different currencies are converted with these rates.
+// @Tags Exchange
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} dto.GetExchangeRatesResponse
+// @Router /exchange-rates [get]
+func (h *exchangeHandler) GetRates(ctx *fiber.Ctx) error {
+	response, err := h.exchangeService.GetRates(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusInternalServerError, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// UpdateRates godoc
+// @Summary Update the exchange rates
+// @Description Creates or overwrites the rates of the given currencies. Only admins can use this endpoint.
+// @Description The rate of a currency is the value of one unit of the currency in the base currency of the bank.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param updateExchangeRatesRequest body dto.UpdateExchangeRatesRequest true "Update Exchange Rates Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/exchange-rates [put]
+func (h *exchangeHandler) UpdateRates(ctx *fiber.Ctx) error {
+	var request dto.UpdateExchangeRatesRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.exchangeService.WithTx(tx).UpdateRates(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidExchangeRate {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,207 @@
+package fee
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type FeeHandler interface {
+	GetSchedules(ctx *fiber.Ctx) error
+	UpdateSchedule(ctx *fiber.Ctx) error
+	DeleteSchedule(ctx *fiber.Ctx) error
+	UpdateSegment(ctx *fiber.Ctx) error
+	GetRevenue(ctx *fiber.Ctx) error
+}
+
+type feeHandler struct {
+	feeService service.FeeService
+}
+
+func NewFeeHandler(feeService service.FeeService) FeeHandler {
+	return &feeHandler{
+		feeService: feeService,
+	}
+}
+
+// GetSchedules godoc
+// @Summary Get the fee schedules
+// @Description Lists the fee schedules of the customer segments and account types. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {object} dto.GetFeeSchedulesResponse
+// @Router /admin/fee-schedules [get]
+func (h *feeHandler) GetSchedules(ctx *fiber.Ctx) error {
+	response, err := h.feeService.GetSchedules(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusInternalServerError, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a fee schedule
+// @Description Creates the schedule or overwrites the schedule of the same segment and account type. Only admins can use this endpoint.
+// @Description An empty segment or account type matches all of them, the most specific schedule is used for a transfer.
+// @Description Flat schedules charge the flat fee, percentage schedules the rate of the amount and tiered schedules the flat fee
+// @Description and the rate of the first tier reaching the amount. The last tier has no upper bound (up_to 0).
+// @Description The fee is kept between the min and the max fee, all amounts are in the base currency.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param feeScheduleItem body dto.FeeScheduleItem true "Fee Schedule"
+// @Success 200 {object} dto.FeeScheduleItem
+// @Router /admin/fee-schedules [put]
+func (h *feeHandler) UpdateSchedule(ctx *fiber.Ctx) error {
+	var request dto.FeeScheduleItem
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.feeService.WithTx(tx).UpdateSchedule(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidFeeSchedule {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a fee schedule
+// @Description Deletes a schedule, its transfers are charged with a less specific schedule. Only admins can use this endpoint.
+// @Description The default schedule matching all segments and account types can not be deleted.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Fee Schedule Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/fee-schedules/{id} [delete]
+func (h *feeHandler) DeleteSchedule(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.feeService.WithTx(tx).DeleteSchedule(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.FeeScheduleNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.DefaultFeeScheduleRequired {
+			status = fiber.StatusConflict
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+// UpdateSegment godoc
+// @Summary Update the segment of a customer
+// @Description Moves the customer to the retail, premium or business segment, the next transfers are charged with the
+// @Description fee schedules of the segment. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "User Id"
+// @Param updateSegmentRequest body dto.UpdateSegmentRequest true "Update Segment Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/users/{id}/segment [put]
+func (h *feeHandler) UpdateSegment(ctx *fiber.Ctx) error {
+	var request dto.UpdateSegmentRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.UserId = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.feeService.WithTx(tx).UpdateSegment(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidSegment {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.UserNotFound {
+			status = fiber.StatusNotFound
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.SegmentUpdated))
+}
+
+// GetRevenue godoc
+// @Summary Get the fee revenue
+// @Description Lists the fees collected in the fee income accounts of the bank per day and currency.
+// @Description The dates are inclusive and can be at most a year apart, the last 30 days are reported without them.
+// @Description Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} dto.GetFeeRevenueResponse
+// @Router /admin/fee-revenue [get]
+func (h *feeHandler) GetRevenue(ctx *fiber.Ctx) error {
+	var request dto.GetFeeRevenueRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	response, err := h.feeService.GetRevenue(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidDateRange {
+			status = fiber.StatusBadRequest
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
@@ -0,0 +1,156 @@
+package hold
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"strconv"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type HoldHandler interface {
+	Place(ctx *fiber.Ctx) error
+	Release(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+}
+
+type holdHandler struct {
+	holdService service.HoldService
+}
+
+func NewHoldHandler(holdService service.HoldService) HoldHandler {
+	return &holdHandler{
+		holdService: holdService,
+	}
+}
+
+// errorStatus returns the http status of an error of the hold service
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound, messages.HoldNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.HoldNotActive, messages.AccountClosed:
+		return fiber.StatusConflict
+	case messages.InvalidHold, messages.AccountReasonRequired:
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Place godoc
+// @Summary Place a hold on an account
+// @Description Holds an amount of a customer account, like a legal block. The held amount can not be withdrawn or transferred,
+// @Description the ledger balance does not change. The hold may be more than the balance, the money coming in later is held then.
+// @Description The type is legal or manual, a reason is required. Without an expiry the hold stays until it is released. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param accountNumber path int true "Account Number"
+// @Param placeHoldRequest body dto.PlaceHoldRequest true "Place Hold Request"
+// @Success 201 {object} dto.HoldItem
+// @Router /admin/accounts/{accountNumber}/holds [post]
+func (h *holdHandler) Place(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	var request dto.PlaceHoldRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.holdService.WithTx(tx).Place(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusCreated, response)
+}
+
+// Release godoc
+// @Summary Release a hold
+// @Description Ends an active hold with a reason, the held money is available again. Holds of pending transfers are released
+// @Description the same way, the transfer checks the balance again when it is approved. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Hold Id"
+// @Param releaseHoldRequest body dto.ReleaseHoldRequest true "Release Hold Request"
+// @Success 200 {object} dto.HoldItem
+// @Router /admin/holds/{id}/release [post]
+func (h *holdHandler) Release(ctx *fiber.Ctx) error {
+	var request dto.ReleaseHoldRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.holdService.WithTx(tx).Release(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// List godoc
+// @Summary List the holds of an account
+// @Description Returns the holds of the account with its ledger balance, the held amount and the available balance.
+// @Description Customers see the holds of their own accounts, admins of all customer accounts.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Success 200 {object} dto.GetHoldsResponse
+// @Router /account/holds/{accountNumber} [get]
+// @Router /admin/accounts/{accountNumber}/holds [get]
+func (h *holdHandler) List(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	response, err := h.holdService.List(ctx.Context(), accountNumber)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
@@ -0,0 +1,123 @@
+package interest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type InterestHandler interface {
+	GetRates(ctx *fiber.Ctx) error
+	UpdateRate(ctx *fiber.Ctx) error
+	DeleteRate(ctx *fiber.Ctx) error
+}
+
+type interestHandler struct {
+	interestService service.InterestService
+}
+
+func NewInterestHandler(interestService service.InterestService) InterestHandler {
+	return &interestHandler{
+		interestService: interestService,
+	}
+}
+
+// GetRates godoc
+// @Summary Get the interest rates
+// @Description Lists the yearly interest rates of the account types and currencies. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {object} dto.GetInterestRatesResponse
+// @Router /admin/interest-rates [get]
+func (h *interestHandler) GetRates(ctx *fiber.Ctx) error {
+	response, err := h.interestService.GetRates(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusInternalServerError, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// UpdateRate godoc
+// @Summary Update an interest rate
+// @Description Creates the rate or overwrites the rate of the same account type and currency. Only admins can use this endpoint.
+// @Description The rate is yearly (0.35 is 35%), every day with a positive balance at its end earns 1/365 of it.
+// @Description The interest is accrued daily and paid to the account at the start of the next month, the new rate is used from the day it is set on, the earlier days keep their rate.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param interestRateItem body dto.InterestRateItem true "Interest Rate"
+// @Success 200 {object} dto.InterestRateItem
+// @Router /admin/interest-rates [put]
+func (h *interestHandler) UpdateRate(ctx *fiber.Ctx) error {
+	var request dto.InterestRateItem
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.interestService.WithTx(tx).UpdateRate(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidInterestRate || err.Error() == messages.UnsupportedCurrency {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// DeleteRate godoc
+// @Summary Delete an interest rate
+// @Description Deletes a rate, the accounts of its type and currency do not earn interest anymore. The interest accrued
+// @Description until then is still paid. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Interest Rate Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/interest-rates/{id} [delete]
+func (h *interestHandler) DeleteRate(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.interestService.WithTx(tx).DeleteRate(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InterestRateNotFound {
+			status = fiber.StatusNotFound
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,158 @@
+package limit
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"strconv"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type LimitHandler interface {
+	GetRemaining(ctx *fiber.Ctx) error
+	GetLimits(ctx *fiber.Ctx) error
+	UpdateLimits(ctx *fiber.Ctx) error
+	DeleteLimit(ctx *fiber.Ctx) error
+}
+
+type limitHandler struct {
+	limitService service.LimitService
+}
+
+func NewLimitHandler(limitService service.LimitService) LimitHandler {
+	return &limitHandler{
+		limitService: limitService,
+	}
+}
+
+// GetRemaining godoc
+// @Summary Get the remaining transfer limits of an account
+// @Description Lists the per transfer, daily and monthly limits of the account with the amounts used and left in the current period.
+// @Description The amounts are in the base currency of the bank. Customer limits count the transfers from all accounts of the user.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Success 200 {object} dto.GetRemainingLimitsResponse
+// @Router /account/limits/{accountNumber} [get]
+func (h *limitHandler) GetRemaining(ctx *fiber.Ctx) error {
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err != nil {
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	response, err := h.limitService.GetRemaining(ctx.Context(), accountNumber)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.AccountNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusForbidden
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// GetLimits godoc
+// @Summary Get the transfer limits
+// @Description Lists the global, customer and account transfer limits. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {object} dto.GetTransferLimitsResponse
+// @Router /admin/transfer-limits [get]
+func (h *limitHandler) GetLimits(ctx *fiber.Ctx) error {
+	response, err := h.limitService.GetLimits(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusInternalServerError, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// UpdateLimits godoc
+// @Summary Update the transfer limits
+// @Description Creates or overwrites the limits of the given levels, scopes and periods. Only admins can use this endpoint.
+// @Description The level is global, customer or account and the period is transaction, daily or monthly. The scope id is
+// @Description empty for global limits, the user id for customer limits and the account number for account limits.
+// @Description A customer limit replaces the global limit of the same period, account limits apply in addition.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param updateTransferLimitsRequest body dto.UpdateTransferLimitsRequest true "Update Transfer Limits Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/transfer-limits [put]
+func (h *limitHandler) UpdateLimits(ctx *fiber.Ctx) error {
+	var request dto.UpdateTransferLimitsRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.limitService.WithTx(tx).UpdateLimits(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidTransferLimit {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+// DeleteLimit godoc
+// @Summary Delete a transfer limit
+// @Description Deletes a limit, customers without their own limit fall back to the global limit. Only admins can use this endpoint.
+// @Tags Admin
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Transfer Limit Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/transfer-limits/{id} [delete]
+func (h *limitHandler) DeleteLimit(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.limitService.WithTx(tx).DeleteLimit(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.TransferLimitNotFound {
+			status = fiber.StatusNotFound
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,258 @@
+package paymentrequest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type PaymentRequestHandler interface {
+	Create(ctx *fiber.Ctx) error
+	ListSent(ctx *fiber.Ctx) error
+	Inbox(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Accept(ctx *fiber.Ctx) error
+	Decline(ctx *fiber.Ctx) error
+	Cancel(ctx *fiber.Ctx) error
+}
+
+type paymentRequestHandler struct {
+	paymentRequestService service.PaymentRequestService
+}
+
+func NewPaymentRequestHandler(paymentRequestService service.PaymentRequestService) PaymentRequestHandler {
+	return &paymentRequestHandler{
+		paymentRequestService: paymentRequestService,
+	}
+}
+
+// errorStatus returns the http status of an error of the payment requests, accepting a request
+// returns the errors of the transfer too
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound, messages.IBANNotFound, messages.UserNotFound, messages.PaymentRequestNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.PaymentRequestNotPending, messages.AccountClosed, messages.AccountFrozen:
+		return fiber.StatusConflict
+	case messages.PaymentRequestExpired:
+		return fiber.StatusGone
+	case messages.PaymentRequestLimitExceeded:
+		return fiber.StatusTooManyRequests
+	case messages.InvalidAmount, messages.InvalidPaymentRequest, messages.PaymentRequestCurrencyMismatch,
+		messages.PaymentRequestAccountMismatch, messages.InSufficientBalance, messages.TransferTransactionLimitExceeded,
+		messages.TransferDailyLimitExceeded, messages.TransferMonthlyLimitExceeded, messages.BadRequest:
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Create godoc
+// @Summary Request money from another customer
+// @Description Asks another customer for money to an account of the user, the payer is given by an account number or by a customer number.
+// @Description A request sent to an account has to be paid from that account. The payer is notified by e-mail and can answer for 7 days.
+// @Description A user can send 20 requests a day, the payer is shown to the requester once the payer answers.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param createPaymentRequestRequest body dto.CreatePaymentRequestRequest true "Create Payment Request Request"
+// @Success 201 {object} dto.PaymentRequestItem
+// @Router /account/payment-requests [post]
+func (h *paymentRequestHandler) Create(ctx *fiber.Ctx) error {
+	var request dto.CreatePaymentRequestRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.paymentRequestService.WithTx(tx).Create(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusCreated, response)
+}
+
+// ListSent godoc
+// @Summary List the sent payment requests
+// @Description Lists the payment requests the user sent with their answers, the newest first.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.PaymentRequestItem
+// @Router /account/payment-requests [get]
+func (h *paymentRequestHandler) ListSent(ctx *fiber.Ctx) error {
+	response, err := h.paymentRequestService.ListSent(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Inbox godoc
+// @Summary List the received payment requests
+// @Description Lists the payment requests waiting for an answer of the user, the newest first.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.PaymentRequestItem
+// @Router /account/payment-requests/inbox [get]
+func (h *paymentRequestHandler) Inbox(ctx *fiber.Ctx) error {
+	response, err := h.paymentRequestService.Inbox(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get a payment request
+// @Description Returns a payment request the user sent or received.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Payment Request Id"
+// @Success 200 {object} dto.PaymentRequestItem
+// @Router /account/payment-requests/{id} [get]
+func (h *paymentRequestHandler) Get(ctx *fiber.Ctx) error {
+	response, err := h.paymentRequestService.Get(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Accept godoc
+// @Summary Accept a payment request
+// @Description Pays a received payment request with a transfer to the requester from an account of the user in the currency of the request.
+// @Description The transfer waits for the approval of the user like any other transfer, the request is paying until then.
+// @Description The requester is notified by e-mail when the transfer is executed, the request is pending again when the transfer fails.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param id path string true "Payment Request Id"
+// @Param acceptPaymentRequestRequest body dto.AcceptPaymentRequestRequest true "Accept Payment Request Request"
+// @Success 200 {object} dto.PaymentRequestItem
+// @Router /account/payment-requests/{id}/accept [post]
+func (h *paymentRequestHandler) Accept(ctx *fiber.Ctx) error {
+	var request dto.AcceptPaymentRequestRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.paymentRequestService.WithTx(tx).Accept(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Decline godoc
+// @Summary Decline a payment request
+// @Description Turns down a received payment request, the requester is notified by e-mail with the optional reason.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Payment Request Id"
+// @Param declinePaymentRequestRequest body dto.DeclinePaymentRequestRequest true "Decline Payment Request Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/payment-requests/{id}/decline [post]
+func (h *paymentRequestHandler) Decline(ctx *fiber.Ctx) error {
+	var request dto.DeclinePaymentRequestRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.paymentRequestService.WithTx(tx).Decline(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+// Cancel godoc
+// @Summary Cancel a payment request
+// @Description Withdraws a payment request of the user which is not answered yet.
+// @Tags Payment Request
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Payment Request Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/payment-requests/{id}/cancel [post]
+func (h *paymentRequestHandler) Cancel(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.paymentRequestService.WithTx(tx).Cancel(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
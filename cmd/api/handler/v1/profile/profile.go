@@ -3,7 +3,7 @@ package profile
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
-	"strconv"
+	"tek-bank/internal/dto"
 	"tek-bank/internal/i18n"
 	"tek-bank/internal/i18n/messages"
 	"tek-bank/internal/service"
@@ -13,6 +13,7 @@ import (
 type ProfileHandler interface {
 	MyProfile(ctx *fiber.Ctx) error
 	MyTransferHistory(ctx *fiber.Ctx) error
+	UpdateApprovalMethod(ctx *fiber.Ctx) error
 }
 
 type profileHandler struct {
@@ -55,32 +56,80 @@ func (h *profileHandler) MyProfile(ctx *fiber.Ctx) error {
 // MyTransferHistory godoc
 // @Summary Get user transfer history
 // @Description You can see only your own transfer history with this endpoint.
-// @Description You can filter the transfer history by account number.
-// @Description The account number is required for this endpoint.
+// @Description The account number is required for this endpoint. The entries are sorted by their creation time, newest first.
+// @Description A page has 20 entries by default and at most 100. The next page is returned with the next_cursor of the page as the cursor and the same filters.
+// @Description The dates are inclusive, the amounts are compared in the currency of the account and the note is searched case-insensitively.
 // @Tags Profile
 // @Accept application/json
 // @Produce application/json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Bearer <token>"
 // @Param accountNumber query string true "Account Number"
-// @Success 200 {object} []dto.GetTransferHistoryResponse
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Number of entries of the page"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param direction query string false "Direction" Enums(in, out)
+// @Param min_amount query string false "Minimum amount"
+// @Param max_amount query string false "Maximum amount"
+// @Param note query string false "Text in the note"
+// @Success 200 {object} dto.GetTransferHistoryPageResponse
 // @Router /profile/transfer-history [get]
 func (h *profileHandler) MyTransferHistory(ctx *fiber.Ctx) error {
-
-	accountNumber, err := strconv.Atoi(ctx.Query("accountNumber"))
-	if err != nil {
-		log.Error(err.Error())
+	var request dto.GetTransferHistoryRequest
+	if err := ctx.QueryParser(&request); err != nil || request.AccountNumber == 0 {
+		if err != nil {
+			log.Error(err.Error())
+		}
 		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
 	}
 
-	response, err := h.profileService.MyTransferHistory(ctx.Context(), int64(accountNumber))
+	response, err := h.profileService.MyTransferHistory(ctx.Context(), request)
 	if err != nil {
 		var status int = fiber.StatusInternalServerError
 		if err.Error() == messages.AccountNotFound {
 			status = fiber.StatusNotFound
+		} else if err.Error() == messages.InvalidHistoryFilter || err.Error() == messages.InvalidCursor || err.Error() == messages.InvalidDateRange {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
 		}
 		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
 	}
 
 	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
 }
+
+// UpdateApprovalMethod godoc
+// @Summary Update the approval method of transfers
+// @Description Chooses how the transfers of the user are approved. email_link sends an approval link via e-mail,
+// @Description email_code and sms_code send a one-time code which is confirmed with the approve endpoint of the transfer.
+// @Tags Profile
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param updateApprovalMethodRequest body dto.UpdateApprovalMethodRequest true "Update Approval Method Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /profile/approval-method [put]
+func (h *profileHandler) UpdateApprovalMethod(ctx *fiber.Ctx) error {
+	var request dto.UpdateApprovalMethodRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	err := h.profileService.UpdateApprovalMethod(ctx.Context(), request)
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.InvalidApprovalMethod {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.ApprovalMethodUpdated))
+}
@@ -0,0 +1,191 @@
+package scheduledtransfer
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type ScheduledTransferHandler interface {
+	Create(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Get(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Cancel(ctx *fiber.Ctx) error
+}
+
+type scheduledTransferHandler struct {
+	scheduledTransferService service.ScheduledTransferService
+}
+
+func NewScheduledTransferHandler(scheduledTransferService service.ScheduledTransferService) ScheduledTransferHandler {
+	return &scheduledTransferHandler{
+		scheduledTransferService: scheduledTransferService,
+	}
+}
+
+// errorStatus returns the http status of an error of the scheduled transfer service
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound, messages.IBANNotFound, messages.ScheduledTransferNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.ScheduledTransferNotActive, messages.AccountClosed:
+		return fiber.StatusConflict
+	case messages.InvalidAmount, messages.InvalidSchedule, messages.InvalidIBAN, messages.BadRequest:
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Create godoc
+// @Summary Schedule a transfer
+// @Description Schedules a future dated (once) or a recurring (daily, weekly, monthly) transfer from an account of the user.
+// @Description Dates are given as YYYY-MM-DD, monthly transfers run on the day of the start date or on the last day of shorter months.
+// @Description Transfers falling on a weekend run on the next business day (shift) or are left out (skip).
+// @Description A transfer failing for the balance is tried again every hour, up to 3 times per occurrence.
+// @Tags Scheduled Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param Idempotency-Key header string false "Unique key of the request, retries with the same key return the first response"
+// @Param createScheduledTransferRequest body dto.CreateScheduledTransferRequest true "Create Scheduled Transfer Request"
+// @Success 201 {object} dto.ScheduledTransferItem
+// @Router /account/scheduled-transfers [post]
+func (h *scheduledTransferHandler) Create(ctx *fiber.Ctx) error {
+	var request dto.CreateScheduledTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.scheduledTransferService.WithTx(tx).Create(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusCreated, response)
+}
+
+// List godoc
+// @Summary List the scheduled transfers
+// @Description Lists the scheduled transfers of the user including the completed, cancelled and failed ones.
+// @Tags Scheduled Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.ScheduledTransferItem
+// @Router /account/scheduled-transfers [get]
+func (h *scheduledTransferHandler) List(ctx *fiber.Ctx) error {
+	response, err := h.scheduledTransferService.List(ctx.Context())
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Get godoc
+// @Summary Get a scheduled transfer
+// @Description Returns the scheduled transfer with its next run and the result of its last run.
+// @Tags Scheduled Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Scheduled Transfer Id"
+// @Success 200 {object} dto.ScheduledTransferItem
+// @Router /account/scheduled-transfers/{id} [get]
+func (h *scheduledTransferHandler) Get(ctx *fiber.Ctx) error {
+	response, err := h.scheduledTransferService.Get(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Update godoc
+// @Summary Update a scheduled transfer
+// @Description Changes the amount, the note or the end date of an active scheduled transfer, the fields which are not given stay the same.
+// @Description An empty end date removes the end date.
+// @Tags Scheduled Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Scheduled Transfer Id"
+// @Param updateScheduledTransferRequest body dto.UpdateScheduledTransferRequest true "Update Scheduled Transfer Request"
+// @Success 200 {object} dto.ScheduledTransferItem
+// @Router /account/scheduled-transfers/{id} [put]
+func (h *scheduledTransferHandler) Update(ctx *fiber.Ctx) error {
+	var request dto.UpdateScheduledTransferRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.Id = ctx.Params("id")
+
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	response, err := h.scheduledTransferService.WithTx(tx).Update(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Cancel godoc
+// @Summary Cancel a scheduled transfer
+// @Description Cancels an active scheduled transfer, the transfers which already ran are not affected.
+// @Tags Scheduled Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Scheduled Transfer Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/scheduled-transfers/{id} [delete]
+func (h *scheduledTransferHandler) Cancel(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.scheduledTransferService.WithTx(tx).Cancel(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
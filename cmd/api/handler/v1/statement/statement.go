@@ -0,0 +1,117 @@
+package statement
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"strconv"
+	"tek-bank/cmd/config"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type StatementHandler interface {
+	Download(ctx *fiber.Ctx) error
+	Email(ctx *fiber.Ctx) error
+}
+
+type statementHandler struct {
+	statementService service.StatementService
+}
+
+func NewStatementHandler(statementService service.StatementService) StatementHandler {
+	return &statementHandler{
+		statementService: statementService,
+	}
+}
+
+// errorStatus returns the http status of an error of the statement service
+func errorStatus(err error) int {
+	switch err.Error() {
+	case messages.AccountNotFound:
+		return fiber.StatusNotFound
+	case messages.Unauthorized:
+		return fiber.StatusForbidden
+	case messages.InvalidDateRange, messages.InvalidStatementFormat:
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Download godoc
+// @Summary Download an account statement
+// @Description Returns the statement of an account of the user as a CSV or PDF file with the opening balance, every posting with the running balance and the closing balance.
+// @Description The dates are inclusive and can be at most a year apart, the last month is returned without them. The labels are in the language of the request.
+// @Tags Account
+// @Produce text/csv,application/pdf
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param format query string false "Format of the file" Enums(csv, pdf) default(pdf)
+// @Success 200 {file} file
+// @Router /account/statements/{accountNumber} [get]
+func (h *statementHandler) Download(ctx *fiber.Ctx) error {
+	var request dto.StatementRequest
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err == nil {
+		err = ctx.QueryParser(&request)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+	request.Language = config.GetLanguage(ctx)
+
+	file, err := h.statementService.Download(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	ctx.Attachment(file.Name)
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	return ctx.Status(fiber.StatusOK).Send(file.Content)
+}
+
+// Email godoc
+// @Summary Send an account statement via e-mail
+// @Description Sends the statement of an account of the user as a CSV or PDF attachment to the e-mail address of the user.
+// @Description The dates are inclusive and can be at most a year apart, the last month is sent without them.
+// @Tags Account
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param accountNumber path int true "Account Number"
+// @Param statementRequest body dto.StatementRequest true "Statement Request"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/statements/{accountNumber}/email [post]
+func (h *statementHandler) Email(ctx *fiber.Ctx) error {
+	var request dto.StatementRequest
+	accountNumber, err := strconv.ParseInt(ctx.Params("accountNumber"), 10, 64)
+	if err == nil {
+		err = ctx.BodyParser(&request)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.BadRequest))
+	}
+
+	request.AccountNumber = accountNumber
+	request.Language = config.GetLanguage(ctx)
+
+	err = h.statementService.Email(ctx.Context(), request)
+	if err != nil {
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, errorStatus(err), i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil, i18n.CreateMsg(ctx, messages.StatementSent))
+}
@@ -0,0 +1,86 @@
+package transfer
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/transaction"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/service"
+	"tek-bank/pkg/cresponse"
+)
+
+type TransferHandler interface {
+	ListPending(ctx *fiber.Ctx) error
+	Cancel(ctx *fiber.Ctx) error
+}
+
+type transferHandler struct {
+	transferService service.TransferService
+}
+
+func NewTransferHandler(transferService service.TransferService) TransferHandler {
+	return &transferHandler{
+		transferService: transferService,
+	}
+}
+
+// ListPending godoc
+// @Summary List the pending transfers
+// @Description Lists the transfers of the user which are waiting for approval and have not expired yet.
+// @Tags Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Success 200 {array} dto.TransferItem
+// @Router /account/transfers/pending [get]
+func (h *transferHandler) ListPending(ctx *fiber.Ctx) error {
+	response, err := h.transferService.ListPending(ctx.Context())
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, response)
+}
+
+// Cancel godoc
+// @Summary Cancel a pending transfer
+// @Description Cancels a transfer of the user which is waiting for approval, its approval link can not be used anymore.
+// @Tags Transfer
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Bearer <token>"
+// @Param id path string true "Transfer Id"
+// @Success 200 {object} map[string]interface{}
+// @Router /account/transfers/{id}/cancel [post]
+func (h *transferHandler) Cancel(ctx *fiber.Ctx) error {
+	// Database transaction
+	tx, err := transaction.GetDbTx(ctx)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(ctx, fiber.StatusBadRequest, i18n.CreateMsg(ctx, messages.TransactionFailed))
+	}
+
+	err = h.transferService.WithTx(tx).Cancel(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		var status int = fiber.StatusInternalServerError
+		if err.Error() == messages.TransferNotFound {
+			status = fiber.StatusNotFound
+		} else if err.Error() == messages.TransferNotPending {
+			status = fiber.StatusConflict
+		} else if err.Error() == messages.Unauthorized {
+			status = fiber.StatusUnauthorized
+		}
+		log.Error(err.Error())
+		return cresponse.ErrorResponse(ctx, status, i18n.CreateMsg(ctx, err.Error()))
+	}
+
+	return cresponse.SuccessResponse(ctx, fiber.StatusOK, nil)
+}
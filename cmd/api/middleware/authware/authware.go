@@ -120,6 +120,7 @@ type CurrentUser struct {
 	PhoneNumber string `json:"phone_number"`
 	Email       string `json:"email"`
 	Username    string `json:"username"`
+	Role        string `json:"role"`
 }
 
 func checkPermission(ctx *fiber.Ctx, db *gorm.DB, claim JWTClaimsPayload) bool {
@@ -130,7 +131,8 @@ func checkPermission(ctx *fiber.Ctx, db *gorm.DB, claim JWTClaimsPayload) bool {
 		return false
 	}
 
-	if user == nil {
+	// Deactivated users can not use their tokens anymore
+	if user == nil || !user.IsActive {
 		return false
 	} else {
 
@@ -145,12 +147,36 @@ func checkPermission(ctx *fiber.Ctx, db *gorm.DB, claim JWTClaimsPayload) bool {
 			return false
 		}
 
-		ctx.Locals(currentUserLabel, currentUser)
+		SetCurrentUser(ctx, currentUser)
 
 		return true
 	}
 }
 
+// RequireRole only lets the current user through if the user has one of the roles,
+// it has to be used after the authentication middleware
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		currentUser, err := GetCurrentUser(c.Context())
+		if err != nil {
+			return cresponse.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized")
+		}
+
+		for _, role := range roles {
+			if currentUser.Role == role {
+				return c.Next()
+			}
+		}
+
+		return cresponse.ErrorResponse(c, fiber.StatusForbidden, "Forbidden")
+	}
+}
+
+// SetCurrentUser keeps the user of the request in the context
+func SetCurrentUser(ctx *fiber.Ctx, user CurrentUser) {
+	ctx.Locals(currentUserLabel, user)
+}
+
 func GetCurrentUser(ctx context.Context) (CurrentUser, error) {
 	var response CurrentUser
 	currentUser := ctx.Value(currentUserLabel)
@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/cresponse"
+	"time"
+)
+
+const maxKeyLength = 255
+
+/*
+Config of the idempotency middleware.
+
+A client sends a unique Idempotency-Key header with a request which must not run twice, like a deposit.
+The first response for the key is stored and returned again for every retry with the same key,
+so a retried request never moves money twice. Requests without the header are not changed.
+*/
+type Config struct {
+	Repository repository.IdempotencyRepository
+	HeaderKey  string
+
+	// LockTimeout is how long a key stays reserved while its first request is running
+	LockTimeout time.Duration
+	// Expiration is how long the response of a key is kept
+	Expiration time.Duration
+}
+
+/*
+Middleware specific
+Function for generating default config
+*/
+func setup(config Config) Config {
+	if config.HeaderKey == "" {
+		config.HeaderKey = "Idempotency-Key"
+	}
+
+	if config.LockTimeout == 0 {
+		config.LockTimeout = time.Minute
+	}
+
+	if config.Expiration == 0 {
+		config.Expiration = 24 * time.Hour
+	}
+
+	return config
+}
+
+// New creates the idempotency middleware. It has to be used after the authentication middleware
+// (if the route has one) and before the transaction middleware, so the response is only stored
+// after the transaction is committed.
+func New(config Config) fiber.Handler {
+	cfg := setup(config)
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(cfg.HeaderKey)
+		if key == "" {
+			return c.Next()
+		}
+
+		if len(key) > maxKeyLength {
+			return cresponse.ErrorResponse(c, fiber.StatusBadRequest, i18n.CreateMsg(c, messages.InvalidIdempotencyKey))
+		}
+
+		requestHash := hashRequest(c)
+		storageKey := callerIdentity(c, requestHash) + ":" + key
+
+		reserved, err := cfg.Repository.Reserve(c.Context(), storageKey, repository.IdempotencyRecord{RequestHash: requestHash}, cfg.LockTimeout)
+		if err != nil {
+			log.Error(err)
+			return cresponse.ErrorResponse(c, fiber.StatusInternalServerError, i18n.CreateMsg(c, messages.UnexpectedError))
+		}
+
+		if !reserved {
+			return replay(c, cfg, storageKey, requestHash)
+		}
+
+		if err := c.Next(); err != nil {
+			_ = cfg.Repository.Delete(c.Context(), storageKey)
+			return err
+		}
+
+		// Server errors are not stored, nothing was written because the transaction is rolled back
+		// and the client may retry with the same key
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := cfg.Repository.Delete(c.Context(), storageKey); err != nil {
+				log.Error(err)
+			}
+			return nil
+		}
+
+		record := repository.IdempotencyRecord{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+
+		if err := cfg.Repository.Save(c.Context(), storageKey, record, cfg.Expiration); err != nil {
+			log.Error(err)
+		}
+
+		return nil
+	}
+}
+
+// replay answers a request whose key is already used
+func replay(c *fiber.Ctx, cfg Config, storageKey string, requestHash string) error {
+	record, err := cfg.Repository.Get(c.Context(), storageKey)
+	if err != nil {
+		log.Error(err)
+		return cresponse.ErrorResponse(c, fiber.StatusInternalServerError, i18n.CreateMsg(c, messages.UnexpectedError))
+	}
+
+	// The first request ended with a server error between the two calls
+	if record == nil {
+		return cresponse.ErrorResponse(c, fiber.StatusConflict, i18n.CreateMsg(c, messages.IdempotencyKeyInProgress))
+	}
+
+	if record.RequestHash != requestHash {
+		return cresponse.ErrorResponse(c, fiber.StatusUnprocessableEntity, i18n.CreateMsg(c, messages.IdempotencyKeyReused))
+	}
+
+	if !record.Completed {
+		return cresponse.ErrorResponse(c, fiber.StatusConflict, i18n.CreateMsg(c, messages.IdempotencyKeyInProgress))
+	}
+
+	c.Set(fiber.HeaderContentType, record.ContentType)
+	c.Set("Idempotent-Replayed", "true")
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+// callerIdentity scopes the keys to the current user. Anonymous requests like register are scoped to the ip address
+// and the request, since the clients behind a proxy share the ip address and must not get the response of another
+// client for the same key.
+func callerIdentity(c *fiber.Ctx, requestHash string) string {
+	currentUser, err := authware.GetCurrentUser(c.Context())
+	if err != nil {
+		return "ip:" + c.IP() + ":" + requestHash
+	}
+
+	return "user:" + currentUser.Id
+}
+
+// hashRequest identifies the request with its method, url and body
+func hashRequest(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,202 @@
+package idempotency
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/i18n"
+)
+
+// memoryRepository keeps the records in memory like redis would
+type memoryRepository struct {
+	mu      sync.Mutex
+	records map[string]repository.IdempotencyRecord
+}
+
+func (r *memoryRepository) Reserve(ctx context.Context, key string, record repository.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[key]; ok {
+		return false, nil
+	}
+	r.records[key] = record
+	return true, nil
+}
+
+func (r *memoryRepository) Get(ctx context.Context, key string) (*repository.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *memoryRepository) Save(ctx context.Context, key string, record repository.IdempotencyRecord, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[key] = record
+	return nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+	return nil
+}
+
+const testUserId = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b"
+
+// setupTest creates the app with the middleware, the requests are anonymous if the user id is empty
+func setupTest(t *testing.T, userId string, handler fiber.Handler) (*fiber.App, *memoryRepository) {
+	i18n.InitBundle("./../../../../internal/i18n/languages")
+
+	repo := &memoryRepository{records: make(map[string]repository.IdempotencyRecord)}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if userId != "" {
+			authware.SetCurrentUser(c, authware.CurrentUser{Id: userId})
+		}
+		return c.Next()
+	})
+	app.Put("/add-money/:accountNumber", New(Config{Repository: repo}), handler)
+
+	return app, repo
+}
+
+func send(t *testing.T, app *fiber.App, key string, body string) (int, string, string) {
+	req := httptest.NewRequest(fiber.MethodPut, "/add-money/123", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(respBody), resp.Header.Get("Idempotent-Replayed")
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	calls := 0
+	app, _ := setupTest(t, testUserId, func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"call": calls})
+	})
+
+	status, body, replayed := send(t, app, "key-1", `{"amount": 10}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Empty(t, replayed)
+
+	status, body, replayed = send(t, app, "key-1", `{"amount": 10}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "true", replayed)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	calls := 0
+	app, _ := setupTest(t, testUserId, func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	send(t, app, "", `{"amount": 10}`)
+	send(t, app, "", `{"amount": 10}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	calls := 0
+	app, _ := setupTest(t, testUserId, func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	send(t, app, "key-1", `{"amount": 10}`)
+	status, _, _ := send(t, app, "key-1", `{"amount": 20}`)
+
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_RequestInProgress(t *testing.T) {
+	app, repo := setupTest(t, testUserId, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Take the reservation of a finished request back, as if it was still running
+	send(t, app, "key-1", `{"amount": 10}`)
+	for key, record := range repo.records {
+		repo.records[key] = repository.IdempotencyRecord{RequestHash: record.RequestHash}
+	}
+
+	status, _, _ := send(t, app, "key-1", `{"amount": 10}`)
+	assert.Equal(t, fiber.StatusConflict, status)
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	app, repo := setupTest(t, testUserId, func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	status, _, _ := send(t, app, "key-1", `{"amount": 10}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Empty(t, repo.records)
+
+	status, _, _ = send(t, app, "key-1", `{"amount": 10}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_AnonymousCallersBehindProxy(t *testing.T) {
+	calls := 0
+	app, _ := setupTest(t, "", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"call": calls})
+	})
+
+	// Another client with the same ip address and key gets its own response
+	send(t, app, "key-1", `{"email": "peter.parker@company.com"}`)
+	status, body, replayed := send(t, app, "key-1", `{"email": "mary.jane@company.com"}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `{"call":2}`, body)
+	assert.Empty(t, replayed)
+
+	// A retry of the same request is still replayed
+	status, body, replayed = send(t, app, "key-1", `{"email": "peter.parker@company.com"}`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "true", replayed)
+
+	assert.Equal(t, 2, calls)
+}
@@ -11,6 +11,9 @@ import (
 
 var DbTx *gorm.DB
 
+// keepChangesKey marks a request whose transaction is committed even though it responds with an error
+const keepChangesKey = "keepChanges"
+
 func Tx(db *gorm.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		txHandle := db.Begin()
@@ -28,7 +31,8 @@ func Tx(db *gorm.DB) fiber.Handler {
 			return err
 		}
 
-		if c.Response().StatusCode() >= fiber.StatusOK && c.Response().StatusCode() < fiber.StatusMultipleChoices {
+		keepChanges, _ := c.Locals(keepChangesKey).(bool)
+		if keepChanges || (c.Response().StatusCode() >= fiber.StatusOK && c.Response().StatusCode() < fiber.StatusMultipleChoices) {
 			if err := txHandle.Commit().Error; err != nil {
 				log.Print("tx commit error: ", err)
 			}
@@ -49,3 +53,9 @@ func GetDbTx(ctx *fiber.Ctx) (*gorm.DB, error) {
 
 	return tx, nil
 }
+
+// KeepChanges commits the transaction of the request even if it responds with an error,
+// it is used for errors which are recorded themselves like an expired transfer
+func KeepChanges(ctx *fiber.Ctx) {
+	ctx.Locals(keepChangesKey, true)
+}
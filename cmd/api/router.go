@@ -7,13 +7,28 @@ import (
 	"gorm.io/gorm"
 	"tek-bank/cmd/api/handler/v1/account"
 	"tek-bank/cmd/api/handler/v1/auth"
+	"tek-bank/cmd/api/handler/v1/balance"
+	"tek-bank/cmd/api/handler/v1/beneficiary"
+	"tek-bank/cmd/api/handler/v1/bulktransfer"
+	"tek-bank/cmd/api/handler/v1/exchange"
+	"tek-bank/cmd/api/handler/v1/fee"
+	"tek-bank/cmd/api/handler/v1/hold"
+	"tek-bank/cmd/api/handler/v1/interest"
+	"tek-bank/cmd/api/handler/v1/limit"
+	"tek-bank/cmd/api/handler/v1/paymentrequest"
 	"tek-bank/cmd/api/handler/v1/profile"
+	"tek-bank/cmd/api/handler/v1/scheduledtransfer"
+	"tek-bank/cmd/api/handler/v1/statement"
+	"tek-bank/cmd/api/handler/v1/transfer"
 	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/cmd/api/middleware/idempotency"
 	"tek-bank/cmd/api/middleware/transaction"
 	"tek-bank/internal/db/repository"
 	"tek-bank/internal/service"
-	"tek-bank/pkg/converter"
 	"tek-bank/pkg/crypto"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/sms"
 )
 
 // HealthCheck godoc
@@ -41,24 +56,67 @@ func InitializeRouters(app *fiber.App, connection *gorm.DB, redis *redis.Client)
 
 	authentication := authware.New(authorizationConfig)
 
+	idempotent := idempotency.New(idempotency.Config{
+		Repository: repository.NewIdempotencyRepository(redis),
+	})
+
 	// Packages
-	pkgConverter := converter.NewConverter()
 	pkgCrypto := crypto.NewCrypto()
+	pkgMailer := gomailer.NewMailer()
+	pkgSMS := sms.NewSender()
 
 	// Repositories
 	userRepository := repository.NewUserRepository(connection)
-	accountRepository := repository.NewAccountRepository(connection, redis)
+	accountRepository := repository.NewAccountRepository(connection)
 	transferHistoryRepository := repository.NewTransferHistoryRepository(connection)
+	ledgerRepository := repository.NewLedgerRepository(connection)
+	exchangeRateRepository := repository.NewExchangeRateRepository(connection)
+	transferRepository := repository.NewTransferRepository(connection)
+	transferLimitRepository := repository.NewTransferLimitRepository(connection)
+	feeScheduleRepository := repository.NewFeeScheduleRepository(connection)
+	scheduledTransferRepository := repository.NewScheduledTransferRepository(connection)
+	accountEventRepository := repository.NewAccountEventRepository(connection)
+	holdRepository := repository.NewHoldRepository(connection)
+	interestRepository := repository.NewInterestRepository(connection)
+	bulkTransferRepository := repository.NewBulkTransferRepository(connection)
+	beneficiaryRepository := repository.NewBeneficiaryRepository(connection)
+	beneficiaryEventRepository := repository.NewBeneficiaryEventRepository(connection)
+	paymentRequestRepository := repository.NewPaymentRequestRepository(connection)
+	notificationRepository := repository.NewNotificationRepository(connection)
 
 	// Services
 	authService := service.NewAuthService(userRepository, pkgCrypto)
-	accountService := service.NewAccountService(accountRepository, userRepository, transferHistoryRepository, pkgCrypto, pkgConverter)
-	profileService := service.NewProfileService(accountRepository, transferHistoryRepository, userRepository)
+	accountService := service.NewAccountService(accountRepository, userRepository, transferHistoryRepository, ledgerRepository, exchangeRateRepository, transferRepository, transferLimitRepository, feeScheduleRepository, accountEventRepository, holdRepository, interestRepository, beneficiaryRepository, notificationRepository, pkgCrypto, pkgMailer, pkgSMS)
+	bulkTransferService := service.NewBulkTransferService(bulkTransferRepository, accountRepository, userRepository, transferRepository, transferLimitRepository, holdRepository, notificationRepository, accountService, pkgSMS)
+	profileService := service.NewProfileService(accountRepository, transferHistoryRepository, userRepository, ledgerRepository, holdRepository, interestRepository)
+	exchangeService := service.NewExchangeService(exchangeRateRepository)
+	transferService := service.NewTransferService(transferRepository, holdRepository)
+	scheduledTransferService := service.NewScheduledTransferService(scheduledTransferRepository, accountRepository, accountService)
+	limitService := service.NewLimitService(transferLimitRepository, transferRepository, accountRepository)
+	feeService := service.NewFeeService(feeScheduleRepository, userRepository, ledgerRepository)
+	balanceService := service.NewBalanceService(accountRepository, ledgerRepository)
+	statementService := service.NewStatementService(accountRepository, transferHistoryRepository, ledgerRepository, pkgMailer)
+	holdService := service.NewHoldService(holdRepository, accountRepository)
+	interestService := service.NewInterestService(interestRepository)
+	paymentRequestService := service.NewPaymentRequestService(paymentRequestRepository, accountRepository, userRepository, notificationRepository, accountService)
+	beneficiaryService := service.NewBeneficiaryService(beneficiaryRepository, beneficiaryEventRepository, accountRepository, userRepository, accountService, pkgSMS)
 
 	// Handlers
 	authHandler := auth.NewAuthHandler(authService)
 	accountHandler := account.NewAccountHandler(accountService)
 	profileHandler := profile.NewProfileHandler(profileService)
+	exchangeHandler := exchange.NewExchangeHandler(exchangeService)
+	transferHandler := transfer.NewTransferHandler(transferService)
+	scheduledTransferHandler := scheduledtransfer.NewScheduledTransferHandler(scheduledTransferService)
+	limitHandler := limit.NewLimitHandler(limitService)
+	feeHandler := fee.NewFeeHandler(feeService)
+	statementHandler := statement.NewStatementHandler(statementService)
+	balanceHandler := balance.NewBalanceHandler(balanceService)
+	holdHandler := hold.NewHoldHandler(holdService)
+	interestHandler := interest.NewInterestHandler(interestService)
+	bulkTransferHandler := bulktransfer.NewBulkTransferHandler(bulkTransferService)
+	paymentRequestHandler := paymentrequest.NewPaymentRequestHandler(paymentRequestService)
+	beneficiaryHandler := beneficiary.NewBeneficiaryHandler(beneficiaryService)
 
 	// Initialize the routes for the application here
 	v1 := app.Group("/v1")
@@ -77,15 +135,91 @@ func InitializeRouters(app *fiber.App, connection *gorm.DB, redis *redis.Client)
 
 	// Account routes
 	accountRouter := v1.Group("/account")
-	accountRouter.Post("/register", transaction.Tx(connection), accountHandler.RegisterAccount)
-	accountRouter.Post("/create", transaction.Tx(connection), accountHandler.CreateNewAccount)
-	accountRouter.Put("/add-money/:accountNumber", authentication, transaction.Tx(connection), accountHandler.AddMoney)
-	accountRouter.Post("/transfer", authentication, transaction.Tx(connection), accountHandler.TransferMoney)
+	accountRouter.Post("/register", idempotent, transaction.Tx(connection), accountHandler.RegisterAccount)
+	accountRouter.Post("/create", idempotent, transaction.Tx(connection), accountHandler.CreateNewAccount)
+	accountRouter.Put("/add-money/:accountNumber", authentication, idempotent, transaction.Tx(connection), accountHandler.AddMoney)
+	accountRouter.Put("/withdraw/:accountNumber", authentication, idempotent, transaction.Tx(connection), accountHandler.Withdraw)
+	accountRouter.Post("/transfer", authentication, idempotent, transaction.Tx(connection), accountHandler.TransferMoney)
 	accountRouter.Get("/transfer-approval", transaction.Tx(connection), accountHandler.TransferApproval)
+	accountRouter.Get("/bulk-transfer-approval", transaction.Tx(connection), bulkTransferHandler.Approval)
+	accountRouter.Post("/close/:accountNumber", authentication, idempotent, transaction.Tx(connection), accountHandler.CloseAccount)
+	accountRouter.Get("/limits/:accountNumber", authentication, limitHandler.GetRemaining)
+	accountRouter.Get("/balance/:accountNumber", authentication, balanceHandler.BalanceAt)
+	accountRouter.Get("/statements/:accountNumber", authentication, statementHandler.Download)
+	accountRouter.Post("/statements/:accountNumber/email", authentication, statementHandler.Email)
+	accountRouter.Get("/holds/:accountNumber", authentication, holdHandler.List)
+
+	// Transfer routes
+	transferRouter := accountRouter.Group("/transfers", authentication)
+	transferRouter.Get("/pending", transferHandler.ListPending)
+	transferRouter.Post("/:id/approve", transaction.Tx(connection), accountHandler.ApproveTransfer)
+	transferRouter.Post("/:id/cancel", transaction.Tx(connection), transferHandler.Cancel)
+
+	// Bulk transfer routes
+	bulkTransferRouter := accountRouter.Group("/bulk-transfers", authentication)
+	bulkTransferRouter.Post("/", idempotent, transaction.Tx(connection), bulkTransferHandler.Create)
+	bulkTransferRouter.Get("/", bulkTransferHandler.List)
+	bulkTransferRouter.Get("/:id", bulkTransferHandler.Get)
+	bulkTransferRouter.Post("/:id/approve", transaction.Tx(connection), bulkTransferHandler.Approve)
+	bulkTransferRouter.Post("/:id/cancel", transaction.Tx(connection), bulkTransferHandler.Cancel)
+
+	// Payment request routes
+	paymentRequestRouter := accountRouter.Group("/payment-requests", authentication)
+	paymentRequestRouter.Post("/", idempotent, transaction.Tx(connection), paymentRequestHandler.Create)
+	paymentRequestRouter.Get("/", paymentRequestHandler.ListSent)
+	paymentRequestRouter.Get("/inbox", paymentRequestHandler.Inbox)
+	paymentRequestRouter.Get("/:id", paymentRequestHandler.Get)
+	paymentRequestRouter.Post("/:id/accept", idempotent, transaction.Tx(connection), paymentRequestHandler.Accept)
+	paymentRequestRouter.Post("/:id/decline", transaction.Tx(connection), paymentRequestHandler.Decline)
+	paymentRequestRouter.Post("/:id/cancel", transaction.Tx(connection), paymentRequestHandler.Cancel)
+
+	// Scheduled transfer routes
+	scheduledTransferRouter := accountRouter.Group("/scheduled-transfers", authentication)
+	scheduledTransferRouter.Post("/", idempotent, transaction.Tx(connection), scheduledTransferHandler.Create)
+	scheduledTransferRouter.Get("/", scheduledTransferHandler.List)
+	scheduledTransferRouter.Get("/:id", scheduledTransferHandler.Get)
+	scheduledTransferRouter.Put("/:id", transaction.Tx(connection), scheduledTransferHandler.Update)
+	scheduledTransferRouter.Delete("/:id", transaction.Tx(connection), scheduledTransferHandler.Cancel)
 
 	// Profile routes
 	profileRouter := v1.Group("/profile")
 	profileRouter.Get("/", authentication, profileHandler.MyProfile)
 	profileRouter.Get("/transfer-history", authentication, profileHandler.MyTransferHistory)
+	profileRouter.Put("/approval-method", authentication, profileHandler.UpdateApprovalMethod)
+	profileRouter.Get("/beneficiaries", authentication, beneficiaryHandler.List)
+	profileRouter.Post("/beneficiaries", authentication, idempotent, transaction.Tx(connection), beneficiaryHandler.Create)
+	profileRouter.Get("/beneficiaries/:id", authentication, beneficiaryHandler.Get)
+	profileRouter.Put("/beneficiaries/:id", authentication, transaction.Tx(connection), beneficiaryHandler.Update)
+	profileRouter.Delete("/beneficiaries/:id", authentication, transaction.Tx(connection), beneficiaryHandler.Delete)
+	profileRouter.Post("/beneficiaries/:id/trust/approve", authentication, transaction.Tx(connection), beneficiaryHandler.ApproveTrust)
+	profileRouter.Get("/beneficiary-trust-approval", transaction.Tx(connection), beneficiaryHandler.TrustApproval)
+
+	// Exchange rate routes
+	exchangeRouter := v1.Group("/exchange-rates")
+	exchangeRouter.Get("/", exchangeHandler.GetRates)
+
+	// Admin routes
+	adminRouter := v1.Group("/admin", authentication, authware.RequireRole(enum.RoleAdmin))
+	adminRouter.Put("/exchange-rates", transaction.Tx(connection), exchangeHandler.UpdateRates)
+	adminRouter.Get("/transfer-limits", limitHandler.GetLimits)
+	adminRouter.Put("/transfer-limits", transaction.Tx(connection), limitHandler.UpdateLimits)
+	adminRouter.Delete("/transfer-limits/:id", transaction.Tx(connection), limitHandler.DeleteLimit)
+	adminRouter.Get("/fee-schedules", feeHandler.GetSchedules)
+	adminRouter.Put("/fee-schedules", transaction.Tx(connection), feeHandler.UpdateSchedule)
+	adminRouter.Delete("/fee-schedules/:id", transaction.Tx(connection), feeHandler.DeleteSchedule)
+	adminRouter.Put("/users/:id/segment", transaction.Tx(connection), feeHandler.UpdateSegment)
+	adminRouter.Get("/interest-rates", interestHandler.GetRates)
+	adminRouter.Put("/interest-rates", transaction.Tx(connection), interestHandler.UpdateRate)
+	adminRouter.Delete("/interest-rates/:id", transaction.Tx(connection), interestHandler.DeleteRate)
+	adminRouter.Get("/fee-revenue", feeHandler.GetRevenue)
+	adminRouter.Post("/transfer-history/:id/reverse", idempotent, transaction.Tx(connection), accountHandler.ReverseTransfer)
+	adminRouter.Get("/accounts/:accountNumber/events", accountHandler.GetAccountEvents)
+	adminRouter.Put("/accounts/:accountNumber/freeze", transaction.Tx(connection), accountHandler.FreezeAccount)
+	adminRouter.Post("/accounts/:accountNumber/unfreeze", transaction.Tx(connection), accountHandler.UnfreezeAccount)
+	adminRouter.Post("/accounts/:accountNumber/close", idempotent, transaction.Tx(connection), accountHandler.CloseAccount)
+	adminRouter.Put("/accounts/:accountNumber/overdraft", transaction.Tx(connection), accountHandler.SetOverdraft)
+	adminRouter.Get("/accounts/:accountNumber/holds", holdHandler.List)
+	adminRouter.Post("/accounts/:accountNumber/holds", idempotent, transaction.Tx(connection), holdHandler.Place)
+	adminRouter.Post("/holds/:id/release", transaction.Tx(connection), holdHandler.Release)
 
 }
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"os"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/service"
+	"tek-bank/internal/worker"
+	"tek-bank/pkg/crypto"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/sms"
+	"time"
+)
+
+// defaultWorkerInterval is how often the workers run if their interval is not configured
+const defaultWorkerInterval = time.Minute
+
+// workerInterval reads the interval of a worker from the environment variable, e.g. "30s"
+func workerInterval(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultWorkerInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Errorf("Invalid %s, the default is used", key)
+		return defaultWorkerInterval
+	}
+
+	return interval
+}
+
+// InitializeWorkers creates the background workers of the application, they have to be started by the caller
+func InitializeWorkers(connection *gorm.DB) []*worker.Worker {
+	// Packages
+	pkgCrypto := crypto.NewCrypto()
+	pkgMailer := gomailer.NewMailer()
+	pkgSMS := sms.NewSender()
+
+	// Repositories
+	userRepository := repository.NewUserRepository(connection)
+	accountRepository := repository.NewAccountRepository(connection)
+	transferHistoryRepository := repository.NewTransferHistoryRepository(connection)
+	ledgerRepository := repository.NewLedgerRepository(connection)
+	exchangeRateRepository := repository.NewExchangeRateRepository(connection)
+	transferRepository := repository.NewTransferRepository(connection)
+	transferLimitRepository := repository.NewTransferLimitRepository(connection)
+	feeScheduleRepository := repository.NewFeeScheduleRepository(connection)
+	scheduledTransferRepository := repository.NewScheduledTransferRepository(connection)
+	accountEventRepository := repository.NewAccountEventRepository(connection)
+	holdRepository := repository.NewHoldRepository(connection)
+	interestRepository := repository.NewInterestRepository(connection)
+	bulkTransferRepository := repository.NewBulkTransferRepository(connection)
+	beneficiaryRepository := repository.NewBeneficiaryRepository(connection)
+	paymentRequestRepository := repository.NewPaymentRequestRepository(connection)
+	notificationRepository := repository.NewNotificationRepository(connection)
+
+	// Services
+	accountService := service.NewAccountService(accountRepository, userRepository, transferHistoryRepository, ledgerRepository, exchangeRateRepository, transferRepository, transferLimitRepository, feeScheduleRepository, accountEventRepository, holdRepository, interestRepository, beneficiaryRepository, notificationRepository, pkgCrypto, pkgMailer, pkgSMS)
+	bulkTransferService := service.NewBulkTransferService(bulkTransferRepository, accountRepository, userRepository, transferRepository, transferLimitRepository, holdRepository, notificationRepository, accountService, pkgSMS)
+	transferService := service.NewTransferService(transferRepository, holdRepository)
+	scheduledTransferService := service.NewScheduledTransferService(scheduledTransferRepository, accountRepository, accountService)
+	balanceService := service.NewBalanceService(accountRepository, ledgerRepository)
+	interestService := service.NewInterestService(interestRepository)
+	paymentRequestService := service.NewPaymentRequestService(paymentRequestRepository, accountRepository, userRepository, notificationRepository, accountService)
+	notificationService := service.NewNotificationService(notificationRepository, pkgMailer)
+
+	return []*worker.Worker{
+		worker.NewScheduledTransferWorker(connection, scheduledTransferService, workerInterval("SCHEDULED_TRANSFER_INTERVAL")),
+		worker.NewTransferExpiryWorker(connection, transferService, workerInterval("TRANSFER_EXPIRY_INTERVAL")),
+		worker.NewBalanceSnapshotWorker(balanceService, workerInterval("BALANCE_SNAPSHOT_INTERVAL")),
+		worker.NewInterestWorker(connection, interestService, accountService, workerInterval("INTEREST_INTERVAL")),
+		worker.NewBulkTransferWorker(connection, bulkTransferService, workerInterval("BULK_TRANSFER_INTERVAL")),
+		worker.NewPaymentRequestExpiryWorker(connection, paymentRequestService, workerInterval("PAYMENT_REQUEST_EXPIRY_INTERVAL")),
+		worker.NewPaymentRequestSettlementWorker(connection, paymentRequestService, workerInterval("PAYMENT_REQUEST_SETTLEMENT_INTERVAL")),
+		worker.NewNotificationWorker(connection, notificationService, workerInterval("NOTIFICATION_INTERVAL")),
+	}
+}
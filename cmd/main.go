@@ -20,7 +20,10 @@ import (
 	"tek-bank/cmd/config"
 	"tek-bank/docs"
 	"tek-bank/internal/db/connection"
+	"tek-bank/internal/db/repository"
 	"tek-bank/internal/i18n"
+	"tek-bank/internal/service"
+	"tek-bank/internal/worker"
 	"time"
 )
 
@@ -75,6 +78,14 @@ func init() {
 
 	//Init i18n
 	i18n.InitBundle("./internal/i18n/languages/")
+
+	// Load the exchange rates of the bank, so transfers between currencies work without a rate provider
+	if path := os.Getenv("EXCHANGE_RATES_FILE"); path != "" {
+		exchangeService := service.NewExchangeService(repository.NewExchangeRateRepository(conn))
+		if err := exchangeService.LoadFromFile(path); err != nil {
+			log.Error("Exchange rates could not be loaded", err)
+		}
+	}
 }
 
 // @title Teknasyon Case Study API
@@ -100,6 +111,12 @@ func main() {
 	// Initialize routes
 	api.InitializeRouters(app, conn, redisConn)
 
+	// Start the background workers
+	workers := api.InitializeWorkers(conn)
+	for _, w := range workers {
+		w.Start()
+	}
+
 	// Start listening on port 8000
 	go func() {
 		if err := app.Listen(":" + serverConf.Port); err != nil {
@@ -108,13 +125,13 @@ func main() {
 	}()
 
 	// Graceful shutdown
-	err := GracefulShutdown(app, 5*time.Second)
+	err := GracefulShutdown(app, workers, 5*time.Second)
 	if err != nil {
 		log.Error("Graceful shutdown error", err)
 	}
 }
 
-func GracefulShutdown(app *fiber.App, timeout time.Duration) error {
+func GracefulShutdown(app *fiber.App, workers []*worker.Worker, timeout time.Duration) error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, os.Kill)
 
@@ -123,6 +140,13 @@ func GracefulShutdown(app *fiber.App, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	// The running jobs are finished before the database is closed
+	for _, w := range workers {
+		if err := w.Stop(ctx); err != nil {
+			return err
+		}
+	}
+
 	db, err := conn.DB()
 	if err != nil {
 		return err
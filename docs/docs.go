@@ -56,6 +56,12 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Add Money Request",
                         "name": "addMoneyRequest",
@@ -76,9 +82,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/account/create": {
-            "post": {
-                "description": "Create a new account for the registered user, the user must be registered before creating an account.\nIf you want to create an account for a user who has not registered yet, you should use the register endpoint.",
+        "/account/balance/{accountNumber}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the balance the account had at the given moment, calculated from the postings of the ledger.\nA day (YYYY-MM-DD) returns the balance at the end of the day, an RFC 3339 time the balance at that moment. The current balance is returned without a time.",
                 "consumes": [
                     "application/json"
                 ],
@@ -88,32 +99,42 @@ const docTemplate = `{
                 "tags": [
                     "Account"
                 ],
-                "summary": "Create a new account for the registered user",
+                "summary": "Get the balance of an account at a moment",
                 "parameters": [
                     {
-                        "description": "Create Account Request",
-                        "name": "createAccountRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateNewAccountRequest"
-                        }
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day (YYYY-MM-DD) or time (RFC 3339)",
+                        "name": "at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/dto.GetBalanceResponse"
                         }
                     }
                 }
             }
         },
-        "/account/register": {
-            "post": {
-                "description": "It should be used for users who will create an account for the first time, because when creating a user account, one user must also be created.\nThe user password will be sent via e-mail.",
+        "/account/bulk-transfer-approval": {
+            "get": {
+                "description": "Approve all transfers of a bulk transfer by providing the token of the approval link.\nA bulk transfer can only be approved once and before it expires.",
                 "consumes": [
                     "application/json"
                 ],
@@ -121,18 +142,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Bulk Transfer"
                 ],
-                "summary": "Register a new account and user",
+                "summary": "Approve the bulk transfer",
                 "parameters": [
                     {
-                        "description": "Register Request",
-                        "name": "registerRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterAccountRequest"
-                        }
+                        "type": "string",
+                        "description": "Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -146,14 +165,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/account/transfer": {
-            "post": {
+        "/account/bulk-transfers": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Transfer money between accounts by providing the account numbers and the amount to be transferred.",
+                "description": "Lists the bulk transfers of the user without their rows, the newest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -161,9 +180,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Bulk Transfer"
                 ],
-                "summary": "Transfer money between accounts",
+                "summary": "List the bulk transfers",
                 "parameters": [
                     {
                         "type": "string",
@@ -171,64 +190,90 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "Transfer Money Request",
-                        "name": "transferMoneyRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.TransferMoneyRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BulkTransferItem"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/account/transfer-approval": {
-            "get": {
-                "description": "Approve the transfer by providing the transfer token.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Uploads a CSV file of transfers, like a payroll, from an account of the user. The header of the file names the columns:\nto_account_number or to_iban for the receiver, amount in the currency of the account and an optional note. A file has at most 1000 rows.\nEvery row is validated with the balance, the limits and the fees. If a row is invalid nothing is created and the preview with the errors of the rows is returned.\nOtherwise the money of the rows is held and the bulk transfer waits for a single approval with the approval method of the user.\nApproved rows are executed in the background, a row which fails does not stop the others and the report is sent by e-mail at the end.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Bulk Transfer"
                 ],
-                "summary": "Approve the transfer",
+                "summary": "Upload a bulk transfer file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token",
-                        "name": "token",
-                        "in": "query",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "from_account_number",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV File",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/dto.BulkTransferItem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkTransferItem"
                         }
                     }
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "You can login with your identity number or customer number. If you are a new user, you can register with the /account/register endpoint.\nIf you registered before, your password will be sent to your e-mail address.",
+        "/account/bulk-transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the bulk transfer with the status of every row, the rows which failed have the reason of the failure.",
                 "consumes": [
                     "application/json"
                 ],
@@ -236,38 +281,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Bulk Transfer"
                 ],
-                "summary": "Login a user",
+                "summary": "Get a bulk transfer",
                 "parameters": [
                     {
-                        "description": "Login Request",
-                        "name": "loginRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bulk Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginResponse"
+                            "$ref": "#/definitions/dto.BulkTransferItem"
                         }
                     }
                 }
             }
         },
-        "/auth/user-info": {
-            "get": {
+        "/account/bulk-transfers/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "You can use this endpoint to get user information.",
+                "description": "Approve all transfers of a bulk transfer with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.\nThe code is valid for 5 minutes. After 3 wrong codes the bulk transfer is rejected.",
                 "consumes": [
                     "application/json"
                 ],
@@ -275,9 +325,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Bulk Transfer"
                 ],
-                "summary": "Get user info",
+                "summary": "Approve the bulk transfer with a one-time code",
                 "parameters": [
                     {
                         "type": "string",
@@ -285,21 +335,43 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bulk Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approve Transfer Request",
+                        "name": "approveTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApproveTransferRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserInfoResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Health Check for the API",
+        "/account/bulk-transfers/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels a bulk transfer of the user which is waiting for approval, the money held for its rows is available again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -307,9 +379,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Health Check"
+                    "Bulk Transfer"
+                ],
+                "summary": "Cancel a bulk transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bulk Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Health Check API",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -321,14 +409,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile": {
-            "get": {
+        "/account/close/{accountNumber}": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "This endpoint is used to get the user's profile information. It returns the user's account information and user information.\nThe user's account information includes the account number, balance, and account type.\nThe user information includes the user's name, surname, and e-mail address.\nNOTE! The user's account information is returned as an array because the user can have more than one account.",
+                "description": "Closes an account of the current user, admins can close any customer account at /admin/accounts/{accountNumber}/close.\nThe balance has to be zero or is swept to another open account of the owner (sweep_to_account_number) with the current\nexchange rate and without a fee. A closed account can not send or receive money anymore, the owner is deactivated\nwhen the last account is closed. A reason is required, it is kept in the audit trail of the account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -336,9 +424,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Account"
                 ],
-                "summary": "Get user profile",
+                "summary": "Close an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -346,26 +434,89 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Close Account Request",
+                        "name": "closeAccountRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CloseAccountRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetProfileResponse"
+                            "$ref": "#/definitions/dto.AccountStatusResponse"
                         }
                     }
                 }
             }
         },
-        "/profile/transfer-history": {
+        "/account/create": {
+            "post": {
+                "description": "Create a new account for the registered user, the user must be registered before creating an account.\nIf you want to create an account for a user who has not registered yet, you should use the register endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Create a new account for the registered user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Account Request",
+                        "name": "createAccountRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateNewAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/holds/{accountNumber}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "You can see only your own transfer history with this endpoint.\nYou can filter the transfer history by account number.\nThe account number is required for this endpoint.",
+                "description": "Returns the holds of the account with its ledger balance, the held amount and the available balance.\nCustomers see the holds of their own accounts, admins of all customer accounts.",
                 "consumes": [
                     "application/json"
                 ],
@@ -373,9 +524,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Account"
                 ],
-                "summary": "Get user transfer history",
+                "summary": "List the holds of an account",
                 "parameters": [
                     {
                         "type": "string",
@@ -385,10 +536,10 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "Account Number",
                         "name": "accountNumber",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -396,149 +547,3949 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
+                            "$ref": "#/definitions/dto.GetHoldsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/limits/{accountNumber}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the per transfer, daily and monthly limits of the account with the amounts used and left in the current period.\nThe amounts are in the base currency of the bank. Customer limits count the transfers from all accounts of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get the remaining transfer limits of an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetRemainingLimitsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/payment-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the payment requests the user sent with their answers, the newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "List the sent payment requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PaymentRequestItem"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Asks another customer for money to an account of the user, the payer is given by an account number or by a customer number.\nA request sent to an account has to be paid from that account. The payer is notified by e-mail and can answer for 7 days.\nA user can send 20 requests a day, the payer is shown to the requester once the payer answers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "Request money from another customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Payment Request Request",
+                        "name": "createPaymentRequestRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentRequestItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/payment-requests/inbox": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the payment requests waiting for an answer of the user, the newest first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "List the received payment requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.GetTransferHistoryResponse"
+                                "$ref": "#/definitions/dto.PaymentRequestItem"
                             }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AccountItem": {
+        },
+        "/account/payment-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a payment request the user sent or received.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "Get a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentRequestItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/payment-requests/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pays a received payment request with a transfer to the requester from an account of the user in the currency of the request.\nThe transfer waits for the approval of the user like any other transfer, the request is paying until then.\nThe requester is notified by e-mail when the transfer is executed, the request is pending again when the transfer fails.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "Accept a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Accept Payment Request Request",
+                        "name": "acceptPaymentRequestRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptPaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentRequestItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/payment-requests/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Withdraws a payment request of the user which is not answered yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "Cancel a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/payment-requests/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Turns down a received payment request, the requester is notified by e-mail with the optional reason.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment Request"
+                ],
+                "summary": "Decline a payment request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decline Payment Request Request",
+                        "name": "declinePaymentRequestRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeclinePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/register": {
+            "post": {
+                "description": "It should be used for users who will create an account for the first time, because when creating a user account, one user must also be created.\nThe user password will be sent via e-mail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Register a new account and user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Register Request",
+                        "name": "registerRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/scheduled-transfers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the scheduled transfers of the user including the completed, cancelled and failed ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled Transfer"
+                ],
+                "summary": "List the scheduled transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ScheduledTransferItem"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Schedules a future dated (once) or a recurring (daily, weekly, monthly) transfer from an account of the user.\nDates are given as YYYY-MM-DD, monthly transfers run on the day of the start date or on the last day of shorter months.\nTransfers falling on a weekend run on the next business day (shift) or are left out (skip).\nA transfer failing for the balance is tried again every hour, up to 3 times per occurrence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled Transfer"
+                ],
+                "summary": "Schedule a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Scheduled Transfer Request",
+                        "name": "createScheduledTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduledTransferItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/scheduled-transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the scheduled transfer with its next run and the result of its last run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled Transfer"
+                ],
+                "summary": "Get a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduledTransferItem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the amount, the note or the end date of an active scheduled transfer, the fields which are not given stay the same.\nAn empty end date removes the end date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled Transfer"
+                ],
+                "summary": "Update a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Scheduled Transfer Request",
+                        "name": "updateScheduledTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ScheduledTransferItem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels an active scheduled transfer, the transfers which already ran are not affected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled Transfer"
+                ],
+                "summary": "Cancel a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/statements/{accountNumber}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the statement of an account of the user as a CSV or PDF file with the opening balance, every posting with the running balance and the closing balance.\nThe dates are inclusive and can be at most a year apart, the last month is returned without them. The labels are in the language of the request.",
+                "produces": [
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Download an account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "default": "pdf",
+                        "description": "Format of the file",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/statements/{accountNumber}/email": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends the statement of an account of the user as a CSV or PDF attachment to the e-mail address of the user.\nThe dates are inclusive and can be at most a year apart, the last month is sent without them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Send an account statement via e-mail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Statement Request",
+                        "name": "statementRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Transfer money between accounts by providing the account numbers and the amount to be transferred.\nThe receiver can be given with its account number or its IBAN (to_iban), the IBAN must belong to an account of the bank.\nThe transfer waits for the approval of the sender, the approval link is sent via e-mail and expires after one hour.\nA saved beneficiary (beneficiary_id) can be given instead of the receiver. Transfers to trusted beneficiaries up to the daily limit of the bank are executed at once without an approval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Transfer money between accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transfer Money Request",
+                        "name": "transferMoneyRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferMoneyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransferItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/transfer-approval": {
+            "get": {
+                "description": "Approve the transfer by providing the transfer token.\nA transfer can only be approved once and before it expires. A transfer whose accounts can not make it anymore is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Approve the transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/transfers/pending": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the transfers of the user which are waiting for approval and have not expired yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "List the pending transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.TransferItem"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/account/transfers/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approve a transfer of the user with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.\nThe code is valid for 5 minutes. After 3 wrong codes the transfer is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Approve the transfer with a one-time code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approve Transfer Request",
+                        "name": "approveTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApproveTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/transfers/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels a transfer of the user which is waiting for approval, its approval link can not be used anymore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Cancel a pending transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/account/withdraw/{accountNumber}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Withdraw money from an account of the current user by providing the account number and the amount.\nYou can imagine this as withdrawing money from an ATM. The daily withdrawal limit of the account can not be exceeded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Withdraw money from the account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Withdraw Request",
+                        "name": "withdrawRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WithdrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WithdrawResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/close": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Closes an account of the current user, admins can close any customer account at /admin/accounts/{accountNumber}/close.\nThe balance has to be zero or is swept to another open account of the owner (sweep_to_account_number) with the current\nexchange rate and without a fee. A closed account can not send or receive money anymore, the owner is deactivated\nwhen the last account is closed. A reason is required, it is kept in the audit trail of the account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Close an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Close Account Request",
+                        "name": "closeAccountRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CloseAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AccountStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the status of a customer account and its freezes, unfreezes and closure with their reasons, the oldest first.\nOnly admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the audit trail of an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAccountEventsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/freeze": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Blocks the debits (money going out), the credits (money coming in) or both of a customer account. Pending transfers\nof a frozen account are rejected when they are approved. A frozen account can be frozen again to change what is blocked.\nA reason is required, it is kept in the audit trail of the account. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Freeze an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Freeze Account Request",
+                        "name": "freezeAccountRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FreezeAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AccountStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/holds": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the holds of the account with its ledger balance, the held amount and the available balance.\nCustomers see the holds of their own accounts, admins of all customer accounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "List the holds of an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetHoldsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Holds an amount of a customer account, like a legal block. The held amount can not be withdrawn or transferred,\nthe ledger balance does not change. The hold may be more than the balance, the money coming in later is held then.\nThe type is legal or manual, a reason is required. Without an expiry the hold stays until it is released. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Place a hold on an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Place Hold Request",
+                        "name": "placeHoldRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaceHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HoldItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/overdraft": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the overdraft limit and the yearly overdraft rate of a current account, a zero limit removes the overdraft.\nThe balance may go below zero down to the negative limit, the negative balance is charged interest every day.\nA limit below the used overdraft does not take money back, the owner is notified to pay the difference in.\nOnly admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set the overdraft of an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set Overdraft Request",
+                        "name": "setOverdraftRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetOverdraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OverdraftResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounts/{accountNumber}/unfreeze": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lets the money in and out of a frozen account again. A reason is required, it is kept in the audit trail of the account.\nOnly admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unfreeze an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Unfreeze Account Request",
+                        "name": "unfreezeAccountRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnfreezeAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AccountStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exchange-rates": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates or overwrites the rates of the given currencies. Only admins can use this endpoint.\nThe rate of a currency is the value of one unit of the currency in the base currency of the bank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update the exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Exchange Rates Request",
+                        "name": "updateExchangeRatesRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateExchangeRatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fee-revenue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the fees collected in the fee income accounts of the bank per day and currency.\nThe dates are inclusive and can be at most a year apart, the last 30 days are reported without them.\nOnly admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the fee revenue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetFeeRevenueResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fee-schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the fee schedules of the customer segments and account types. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the fee schedules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetFeeSchedulesResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates the schedule or overwrites the schedule of the same segment and account type. Only admins can use this endpoint.\nAn empty segment or account type matches all of them, the most specific schedule is used for a transfer.\nFlat schedules charge the flat fee, percentage schedules the rate of the amount and tiered schedules the flat fee\nand the rate of the first tier reaching the amount. The last tier has no upper bound (up_to 0).\nThe fee is kept between the min and the max fee, all amounts are in the base currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update a fee schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fee Schedule",
+                        "name": "feeScheduleItem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FeeScheduleItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FeeScheduleItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fee-schedules/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a schedule, its transfers are charged with a less specific schedule. Only admins can use this endpoint.\nThe default schedule matching all segments and account types can not be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a fee schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fee Schedule Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/holds/{id}/release": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ends an active hold with a reason, the held money is available again. Holds of pending transfers are released\nthe same way, the transfer checks the balance again when it is approved. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Release a hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hold Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Release Hold Request",
+                        "name": "releaseHoldRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReleaseHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HoldItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/interest-rates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the yearly interest rates of the account types and currencies. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the interest rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetInterestRatesResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates the rate or overwrites the rate of the same account type and currency. Only admins can use this endpoint.\nThe rate is yearly (0.35 is 35%), every day with a positive balance at its end earns 1/365 of it.\nThe interest is accrued daily and paid to the account at the start of the next month, the new rate is used from the day it is set on, the earlier days keep their rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update an interest rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Interest Rate",
+                        "name": "interestRateItem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InterestRateItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InterestRateItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/interest-rates/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a rate, the accounts of its type and currency do not earn interest anymore. The interest accrued\nuntil then is still paid. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete an interest rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interest Rate Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transfer-history/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gives back the whole or a part of an executed transfer to the sender by its transfer history entry, the amount is\nin the currency of the sender and the whole remaining amount is reversed without it. The fee is refunded on request.\nA reason is required. The reversal fails if the receiver does not have the money without its overdraft and holds, unless it is forced and the policy\nof the bank allows forcing (REVERSAL_ALLOW_FORCE). Only admins can use this endpoint.\nA reference like a ticket number is required, a transfer is reversed once with a reference, so retries do not post twice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reverse a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer History Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Reverse Transfer Request",
+                        "name": "reverseTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReverseTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReverseTransferResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transfer-limits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the global, customer and account transfer limits. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTransferLimitsResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates or overwrites the limits of the given levels, scopes and periods. Only admins can use this endpoint.\nThe level is global, customer or account and the period is transaction, daily or monthly. The scope id is\nempty for global limits, the user id for customer limits and the account number for account limits.\nA customer limit replaces the global limit of the same period, account limits apply in addition.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update the transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Transfer Limits Request",
+                        "name": "updateTransferLimitsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTransferLimitsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transfer-limits/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a limit, customers without their own limit fall back to the global limit. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a transfer limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer Limit Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/segment": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves the customer to the retail, premium or business segment, the next transfers are charged with the\nfee schedules of the segment. Only admins can use this endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update the segment of a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Segment Request",
+                        "name": "updateSegmentRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateSegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "You can login with your identity number or customer number. If you are a new user, you can register with the /account/register endpoint.\nIf you registered before, your password will be sent to your e-mail address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login a user",
+                "parameters": [
+                    {
+                        "description": "Login Request",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/user-info": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "You can use this endpoint to get user information.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get user info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserInfoResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "Every rate is the value of one unit of the currency in the base currency of the bank.\nTransfers between accounts of different currencies are converted with these rates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange"
+                ],
+                "summary": "Get the exchange rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetExchangeRatesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Health Check for the API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health Check"
+                ],
+                "summary": "Health Check API",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "This endpoint is used to get the user's profile information. It returns the user's account information and user information.\nThe user's account information includes the account number, balance, and account type.\nThe user information includes the user's name, surname, and e-mail address.\nNOTE! The user's account information is returned as an array because the user can have more than one account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get user profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetProfileResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/approval-method": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Chooses how the transfers of the user are approved. email_link sends an approval link via e-mail,\nemail_code and sms_code send a one-time code which is confirmed with the approve endpoint of the transfer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update the approval method of transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Approval Method Request",
+                        "name": "updateApprovalMethodRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateApprovalMethodRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/beneficiaries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the saved beneficiaries of the user ordered by their nicknames.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "List the beneficiaries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BeneficiaryItem"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saves a receiver under a nickname, the receiver is given by an account number or by an IBAN.\nThe optional holder name has to match the holder of the account. Transfers to trusted beneficiaries up to the daily limit of the bank are executed without an approval.\nA trusted beneficiary is saved untrusted, the trust is given when the user approves it like a transfer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Save a beneficiary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request, retries with the same key return the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create Beneficiary Request",
+                        "name": "createBeneficiaryRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateBeneficiaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BeneficiaryItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/beneficiaries/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a saved beneficiary of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Get a beneficiary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Beneficiary Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BeneficiaryItem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the nickname or the trust of a saved beneficiary, the account can not be changed.\nTrust is given when the user approves it like a transfer, it is taken back at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Update a beneficiary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Beneficiary Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Beneficiary Request",
+                        "name": "updateBeneficiaryRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBeneficiaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BeneficiaryItem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a saved beneficiary, the transfers made to it stay in the history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Delete a beneficiary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Beneficiary Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/beneficiaries/{id}/trust/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Trusts a beneficiary with the one-time code sent via e-mail or SMS, it is used when the approval method of the user is email_code or sms_code.\nThe code is valid for 5 minutes. After 3 wrong codes the beneficiary is not trusted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Approve the trust of a beneficiary with a one-time code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Beneficiary Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approve Transfer Request",
+                        "name": "approveTransferRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApproveTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/beneficiary-trust-approval": {
+            "get": {
+                "description": "Trusts a beneficiary by providing the token of the approval link. The trust can only be approved once and before it expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Beneficiary"
+                ],
+                "summary": "Approve the trust of a beneficiary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/transfer-history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "You can see only your own transfer history with this endpoint.\nThe account number is required for this endpoint. The entries are sorted by their creation time, newest first.\nA page has 20 entries by default and at most 100. The next page is returned with the next_cursor of the page as the cursor and the same filters.\nThe dates are inclusive, the amounts are compared in the currency of the account and the note is searched case-insensitively.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get user transfer history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account Number",
+                        "name": "accountNumber",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries of the page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "in",
+                            "out"
+                        ],
+                        "type": "string",
+                        "description": "Direction",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text in the note",
+                        "name": "note",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTransferHistoryPageResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AcceptPaymentRequestRequest": {
+            "type": "object",
+            "properties": {
+                "from_account_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AccountEventItem": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "freeze",
+                        "unfreeze",
+                        "close"
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "credits_frozen": {
+                    "type": "boolean"
+                },
+                "debits_frozen": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "sweep_account_number": {
+                    "type": "integer"
+                },
+                "swept_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AccountItem": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "accrued_interest": {
+                    "description": "AccruedInterest is the interest earned but not paid yet, it is paid at the start of the next month",
+                    "type": "number"
+                },
+                "available_balance": {
+                    "type": "number"
+                },
+                "balance": {
+                    "description": "Balance is the ledger balance, the available balance adds the unused overdraft and is without the held amount",
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "held_amount": {
+                    "type": "number"
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "overdraft_limit": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "closed"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "current",
+                        "savings",
+                        "time_deposit"
+                    ]
+                }
+            }
+        },
+        "dto.AccountStatusResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "credits_frozen": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "debits_frozen": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "dto.AddMoneyRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AddMoneyResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ApproveTransferRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "dto.BeneficiaryItem": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "holder_name": {
+                    "type": "string"
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "trust_pending": {
+                    "type": "boolean"
+                },
+                "trusted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BulkTransferItem": {
+            "type": "object",
+            "properties": {
+                "approval_method": {
+                    "type": "string"
+                },
+                "approved_at": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "executed_count": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "from_account_number": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "Rows are returned with the preview of a new bulk transfer and with the report of a single bulk transfer",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BulkTransferRow"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending_approval",
+                        "processing",
+                        "completed",
+                        "rejected",
+                        "expired",
+                        "cancelled"
+                    ]
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "total_fee": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.BulkTransferRow": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "converted_amount": {
+                    "type": "number"
+                },
+                "converted_currency": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending_approval",
+                        "approved",
+                        "executed",
+                        "rejected",
+                        "expired",
+                        "cancelled"
+                    ]
+                },
+                "to_account_number": {
+                    "type": "integer"
+                },
+                "to_iban": {
+                    "type": "string"
+                },
+                "transaction_fee": {
+                    "type": "number"
+                },
+                "transfer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CloseAccountRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "No longer needed"
+                },
+                "sweep_to_account_number": {
+                    "description": "The balance is moved to this account of the owner, it is required if the balance is not zero",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateBeneficiaryRequest": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "holder_name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "iban": {
+                    "type": "string",
+                    "example": "TR33 0006 1005 1978 6457 8413 26"
+                },
+                "nickname": {
+                    "type": "string",
+                    "example": "Mom"
+                },
+                "trusted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateNewAccountRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "iso_country_code": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "current",
+                        "savings",
+                        "time_deposit"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePaymentRequestRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Dinner"
+                },
+                "payer_account_number": {
+                    "type": "integer"
+                },
+                "payer_customer_number": {
+                    "type": "integer"
+                },
+                "to_account_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateScheduledTransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-06-30"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "once",
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ]
+                },
+                "from_account_number": {
+                    "type": "integer"
+                },
+                "non_business_day": {
+                    "type": "string",
+                    "enum": [
+                        "shift",
+                        "skip"
+                    ]
+                },
+                "note": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-07-01"
+                },
+                "to_account_number": {
+                    "type": "integer"
+                },
+                "to_iban": {
+                    "type": "string",
+                    "example": "TR33 0006 1005 1978 6457 8413 26"
+                }
+            }
+        },
+        "dto.DeclinePaymentRequestRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "I already paid"
+                }
+            }
+        },
+        "dto.ExchangeRateItem": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.FeeRevenueItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FeeRevenueTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FeeScheduleItem": {
+            "type": "object",
+            "properties": {
+                "account_type": {
+                    "type": "string",
+                    "example": "current"
+                },
+                "flat_fee": {
+                    "type": "number"
+                },
+                "free_own_accounts": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_fee": {
+                    "type": "number"
+                },
+                "min_fee": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Premium"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.001
+                },
+                "segment": {
+                    "type": "string",
+                    "example": "premium"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeeTierItem"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "flat",
+                        "percentage",
+                        "tiered"
+                    ]
+                }
+            }
+        },
+        "dto.FeeTierItem": {
+            "type": "object",
+            "properties": {
+                "flat_fee": {
+                    "type": "number"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "up_to": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.FreezeAccountRequest": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "type": "boolean"
+                },
+                "debits": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Suspicious activity"
+                }
+            }
+        },
+        "dto.GetAccountEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AccountEventItem"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/dto.AccountStatusResponse"
+                }
+            }
+        },
+        "dto.GetBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExchangeRateItem"
+                    }
+                }
+            }
+        },
+        "dto.GetFeeRevenueResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeeRevenueItem"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeeRevenueTotal"
+                    }
+                }
+            }
+        },
+        "dto.GetFeeSchedulesResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "schedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FeeScheduleItem"
+                    }
+                }
+            }
+        },
+        "dto.GetHoldsResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "available_balance": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "held_amount": {
+                    "type": "number"
+                },
+                "holds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HoldItem"
+                    }
+                }
+            }
+        },
+        "dto.GetInterestRatesResponse": {
+            "type": "object",
+            "properties": {
+                "days_in_year": {
+                    "type": "integer"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.InterestRateItem"
+                    }
+                }
+            }
+        },
+        "dto.GetProfileResponse": {
+            "type": "object",
+            "properties": {
+                "account_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AccountItem"
+                    }
+                },
+                "approval_method": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetRemainingLimitsResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "base_currency": {
+                    "type": "string"
+                },
+                "limits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RemainingLimitItem"
+                    }
+                }
+            }
+        },
+        "dto.GetTransferHistoryPageResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GetTransferHistoryResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor returns the next page when it is sent as the cursor, it is empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetTransferHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "description": "Balance of the account after the entry, entries from before the ledger have none",
+                    "type": "number"
+                },
+                "counterparty_account_number": {
+                    "type": "integer"
+                },
+                "counterparty_iban": {
+                    "type": "string"
+                },
+                "counterparty_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "in",
+                        "out"
+                    ]
+                },
+                "exchange_rate": {
+                    "type": "number"
+                },
+                "fee_rule": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetTransferLimitsResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "limits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransferLimitItem"
+                    }
+                }
+            }
+        },
+        "dto.HoldItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "release_reason": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "captured",
+                        "released",
+                        "expired"
+                    ]
+                },
+                "transfer_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "transfer",
+                        "legal",
+                        "manual"
+                    ]
+                }
+            }
+        },
+        "dto.InterestRateItem": {
+            "type": "object",
+            "properties": {
+                "account_type": {
+                    "type": "string",
+                    "enum": [
+                        "current",
+                        "savings",
+                        "time_deposit"
+                    ]
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.35
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "unique_identifier": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OverdraftResponse": {
             "type": "object",
             "properties": {
                 "account_number": {
                     "type": "integer"
                 },
-                "balance": {
-                    "type": "number"
+                "available_balance": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "overdraft_limit": {
+                    "type": "number"
+                },
+                "overdraft_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PaymentRequestItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "answered_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "decline_reason": {
+                    "type": "string"
                 },
-                "iban": {
+                "expires_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "payer_account_number": {
+                    "type": "integer"
+                },
+                "payer_customer_number": {
+                    "type": "integer"
+                },
+                "payer_name": {
+                    "type": "string"
+                },
+                "requester_customer_number": {
+                    "type": "integer"
+                },
+                "requester_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paying",
+                        "accepted",
+                        "declined",
+                        "cancelled",
+                        "expired"
+                    ]
+                },
+                "to_account_number": {
+                    "type": "integer"
+                },
+                "transfer": {
+                    "description": "Transfer is returned when the request is accepted, the request is paying while it waits for the approval of the payer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TransferItem"
+                        }
+                    ]
+                },
+                "transfer_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.AddMoneyRequest": {
+        "dto.PlaceHoldRequest": {
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "number"
+                },
+                "expires_at": {
+                    "description": "The hold ends by itself at this time, without it the hold stays until it is released",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Court order 2024/123"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "legal",
+                        "manual"
+                    ]
                 }
             }
         },
-        "dto.AddMoneyResponse": {
+        "dto.RegisterAccountRequest": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "number"
+                "currency": {
+                    "type": "string"
                 },
-                "customer_number": {
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "identity_number": {
+                    "type": "integer"
+                },
+                "iso_country_code": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone_number": {
                     "type": "integer"
                 }
             }
         },
-        "dto.CreateNewAccountRequest": {
+        "dto.ReleaseHoldRequest": {
             "type": "object",
             "properties": {
-                "iso_country_code": {
+                "reason": {
+                    "type": "string",
+                    "example": "Court order lifted"
+                }
+            }
+        },
+        "dto.RemainingLimitItem": {
+            "type": "object",
+            "properties": {
+                "level": {
                     "type": "string"
                 },
-                "user_id": {
+                "limit": {
+                    "type": "number"
+                },
+                "period": {
                     "type": "string"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "used": {
+                    "type": "number"
                 }
             }
         },
-        "dto.GetProfileResponse": {
+        "dto.ReverseTransferRequest": {
             "type": "object",
             "properties": {
-                "account_list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.AccountItem"
-                    }
+                "amount": {
+                    "type": "number"
                 },
-                "email": {
+                "force": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Sent to the wrong account"
+                },
+                "reference": {
+                    "description": "Reference of the reversal given by the admin, like a ticket number. A transfer is reversed once with a reference.",
+                    "type": "string",
+                    "example": "TICKET-4711"
+                },
+                "refund_fee": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReverseTransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "converted_amount": {
+                    "type": "number"
+                },
+                "converted_currency": {
                     "type": "string"
                 },
-                "first_name": {
+                "currency": {
                     "type": "string"
                 },
-                "id": {
+                "forced": {
+                    "type": "boolean"
+                },
+                "journal_id": {
                     "type": "string"
                 },
-                "last_name": {
+                "reference": {
                     "type": "string"
                 },
-                "phone_number": {
+                "refunded_fee": {
+                    "type": "number"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "transfer_history_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.GetTransferHistoryResponse": {
+        "dto.ScheduledTransferItem": {
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "number"
                 },
-                "from": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "from_account_number": {
                     "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "non_business_day": {
+                    "type": "string"
+                },
                 "note": {
                     "type": "string"
                 },
-                "to": {
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_account_number": {
                     "type": "integer"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.SetOverdraftRequest": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
+                "limit": {
+                    "description": "Limit is how far the balance may go below zero, zero removes the overdraft",
+                    "type": "number",
+                    "example": 5000
                 },
-                "unique_identifier": {
-                    "type": "string"
+                "rate": {
+                    "description": "Rate is the yearly interest rate charged daily on the negative balance, 0.6 is 60%",
+                    "type": "number",
+                    "example": 0.6
                 }
             }
         },
-        "dto.LoginResponse": {
+        "dto.StatementRequest": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "csv",
+                        "pdf"
+                    ]
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-01-01"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-01-31"
                 }
             }
         },
-        "dto.RegisterAccountRequest": {
+        "dto.TransferItem": {
             "type": "object",
             "properties": {
-                "email": {
+                "amount": {
+                    "type": "number"
+                },
+                "approval_method": {
                     "type": "string"
                 },
-                "first_name": {
+                "beneficiary_id": {
                     "type": "string"
                 },
-                "identity_number": {
+                "converted_amount": {
+                    "type": "number"
+                },
+                "converted_currency": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "exchange_rate": {
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee_rule": {
+                    "type": "string"
+                },
+                "from_account_number": {
                     "type": "integer"
                 },
-                "iso_country_code": {
+                "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "note": {
                     "type": "string"
                 },
-                "phone_number": {
+                "status": {
+                    "type": "string"
+                },
+                "to_account_number": {
                     "type": "integer"
+                },
+                "transaction_fee": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.TransferLimitItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "customer"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "daily"
+                },
+                "scope_id": {
+                    "type": "string"
                 }
             }
         },
@@ -548,6 +4499,10 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "beneficiary_id": {
+                    "description": "BeneficiaryId sends the transfer to a saved beneficiary instead of the account number or the IBAN",
+                    "type": "string"
+                },
                 "from_account_number": {
                     "type": "integer"
                 },
@@ -556,6 +4511,93 @@ const docTemplate = `{
                 },
                 "to_account_number": {
                     "type": "integer"
+                },
+                "to_iban": {
+                    "type": "string",
+                    "example": "TR33 0006 1005 1978 6457 8413 26"
+                }
+            }
+        },
+        "dto.UnfreezeAccountRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Identity verified"
+                }
+            }
+        },
+        "dto.UpdateApprovalMethodRequest": {
+            "type": "object",
+            "properties": {
+                "approval_method": {
+                    "type": "string",
+                    "enum": [
+                        "email_link",
+                        "email_code",
+                        "sms_code"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateBeneficiaryRequest": {
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "type": "string"
+                },
+                "trusted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.UpdateExchangeRatesRequest": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExchangeRateItem"
+                    }
+                }
+            }
+        },
+        "dto.UpdateScheduledTransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-06-30"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdateSegmentRequest": {
+            "type": "object",
+            "properties": {
+                "segment": {
+                    "type": "string",
+                    "enum": [
+                        "retail",
+                        "premium",
+                        "business"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateTransferLimitsRequest": {
+            "type": "object",
+            "properties": {
+                "limits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransferLimitItem"
+                    }
                 }
             }
         },
@@ -581,6 +4623,31 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "dto.WithdrawRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.WithdrawResponse": {
+            "type": "object",
+            "properties": {
+                "account_number": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "daily_limit_remaining": {
+                    "type": "number"
+                }
+            }
         }
     }
 }`
@@ -47,11 +47,35 @@ func migration(connection *gorm.DB) {
 			models.User{},
 			models.Account{},
 			models.TransferHistory{},
+			models.Journal{},
+			models.Posting{},
+			models.ExchangeRate{},
+			models.ScheduledTransfer{},
+			models.Transfer{},
+			models.BulkTransfer{},
+			models.PaymentRequest{},
+			models.Beneficiary{},
+			models.BeneficiaryEvent{},
+			models.TransferLimit{},
+			models.FeeSchedule{},
+			models.FeeTier{},
+			models.BalanceSnapshot{},
+			models.AccountEvent{},
+			models.Hold{},
+			models.InterestRate{},
+			models.InterestRateChange{},
+			models.InterestAccrual{},
+			models.Notification{},
 		)
 		if err != nil {
 			log.Error("Error migrating the database: ", err)
-		} else {
-			log.Info("Database migration is successful.")
+			return
+		}
+
+		log.Info("Database migration is successful.")
+
+		if err := seed(connection); err != nil {
+			log.Error("Error seeding the database: ", err)
 		}
 	})
 }
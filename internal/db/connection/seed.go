@@ -0,0 +1,145 @@
+package connection
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+// internalAccounts are the accounts of the bank which take the other side of the customer postings,
+// accounts in other currencies are created when they are needed for the first time
+var internalAccounts = []models.Account{
+	{AccountNumber: 1, IBAN: "TEKBANK-INTERNAL-CASH-TRY", InternalCode: enum.CashAccountCode},
+	{AccountNumber: 2, IBAN: "TEKBANK-INTERNAL-FEE_INCOME-TRY", InternalCode: enum.FeeIncomeAccountCode},
+	{AccountNumber: 3, IBAN: "TEKBANK-INTERNAL-FX_POSITION-TRY", InternalCode: enum.FxPositionAccountCode},
+}
+
+// defaultTransferLimits are the global limits of every customer in the base currency,
+// they are only created if they do not exist so changes of the admins are kept
+var defaultTransferLimits = []models.TransferLimit{
+	{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodTransaction, Amount: money.MustParse("50000")},
+	{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodDaily, Amount: money.MustParse("100000")},
+	{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodMonthly, Amount: money.MustParse("500000")},
+}
+
+// defaultFeeSchedule applies to every transfer which has no more specific schedule, it can not be deleted
+var defaultFeeSchedule = models.FeeSchedule{
+	Name:            "Standard",
+	Type:            enum.FeeTypeFlat,
+	FlatFee:         money.MustParse("4.22"),
+	FreeOwnAccounts: true,
+}
+
+func seed(connection *gorm.DB) error {
+	// The system user owns the internal accounts, the password can never match a bcrypt hash
+	systemUser := models.User{
+		IdentityNumber: 0,
+		CustomerNumber: 0,
+		FirstName:      "TEK",
+		LastName:       "Bank",
+		Email:          enum.SystemUserEmail,
+		PhoneNumber:    0,
+		Password:       "!",
+		Role:           enum.RoleSystem,
+	}
+
+	result := connection.Where(models.User{Email: enum.SystemUserEmail}).FirstOrCreate(&systemUser)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, account := range internalAccounts {
+		account.OwnerId = systemUser.Id
+		account.IsInternal = true
+		account.CreatedBy = systemUser.Id
+		account.UpdatedBy = systemUser.Id
+		account.Currency = money.DefaultCurrency
+
+		result = connection.Where(models.Account{InternalCode: account.InternalCode, Currency: account.Currency}).FirstOrCreate(&account)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	log.Info("Internal accounts are ready.")
+
+	for _, limit := range defaultTransferLimits {
+		result = connection.Where(models.TransferLimit{Level: limit.Level, Period: limit.Period}).Where("scope_id = ''").FirstOrCreate(&limit)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	log.Info("Transfer limits are ready.")
+
+	feeSchedule := defaultFeeSchedule
+	result = connection.Where("segment = '' AND account_type = ''").Attrs(feeSchedule).FirstOrCreate(&feeSchedule)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Info("Fee schedules are ready.")
+
+	// Fee entries were written with the receiver of the transfer, they belong to the fee income account of the bank
+	result = connection.Exec(`UPDATE public.transfer_history AS h SET "to" = a.account_number
+		FROM public.accounts AS a
+		WHERE h.is_fee AND a.internal_code = ? AND a.currency = h.currency AND h."to" <> a.account_number`,
+		enum.FeeIncomeAccountCode)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Infof("%d fee entries are moved to the fee income accounts.", result.RowsAffected)
+	}
+
+	// IBANs were stored as they were given, they are looked up normalized
+	result = connection.Exec(`UPDATE public.accounts SET iban = UPPER(REPLACE(iban, ' ', ''))
+		WHERE iban <> UPPER(REPLACE(iban, ' ', ''))`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Infof("%d IBANs are normalized.", result.RowsAffected)
+	}
+
+	// Overdraft charges were stored like interest accruals, they are the accruals of negative balances
+	result = connection.Exec(`UPDATE public.interest_accruals SET kind = ? WHERE balance < 0 AND kind <> ?`,
+		enum.AccrualKindOverdraft, enum.AccrualKindOverdraft)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Infof("%d overdraft charges are separated from the interest accruals.", result.RowsAffected)
+	}
+
+	// Rates were overwritten without their history, the current rates are in force from the time they were created
+	result = connection.Exec(`INSERT INTO public.interest_rate_changes (id, account_type, currency, rate, effective_from, updated_by)
+		SELECT gen_random_uuid(), r.account_type, r.currency, r.rate, r.created_at, r.updated_by FROM public.interest_rates AS r
+		WHERE NOT EXISTS (SELECT 1 FROM public.interest_rate_changes AS c WHERE c.account_type = r.account_type AND c.currency = r.currency)`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Infof("%d interest rate changes are recorded.", result.RowsAffected)
+	}
+
+	// The accrued days were not kept on the accounts, the catch up goes on from the last day with an accrual or a charge
+	result = connection.Exec(`UPDATE public.accounts AS a SET interest_accrued_on = d.day, overdraft_checked_on = d.day
+		FROM (SELECT account_id, MAX(day) AS day FROM public.interest_accruals GROUP BY account_id) AS d
+		WHERE d.account_id = a.id AND a.interest_accrued_on IS NULL AND a.overdraft_checked_on IS NULL`)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		log.Infof("%d accounts continue accruing interest from their last accrual.", result.RowsAffected)
+	}
+
+	return nil
+}
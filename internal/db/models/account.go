@@ -3,15 +3,44 @@ package models
 import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/iban"
+	"tek-bank/pkg/money"
 	"time"
 )
 
 type Account struct {
-	Id            string `gorm:"primary_key;type:uuid;"`
-	OwnerId       string `gorm:"type:uuid;not null"`
-	IBAN          string `gorm:"unique;not null"`
-	AccountNumber int64  `gorm:"unique;not null"`
-	Balance       float64
+	Id            string         `gorm:"primary_key;type:uuid;"`
+	OwnerId       string         `gorm:"type:uuid;not null"`
+	IBAN          string         `gorm:"unique;not null"`
+	AccountNumber int64          `gorm:"unique;not null"`
+	Balance       money.Amount   `gorm:"type:numeric(20,2);not null;default:0"`
+	Currency      money.Currency `gorm:"type:char(3);not null;default:'TRY'"`
+
+	// Type of the account, one of enum.AccountType*
+	Type string `gorm:"not null;default:current"`
+
+	// DailyWithdrawalLimit of the account in its currency, zero means the default limit of the bank
+	DailyWithdrawalLimit money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+
+	// OverdraftLimit lets the balance of a current account go below zero down to -OverdraftLimit,
+	// the negative balance is charged every day with the yearly OverdraftRate
+	OverdraftLimit money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	OverdraftRate  money.Rate   `gorm:"type:numeric(20,10);default:null"`
+
+	// InterestAccruedOn is the last day the interest of the account is accrued for and OverdraftCheckedOn the last
+	// day its overdraft interest is charged for, the days after them are caught up however long the worker was down
+	InterestAccruedOn  *time.Time `gorm:"type:date;default:null"`
+	OverdraftCheckedOn *time.Time `gorm:"type:date;default:null"`
+
+	// Internal accounts belong to the bank itself (cash, fee income, ...) and may go negative
+	IsInternal   bool   `gorm:"default:false"`
+	InternalCode string `gorm:"default:null;index"`
+
+	// A frozen account can not send (debits) or receive (credits) money, closed accounts are not active
+	DebitsFrozen  bool       `gorm:"not null;default:false"`
+	CreditsFrozen bool       `gorm:"not null;default:false"`
+	ClosedAt      *time.Time `gorm:"default:null"`
 
 	// Audit fields
 	CreatedAt time.Time `gorm:"default:current_timestamp"`
@@ -26,9 +55,23 @@ type Account struct {
 
 func (a *Account) BeforeCreate(tx *gorm.DB) error {
 	a.Id = uuid.New().String()
+
+	// The IBAN is stored without spaces in upper case, so it is looked up with its unique index
+	a.IBAN = iban.Normalize(a.IBAN)
 	return nil
 }
 
+// Status returns the status of the account, one of enum.AccountStatus*
+func (a *Account) Status() string {
+	if !a.IsActive {
+		return enum.AccountStatusClosed
+	}
+	if a.DebitsFrozen || a.CreditsFrozen {
+		return enum.AccountStatusFrozen
+	}
+	return enum.AccountStatusActive
+}
+
 func (a *Account) TableName() string {
 	return "public.accounts"
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// AccountEvent is the audit trail of the lifecycle of an account, every freeze, unfreeze and closure is recorded with its reason
+type AccountEvent struct {
+	Id        string `gorm:"primary_key;type:uuid;"`
+	AccountId string `gorm:"type:uuid;not null;index"`
+
+	// Action of the event, one of enum.AccountAction*
+	Action string `gorm:"not null"`
+	Reason string `gorm:"not null"`
+
+	// State of the freeze after the event
+	DebitsFrozen  bool `gorm:"not null;default:false"`
+	CreditsFrozen bool `gorm:"not null;default:false"`
+
+	// The balance moved to another account of the owner when the account was closed
+	SweepAccountNumber int64        `gorm:"default:null"`
+	SweptAmount        money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	JournalId          string       `gorm:"type:uuid;default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Account Account `gorm:"foreignKey:AccountId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (e *AccountEvent) BeforeCreate(tx *gorm.DB) error {
+	e.Id = uuid.New().String()
+	return nil
+}
+
+func (e *AccountEvent) TableName() string {
+	return "public.account_events"
+}
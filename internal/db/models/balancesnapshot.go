@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// BalanceSnapshot is the balance of an account at a moment, the snapshots are taken every midnight,
+// so a balance in the past is calculated from the nearest snapshot and the postings after it
+type BalanceSnapshot struct {
+	Id        string         `gorm:"primary_key;type:uuid;"`
+	AccountId string         `gorm:"type:uuid;not null;uniqueIndex:idx_balance_snapshots_account_at,priority:1"`
+	At        time.Time      `gorm:"not null;uniqueIndex:idx_balance_snapshots_account_at,priority:2"`
+	Balance   money.Amount   `gorm:"type:numeric(20,2);not null"`
+	Currency  money.Currency `gorm:"type:char(3);not null;default:'TRY'"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+
+	// Relationship
+	Account Account `gorm:"foreignKey:AccountId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (b *BalanceSnapshot) BeforeCreate(tx *gorm.DB) error {
+	b.Id = uuid.New().String()
+	return nil
+}
+
+func (b *BalanceSnapshot) TableName() string {
+	return "public.balance_snapshots"
+}
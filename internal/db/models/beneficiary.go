@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Beneficiary is a saved receiver of the transfers of a customer. The holder name is taken from the account
+// when it is saved, transfers to trusted beneficiaries may be executed without the approval of the sender.
+// The trust is only given when the owner approves it like a transfer.
+type Beneficiary struct {
+	Id            string `gorm:"primary_key;type:uuid;"`
+	OwnerId       string `gorm:"type:uuid;not null;uniqueIndex:idx_beneficiaries_owner_account,priority:1"`
+	Nickname      string `gorm:"not null"`
+	AccountNumber int64  `gorm:"type:bigint;not null;uniqueIndex:idx_beneficiaries_owner_account,priority:2"`
+	IBAN          string `gorm:"default:null"`
+	HolderName    string `gorm:"not null"`
+	Trusted       bool   `gorm:"not null;default:false"`
+
+	// Trust waiting for the approval of the owner until it expires, the token and the code are only stored as hashes
+	TrustApprovalMethod string     `gorm:"default:null"`
+	TrustTokenHash      string     `gorm:"type:char(64);index;default:null"`
+	TrustCodeHash       string     `gorm:"type:char(64);default:null"`
+	TrustCodeAttempts   int        `gorm:"not null;default:0"`
+	TrustExpiresAt      *time.Time `gorm:"default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Owner   User    `gorm:"foreignKey:OwnerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Account Account `gorm:"foreignKey:AccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (b *Beneficiary) BeforeCreate(tx *gorm.DB) error {
+	b.Id = uuid.New().String()
+	return nil
+}
+
+func (b *Beneficiary) TableName() string {
+	return "public.beneficiaries"
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// BeneficiaryEvent is the audit trail of the trust of the beneficiaries. The events are kept when the beneficiary is deleted,
+// so the owner and the account are recorded with them.
+type BeneficiaryEvent struct {
+	Id            string `gorm:"primary_key;type:uuid;"`
+	BeneficiaryId string `gorm:"type:uuid;not null;index"`
+	OwnerId       string `gorm:"type:uuid;not null;index"`
+	AccountNumber int64  `gorm:"type:bigint;not null"`
+
+	// Action of the event, one of enum.BeneficiaryAction*
+	Action string `gorm:"not null"`
+
+	// How the trust was asked for and approved, one of enum.ApprovalMethod*
+	ApprovalMethod string `gorm:"default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Owner User `gorm:"foreignKey:OwnerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (e *BeneficiaryEvent) BeforeCreate(tx *gorm.DB) error {
+	e.Id = uuid.New().String()
+	return nil
+}
+
+func (e *BeneficiaryEvent) TableName() string {
+	return "public.beneficiary_events"
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// BulkTransfer is a file of transfers from one account of a customer, like a payroll. Every row of the file is
+// a transfer of the bulk transfer, the rows are approved together and executed one by one in the background.
+type BulkTransfer struct {
+	Id                string         `gorm:"primary_key;type:uuid;"`
+	OwnerId           string         `gorm:"type:uuid;not null;index"`
+	FromAccountNumber int64          `gorm:"type:bigint;not null"`
+	FileName          string         `gorm:"not null;default:''"`
+	Currency          money.Currency `gorm:"type:char(3);not null"`
+
+	// Totals of the rows in the currency of the sender
+	RowCount    int          `gorm:"not null"`
+	TotalAmount money.Amount `gorm:"type:numeric(20,2);not null"`
+	TotalFee    money.Amount `gorm:"type:numeric(20,2);not null"`
+
+	// Status is one of enum.BulkTransfer*, the approval token is only stored as a hash
+	Status        string     `gorm:"not null;index"`
+	TokenHash     string     `gorm:"type:char(64);uniqueIndex;default:null"`
+	ExpiresAt     *time.Time `gorm:"default:null"`
+	ApprovedAt    *time.Time `gorm:"default:null"`
+	CompletedAt   *time.Time `gorm:"default:null"`
+	RejectedAt    *time.Time `gorm:"default:null"`
+	ExpiredAt     *time.Time `gorm:"default:null"`
+	CancelledAt   *time.Time `gorm:"default:null"`
+	FailureReason string     `gorm:"default:null"`
+
+	// One-time code of bulk transfers approved with a code, only its hash is stored
+	ApprovalMethod string `gorm:"not null;default:email_link"`
+	CodeHash       string `gorm:"type:char(64);default:null"`
+	CodeAttempts   int    `gorm:"not null;default:0"`
+
+	// Results of the rows, they are counted when the bulk transfer is completed
+	ExecutedCount int `gorm:"not null;default:0"`
+	FailedCount   int `gorm:"not null;default:0"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	FromAccount Account `gorm:"foreignKey:FromAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (b *BulkTransfer) BeforeCreate(tx *gorm.DB) error {
+	b.Id = uuid.New().String()
+	return nil
+}
+
+func (b *BulkTransfer) TableName() string {
+	return "public.bulk_transfers"
+}
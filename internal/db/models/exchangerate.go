@@ -0,0 +1,21 @@
+package models
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// ExchangeRate is the value of one unit of the currency in the base currency of the bank
+type ExchangeRate struct {
+	Currency money.Currency `gorm:"primary_key;type:char(3);"`
+	Rate     money.Rate     `gorm:"type:numeric(20,10);not null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedBy string    `gorm:"type:uuid;default:null"`
+}
+
+func (e *ExchangeRate) TableName() string {
+	return "public.exchange_rates"
+}
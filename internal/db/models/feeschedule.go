@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// FeeSchedule decides the fee of the transfers from accounts of a segment and an account type, an empty segment
+// or account type matches all of them. The fee amounts are in the base currency of the bank.
+type FeeSchedule struct {
+	Id          string `gorm:"primary_key;type:uuid;"`
+	Name        string `gorm:"not null"`
+	Segment     string `gorm:"not null;default:'';uniqueIndex:idx_fee_schedules_scope,priority:1"`
+	AccountType string `gorm:"not null;default:'';uniqueIndex:idx_fee_schedules_scope,priority:2"`
+
+	// Type of the fee, one of enum.FeeType*
+	Type    string       `gorm:"not null"`
+	FlatFee money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	// Rate is the part of the amount charged by percentage fees, 0.001 is 0.1%
+	Rate money.Rate `gorm:"type:numeric(20,10);default:null"`
+
+	// Bounds of the fee, zero means no bound
+	MinFee money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	MaxFee money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+
+	// Transfers between the accounts of the same owner are free
+	FreeOwnAccounts bool `gorm:"not null;default:false"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedBy string    `gorm:"type:uuid;default:null"`
+
+	// Relationship
+	Tiers []FeeTier `gorm:"foreignKey:ScheduleId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (f *FeeSchedule) BeforeCreate(tx *gorm.DB) error {
+	f.Id = uuid.New().String()
+	return nil
+}
+
+func (f *FeeSchedule) TableName() string {
+	return "public.fee_schedules"
+}
+
+// FeeTier is a band of the amounts of a tiered fee schedule, the fee of an amount is the flat fee and the rate
+// of the first tier which reaches the amount
+type FeeTier struct {
+	Id         string `gorm:"primary_key;type:uuid;"`
+	ScheduleId string `gorm:"type:uuid;not null;index"`
+
+	// UpTo is the highest amount of the tier, zero means no upper bound
+	UpTo    money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	FlatFee money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+	Rate    money.Rate   `gorm:"type:numeric(20,10);default:null"`
+}
+
+func (f *FeeTier) BeforeCreate(tx *gorm.DB) error {
+	f.Id = uuid.New().String()
+	return nil
+}
+
+func (f *FeeTier) TableName() string {
+	return "public.fee_tiers"
+}
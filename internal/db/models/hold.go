@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// Hold reserves an amount of an account, the available balance of the account is its balance without the active holds.
+// A hold is active until it is released or its expiry time is reached.
+type Hold struct {
+	Id        string         `gorm:"primary_key;type:uuid;"`
+	AccountId string         `gorm:"type:uuid;not null;index:idx_holds_account_status,priority:1"`
+	Amount    money.Amount   `gorm:"type:numeric(20,2);not null"`
+	Currency  money.Currency `gorm:"type:char(3);not null"`
+
+	// Type of the hold, one of enum.HoldType*
+	Type   string `gorm:"not null"`
+	Reason string `gorm:"not null;default:''"`
+
+	// TransferId is the pending transfer the money is reserved for, the hold is captured when the transfer is executed
+	TransferId string `gorm:"type:uuid;default:null;index"`
+
+	// Status of the hold, one of enum.Hold*
+	Status        string     `gorm:"not null;default:active;index:idx_holds_account_status,priority:2"`
+	ExpiresAt     *time.Time `gorm:"default:null"`
+	ReleasedAt    *time.Time `gorm:"default:null"`
+	ReleaseReason string     `gorm:"default:null"`
+	ReleasedBy    string     `gorm:"type:uuid;default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Account Account `gorm:"foreignKey:AccountId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (h *Hold) BeforeCreate(tx *gorm.DB) error {
+	h.Id = uuid.New().String()
+	return nil
+}
+
+func (h *Hold) TableName() string {
+	return "public.holds"
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// InterestRate is the yearly interest rate of the customer accounts of a type and a currency,
+// accounts without a rate do not earn interest
+type InterestRate struct {
+	Id          string         `gorm:"primary_key;type:uuid;"`
+	AccountType string         `gorm:"not null;uniqueIndex:idx_interest_rates_scope,priority:1"`
+	Currency    money.Currency `gorm:"type:char(3);not null;uniqueIndex:idx_interest_rates_scope,priority:2"`
+
+	// Rate is the yearly rate, 0.35 is 35%
+	Rate money.Rate `gorm:"type:numeric(20,10);not null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedBy string    `gorm:"type:uuid;default:null"`
+}
+
+func (i *InterestRate) BeforeCreate(tx *gorm.DB) error {
+	i.Id = uuid.New().String()
+	return nil
+}
+
+func (i *InterestRate) TableName() string {
+	return "public.interest_rates"
+}
+
+// InterestRateChange is a rate set or removed at a point in time, the accrual of a day uses the last change before
+// the end of the day. A removed rate is recorded without a rate.
+type InterestRateChange struct {
+	Id            string         `gorm:"primary_key;type:uuid;"`
+	AccountType   string         `gorm:"not null;index:idx_interest_rate_changes_scope,priority:1"`
+	Currency      money.Currency `gorm:"type:char(3);not null;index:idx_interest_rate_changes_scope,priority:2"`
+	Rate          money.Rate     `gorm:"type:numeric(20,10);default:null"`
+	EffectiveFrom time.Time      `gorm:"not null;default:current_timestamp;index:idx_interest_rate_changes_scope,priority:3"`
+
+	// Audit fields
+	UpdatedBy string `gorm:"type:uuid;default:null"`
+}
+
+func (i *InterestRateChange) BeforeCreate(tx *gorm.DB) error {
+	i.Id = uuid.New().String()
+	return nil
+}
+
+func (i *InterestRateChange) TableName() string {
+	return "public.interest_rate_changes"
+}
+
+// InterestAccrual is the interest an account earned on a day with the balance at the end of the day and the rate
+// of the day. The exact interest of the days is summed and rounded once, when it is paid at the start of the next month.
+// The overdraft interest charged for a negative day is kept as an accrual of the overdraft kind, an account ends a day
+// either above or below zero, so it has one accrual of a day at most.
+type InterestAccrual struct {
+	Id        string    `gorm:"primary_key;type:uuid;"`
+	AccountId string    `gorm:"type:uuid;not null;uniqueIndex:idx_interest_accruals_account_day,priority:1"`
+	Day       time.Time `gorm:"type:date;not null;uniqueIndex:idx_interest_accruals_account_day,priority:2"`
+
+	// Kind of the accrual, one of enum.AccrualKind*. Every query of the earned interest filters on it.
+	Kind string `gorm:"not null;default:interest;index"`
+
+	Balance money.Amount `gorm:"type:numeric(20,2);not null"`
+	Rate    money.Rate   `gorm:"type:numeric(20,10);not null"`
+
+	// The journal of the payment, paid accruals without a journal were rounded to zero
+	JournalId string     `gorm:"type:uuid;default:null"`
+	PaidAt    *time.Time `gorm:"default:null;index"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+
+	// Relationship
+	Account Account `gorm:"foreignKey:AccountId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (i *InterestAccrual) BeforeCreate(tx *gorm.DB) error {
+	i.Id = uuid.New().String()
+	return nil
+}
+
+func (i *InterestAccrual) TableName() string {
+	return "public.interest_accruals"
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// Journal groups the postings of a single money movement (deposit, transfer, ...).
+// The amounts of the postings of a journal always sum up to zero.
+type Journal struct {
+	Id          string `gorm:"primary_key;type:uuid;"`
+	Type        string `gorm:"not null;index"`
+	Description string `gorm:"default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Postings []Posting `gorm:"foreignKey:JournalId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (j *Journal) BeforeCreate(tx *gorm.DB) error {
+	j.Id = uuid.New().String()
+	return nil
+}
+
+func (j *Journal) TableName() string {
+	return "public.journals"
+}
+
+// Posting is a single line of a journal. A positive amount increases the balance
+// of the account, a negative amount decreases it.
+type Posting struct {
+	Id        string         `gorm:"primary_key;type:uuid;"`
+	JournalId string         `gorm:"type:uuid;not null;index"`
+	AccountId string         `gorm:"type:uuid;not null;index;index:idx_postings_account_created,priority:1"`
+	Amount    money.Amount   `gorm:"type:numeric(20,2);not null"`
+	Currency  money.Currency `gorm:"type:char(3);not null;default:'TRY'"`
+
+	// AllowNegative lets the posting take the balance of a customer account below its overdraft limit, it is not stored
+	AllowNegative bool `gorm:"-"`
+
+	// Audit fields, the postings of an account are read by their creation time for past balances and statements
+	CreatedAt time.Time `gorm:"default:current_timestamp;index:idx_postings_account_created,priority:2"`
+	CreatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Account Account `gorm:"foreignKey:AccountId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (p *Posting) BeforeCreate(tx *gorm.DB) error {
+	p.Id = uuid.New().String()
+	return nil
+}
+
+func (p *Posting) TableName() string {
+	return "public.postings"
+}
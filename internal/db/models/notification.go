@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Notification is an e-mail of the outbox. It is stored in the transaction of the change it tells about and sent
+// by a worker after the commit, so a mail is never sent for a rolled back change and a failed mail never rolls
+// back the change.
+type Notification struct {
+	Id      string `gorm:"primary_key;type:uuid;"`
+	To      string `gorm:"not null"`
+	Subject string `gorm:"not null"`
+	Body    string `gorm:"not null"`
+
+	// Failed sends are tried again until enum.NotificationMaxAttempts
+	Attempts  int        `gorm:"not null;default:0"`
+	LastError string     `gorm:"default:null"`
+	SentAt    *time.Time `gorm:"default:null;index"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	n.Id = uuid.New().String()
+	return nil
+}
+
+func (n *Notification) TableName() string {
+	return "public.notifications"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// PaymentRequest is a request of a customer for money from another customer. The payer accepts it with
+// a transfer to the account of the requester or declines it until it expires.
+type PaymentRequest struct {
+	Id              string         `gorm:"primary_key;type:uuid;"`
+	RequesterId     string         `gorm:"type:uuid;not null;index"`
+	ToAccountNumber int64          `gorm:"type:bigint;not null"`
+	Amount          money.Amount   `gorm:"type:numeric(20,2);not null"`
+	Currency        money.Currency `gorm:"type:char(3);not null"`
+	Note            string         `gorm:"default:null"`
+
+	// The payer, a request addressed to an account has to be paid from that account
+	PayerId            string `gorm:"type:uuid;not null;index"`
+	PayerAccountNumber int64  `gorm:"type:bigint;default:null"`
+
+	// Answer of the payer, a paying or accepted request has the transfer which pays it
+	Status        string     `gorm:"not null;default:pending;index"`
+	ExpiresAt     time.Time  `gorm:"not null;index"`
+	TransferId    string     `gorm:"type:uuid;default:null"`
+	DeclineReason string     `gorm:"default:null"`
+	AnsweredAt    *time.Time `gorm:"default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	Requester User    `gorm:"foreignKey:RequesterId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Payer     User    `gorm:"foreignKey:PayerId;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ToAccount Account `gorm:"foreignKey:ToAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (p *PaymentRequest) BeforeCreate(tx *gorm.DB) error {
+	p.Id = uuid.New().String()
+	return nil
+}
+
+func (p *PaymentRequest) TableName() string {
+	return "public.payment_requests"
+}
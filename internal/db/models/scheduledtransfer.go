@@ -0,0 +1,54 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// ScheduledTransfer is a future dated or recurring transfer (standing order) of a customer,
+// the worker executes it when NextRunAt is reached
+type ScheduledTransfer struct {
+	Id                string       `gorm:"primary_key;type:uuid;"`
+	OwnerId           string       `gorm:"type:uuid;not null;index"`
+	FromAccountNumber int64        `gorm:"type:bigint;not null"`
+	ToAccountNumber   int64        `gorm:"type:bigint;not null"`
+	Amount            money.Amount `gorm:"type:numeric(20,2);not null"`
+	Note              string       `gorm:"default:null"`
+
+	// Schedule
+	Frequency      string     `gorm:"not null"`
+	DayOfMonth     int        `gorm:"not null"`
+	NonBusinessDay string     `gorm:"not null;default:shift"`
+	StartDate      time.Time  `gorm:"type:date;not null"`
+	EndDate        *time.Time `gorm:"type:date;default:null"`
+
+	// Execution state, NextRunDate is the planned date of the next occurrence and NextRunAt
+	// the time it is tried, which differs after a weekend shift or a failed attempt
+	Status      string     `gorm:"not null;default:active;index:idx_scheduled_transfers_due,priority:1"`
+	NextRunDate time.Time  `gorm:"type:date;not null"`
+	NextRunAt   time.Time  `gorm:"not null;index:idx_scheduled_transfers_due,priority:2"`
+	Attempts    int        `gorm:"not null;default:0"`
+	LastRunAt   *time.Time `gorm:"default:null"`
+	LastError   string     `gorm:"default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	FromAccount Account `gorm:"foreignKey:FromAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ToAccount   Account `gorm:"foreignKey:ToAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (s *ScheduledTransfer) BeforeCreate(tx *gorm.DB) error {
+	s.Id = uuid.New().String()
+	return nil
+}
+
+func (s *ScheduledTransfer) TableName() string {
+	return "public.scheduled_transfers"
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// Transfer is a transfer requested by a customer, it moves through the statuses of enum.Transfer*
+// and keeps the amounts, the exchange rate and the fee fixed when it was requested
+type Transfer struct {
+	Id                string       `gorm:"primary_key;type:uuid;"`
+	OwnerId           string       `gorm:"type:uuid;not null;index"`
+	FromAccountNumber int64        `gorm:"type:bigint;not null"`
+	ToAccountNumber   int64        `gorm:"type:bigint;not null"`
+	Amount            money.Amount `gorm:"type:numeric(20,2);not null"`
+	Note              string       `gorm:"default:null"`
+
+	// Currency of the sender, the receiver gets the converted amount in its own currency
+	Currency          money.Currency `gorm:"type:char(3);not null"`
+	ConvertedAmount   money.Amount   `gorm:"type:numeric(20,2);not null"`
+	ConvertedCurrency money.Currency `gorm:"type:char(3);not null"`
+	ExchangeRate      money.Rate     `gorm:"type:numeric(20,10);not null"`
+	TransactionFee    money.Amount   `gorm:"type:numeric(20,2);not null"`
+
+	// Fee schedule rule the transaction fee was calculated with
+	FeeRule string `gorm:"default:null"`
+
+	// Amount in the base currency of the bank, the transfer limits are checked with it
+	BaseAmount money.Amount `gorm:"type:numeric(20,2);not null;default:0"`
+
+	// Status and the time of every change, the approval token is only stored as a hash
+	Status        string     `gorm:"not null;index"`
+	TokenHash     string     `gorm:"type:char(64);uniqueIndex;default:null"`
+	ExpiresAt     *time.Time `gorm:"default:null"`
+	ApprovedAt    *time.Time `gorm:"default:null"`
+	ExecutedAt    *time.Time `gorm:"default:null"`
+	RejectedAt    *time.Time `gorm:"default:null"`
+	ExpiredAt     *time.Time `gorm:"default:null"`
+	CancelledAt   *time.Time `gorm:"default:null"`
+	FailureReason string     `gorm:"default:null"`
+
+	// One-time code of transfers approved with a code, only its hash is stored
+	ApprovalMethod string `gorm:"not null;default:email_link"`
+	CodeHash       string `gorm:"type:char(64);default:null"`
+	CodeAttempts   int    `gorm:"not null;default:0"`
+
+	// Journal which moved the money of the executed transfer
+	JournalId string `gorm:"type:uuid;default:null"`
+
+	// Bulk transfer the transfer is a row of and the line of the row in its file, rows are approved with the bulk transfer
+	BulkTransferId string `gorm:"type:uuid;default:null;index"`
+	BulkRow        int    `gorm:"not null;default:0"`
+
+	// Saved beneficiary the transfer was sent to
+	BeneficiaryId string `gorm:"type:uuid;default:null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedBy string    `gorm:"type:uuid"`
+	UpdatedBy string    `gorm:"type:uuid"`
+
+	// Relationship
+	FromAccount Account `gorm:"foreignKey:FromAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ToAccount   Account `gorm:"foreignKey:ToAccountNumber;references:AccountNumber;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (t *Transfer) BeforeCreate(tx *gorm.DB) error {
+	t.Id = uuid.New().String()
+	return nil
+}
+
+func (t *Transfer) TableName() string {
+	return "public.transfers"
+}
@@ -3,19 +3,42 @@ package models
 import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"tek-bank/pkg/money"
 	"time"
 )
 
+// The history of an account is paged by the creation time, newest first, with the indexes of both sides
 type TransferHistory struct {
-	Id     string  `gorm:"primary_key;type:uuid;"`
-	From   int64   `gorm:"type:bigint;not null"`
-	To     int64   `gorm:"type:bigint;not null"`
-	Note   string  `gorm:"default:null"`
-	Amount float64 `gorm:"type:numeric;not null"`
-	IsFee  bool    `gorm:"default:false"`
+	Id     string       `gorm:"primary_key;type:uuid;index:idx_transfer_history_from_created,priority:3;index:idx_transfer_history_to_created,priority:3"`
+	From   int64        `gorm:"type:bigint;not null;index:idx_transfer_history_from_created,priority:1"`
+	To     int64        `gorm:"type:bigint;not null;index:idx_transfer_history_to_created,priority:1"`
+	Note   string       `gorm:"default:null"`
+	Amount money.Amount `gorm:"type:numeric(20,2);not null"`
+	IsFee  bool         `gorm:"default:false"`
+
+	// Fee schedule rule of a fee entry
+	FeeRule string `gorm:"default:null"`
+
+	// Type of the journal of the entry, a withdrawal goes to the cash account of the bank
+	Type string `gorm:"not null;default:transfer"`
+
+	// Currency of the sender, the receiver gets the converted amount in its own currency
+	Currency          money.Currency `gorm:"type:char(3);not null;default:'TRY'"`
+	ConvertedAmount   money.Amount   `gorm:"type:numeric(20,2);default:null"`
+	ConvertedCurrency money.Currency `gorm:"type:char(3);default:null"`
+	ExchangeRate      money.Rate     `gorm:"type:numeric(20,10);default:null"`
+
+	// Journal which moved the money of this history entry
+	JournalId string `gorm:"type:uuid;default:null"`
+
+	// ReversalOf is the entry a reversal or a fee refund gives money back for, Reason is why it was reversed.
+	// Reference is given by the admin, an entry is reversed once with a reference, so a retry can not post twice.
+	ReversalOf string `gorm:"type:uuid;default:null;index;uniqueIndex:idx_transfer_history_reversal_reference,priority:1"`
+	Reason     string `gorm:"default:null"`
+	Reference  string `gorm:"default:null;uniqueIndex:idx_transfer_history_reversal_reference,priority:2"`
 
 	// Audit fields
-	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	CreatedAt time.Time `gorm:"default:current_timestamp;index:idx_transfer_history_from_created,priority:2;index:idx_transfer_history_to_created,priority:2"`
 	UpdatedAt time.Time `gorm:"default:current_timestamp"`
 	CreatedBy string    `gorm:"type:uuid"`
 	UpdatedBy string    `gorm:"type:uuid"`
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// TransferLimit is the most that can be transferred in a period, the amount is in the base currency of the bank.
+// ScopeId is empty for global limits, the user id for customer limits and the account number for account limits.
+type TransferLimit struct {
+	Id      string       `gorm:"primary_key;type:uuid;"`
+	Level   string       `gorm:"not null;uniqueIndex:idx_transfer_limits_scope,priority:1"`
+	ScopeId string       `gorm:"not null;default:'';uniqueIndex:idx_transfer_limits_scope,priority:2"`
+	Period  string       `gorm:"not null;uniqueIndex:idx_transfer_limits_scope,priority:3"`
+	Amount  money.Amount `gorm:"type:numeric(20,2);not null"`
+
+	// Audit fields
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedBy string    `gorm:"type:uuid;default:null"`
+}
+
+func (t *TransferLimit) BeforeCreate(tx *gorm.DB) error {
+	t.Id = uuid.New().String()
+	return nil
+}
+
+func (t *TransferLimit) TableName() string {
+	return "public.transfer_limits"
+}
@@ -15,6 +15,13 @@ type User struct {
 	Email          string `gorm:"unique;not null"`
 	PhoneNumber    uint64 `gorm:"unique;not null"`
 	Password       string `gorm:"not null"`
+	Role           string `gorm:"not null;default:customer"`
+
+	// How the transfers of the user are approved, one of enum.ApprovalMethod*
+	ApprovalMethod string `gorm:"not null;default:email_link"`
+
+	// Segment of the customer, one of enum.Segment*, the fees of its transfers depend on it
+	Segment string `gorm:"not null;default:retail"`
 
 	// Audit fields
 	CreatedAt time.Time `gorm:"default:current_timestamp"`
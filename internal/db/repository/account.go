@@ -1,74 +1,72 @@
 package repository
 
 import (
-	"context"
 	"github.com/gofiber/fiber/v2/log"
-	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
-	"sync"
+	"gorm.io/gorm/clause"
 	"tek-bank/internal/db/models"
+	"tek-bank/pkg/iban"
+	"tek-bank/pkg/money"
+	"time"
 )
 
 type AccountRepository interface {
 	Create(account models.Account) (*models.Account, error)
-	UpdateBalance(amount float64, id string) error
 	FindByAccountNumber(accountNumber int64) (*models.Account, error)
 	FindByIBAN(iban string) (*models.Account, error)
 	FindByOwnerId(ownerId string) ([]models.Account, error)
-
-	// Redis operations
-	SetToken(ctx context.Context, key string, value string) error
-	GetToken(ctx context.Context, key string) (*string, error)
-	DeleteToken(ctx context.Context, key string) error
+	FindInternal(code string, currency money.Currency) (*models.Account, error)
+	Lock(ids ...string) ([]models.Account, error)
+	UpdateState(account models.Account) error
+	UpdateOverdraft(account models.Account) error
 
 	WithTx(trxHandle *gorm.DB) AccountRepository
 }
 
 //go:generate mockgen -destination=../../mocks/repository/account_repository_mock.go -package=repository tek-bank/internal/db/repository AccountRepository
 type accountRepository struct {
-	db          *gorm.DB
-	redisClient *redis.Client
-	tableName   string
-	dbMutex     sync.Mutex
+	db        *gorm.DB
+	tableName string
 }
 
-func NewAccountRepository(db *gorm.DB, client *redis.Client) AccountRepository {
+func NewAccountRepository(db *gorm.DB) AccountRepository {
 	var account models.Account
 	return &accountRepository{
-		db:          db,
-		redisClient: client,
-		tableName:   account.TableName(),
+		db:        db,
+		tableName: account.TableName(),
 	}
 }
 
+// WithTx returns a copy of the repository which runs on the transaction,
+// the repository itself is shared between requests and is never changed
 func (d *accountRepository) WithTx(txHandle *gorm.DB) AccountRepository {
 	if txHandle == nil {
 		log.Error("Transaction not found")
 		return d
 	}
-	d.db = txHandle
-	return d
+	clone := *d
+	clone.db = txHandle
+	return &clone
 }
 
+// Create creates the account, ErrDuplicateNumber is returned if the account number or the IBAN is already used
 func (r *accountRepository) Create(account models.Account) (*models.Account, error) {
-	result := r.db.Table(r.tableName).Preload("Owner").Create(&account).First(&account)
-	if result.Error != nil {
-		return nil, result.Error
+	// The insert runs in a savepoint, so a failed insert does not abort the transaction of the request
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Table(r.tableName).Create(&account).Error
+	})
+	if isUniqueViolation(err, "account_number", "iban") {
+		return nil, ErrDuplicateNumber
+	}
+	if err != nil {
+		return nil, err
 	}
-	return &account, nil
-}
-
-func (r *accountRepository) UpdateBalance(amount float64, id string) error {
-	r.dbMutex.Lock()
-	defer r.dbMutex.Unlock()
 
-	// Add money to the account
-	result := r.db.Table(r.tableName).Preload("Owner").Where("id = ?", id).Update("balance", amount)
+	result := r.db.Table(r.tableName).Preload("Owner").First(&account)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	return nil
-
+	return &account, nil
 }
 
 func (r *accountRepository) FindByAccountNumber(accountNumber int64) (*models.Account, error) {
@@ -82,9 +80,10 @@ func (r *accountRepository) FindByAccountNumber(accountNumber int64) (*models.Ac
 	return &account, nil
 }
 
-func (r *accountRepository) FindByIBAN(iban string) (*models.Account, error) {
+// FindByIBAN finds the account of the IBAN, the IBANs are stored normalized, so the unique index of the IBAN is used
+func (r *accountRepository) FindByIBAN(value string) (*models.Account, error) {
 	var account models.Account
-	result := r.db.Table(r.tableName).Preload("Owner").Where("iban = ?", iban).First(&account)
+	result := r.db.Table(r.tableName).Preload("Owner").Where("iban = ?", iban.Normalize(value)).First(&account)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -100,28 +99,59 @@ func (r *accountRepository) FindByOwnerId(ownerId string) ([]models.Account, err
 	return accounts, nil
 }
 
-func (r *accountRepository) SetToken(ctx context.Context, key string, value string) error {
+// FindInternal finds the internal account of the bank with the given code and currency
+func (r *accountRepository) FindInternal(code string, currency money.Currency) (*models.Account, error) {
+	var account models.Account
+	result := r.db.Table(r.tableName).Where("is_internal AND internal_code = ? AND currency = ?", code, currency).First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
 
-	result := r.redisClient.Set(ctx, key, value, 0)
-	if result.Err() != nil {
-		return result.Err()
+// Lock locks the accounts with SELECT ... FOR UPDATE until the end of the transaction and returns
+// their latest state. Customer accounts are always locked before internal accounts and in the
+// order of their ids, so two transfers between the same accounts can not deadlock.
+func (r *accountRepository) Lock(ids ...string) ([]models.Account, error) {
+	var accounts []models.Account
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("is_internal").
+		Order("id").
+		Find(&accounts)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	return nil
+	return accounts, nil
 }
 
-func (r *accountRepository) GetToken(ctx context.Context, key string) (*string, error) {
-	result := r.redisClient.Get(ctx, key)
-	if result.Err() != nil {
-		return nil, result.Err()
+// UpdateOverdraft saves the overdraft limit and the overdraft rate of the account
+func (r *accountRepository) UpdateOverdraft(account models.Account) error {
+	result := r.db.Table(r.tableName).Where("id = ?", account.Id).Updates(map[string]interface{}{
+		"overdraft_limit": account.OverdraftLimit,
+		"overdraft_rate":  account.OverdraftRate,
+		"updated_by":      account.UpdatedBy,
+		"updated_at":      time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
 	}
-	val := result.Val()
-	return &val, nil
+	return nil
 }
 
-func (r *accountRepository) DeleteToken(ctx context.Context, key string) error {
-	result := r.redisClient.Del(ctx, key)
-	if result.Err() != nil {
-		return result.Err()
+// UpdateState saves the freeze and the closure of the account
+func (r *accountRepository) UpdateState(account models.Account) error {
+	result := r.db.Table(r.tableName).Where("id = ?", account.Id).Updates(map[string]interface{}{
+		"debits_frozen":  account.DebitsFrozen,
+		"credits_frozen": account.CreditsFrozen,
+		"closed_at":      account.ClosedAt,
+		"is_active":      account.IsActive,
+		"updated_by":     account.UpdatedBy,
+		"updated_at":     time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
 	}
 	return nil
 }
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/money"
+)
+
+func TestAccountRepository_FindByIBAN(t *testing.T) {
+	db := setupLedgerTest(t)
+
+	accounts := createTestAccounts(t, db, 1, money.Zero)
+	accountRepository := NewAccountRepository(db)
+
+	// The IBAN is stored without the spaces of the print format
+	account, err := accountRepository.Create(models.Account{
+		OwnerId:       accounts[0].OwnerId,
+		IBAN:          "tr33 0006 1005 1978 6457 8413 26",
+		AccountNumber: accounts[0].AccountNumber + 1,
+		Currency:      money.DefaultCurrency,
+		CreatedBy:     accounts[0].OwnerId,
+		UpdatedBy:     accounts[0].OwnerId,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM public.accounts WHERE id = ?", account.Id)
+	})
+	assert.Equal(t, "TR330006100519786457841326", account.IBAN)
+
+	found, err := accountRepository.FindByIBAN("TR33 0006 1005 1978 6457 8413 26")
+	require.NoError(t, err)
+	assert.Equal(t, account.Id, found.Id)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/db/models"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/account_event_repository_mock.go -package=repository tek-bank/internal/db/repository AccountEventRepository
+type AccountEventRepository interface {
+	Create(event models.AccountEvent) error
+	FindByAccountId(accountId string) ([]models.AccountEvent, error)
+
+	WithTx(trxHandle *gorm.DB) AccountEventRepository
+}
+
+type accountEventRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewAccountEventRepository(db *gorm.DB) AccountEventRepository {
+	var accountEvent models.AccountEvent
+	return &accountEventRepository{
+		db:        db,
+		tableName: accountEvent.TableName(),
+	}
+}
+
+func (r *accountEventRepository) WithTx(txHandle *gorm.DB) AccountEventRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *accountEventRepository) Create(event models.AccountEvent) error {
+	result := r.db.Table(r.tableName).Create(&event)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// FindByAccountId returns the events of the account, the oldest first
+func (r *accountEventRepository) FindByAccountId(accountId string) ([]models.AccountEvent, error) {
+	var events []models.AccountEvent
+	result := r.db.Table(r.tableName).Where("account_id = ?", accountId).Order("created_at, id").Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/beneficiary_repository_mock.go -package=repository tek-bank/internal/db/repository BeneficiaryRepository
+type BeneficiaryRepository interface {
+	Create(beneficiary models.Beneficiary) (*models.Beneficiary, error)
+	FindById(id string) (*models.Beneficiary, error)
+	FindByOwnerId(ownerId string) ([]models.Beneficiary, error)
+	LockById(id string) (*models.Beneficiary, error)
+	LockByTrustTokenHash(tokenHash string) (*models.Beneficiary, error)
+	Update(beneficiary models.Beneficiary) error
+	Delete(id string) error
+
+	WithTx(trxHandle *gorm.DB) BeneficiaryRepository
+}
+
+type beneficiaryRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewBeneficiaryRepository(db *gorm.DB) BeneficiaryRepository {
+	var beneficiary models.Beneficiary
+	return &beneficiaryRepository{
+		db:        db,
+		tableName: beneficiary.TableName(),
+	}
+}
+
+func (r *beneficiaryRepository) WithTx(txHandle *gorm.DB) BeneficiaryRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+// Create saves the beneficiary, ErrDuplicateBeneficiary is returned if the owner already saved the account
+func (r *beneficiaryRepository) Create(beneficiary models.Beneficiary) (*models.Beneficiary, error) {
+	// The insert runs in a savepoint, so a failed insert does not abort the transaction of the request
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Table(r.tableName).Omit(clause.Associations).Create(&beneficiary).Error
+	})
+	if isUniqueViolation(err, "owner_account") {
+		return nil, ErrDuplicateBeneficiary
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &beneficiary, nil
+}
+
+func (r *beneficiaryRepository) FindById(id string) (*models.Beneficiary, error) {
+	var beneficiary models.Beneficiary
+	result := r.db.Table(r.tableName).Where("id = ?", id).First(&beneficiary)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &beneficiary, nil
+}
+
+// FindByOwnerId returns the beneficiaries of the customer ordered by their nicknames
+func (r *beneficiaryRepository) FindByOwnerId(ownerId string) ([]models.Beneficiary, error) {
+	var beneficiaries []models.Beneficiary
+	result := r.db.Table(r.tableName).Where("owner_id = ?", ownerId).Order("lower(nickname), created_at").Find(&beneficiaries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return beneficiaries, nil
+}
+
+// LockById locks the beneficiary until the end of the transaction
+func (r *beneficiaryRepository) LockById(id string) (*models.Beneficiary, error) {
+	var beneficiary models.Beneficiary
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&beneficiary)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &beneficiary, nil
+}
+
+// LockByTrustTokenHash locks the beneficiary of the trust approval token until the end of the transaction
+func (r *beneficiaryRepository) LockByTrustTokenHash(tokenHash string) (*models.Beneficiary, error) {
+	var beneficiary models.Beneficiary
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("trust_token_hash = ?", tokenHash).
+		First(&beneficiary)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &beneficiary, nil
+}
+
+func (r *beneficiaryRepository) Update(beneficiary models.Beneficiary) error {
+	beneficiary.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&beneficiary)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *beneficiaryRepository) Delete(id string) error {
+	result := r.db.Table(r.tableName).Where("id = ?", id).Delete(&models.Beneficiary{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
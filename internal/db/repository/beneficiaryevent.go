@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/beneficiary_event_repository_mock.go -package=repository tek-bank/internal/db/repository BeneficiaryEventRepository
+type BeneficiaryEventRepository interface {
+	Create(event models.BeneficiaryEvent) error
+
+	WithTx(trxHandle *gorm.DB) BeneficiaryEventRepository
+}
+
+type beneficiaryEventRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewBeneficiaryEventRepository(db *gorm.DB) BeneficiaryEventRepository {
+	var beneficiaryEvent models.BeneficiaryEvent
+	return &beneficiaryEventRepository{
+		db:        db,
+		tableName: beneficiaryEvent.TableName(),
+	}
+}
+
+func (r *beneficiaryEventRepository) WithTx(txHandle *gorm.DB) BeneficiaryEventRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *beneficiaryEventRepository) Create(event models.BeneficiaryEvent) error {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&event)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/bulk_transfer_repository_mock.go -package=repository tek-bank/internal/db/repository BulkTransferRepository
+type BulkTransferRepository interface {
+	Create(bulkTransfer models.BulkTransfer) (*models.BulkTransfer, error)
+	FindById(id string) (*models.BulkTransfer, error)
+	FindByOwnerId(ownerId string) ([]models.BulkTransfer, error)
+	LockById(id string) (*models.BulkTransfer, error)
+	LockByTokenHash(tokenHash string) (*models.BulkTransfer, error)
+	LockExpired(now time.Time, limit int) ([]models.BulkTransfer, error)
+	Update(bulkTransfer models.BulkTransfer) error
+
+	WithTx(trxHandle *gorm.DB) BulkTransferRepository
+}
+
+type bulkTransferRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewBulkTransferRepository(db *gorm.DB) BulkTransferRepository {
+	var bulkTransfer models.BulkTransfer
+	return &bulkTransferRepository{
+		db:        db,
+		tableName: bulkTransfer.TableName(),
+	}
+}
+
+func (r *bulkTransferRepository) WithTx(txHandle *gorm.DB) BulkTransferRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *bulkTransferRepository) Create(bulkTransfer models.BulkTransfer) (*models.BulkTransfer, error) {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&bulkTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &bulkTransfer, nil
+}
+
+func (r *bulkTransferRepository) FindById(id string) (*models.BulkTransfer, error) {
+	var bulkTransfer models.BulkTransfer
+	result := r.db.Table(r.tableName).Where("id = ?", id).First(&bulkTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &bulkTransfer, nil
+}
+
+// FindByOwnerId returns the bulk transfers of the owner, the newest first
+func (r *bulkTransferRepository) FindByOwnerId(ownerId string) ([]models.BulkTransfer, error) {
+	var bulkTransfers []models.BulkTransfer
+	result := r.db.Table(r.tableName).
+		Where("owner_id = ?", ownerId).
+		Order("created_at DESC").
+		Find(&bulkTransfers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return bulkTransfers, nil
+}
+
+// LockById locks the bulk transfer until the end of the transaction, so its status can only be changed once
+func (r *bulkTransferRepository) LockById(id string) (*models.BulkTransfer, error) {
+	var bulkTransfer models.BulkTransfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&bulkTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &bulkTransfer, nil
+}
+
+// LockByTokenHash locks the bulk transfer of the approval token until the end of the transaction
+func (r *bulkTransferRepository) LockByTokenHash(tokenHash string) (*models.BulkTransfer, error) {
+	var bulkTransfer models.BulkTransfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&bulkTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &bulkTransfer, nil
+}
+
+// LockExpired locks the bulk transfers which were not approved in time, the locked ones are skipped
+func (r *bulkTransferRepository) LockExpired(now time.Time, limit int) ([]models.BulkTransfer, error) {
+	var bulkTransfers []models.BulkTransfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND expires_at <= ?", enum.BulkTransferPendingApproval, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&bulkTransfers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return bulkTransfers, nil
+}
+
+func (r *bulkTransferRepository) Update(bulkTransfer models.BulkTransfer) error {
+	bulkTransfer.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&bulkTransfer)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+)
+
+// ErrDuplicateNumber is returned when a generated number (account number, IBAN, customer number)
+// is already used, the caller should generate a new one and try again
+var ErrDuplicateNumber = errors.New("generated number is already used")
+
+// ErrDuplicateBeneficiary is returned when the customer already saved the account as a beneficiary
+var ErrDuplicateBeneficiary = errors.New("beneficiary is already saved")
+
+// ErrDuplicateReversal is returned when the entry was already reversed with the reference
+var ErrDuplicateReversal = errors.New("reversal reference is already used")
+
+// uniqueViolationCode is the error code of PostgreSQL for unique constraint violations
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation reports whether the error is a unique constraint violation on one of the columns
+func isUniqueViolation(err error, columns ...string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return false
+	}
+
+	for _, column := range columns {
+		if strings.Contains(pgErr.ConstraintName, column) {
+			return true
+		}
+	}
+
+	return false
+}
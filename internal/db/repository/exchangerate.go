@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/money"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/exchange_rate_repository_mock.go -package=repository tek-bank/internal/db/repository ExchangeRateRepository
+type ExchangeRateRepository interface {
+	FindAll() ([]models.ExchangeRate, error)
+	FindByCurrency(currency money.Currency) (*models.ExchangeRate, error)
+	Upsert(rates []models.ExchangeRate) error
+
+	WithTx(trxHandle *gorm.DB) ExchangeRateRepository
+}
+
+type exchangeRateRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	var exchangeRate models.ExchangeRate
+	return &exchangeRateRepository{
+		db:        db,
+		tableName: exchangeRate.TableName(),
+	}
+}
+
+func (r *exchangeRateRepository) WithTx(txHandle *gorm.DB) ExchangeRateRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *exchangeRateRepository) FindAll() ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	result := r.db.Table(r.tableName).Order("currency").Find(&rates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rates, nil
+}
+
+func (r *exchangeRateRepository) FindByCurrency(currency money.Currency) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	result := r.db.Table(r.tableName).Where("currency = ?", currency).First(&rate)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &rate, nil
+}
+
+// Upsert creates the rates or overwrites the existing rates of the same currencies
+func (r *exchangeRateRepository) Upsert(rates []models.ExchangeRate) error {
+	result := r.db.Table(r.tableName).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"rate":       gorm.Expr("excluded.rate"),
+			"updated_by": gorm.Expr("excluded.updated_by"),
+			"updated_at": gorm.Expr("current_timestamp"),
+		}),
+	}).Create(&rates)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
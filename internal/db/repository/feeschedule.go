@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/fee_schedule_repository_mock.go -package=repository tek-bank/internal/db/repository FeeScheduleRepository
+type FeeScheduleRepository interface {
+	FindAll() ([]models.FeeSchedule, error)
+	FindById(id string) (*models.FeeSchedule, error)
+	FindApplicable(segment string, accountType string) ([]models.FeeSchedule, error)
+	Upsert(schedule models.FeeSchedule) (*models.FeeSchedule, error)
+	Delete(id string) error
+
+	WithTx(trxHandle *gorm.DB) FeeScheduleRepository
+}
+
+type feeScheduleRepository struct {
+	db            *gorm.DB
+	tableName     string
+	tierTableName string
+}
+
+func NewFeeScheduleRepository(db *gorm.DB) FeeScheduleRepository {
+	var schedule models.FeeSchedule
+	var tier models.FeeTier
+	return &feeScheduleRepository{
+		db:            db,
+		tableName:     schedule.TableName(),
+		tierTableName: tier.TableName(),
+	}
+}
+
+func (r *feeScheduleRepository) WithTx(txHandle *gorm.DB) FeeScheduleRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+// withTiers loads the tiers of the schedules ordered by their upper bounds, the unbounded tier comes last
+func (r *feeScheduleRepository) withTiers() *gorm.DB {
+	return r.db.Table(r.tableName).Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("up_to = 0, up_to")
+	})
+}
+
+func (r *feeScheduleRepository) FindAll() ([]models.FeeSchedule, error) {
+	var schedules []models.FeeSchedule
+	result := r.withTiers().Order("segment, account_type").Find(&schedules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schedules, nil
+}
+
+func (r *feeScheduleRepository) FindById(id string) (*models.FeeSchedule, error) {
+	var schedule models.FeeSchedule
+	result := r.withTiers().Where("id = ?", id).First(&schedule)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+// FindApplicable returns the schedules of the segment and the account type and the schedules matching all of them
+func (r *feeScheduleRepository) FindApplicable(segment string, accountType string) ([]models.FeeSchedule, error) {
+	var schedules []models.FeeSchedule
+	result := r.withTiers().
+		Where("segment IN ? AND account_type IN ?", []string{segment, ""}, []string{accountType, ""}).
+		Find(&schedules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schedules, nil
+}
+
+// Upsert creates the schedule or overwrites the schedule of the same segment and account type, the tiers are replaced
+func (r *feeScheduleRepository) Upsert(schedule models.FeeSchedule) (*models.FeeSchedule, error) {
+	tiers := schedule.Tiers
+	schedule.Tiers = nil
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(r.tableName).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "segment"}, {Name: "account_type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"name":              gorm.Expr("excluded.name"),
+				"type":              gorm.Expr("excluded.type"),
+				"flat_fee":          gorm.Expr("excluded.flat_fee"),
+				"rate":              gorm.Expr("excluded.rate"),
+				"min_fee":           gorm.Expr("excluded.min_fee"),
+				"max_fee":           gorm.Expr("excluded.max_fee"),
+				"free_own_accounts": gorm.Expr("excluded.free_own_accounts"),
+				"updated_by":        gorm.Expr("excluded.updated_by"),
+				"updated_at":        gorm.Expr("current_timestamp"),
+			}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Create(&schedule)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Table(r.tierTableName).Where("schedule_id = ?", schedule.Id).Delete(&models.FeeTier{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if len(tiers) == 0 {
+			return nil
+		}
+
+		for i := range tiers {
+			tiers[i].ScheduleId = schedule.Id
+		}
+
+		return tx.Table(r.tierTableName).Create(&tiers).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Tiers = tiers
+	return &schedule, nil
+}
+
+func (r *feeScheduleRepository) Delete(id string) error {
+	result := r.db.Table(r.tableName).Where("id = ?", id).Delete(&models.FeeSchedule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
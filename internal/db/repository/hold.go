@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/hold_repository_mock.go -package=repository tek-bank/internal/db/repository HoldRepository
+type HoldRepository interface {
+	Create(hold models.Hold) (*models.Hold, error)
+	LockById(id string) (*models.Hold, error)
+	FindByAccountId(accountId string) ([]models.Hold, error)
+	SumActive(accountIds []string, now time.Time) (map[string]money.Amount, error)
+	Update(hold models.Hold) error
+	ReleaseByTransferId(transferId string, status string, reason string, now time.Time) error
+	ReleaseExpired(now time.Time) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) HoldRepository
+}
+
+type holdRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	var hold models.Hold
+	return &holdRepository{
+		db:        db,
+		tableName: hold.TableName(),
+	}
+}
+
+func (r *holdRepository) WithTx(txHandle *gorm.DB) HoldRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *holdRepository) Create(hold models.Hold) (*models.Hold, error) {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&hold)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &hold, nil
+}
+
+// LockById locks the hold with SELECT ... FOR UPDATE until the end of the transaction
+func (r *holdRepository) LockById(id string) (*models.Hold, error) {
+	var hold models.Hold
+	result := r.db.Table(r.tableName).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Account").Where("id = ?", id).First(&hold)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &hold, nil
+}
+
+// FindByAccountId returns the holds of the account, the newest first
+func (r *holdRepository) FindByAccountId(accountId string) ([]models.Hold, error) {
+	var holds []models.Hold
+	result := r.db.Table(r.tableName).Where("account_id = ?", accountId).Order("created_at DESC, id").Find(&holds)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return holds, nil
+}
+
+// SumActive returns the held amount of every account, a hold is active until it is released or expires.
+// Accounts without active holds are not in the result.
+func (r *holdRepository) SumActive(accountIds []string, now time.Time) (map[string]money.Amount, error) {
+	var rows []struct {
+		AccountId string
+		Total     money.Amount
+	}
+	result := r.db.Table(r.tableName).
+		Select("account_id, SUM(amount) AS total").
+		Where("account_id IN ? AND status = ?", accountIds, enum.HoldActive).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Group("account_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	totals := make(map[string]money.Amount, len(rows))
+	for _, row := range rows {
+		totals[row.AccountId] = row.Total
+	}
+	return totals, nil
+}
+
+func (r *holdRepository) Update(hold models.Hold) error {
+	hold.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&hold)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// ReleaseByTransferId ends the active hold of the transfer with the status
+func (r *holdRepository) ReleaseByTransferId(transferId string, status string, reason string, now time.Time) error {
+	result := r.db.Table(r.tableName).
+		Where("transfer_id = ? AND status = ?", transferId, enum.HoldActive).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"release_reason": reason,
+			"released_at":    now,
+			"updated_at":     now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// ReleaseExpired marks the active holds which reached their expiry time as expired and returns how many there were
+func (r *holdRepository) ReleaseExpired(now time.Time) (int64, error) {
+	result := r.db.Table(r.tableName).
+		Where("status = ? AND expires_at <= ?", enum.HoldActive, now).
+		Updates(map[string]interface{}{
+			"status":      enum.HoldExpired,
+			"released_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// IdempotencyRecord is what is kept for an idempotency key. It is saved without a response
+// when the request starts and completed with the response when the request ends.
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+//go:generate mockgen -destination=../../mocks/repository/idempotency_repository_mock.go -package=repository tek-bank/internal/db/repository IdempotencyRepository
+type IdempotencyRepository interface {
+	// Reserve saves the record only if the key is not used yet and reports whether it was saved
+	Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (*IdempotencyRecord, error)
+	Save(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type idempotencyRepository struct {
+	redisClient *redis.Client
+	prefix      string
+}
+
+func NewIdempotencyRepository(client *redis.Client) IdempotencyRepository {
+	return &idempotencyRepository{
+		redisClient: client,
+		prefix:      "idempotency:",
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (bool, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+
+	return r.redisClient.SetNX(ctx, r.prefix+key, value, ttl).Result()
+}
+
+// Get returns nil without an error if the key is not used
+func (r *idempotencyRepository) Get(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	value, err := r.redisClient.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Save(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.Set(ctx, r.prefix+key, value, ttl).Err()
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, key string) error {
+	return r.redisClient.Del(ctx, r.prefix+key).Err()
+}
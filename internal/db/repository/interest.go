@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+// OverdrawnDay is a day an account ended below zero and the overdraft interest of which is not charged yet
+type OverdrawnDay struct {
+	AccountId string
+	Day       time.Time
+}
+
+//go:generate mockgen -destination=../../mocks/repository/interest_repository_mock.go -package=repository tek-bank/internal/db/repository InterestRepository
+type InterestRepository interface {
+	FindRates() ([]models.InterestRate, error)
+	UpsertRate(rate models.InterestRate) (*models.InterestRate, error)
+	DeleteRate(id string) error
+	FindAccrualStart() (*time.Time, error)
+	Accrue(day time.Time, end time.Time) (int64, error)
+	FindUnpaid(accountIds []string) ([]models.InterestAccrual, error)
+	FindDue(before time.Time, limit int) ([]string, error)
+	LockUnpaid(accountId string, before time.Time) ([]models.InterestAccrual, error)
+	MarkPaid(ids []string, journalId string, paidAt time.Time) error
+	FindOverdraftStart() (*time.Time, error)
+	FindOverdrawn(day time.Time, end time.Time, limit int) ([]OverdrawnDay, error)
+	MarkOverdraftChecked(day time.Time, end time.Time) error
+	CreateCharge(charge models.InterestAccrual) (string, error)
+
+	WithTx(trxHandle *gorm.DB) InterestRepository
+}
+
+type interestRepository struct {
+	db               *gorm.DB
+	rateTableName    string
+	changeTableName  string
+	accrualTableName string
+	accountTableName string
+	postingTableName string
+}
+
+func NewInterestRepository(db *gorm.DB) InterestRepository {
+	var rate models.InterestRate
+	var change models.InterestRateChange
+	var accrual models.InterestAccrual
+	var account models.Account
+	var posting models.Posting
+	return &interestRepository{
+		db:               db,
+		rateTableName:    rate.TableName(),
+		changeTableName:  change.TableName(),
+		accrualTableName: accrual.TableName(),
+		accountTableName: account.TableName(),
+		postingTableName: posting.TableName(),
+	}
+}
+
+func (r *interestRepository) WithTx(txHandle *gorm.DB) InterestRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *interestRepository) FindRates() ([]models.InterestRate, error) {
+	var rates []models.InterestRate
+	result := r.db.Table(r.rateTableName).Order("account_type, currency").Find(&rates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rates, nil
+}
+
+// UpsertRate creates the rate or overwrites the rate of the same account type and currency,
+// the change is kept with the time it takes effect
+func (r *interestRepository) UpsertRate(rate models.InterestRate) (*models.InterestRate, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(r.rateTableName).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "account_type"}, {Name: "currency"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"rate":       gorm.Expr("excluded.rate"),
+				"updated_by": gorm.Expr("excluded.updated_by"),
+				"updated_at": gorm.Expr("current_timestamp"),
+			}),
+		}, clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Create(&rate)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Table(r.changeTableName).Create(&models.InterestRateChange{
+			AccountType: rate.AccountType,
+			Currency:    rate.Currency,
+			Rate:        rate.Rate,
+			UpdatedBy:   rate.UpdatedBy,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// DeleteRate removes the rate, the removal is kept as a change without a rate
+func (r *interestRepository) DeleteRate(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var rates []models.InterestRate
+		result := tx.Table(r.rateTableName).Clauses(clause.Returning{}).Where("id = ?", id).Delete(&rates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(rates) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Table(r.changeTableName).Create(&models.InterestRateChange{
+			AccountType: rates[0].AccountType,
+			Currency:    rates[0].Currency,
+		}).Error
+	})
+}
+
+// FindAccrualStart returns the first day the interest of a customer account is not accrued for,
+// nil is returned if there are no customer accounts
+func (r *interestRepository) FindAccrualStart() (*time.Time, error) {
+	return r.findStart("NOT is_internal", "interest_accrued_on")
+}
+
+// FindOverdraftStart returns the first day the overdraft interest of an account with an overdraft rate is not
+// charged for, nil is returned if there are no such accounts
+func (r *interestRepository) FindOverdraftStart() (*time.Time, error) {
+	return r.findStart("NOT is_internal AND overdraft_rate > 0", "overdraft_checked_on")
+}
+
+// findStart returns the earliest day after the last day in the column of the accounts, accounts without it
+// start on the day they are created
+func (r *interestRepository) findStart(condition string, column string) (*time.Time, error) {
+	var start sql.NullTime
+	result := r.db.Raw(
+		"SELECT MIN(COALESCE(" + column + " + 1, created_at::date)) FROM " + r.accountTableName + " WHERE " + condition,
+	).Scan(&start)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if !start.Valid {
+		return nil, nil
+	}
+
+	day := time.Date(start.Time.Year(), start.Time.Month(), start.Time.Day(), 0, 0, 0, 0, time.Local)
+	return &day, nil
+}
+
+// Accrue stores the interest of the day for the open customer accounts with a positive balance at the end of the day
+// and the rate in force at the end of the day, end is the start of the next day. The day is marked as accrued on every
+// customer account, the accounts which are accrued for the day already are left out, so it can be run again for the same day.
+func (r *interestRepository) Accrue(day time.Time, end time.Time) (int64, error) {
+	var count int64
+	result := r.db.Raw(
+		"WITH pending AS ("+
+			"SELECT a.id, a.type, a.currency, a.balance, a.is_active FROM "+r.accountTableName+" AS a "+
+			"WHERE NOT a.is_internal AND a.created_at < @end AND (a.interest_accrued_on IS NULL OR a.interest_accrued_on < @day)"+
+			"), accrued AS ("+
+			"INSERT INTO "+r.accrualTableName+" (id, account_id, day, kind, balance, rate, created_at) "+
+			"SELECT gen_random_uuid(), b.id, @day, @kind, b.balance, b.rate, current_timestamp FROM ("+
+			"SELECT p.id, p.balance - COALESCE((SELECT SUM(po.amount) FROM "+r.postingTableName+" AS po WHERE po.account_id = p.id AND po.created_at >= @end), 0) AS balance, rc.rate "+
+			"FROM pending AS p "+
+			"JOIN LATERAL (SELECT c.rate FROM "+r.changeTableName+" AS c WHERE c.account_type = p.type AND c.currency = p.currency AND c.effective_from < @end "+
+			"ORDER BY c.effective_from DESC LIMIT 1) AS rc ON rc.rate > 0 "+
+			"WHERE p.is_active"+
+			") AS b WHERE b.balance > 0 "+
+			"ON CONFLICT (account_id, day) DO NOTHING RETURNING id"+
+			"), marked AS ("+
+			"UPDATE "+r.accountTableName+" SET interest_accrued_on = @day WHERE id IN (SELECT id FROM pending)"+
+			") SELECT COUNT(*) FROM accrued",
+		sql.Named("day", day.Format("2006-01-02")),
+		sql.Named("end", end),
+		sql.Named("kind", enum.AccrualKindInterest),
+	).Scan(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// FindOverdrawn returns the accounts with an overdraft rate which ended the day below zero and are not charged for
+// the day yet. The balance at the end of the day is the current balance without the postings after it.
+func (r *interestRepository) FindOverdrawn(day time.Time, end time.Time, limit int) ([]OverdrawnDay, error) {
+	var ids []string
+	result := r.db.Raw(
+		"SELECT b.id FROM ("+
+			"SELECT a.id, a.balance - COALESCE((SELECT SUM(p.amount) FROM "+r.postingTableName+" AS p WHERE p.account_id = a.id AND p.created_at >= @end), 0) AS balance "+
+			"FROM "+r.accountTableName+" AS a "+
+			"WHERE NOT a.is_internal AND a.overdraft_rate > 0 AND a.created_at < @end "+
+			"AND (a.overdraft_checked_on IS NULL OR a.overdraft_checked_on < @day) "+
+			"AND NOT EXISTS (SELECT 1 FROM "+r.accrualTableName+" AS ia WHERE ia.account_id = a.id AND ia.day = @day AND ia.kind = @kind)"+
+			") AS b WHERE b.balance < 0 ORDER BY b.id LIMIT @limit",
+		sql.Named("day", day.Format("2006-01-02")),
+		sql.Named("end", end),
+		sql.Named("kind", enum.AccrualKindOverdraft),
+		sql.Named("limit", limit),
+	).Scan(&ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	days := make([]OverdrawnDay, 0, len(ids))
+	for _, id := range ids {
+		days = append(days, OverdrawnDay{AccountId: id, Day: day})
+	}
+	return days, nil
+}
+
+// MarkOverdraftChecked marks the day as charged on the accounts with an overdraft rate, it is called once
+// no account is left to be charged for the day
+func (r *interestRepository) MarkOverdraftChecked(day time.Time, end time.Time) error {
+	result := r.db.Exec(
+		"UPDATE "+r.accountTableName+" SET overdraft_checked_on = @day "+
+			"WHERE NOT is_internal AND overdraft_rate > 0 AND created_at < @end "+
+			"AND (overdraft_checked_on IS NULL OR overdraft_checked_on < @day)",
+		sql.Named("day", day.Format("2006-01-02")),
+		sql.Named("end", end),
+	)
+	return result.Error
+}
+
+// CreateCharge saves the overdraft interest charge of a day as an accrual of the overdraft kind and returns its id,
+// the id is empty if the day of the account is charged already
+func (r *interestRepository) CreateCharge(charge models.InterestAccrual) (string, error) {
+	var ids []string
+	result := r.db.Raw(
+		"INSERT INTO "+r.accrualTableName+" (id, account_id, day, kind, balance, rate, paid_at, created_at) "+
+			"VALUES (gen_random_uuid(), @account, @day, @kind, @balance, @rate, @paid, current_timestamp) "+
+			"ON CONFLICT (account_id, day) DO NOTHING RETURNING id",
+		sql.Named("account", charge.AccountId),
+		sql.Named("day", charge.Day.Format("2006-01-02")),
+		sql.Named("kind", enum.AccrualKindOverdraft),
+		sql.Named("balance", charge.Balance),
+		sql.Named("rate", charge.Rate),
+		sql.Named("paid", charge.PaidAt),
+	).Scan(&ids)
+	if result.Error != nil {
+		return "", result.Error
+	}
+
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return ids[0], nil
+}
+
+// FindUnpaid returns the interest accruals of the accounts which are not paid yet, overdraft charges are left out
+func (r *interestRepository) FindUnpaid(accountIds []string) ([]models.InterestAccrual, error) {
+	var accruals []models.InterestAccrual
+	if len(accountIds) == 0 {
+		return accruals, nil
+	}
+
+	result := r.db.Table(r.accrualTableName).
+		Where("account_id IN ? AND kind = ? AND paid_at IS NULL", accountIds, enum.AccrualKindInterest).
+		Order("day").
+		Find(&accruals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return accruals, nil
+}
+
+// FindDue returns the ids of the accounts with unpaid interest accruals of the days before the given day
+func (r *interestRepository) FindDue(before time.Time, limit int) ([]string, error) {
+	var ids []string
+	result := r.db.Table(r.accrualTableName).
+		Distinct("account_id").
+		Where("kind = ? AND paid_at IS NULL AND day < ?", enum.AccrualKindInterest, before.Format("2006-01-02")).
+		Order("account_id").
+		Limit(limit).
+		Pluck("account_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+// LockUnpaid locks the unpaid interest accruals of the account of the days before the given day until the end of the transaction
+func (r *interestRepository) LockUnpaid(accountId string, before time.Time) ([]models.InterestAccrual, error) {
+	var accruals []models.InterestAccrual
+	result := r.db.Table(r.accrualTableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id = ? AND kind = ? AND paid_at IS NULL AND day < ?", accountId, enum.AccrualKindInterest, before.Format("2006-01-02")).
+		Order("day").
+		Find(&accruals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return accruals, nil
+}
+
+// MarkPaid marks the accruals as paid with the journal of the payment
+func (r *interestRepository) MarkPaid(ids []string, journalId string, paidAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var journal interface{}
+	if journalId != "" {
+		journal = journalId
+	}
+
+	result := r.db.Table(r.accrualTableName).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"journal_id": journal,
+		"paid_at":    paidAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+func TestInterestRepository_ChargesAreNotInterest(t *testing.T) {
+	db := setupLedgerTest(t)
+
+	accounts := createTestAccounts(t, db, 1, money.Zero)
+	interestRepository := NewInterestRepository(db)
+
+	// An interest accrual and an overdraft charge of the last month, the charge is left unpaid on purpose
+	day := time.Now().AddDate(0, -1, 0)
+	accrual := models.InterestAccrual{
+		AccountId: accounts[0].Id,
+		Day:       day,
+		Kind:      enum.AccrualKindInterest,
+		Balance:   money.MustParse("1000"),
+		Rate:      money.MustParseRate("0.35"),
+	}
+	require.NoError(t, db.Create(&accrual).Error)
+
+	chargeId, err := interestRepository.CreateCharge(models.InterestAccrual{
+		AccountId: accounts[0].Id,
+		Day:       day.AddDate(0, 0, 1),
+		Balance:   -money.MustParse("500"),
+		Rate:      money.MustParseRate("0.6"),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, chargeId)
+
+	// The accrued interest of the profile does not show the charge
+	unpaid, err := interestRepository.FindUnpaid([]string{accounts[0].Id})
+	require.NoError(t, err)
+	require.Len(t, unpaid, 1)
+	assert.Equal(t, accrual.Id, unpaid[0].Id)
+
+	// The monthly payout does not pay the charge
+	locked, err := interestRepository.LockUnpaid(accounts[0].Id, time.Now())
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, accrual.Id, locked[0].Id)
+
+	require.NoError(t, interestRepository.MarkPaid([]string{accrual.Id}, "", time.Now()))
+
+	due, err := interestRepository.FindDue(time.Now(), 1000)
+	require.NoError(t, err)
+	assert.NotContains(t, due, accounts[0].Id)
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+var (
+	ErrUnbalancedJournal   = errors.New("journal is not balanced")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrCurrencyMismatch    = errors.New("posting currency does not match the account currency")
+)
+
+// DailyTotal is the sum of the postings of a day in one currency
+type DailyTotal struct {
+	Day      time.Time
+	Currency money.Currency
+	Amount   money.Amount
+}
+
+// AccountPosting is a posting of an account with the type and the description of its journal
+type AccountPosting struct {
+	Id          string
+	JournalId   string
+	JournalType string
+	Description string
+	Amount      money.Amount
+	Currency    money.Currency
+	CreatedAt   time.Time
+}
+
+//go:generate mockgen -destination=../../mocks/repository/ledger_repository_mock.go -package=repository tek-bank/internal/db/repository LedgerRepository
+type LedgerRepository interface {
+	Post(journal models.Journal) (*models.Journal, error)
+	SumPostings(accountId string, journalType string, since time.Time) (money.Amount, error)
+	SumInternalByDay(internalCode string, from time.Time, to time.Time) ([]DailyTotal, error)
+	FindPostings(accountId string, from time.Time, to time.Time) ([]AccountPosting, error)
+	FindPostingsByJournalIds(accountId string, journalIds []string) ([]AccountPosting, error)
+	BalanceAt(accountId string, at time.Time) (money.Amount, error)
+	CreateSnapshots(at time.Time) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) LedgerRepository
+}
+
+type ledgerRepository struct {
+	db                *gorm.DB
+	tableName         string
+	postingTableName  string
+	accountTableName  string
+	snapshotTableName string
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	var journal models.Journal
+	var posting models.Posting
+	var account models.Account
+	var snapshot models.BalanceSnapshot
+	return &ledgerRepository{
+		db:                db,
+		tableName:         journal.TableName(),
+		postingTableName:  posting.TableName(),
+		accountTableName:  account.TableName(),
+		snapshotTableName: snapshot.TableName(),
+	}
+}
+
+func (r *ledgerRepository) WithTx(txHandle *gorm.DB) LedgerRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+// Post writes the journal with its postings and applies every posting to the balance
+// of its account in the same transaction. Customer accounts can not go below their overdraft limit (zero without
+// an overdraft) unless the posting allows it, in that case ErrInsufficientBalance is returned and nothing is written.
+func (r *ledgerRepository) Post(journal models.Journal) (*models.Journal, error) {
+	if len(journal.Postings) < 2 {
+		return nil, ErrUnbalancedJournal
+	}
+
+	// Every currency has to balance on its own, exchanges go over the fx position accounts
+	totals := make(map[money.Currency]money.Amount)
+	accountIds := make([]string, 0, len(journal.Postings))
+	for _, posting := range journal.Postings {
+		totals[posting.Currency] += posting.Amount
+		accountIds = append(accountIds, posting.AccountId)
+	}
+
+	for _, total := range totals {
+		if !total.IsZero() {
+			return nil, ErrUnbalancedJournal
+		}
+	}
+
+	postings := journal.Postings
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock every account of the journal in the same order as AccountRepository.Lock
+		var accounts []models.Account
+		result := tx.Table(r.accountTableName).
+			Select("id", "currency").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", accountIds).
+			Order("is_internal").
+			Order("id").
+			Find(&accounts)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		currencies := make(map[string]money.Currency, len(accounts))
+		for _, account := range accounts {
+			currencies[account.Id] = account.Currency
+		}
+
+		for _, posting := range postings {
+			if currencies[posting.AccountId] != posting.Currency {
+				return ErrCurrencyMismatch
+			}
+		}
+
+		result = tx.Table(r.tableName).Omit(clause.Associations).Create(&journal)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for i := range postings {
+			postings[i].JournalId = journal.Id
+			postings[i].CreatedBy = journal.CreatedBy
+		}
+
+		result = tx.Table(r.postingTableName).Omit(clause.Associations).Create(&postings)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// The balance is changed relatively, so concurrent postings to the same account never overwrite each other
+		for _, posting := range postings {
+			result = tx.Table(r.accountTableName).
+				Where("id = ? AND (is_internal OR ? OR balance + ? >= -overdraft_limit)", posting.AccountId, posting.AllowNegative, posting.Amount).
+				Updates(map[string]interface{}{
+					"balance":    gorm.Expr("balance + ?", posting.Amount),
+					"updated_at": gorm.Expr("current_timestamp"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return ErrInsufficientBalance
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	journal.Postings = postings
+	return &journal, nil
+}
+
+// SumPostings returns the sum of the postings of the account in journals of the type since the given time
+func (r *ledgerRepository) SumPostings(accountId string, journalType string, since time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Table(r.postingTableName+" AS p").
+		Select("COALESCE(SUM(p.amount), 0)").
+		Joins("JOIN "+r.tableName+" AS j ON j.id = p.journal_id").
+		Where("p.account_id = ? AND j.type = ? AND p.created_at >= ?", accountId, journalType, since).
+		Row().
+		Scan(&total)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	return total, nil
+}
+
+// SumInternalByDay returns the daily sums of the postings to the internal accounts of the code between from and to,
+// to is exclusive. The days are in the time zone of the database.
+func (r *ledgerRepository) SumInternalByDay(internalCode string, from time.Time, to time.Time) ([]DailyTotal, error) {
+	var totals []DailyTotal
+	result := r.db.Table(r.postingTableName+" AS p").
+		Select("DATE(p.created_at) AS day, p.currency AS currency, SUM(p.amount) AS amount").
+		Joins("JOIN "+r.accountTableName+" AS a ON a.id = p.account_id").
+		Where("a.is_internal AND a.internal_code = ? AND p.created_at >= ? AND p.created_at < ?", internalCode, from, to).
+		Group("DATE(p.created_at), p.currency").
+		Order("day, currency").
+		Scan(&totals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return totals, nil
+}
+
+// FindPostings returns the postings of the account between from and to in the order they were posted, to is exclusive
+func (r *ledgerRepository) FindPostings(accountId string, from time.Time, to time.Time) ([]AccountPosting, error) {
+	var postings []AccountPosting
+	result := r.db.Table(r.postingTableName+" AS p").
+		Select("p.id, p.journal_id, j.type AS journal_type, j.description, p.amount, p.currency, p.created_at").
+		Joins("JOIN "+r.tableName+" AS j ON j.id = p.journal_id").
+		Where("p.account_id = ? AND p.created_at >= ? AND p.created_at < ?", accountId, from, to).
+		Order("p.created_at, p.id").
+		Scan(&postings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postings, nil
+}
+
+// FindPostingsByJournalIds returns the postings of the account in the journals
+func (r *ledgerRepository) FindPostingsByJournalIds(accountId string, journalIds []string) ([]AccountPosting, error) {
+	var postings []AccountPosting
+	if len(journalIds) == 0 {
+		return postings, nil
+	}
+
+	result := r.db.Table(r.postingTableName+" AS p").
+		Select("p.id, p.journal_id, j.type AS journal_type, j.description, p.amount, p.currency, p.created_at").
+		Joins("JOIN "+r.tableName+" AS j ON j.id = p.journal_id").
+		Where("p.account_id = ? AND p.journal_id IN ?", accountId, journalIds).
+		Order("p.created_at, p.id").
+		Scan(&postings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postings, nil
+}
+
+// BalanceAt returns the balance of the account right before the given time. It is the latest snapshot before the time
+// with the postings after it. Without a snapshot it is calculated back from the current balance, so the money which
+// was on the account before it was kept in the ledger is counted too.
+func (r *ledgerRepository) BalanceAt(accountId string, at time.Time) (money.Amount, error) {
+	var snapshot models.BalanceSnapshot
+	result := r.db.Table(r.snapshotTableName).
+		Where("account_id = ? AND at <= ?", accountId, at).
+		Order("at DESC").
+		Limit(1).
+		Find(&snapshot)
+	if result.Error != nil {
+		return money.Zero, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		var total money.Amount
+		err := r.db.Table(r.postingTableName).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("account_id = ? AND created_at >= ? AND created_at < ?", accountId, snapshot.At, at).
+			Row().
+			Scan(&total)
+		if err != nil {
+			return money.Zero, err
+		}
+
+		return snapshot.Balance + total, nil
+	}
+
+	var balance money.Amount
+	err := r.db.Table(r.accountTableName+" AS a").
+		Select("a.balance - COALESCE((SELECT SUM(p.amount) FROM "+r.postingTableName+" AS p WHERE p.account_id = a.id AND p.created_at >= ?), 0)", at).
+		Where("a.id = ?", accountId).
+		Row().
+		Scan(&balance)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	return balance, nil
+}
+
+// CreateSnapshots stores the balances of the accounts at the time and returns how many were stored. The accounts
+// which already have a snapshot at the time are left out, so it can be run again for the same time.
+func (r *ledgerRepository) CreateSnapshots(at time.Time) (int64, error) {
+	result := r.db.Exec(
+		"INSERT INTO "+r.snapshotTableName+" (id, account_id, at, balance, currency, created_at) "+
+			"SELECT gen_random_uuid(), a.id, @at, a.balance - COALESCE((SELECT SUM(p.amount) FROM "+r.postingTableName+" AS p WHERE p.account_id = a.id AND p.created_at >= @at), 0), a.currency, current_timestamp "+
+			"FROM "+r.accountTableName+" AS a "+
+			"WHERE a.created_at < @at AND NOT EXISTS (SELECT 1 FROM "+r.snapshotTableName+" AS s WHERE s.account_id = a.id AND s.at = @at) "+
+			"ON CONFLICT (account_id, at) DO NOTHING",
+		sql.Named("at", at),
+	)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"tek-bank/internal/db/connection"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+// setupLedgerTest connects to the PostgreSQL database given with the TEST_DB_* variables,
+// row locks can not be tested against mocks
+func setupLedgerTest(t *testing.T) *gorm.DB {
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST is not set, this test needs a PostgreSQL database")
+	}
+
+	db := connection.PostgresSQLConnection(connection.DatabaseConfig{
+		Host:     os.Getenv("TEST_DB_HOST"),
+		Username: os.Getenv("TEST_DB_USER"),
+		Password: os.Getenv("TEST_DB_PASSWORD"),
+		DBName:   os.Getenv("TEST_DB_NAME"),
+		Port:     os.Getenv("TEST_DB_PORT"),
+		AppName:  "tekbank-test",
+		SSLMode:  "disable",
+		Timezone: "UTC",
+	})
+	require.NotNil(t, db)
+
+	return db
+}
+
+func createTestAccounts(t *testing.T, db *gorm.DB, count int, balance money.Amount) []models.Account {
+	seed := rand.Int63n(1e9)
+
+	owner := models.User{
+		IdentityNumber: 9e10 + seed,
+		CustomerNumber: 9e10 + seed,
+		FirstName:      "Concurrency",
+		LastName:       "Test",
+		Email:          fmt.Sprintf("concurrency-%d@company.com", seed),
+		PhoneNumber:    uint64(9e10 + seed),
+		Password:       "!",
+	}
+	require.NoError(t, db.Create(&owner).Error)
+
+	var accounts []models.Account
+	for i := 0; i < count; i++ {
+		account := models.Account{
+			OwnerId:       owner.Id,
+			IBAN:          fmt.Sprintf("TEST-%d-%d", seed, i),
+			AccountNumber: 9e11 + seed*10 + int64(i),
+			Balance:       balance,
+			Currency:      money.DefaultCurrency,
+			CreatedBy:     owner.Id,
+			UpdatedBy:     owner.Id,
+		}
+		require.NoError(t, db.Create(&account).Error)
+		accounts = append(accounts, account)
+	}
+
+	t.Cleanup(func() {
+		var ids []string
+		for _, account := range accounts {
+			ids = append(ids, account.Id)
+		}
+
+		db.Exec("DELETE FROM public.journals WHERE id IN (SELECT journal_id FROM public.postings WHERE account_id IN ?)", ids)
+		db.Exec("DELETE FROM public.accounts WHERE id IN ?", ids)
+		db.Exec("DELETE FROM public.users WHERE id = ?", owner.Id)
+	})
+
+	return accounts
+}
+
+func TestLedgerRepository_ConcurrentTransfersConserveMoney(t *testing.T) {
+	db := setupLedgerTest(t)
+
+	initialBalance := money.MustParse("1000")
+	accounts := createTestAccounts(t, db, 4, initialBalance)
+
+	const workers = 8
+	const transfersPerWorker = 25
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded, rejected int
+	var failures []error
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			random := rand.New(rand.NewSource(int64(worker)))
+			for i := 0; i < transfersPerWorker; i++ {
+				from := accounts[random.Intn(len(accounts))]
+				to := accounts[random.Intn(len(accounts))]
+				if from.Id == to.Id {
+					continue
+				}
+
+				// Between 0.01 and 400.00, so some transfers run out of balance
+				amount := money.FromMinor(random.Int63n(40000) + 1)
+
+				err := db.Transaction(func(tx *gorm.DB) error {
+					accountRepository := NewAccountRepository(db).WithTx(tx)
+					ledgerRepository := NewLedgerRepository(db).WithTx(tx)
+
+					if _, err := accountRepository.Lock(from.Id, to.Id); err != nil {
+						return err
+					}
+
+					_, err := ledgerRepository.Post(models.Journal{
+						Type:      enum.JournalTypeTransfer,
+						CreatedBy: from.OwnerId,
+						UpdatedBy: from.OwnerId,
+						Postings: []models.Posting{
+							{AccountId: from.Id, Amount: -amount, Currency: money.DefaultCurrency},
+							{AccountId: to.Id, Amount: amount, Currency: money.DefaultCurrency},
+						},
+					})
+					return err
+				})
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					succeeded++
+				case errors.Is(err, ErrInsufficientBalance):
+					rejected++
+				default:
+					failures = append(failures, err)
+				}
+				mu.Unlock()
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	// Deadlocks or serialization errors would end up here
+	assert.Empty(t, failures)
+	assert.Greater(t, succeeded, 0)
+	t.Logf("%d transfers succeeded, %d were rejected for insufficient balance", succeeded, rejected)
+
+	var ids []string
+	for _, account := range accounts {
+		ids = append(ids, account.Id)
+	}
+
+	var reloaded []models.Account
+	require.NoError(t, db.Where("id IN ?", ids).Find(&reloaded).Error)
+
+	var total money.Amount
+	for _, account := range reloaded {
+		assert.False(t, account.Balance.IsNegative(), "account %d went below zero", account.AccountNumber)
+
+		// The balance always matches the postings of the account
+		var postings money.Amount
+		require.NoError(t, db.Model(&models.Posting{}).Select("COALESCE(SUM(amount), 0)").Where("account_id = ?", account.Id).Row().Scan(&postings))
+		assert.Equal(t, initialBalance+postings, account.Balance)
+
+		total += account.Balance
+	}
+
+	assert.Equal(t, initialBalance*money.Amount(len(accounts)), total)
+}
+
+func TestLedgerRepository_BalanceAt(t *testing.T) {
+	db := setupLedgerTest(t)
+
+	accounts := createTestAccounts(t, db, 2, money.MustParse("100"))
+	ledgerRepository := NewLedgerRepository(db)
+
+	transfer := func(amount money.Amount) {
+		_, err := ledgerRepository.Post(models.Journal{
+			Type:      enum.JournalTypeTransfer,
+			CreatedBy: accounts[0].OwnerId,
+			UpdatedBy: accounts[0].OwnerId,
+			Postings: []models.Posting{
+				{AccountId: accounts[0].Id, Amount: -amount, Currency: money.DefaultCurrency},
+				{AccountId: accounts[1].Id, Amount: amount, Currency: money.DefaultCurrency},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	transfer(money.MustParse("10"))
+	snapshotAt := time.Now().Truncate(time.Microsecond)
+	transfer(money.MustParse("25"))
+
+	// Without a snapshot the balance is calculated back from the current balance
+	balance, err := ledgerRepository.BalanceAt(accounts[0].Id, snapshotAt)
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("90"), balance)
+
+	count, err := ledgerRepository.CreateSnapshots(snapshotAt)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(2))
+
+	// Snapshots are only taken once per time
+	count, err = ledgerRepository.CreateSnapshots(snapshotAt)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	transfer(money.MustParse("5"))
+
+	balance, err = ledgerRepository.BalanceAt(accounts[0].Id, snapshotAt)
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("90"), balance)
+
+	balance, err = ledgerRepository.BalanceAt(accounts[1].Id, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("140"), balance)
+}
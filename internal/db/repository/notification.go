@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/notification_repository_mock.go -package=repository tek-bank/internal/db/repository NotificationRepository
+type NotificationRepository interface {
+	Create(notification models.Notification) error
+	LockPending(limit int) ([]models.Notification, error)
+	MarkSent(id string, sentAt time.Time) error
+	MarkFailed(id string, reason string) error
+
+	WithTx(trxHandle *gorm.DB) NotificationRepository
+}
+
+type notificationRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	var notification models.Notification
+	return &notificationRepository{
+		db:        db,
+		tableName: notification.TableName(),
+	}
+}
+
+func (r *notificationRepository) WithTx(txHandle *gorm.DB) NotificationRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *notificationRepository) Create(notification models.Notification) error {
+	return r.db.Table(r.tableName).Create(&notification).Error
+}
+
+// LockPending locks the notifications which are not sent yet and have attempts left, the oldest first.
+// Notifications locked by another worker are skipped.
+func (r *notificationRepository) LockPending(limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("sent_at IS NULL AND attempts < ?", enum.NotificationMaxAttempts).
+		Order("created_at").
+		Limit(limit).
+		Find(&notifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) MarkSent(id string, sentAt time.Time) error {
+	return r.db.Table(r.tableName).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts": gorm.Expr("attempts + 1"),
+		"sent_at":  sentAt,
+	}).Error
+}
+
+// MarkFailed counts the failed attempt with its reason, the notification is tried again on the next run
+func (r *notificationRepository) MarkFailed(id string, reason string) error {
+	return r.db.Table(r.tableName).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}).Error
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/payment_request_repository_mock.go -package=repository tek-bank/internal/db/repository PaymentRequestRepository
+type PaymentRequestRepository interface {
+	Create(paymentRequest models.PaymentRequest) (*models.PaymentRequest, error)
+	FindById(id string) (*models.PaymentRequest, error)
+	FindByRequesterId(requesterId string) ([]models.PaymentRequest, error)
+	FindPendingByPayerId(payerId string, now time.Time) ([]models.PaymentRequest, error)
+	CountByRequesterIdSince(requesterId string, since time.Time) (int64, error)
+	LockById(id string) (*models.PaymentRequest, error)
+	LockExpired(now time.Time, limit int) ([]models.PaymentRequest, error)
+	LockPaying(transferStatuses []string, limit int) ([]models.PaymentRequest, error)
+	Update(paymentRequest models.PaymentRequest) error
+
+	WithTx(trxHandle *gorm.DB) PaymentRequestRepository
+}
+
+type paymentRequestRepository struct {
+	db                *gorm.DB
+	tableName         string
+	transferTableName string
+}
+
+func NewPaymentRequestRepository(db *gorm.DB) PaymentRequestRepository {
+	var paymentRequest models.PaymentRequest
+	var transfer models.Transfer
+	return &paymentRequestRepository{
+		db:                db,
+		tableName:         paymentRequest.TableName(),
+		transferTableName: transfer.TableName(),
+	}
+}
+
+func (r *paymentRequestRepository) WithTx(txHandle *gorm.DB) PaymentRequestRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *paymentRequestRepository) Create(paymentRequest models.PaymentRequest) (*models.PaymentRequest, error) {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&paymentRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &paymentRequest, nil
+}
+
+// FindById returns the payment request with the requester and the payer
+func (r *paymentRequestRepository) FindById(id string) (*models.PaymentRequest, error) {
+	var paymentRequest models.PaymentRequest
+	result := r.db.Table(r.tableName).Preload("Requester").Preload("Payer").Where("id = ?", id).First(&paymentRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &paymentRequest, nil
+}
+
+// FindByRequesterId returns the payment requests the user sent with their payers, the newest first
+func (r *paymentRequestRepository) FindByRequesterId(requesterId string) ([]models.PaymentRequest, error) {
+	var paymentRequests []models.PaymentRequest
+	result := r.db.Table(r.tableName).Preload("Requester").Preload("Payer").
+		Where("requester_id = ?", requesterId).
+		Order("created_at DESC").
+		Find(&paymentRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return paymentRequests, nil
+}
+
+// FindPendingByPayerId returns the payment requests waiting for an answer of the user with their requesters, the newest first
+func (r *paymentRequestRepository) FindPendingByPayerId(payerId string, now time.Time) ([]models.PaymentRequest, error) {
+	var paymentRequests []models.PaymentRequest
+	result := r.db.Table(r.tableName).Preload("Requester").Preload("Payer").
+		Where("payer_id = ? AND status = ? AND expires_at > ?", payerId, enum.PaymentRequestPending, now).
+		Order("created_at DESC").
+		Find(&paymentRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return paymentRequests, nil
+}
+
+// CountByRequesterIdSince returns how many payment requests the user sent since the time, whatever their status
+func (r *paymentRequestRepository) CountByRequesterIdSince(requesterId string, since time.Time) (int64, error) {
+	var count int64
+	result := r.db.Table(r.tableName).
+		Where("requester_id = ? AND created_at >= ?", requesterId, since).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// LockById locks the payment request for its answer and returns it with the requester and the payer
+func (r *paymentRequestRepository) LockById(id string) (*models.PaymentRequest, error) {
+	var paymentRequest models.PaymentRequest
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Requester").Preload("Payer").
+		Where("id = ?", id).
+		First(&paymentRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &paymentRequest, nil
+}
+
+// LockExpired locks the pending payment requests which ran out with the requesters and the payers,
+// requests locked by an answer are skipped
+func (r *paymentRequestRepository) LockExpired(now time.Time, limit int) ([]models.PaymentRequest, error) {
+	var paymentRequests []models.PaymentRequest
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Requester").Preload("Payer").
+		Where("status = ? AND expires_at <= ?", enum.PaymentRequestPending, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&paymentRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return paymentRequests, nil
+}
+
+// LockPaying locks the payment requests being paid whose transfers have one of the statuses with the requesters
+// and the payers, requests locked by another worker are skipped
+func (r *paymentRequestRepository) LockPaying(transferStatuses []string, limit int) ([]models.PaymentRequest, error) {
+	var paymentRequests []models.PaymentRequest
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Requester").Preload("Payer").
+		Where("status = ?", enum.PaymentRequestPaying).
+		Where("transfer_id IN (SELECT id FROM "+r.transferTableName+" WHERE status IN ?)", transferStatuses).
+		Order("answered_at").
+		Limit(limit).
+		Find(&paymentRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return paymentRequests, nil
+}
+
+func (r *paymentRequestRepository) Update(paymentRequest models.PaymentRequest) error {
+	paymentRequest.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&paymentRequest)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/scheduled_transfer_repository_mock.go -package=repository tek-bank/internal/db/repository ScheduledTransferRepository
+type ScheduledTransferRepository interface {
+	Create(scheduledTransfer models.ScheduledTransfer) (*models.ScheduledTransfer, error)
+	FindById(id string) (*models.ScheduledTransfer, error)
+	FindByOwnerId(ownerId string) ([]models.ScheduledTransfer, error)
+	FindDueIds(now time.Time, limit int) ([]string, error)
+	LockDue(id string, now time.Time) (*models.ScheduledTransfer, error)
+	Update(scheduledTransfer models.ScheduledTransfer) error
+
+	WithTx(trxHandle *gorm.DB) ScheduledTransferRepository
+}
+
+type scheduledTransferRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewScheduledTransferRepository(db *gorm.DB) ScheduledTransferRepository {
+	var scheduledTransfer models.ScheduledTransfer
+	return &scheduledTransferRepository{
+		db:        db,
+		tableName: scheduledTransfer.TableName(),
+	}
+}
+
+func (r *scheduledTransferRepository) WithTx(txHandle *gorm.DB) ScheduledTransferRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *scheduledTransferRepository) Create(scheduledTransfer models.ScheduledTransfer) (*models.ScheduledTransfer, error) {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&scheduledTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &scheduledTransfer, nil
+}
+
+func (r *scheduledTransferRepository) FindById(id string) (*models.ScheduledTransfer, error) {
+	var scheduledTransfer models.ScheduledTransfer
+	result := r.db.Table(r.tableName).Where("id = ?", id).First(&scheduledTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &scheduledTransfer, nil
+}
+
+func (r *scheduledTransferRepository) FindByOwnerId(ownerId string) ([]models.ScheduledTransfer, error) {
+	var scheduledTransfers []models.ScheduledTransfer
+	result := r.db.Table(r.tableName).Where("owner_id = ?", ownerId).Order("created_at").Find(&scheduledTransfers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return scheduledTransfers, nil
+}
+
+// FindDueIds returns the ids of the active scheduled transfers which should have run until now, the oldest first
+func (r *scheduledTransferRepository) FindDueIds(now time.Time, limit int) ([]string, error) {
+	var ids []string
+	result := r.db.Table(r.tableName).
+		Where("status = ? AND next_run_at <= ?", enum.ScheduledTransferActive, now).
+		Order("next_run_at").
+		Limit(limit).
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+// LockDue locks the scheduled transfer if it is still due. Transfers locked by another worker are
+// skipped, gorm.ErrRecordNotFound is returned for them like for transfers which are not due anymore.
+func (r *scheduledTransferRepository) LockDue(id string, now time.Time) (*models.ScheduledTransfer, error) {
+	var scheduledTransfer models.ScheduledTransfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND status = ? AND next_run_at <= ?", id, enum.ScheduledTransferActive, now).
+		First(&scheduledTransfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &scheduledTransfer, nil
+}
+
+func (r *scheduledTransferRepository) Update(scheduledTransfer models.ScheduledTransfer) error {
+	scheduledTransfer.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&scheduledTransfer)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
@@ -1,14 +1,51 @@
 package repository
 
 import (
+	"github.com/gofiber/fiber/v2/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
 	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
 )
 
+// TransferHistoryFilter selects a page of the history of an account, the zero values do not filter
+type TransferHistoryFilter struct {
+	AccountNumber int64
+
+	// Only the entries after the cursor entry in the order of the history are returned
+	CursorCreatedAt time.Time
+	CursorId        string
+
+	// From is inclusive, To is exclusive
+	From time.Time
+	To   time.Time
+
+	// Direction is one of enum.HistoryDirection*
+	Direction string
+
+	// Amounts are compared in the currency of the account
+	MinAmount money.Amount
+	MaxAmount money.Amount
+
+	// Note is searched case-insensitively in the notes of the entries
+	Note string
+
+	Limit int
+}
+
 //go:generate mockgen -destination=../../mocks/repository/transfer_history_repository_mock.go -package=repository tek-bank/internal/db/repository TransferHistoryRepository
 type TransferHistoryRepository interface {
 	Create(transferHistory []models.TransferHistory) error
-	FetchByAccountNumber(accountNumber int64) ([]models.TransferHistory, error)
+	FetchByAccountNumber(filter TransferHistoryFilter) ([]models.TransferHistory, error)
+	LockById(id string) (*models.TransferHistory, error)
+	FindByJournalId(journalId string) ([]models.TransferHistory, error)
+	FindByJournalIds(journalIds []string) ([]models.TransferHistory, error)
+	FindReversals(id string) ([]models.TransferHistory, error)
+
+	WithTx(trxHandle *gorm.DB) TransferHistoryRepository
 }
 
 type transferHistoryRepository struct {
@@ -24,17 +61,123 @@ func NewTransferHistoryRepository(db *gorm.DB) TransferHistoryRepository {
 	}
 }
 
+func (d *transferHistoryRepository) WithTx(txHandle *gorm.DB) TransferHistoryRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return d
+	}
+	clone := *d
+	clone.db = txHandle
+	return &clone
+}
+
+// Create inserts the entries, ErrDuplicateReversal is returned if an entry was already reversed with the reference
 func (d *transferHistoryRepository) Create(transferHistory []models.TransferHistory) error {
 	result := d.db.Table(d.tableName).Create(&transferHistory)
+	if isUniqueViolation(result.Error, "reversal_reference") {
+		return ErrDuplicateReversal
+	}
 	if result.Error != nil {
 		return result.Error
 	}
 	return nil
 }
 
-func (d *transferHistoryRepository) FetchByAccountNumber(accountNumber int64) ([]models.TransferHistory, error) {
+// FetchByAccountNumber returns the entries of the account matching the filter with their accounts and owners,
+// newest first. The entries created at the same time are sorted by their id, so the order is stable between pages.
+func (d *transferHistoryRepository) FetchByAccountNumber(filter TransferHistoryFilter) ([]models.TransferHistory, error) {
+	accountNumber := filter.AccountNumber
+	query := d.db.Table(d.tableName).Preload("FromAccount.Owner").Preload("ToAccount.Owner")
+
+	switch filter.Direction {
+	case enum.HistoryDirectionIn:
+		query = query.Where("\"to\" = ?", accountNumber)
+	case enum.HistoryDirectionOut:
+		query = query.Where("\"from\" = ?", accountNumber)
+	default:
+		query = query.Where("(\"from\" = ? OR \"to\" = ?)", accountNumber, accountNumber)
+	}
+
+	if !filter.CursorCreatedAt.IsZero() {
+		query = query.Where("(created_at, id) < (?, ?)", filter.CursorCreatedAt, filter.CursorId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	// The receiver got the converted amount in its own currency
+	amount := "CASE WHEN \"from\" = ? THEN amount ELSE COALESCE(converted_amount, amount) END"
+	if !filter.MinAmount.IsZero() {
+		query = query.Where(amount+" >= ?", accountNumber, filter.MinAmount)
+	}
+	if !filter.MaxAmount.IsZero() {
+		query = query.Where(amount+" <= ?", accountNumber, filter.MaxAmount)
+	}
+
+	if filter.Note != "" {
+		query = query.Where("note ILIKE ?", "%"+likeEscaper.Replace(filter.Note)+"%")
+	}
+
+	var transferHistory []models.TransferHistory
+	result := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Find(&transferHistory)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transferHistory, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, so they are searched as text
+var likeEscaper = strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+
+// LockById locks the entry until the end of the transaction, so it can not be reversed twice at the same time
+func (d *transferHistoryRepository) LockById(id string) (*models.TransferHistory, error) {
+	var transferHistory models.TransferHistory
+	result := d.db.Table(d.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&transferHistory)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transferHistory, nil
+}
+
+// FindByJournalId returns the entries written for the money movement of the journal
+func (d *transferHistoryRepository) FindByJournalId(journalId string) ([]models.TransferHistory, error) {
+	var transferHistory []models.TransferHistory
+	result := d.db.Table(d.tableName).Where("journal_id = ?", journalId).Find(&transferHistory)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transferHistory, nil
+}
+
+// FindByJournalIds returns the entries of the journals with their accounts and owners
+func (d *transferHistoryRepository) FindByJournalIds(journalIds []string) ([]models.TransferHistory, error) {
+	var transferHistory []models.TransferHistory
+	if len(journalIds) == 0 {
+		return transferHistory, nil
+	}
+
+	result := d.db.Table(d.tableName).
+		Preload("FromAccount.Owner").
+		Preload("ToAccount.Owner").
+		Where("journal_id IN ?", journalIds).
+		Order("created_at, id").
+		Find(&transferHistory)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transferHistory, nil
+}
+
+// FindReversals returns the reversals and the fee refunds of the entry
+func (d *transferHistoryRepository) FindReversals(id string) ([]models.TransferHistory, error) {
 	var transferHistory []models.TransferHistory
-	result := d.db.Table(d.tableName).Where("\"from\" = ? OR \"to\" = ?", accountNumber, accountNumber).Find(&transferHistory)
+	result := d.db.Table(d.tableName).Where("reversal_of = ?", id).Order("created_at").Find(&transferHistory)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/transfer_limit_repository_mock.go -package=repository tek-bank/internal/db/repository TransferLimitRepository
+type TransferLimitRepository interface {
+	FindAll() ([]models.TransferLimit, error)
+	FindApplicable(ownerId string, accountNumber int64) ([]models.TransferLimit, error)
+	Upsert(limits []models.TransferLimit) error
+	Delete(id string) error
+
+	WithTx(trxHandle *gorm.DB) TransferLimitRepository
+}
+
+type transferLimitRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewTransferLimitRepository(db *gorm.DB) TransferLimitRepository {
+	var transferLimit models.TransferLimit
+	return &transferLimitRepository{
+		db:        db,
+		tableName: transferLimit.TableName(),
+	}
+}
+
+func (r *transferLimitRepository) WithTx(txHandle *gorm.DB) TransferLimitRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *transferLimitRepository) FindAll() ([]models.TransferLimit, error) {
+	var limits []models.TransferLimit
+	result := r.db.Table(r.tableName).Order("level, scope_id, period").Find(&limits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return limits, nil
+}
+
+// FindApplicable returns the global limits, the limits of the owner and the limits of the account
+func (r *transferLimitRepository) FindApplicable(ownerId string, accountNumber int64) ([]models.TransferLimit, error) {
+	var limits []models.TransferLimit
+	result := r.db.Table(r.tableName).
+		Where("level = ?", enum.LimitLevelGlobal).
+		Or("level = ? AND scope_id = ?", enum.LimitLevelCustomer, ownerId).
+		Or("level = ? AND scope_id = ?", enum.LimitLevelAccount, strconv.FormatInt(accountNumber, 10)).
+		Order("level, period").
+		Find(&limits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return limits, nil
+}
+
+// Upsert creates the limits or overwrites the existing limits of the same level, scope and period
+func (r *transferLimitRepository) Upsert(limits []models.TransferLimit) error {
+	result := r.db.Table(r.tableName).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "level"}, {Name: "scope_id"}, {Name: "period"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"amount":     gorm.Expr("excluded.amount"),
+			"updated_by": gorm.Expr("excluded.updated_by"),
+			"updated_at": gorm.Expr("current_timestamp"),
+		}),
+	}).Create(&limits)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *transferLimitRepository) Delete(id string) error {
+	result := r.db.Table(r.tableName).Where("id = ?", id).Delete(&models.TransferLimit{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
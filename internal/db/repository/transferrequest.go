@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tek-bank/internal/db/models"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+//go:generate mockgen -destination=../../mocks/repository/transfer_repository_mock.go -package=repository tek-bank/internal/db/repository TransferRepository
+type TransferRepository interface {
+	Create(transfer models.Transfer) (*models.Transfer, error)
+	FindPendingByOwnerId(ownerId string, now time.Time) ([]models.Transfer, error)
+	LockById(id string) (*models.Transfer, error)
+	LockByTokenHash(tokenHash string) (*models.Transfer, error)
+	Update(transfer models.Transfer) error
+	ExpirePending(now time.Time) (int64, error)
+	SumExecutedByOwner(ownerId string, since time.Time) (money.Amount, error)
+	SumExecutedByAccount(accountNumber int64, since time.Time) (money.Amount, error)
+	SumExecutedTrustedByOwner(ownerId string, since time.Time) (money.Amount, error)
+	FindByBulkTransferId(bulkTransferId string) ([]models.Transfer, error)
+	ApproveBulkRows(bulkTransferId string, now time.Time) (int64, error)
+	FindApprovedBulkRows(limit int) ([]string, error)
+	CountBulkRows(bulkTransferId string, status string) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) TransferRepository
+}
+
+type transferRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	var transfer models.Transfer
+	return &transferRepository{
+		db:        db,
+		tableName: transfer.TableName(),
+	}
+}
+
+func (r *transferRepository) WithTx(txHandle *gorm.DB) TransferRepository {
+	if txHandle == nil {
+		log.Error("Transaction not found")
+		return r
+	}
+	clone := *r
+	clone.db = txHandle
+	return &clone
+}
+
+func (r *transferRepository) Create(transfer models.Transfer) (*models.Transfer, error) {
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Create(&transfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transfer, nil
+}
+
+// FindPendingByOwnerId returns the transfers of the owner which can still be approved, the newest first.
+// The rows of bulk transfers are approved with their bulk transfer, they are not returned.
+func (r *transferRepository) FindPendingByOwnerId(ownerId string, now time.Time) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	result := r.db.Table(r.tableName).
+		Where("owner_id = ? AND status = ? AND expires_at > ? AND bulk_transfer_id IS NULL", ownerId, enum.TransferPendingApproval, now).
+		Order("created_at DESC").
+		Find(&transfers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transfers, nil
+}
+
+// LockById locks the transfer until the end of the transaction, so its status can only be changed once
+func (r *transferRepository) LockById(id string) (*models.Transfer, error) {
+	var transfer models.Transfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&transfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transfer, nil
+}
+
+// LockByTokenHash locks the transfer of the approval token until the end of the transaction
+func (r *transferRepository) LockByTokenHash(tokenHash string) (*models.Transfer, error) {
+	var transfer models.Transfer
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&transfer)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepository) Update(transfer models.Transfer) error {
+	transfer.UpdatedAt = time.Now()
+	result := r.db.Table(r.tableName).Omit(clause.Associations).Save(&transfer)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// ExpirePending expires the transfers which were not approved in time and returns how many were expired
+func (r *transferRepository) ExpirePending(now time.Time) (int64, error) {
+	result := r.db.Table(r.tableName).
+		Where("status = ? AND expires_at <= ?", enum.TransferPendingApproval, now).
+		Updates(map[string]interface{}{
+			"status":     enum.TransferExpired,
+			"expired_at": now,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// FindByBulkTransferId returns the rows of the bulk transfer in the order of its file
+func (r *transferRepository) FindByBulkTransferId(bulkTransferId string) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	result := r.db.Table(r.tableName).
+		Where("bulk_transfer_id = ?", bulkTransferId).
+		Order("bulk_row").
+		Find(&transfers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transfers, nil
+}
+
+// ApproveBulkRows approves the pending rows of the bulk transfer and returns how many were approved
+func (r *transferRepository) ApproveBulkRows(bulkTransferId string, now time.Time) (int64, error) {
+	result := r.db.Table(r.tableName).
+		Where("bulk_transfer_id = ? AND status = ?", bulkTransferId, enum.TransferPendingApproval).
+		Updates(map[string]interface{}{
+			"status":      enum.TransferApproved,
+			"approved_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// FindApprovedBulkRows returns the ids of the approved rows of bulk transfers which are not executed yet,
+// the oldest bulk transfers first and their rows in the order of the file
+func (r *transferRepository) FindApprovedBulkRows(limit int) ([]string, error) {
+	var ids []string
+	result := r.db.Table(r.tableName).
+		Where("bulk_transfer_id IS NOT NULL AND status = ?", enum.TransferApproved).
+		Order("approved_at, bulk_transfer_id, bulk_row").
+		Limit(limit).
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+// CountBulkRows returns how many rows of the bulk transfer have the status
+func (r *transferRepository) CountBulkRows(bulkTransferId string, status string) (int64, error) {
+	var count int64
+	result := r.db.Table(r.tableName).
+		Where("bulk_transfer_id = ? AND status = ?", bulkTransferId, status).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// SumExecutedByOwner returns the base currency amount the owner transferred since the given time
+func (r *transferRepository) SumExecutedByOwner(ownerId string, since time.Time) (money.Amount, error) {
+	return r.sumExecuted(since, "owner_id = ?", ownerId)
+}
+
+// SumExecutedByAccount returns the base currency amount transferred from the account since the given time
+func (r *transferRepository) SumExecutedByAccount(accountNumber int64, since time.Time) (money.Amount, error) {
+	return r.sumExecuted(since, "from_account_number = ?", accountNumber)
+}
+
+// SumExecutedTrustedByOwner returns the base currency amount the owner transferred to trusted beneficiaries
+// without an approval since the given time
+func (r *transferRepository) SumExecutedTrustedByOwner(ownerId string, since time.Time) (money.Amount, error) {
+	return r.sumExecuted(since, "owner_id = ? AND approval_method = ?", ownerId, enum.ApprovalTrustedBeneficiary)
+}
+
+func (r *transferRepository) sumExecuted(since time.Time, condition string, values ...interface{}) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Table(r.tableName).
+		Select("COALESCE(SUM(base_amount), 0)").
+		Where(condition, values...).
+		Where("status = ? AND executed_at >= ?", enum.TransferExecuted, since).
+		Row().
+		Scan(&total)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	return total, nil
+}
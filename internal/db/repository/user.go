@@ -5,7 +5,7 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
-	"sync"
+	"gorm.io/gorm/clause"
 	"tek-bank/internal/db/models"
 	"time"
 )
@@ -16,8 +16,12 @@ type UserRepository interface {
 	FindByID(id string) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	FindByUniqueIdentifier(uniqueIdentifier string) (*models.User, error)
+	FindByCustomerNumber(customerNumber int64) (*models.User, error)
 	Create(user models.User) (*models.User, error)
 	SoftDelete(id string) error
+	UpdateApprovalMethod(id string, approvalMethod string) error
+	UpdateSegment(id string, segment string) error
+	Lock(id string) error
 
 	SetTokenBlacklist(ctx *context.Context, key string, value string, exp time.Duration) error
 	GetTokenBlacklist(ctx *context.Context, key string) (string, error)
@@ -29,7 +33,6 @@ type userRepository struct {
 	db          *gorm.DB
 	redisClient *redis.Client
 	tableName   string
-	dbMutex     sync.Mutex
 }
 
 func NewUserRepository(db *gorm.DB) UserRepository {
@@ -45,8 +48,9 @@ func (d *userRepository) WithTx(txHandle *gorm.DB) UserRepository {
 		log.Error("Transaction not found")
 		return d
 	}
-	d.db = txHandle
-	return d
+	clone := *d
+	clone.db = txHandle
+	return &clone
 }
 
 func (r *userRepository) FindAll() ([]models.User, error) {
@@ -89,18 +93,32 @@ func (r *userRepository) FindByUniqueIdentifier(uniqueIdentifier string) (*model
 	return &user, nil
 }
 
-func (r *userRepository) Create(user models.User) (*models.User, error) {
-	result := r.db.Table(r.tableName).Create(&user)
+func (r *userRepository) FindByCustomerNumber(customerNumber int64) (*models.User, error) {
+	var user models.User
+	result := r.db.Table(r.tableName).Where("customer_number = ?", customerNumber).First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+
 	return &user, nil
 }
 
-func (r *userRepository) SoftDelete(id string) error {
-	r.dbMutex.Lock()
-	defer r.dbMutex.Unlock()
+// Create creates the user, ErrDuplicateNumber is returned if the customer number is already used
+func (r *userRepository) Create(user models.User) (*models.User, error) {
+	// The insert runs in a savepoint, so a failed insert does not abort the transaction of the request
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Table(r.tableName).Create(&user).Error
+	})
+	if isUniqueViolation(err, "customer_number") {
+		return nil, ErrDuplicateNumber
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
 
+func (r *userRepository) SoftDelete(id string) error {
 	result := r.db.Table(r.tableName).Where("id = ?", id).Update("is_active", false)
 	if result.Error != nil {
 		return result.Error
@@ -108,6 +126,44 @@ func (r *userRepository) SoftDelete(id string) error {
 	return nil
 }
 
+func (r *userRepository) UpdateApprovalMethod(id string, approvalMethod string) error {
+	result := r.db.Table(r.tableName).Where("id = ?", id).Updates(map[string]interface{}{
+		"approval_method": approvalMethod,
+		"updated_at":      time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Lock locks the user until the end of the transaction, so checks over all accounts of the user run one at a time.
+// The key is not locked, rows referencing the user can still be written.
+func (r *userRepository) Lock(id string) error {
+	var user models.User
+	result := r.db.Table(r.tableName).
+		Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Select("id").
+		Where("id = ?", id).
+		First(&user)
+	return result.Error
+}
+
+// UpdateSegment changes the segment of the customer, gorm.ErrRecordNotFound is returned for an unknown user
+func (r *userRepository) UpdateSegment(id string, segment string) error {
+	result := r.db.Table(r.tableName).Where("id = ?", id).Updates(map[string]interface{}{
+		"segment":    segment,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *userRepository) SetTokenBlacklist(ctx *context.Context, key string, value string, exp time.Duration) error {
 	err := r.redisClient.Set(*ctx, key, value, exp).Err()
 	if err != nil {
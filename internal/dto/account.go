@@ -1,10 +1,16 @@
 package dto
 
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
 type RegisterAccountRequest struct {
 	FirstName      string `json:"first_name"`
 	LastName       string `json:"last_name"`
 	Email          string `json:"email"`
 	ISOCountryCode string `json:"iso_country_code"`
+	Currency       string `json:"currency"`
 	IdentityNumber int64  `json:"identity_number"`
 	PhoneNumber    uint64 `json:"phone_number"`
 }
@@ -12,31 +18,112 @@ type RegisterAccountRequest struct {
 type CreateNewAccountRequest struct {
 	UserId         string `json:"user_id"`
 	ISOCountryCode string `json:"iso_country_code"`
+	Currency       string `json:"currency"`
+	Type           string `json:"type" enums:"current,savings,time_deposit"`
 }
 
 type CreateNewAccountResponse struct {
-	UserId        string  `json:"user_id"`
-	FirstName     string  `json:"first_name"`
-	LastName      string  `json:"last_name"`
-	AccountNumber int64   `json:"account_number"`
-	IBAN          string  `json:"iban"`
-	Balance       float64 `json:"balance"`
-	IsActive      bool    `json:"is_active"`
+	UserId        string       `json:"user_id"`
+	FirstName     string       `json:"first_name"`
+	LastName      string       `json:"last_name"`
+	AccountNumber int64        `json:"account_number"`
+	IBAN          string       `json:"iban"`
+	Type          string       `json:"type"`
+	Balance       money.Amount `json:"balance" swaggertype:"number"`
+	Currency      string       `json:"currency"`
+	IsActive      bool         `json:"is_active"`
 }
 
 type AddMoneyRequest struct {
-	AccountNumber int64   `json:"-"`
-	Amount        float64 `json:"amount"`
+	AccountNumber int64        `json:"-"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
 }
 
 type AddMoneyResponse struct {
-	CustomerNumber int64   `json:"customer_number"`
-	Balance        float64 `json:"balance"`
+	CustomerNumber int64        `json:"customer_number"`
+	Balance        money.Amount `json:"balance" swaggertype:"number"`
+	Currency       string       `json:"currency"`
+}
+
+type WithdrawRequest struct {
+	AccountNumber int64        `json:"-"`
+	Language      string       `json:"-"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type WithdrawResponse struct {
+	AccountNumber       int64        `json:"account_number"`
+	Balance             money.Amount `json:"balance" swaggertype:"number"`
+	Currency            string       `json:"currency"`
+	DailyLimitRemaining money.Amount `json:"daily_limit_remaining" swaggertype:"number"`
 }
 
 type TransferMoneyRequest struct {
-	Note              string  `json:"note"`
-	Amount            float64 `json:"amount"`
-	FromAccountNumber int64   `json:"from_account_number"`
-	ToAccountNumber   int64   `json:"to_account_number"`
+	Note              string       `json:"note"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	FromAccountNumber int64        `json:"from_account_number"`
+	ToAccountNumber   int64        `json:"to_account_number"`
+	ToIBAN            string       `json:"to_iban" example:"TR33 0006 1005 1978 6457 8413 26"`
+
+	// BeneficiaryId sends the transfer to a saved beneficiary instead of the account number or the IBAN
+	BeneficiaryId string `json:"beneficiary_id,omitempty"`
+}
+
+type GetBalanceRequest struct {
+	AccountNumber int64  `json:"-"`
+	At            string `query:"at" example:"2024-01-31"`
+}
+
+type GetBalanceResponse struct {
+	AccountNumber int64        `json:"account_number"`
+	At            time.Time    `json:"at"`
+	Balance       money.Amount `json:"balance" swaggertype:"number"`
+	Currency      string       `json:"currency"`
+}
+
+type FreezeAccountRequest struct {
+	AccountNumber int64  `json:"-"`
+	Debits        bool   `json:"debits"`
+	Credits       bool   `json:"credits"`
+	Reason        string `json:"reason" example:"Suspicious activity"`
+}
+
+type UnfreezeAccountRequest struct {
+	AccountNumber int64  `json:"-"`
+	Reason        string `json:"reason" example:"Identity verified"`
+}
+
+type CloseAccountRequest struct {
+	AccountNumber int64  `json:"-"`
+	Reason        string `json:"reason" example:"No longer needed"`
+
+	// The balance is moved to this account of the owner, it is required if the balance is not zero
+	SweepToAccountNumber int64 `json:"sweep_to_account_number"`
+}
+
+type AccountStatusResponse struct {
+	AccountNumber int64        `json:"account_number"`
+	Status        string       `json:"status" enums:"active,frozen,closed"`
+	DebitsFrozen  bool         `json:"debits_frozen"`
+	CreditsFrozen bool         `json:"credits_frozen"`
+	ClosedAt      *time.Time   `json:"closed_at,omitempty"`
+	Balance       money.Amount `json:"balance" swaggertype:"number"`
+	Currency      string       `json:"currency"`
+}
+
+type AccountEventItem struct {
+	Id                 string       `json:"id"`
+	Action             string       `json:"action" enums:"freeze,unfreeze,close"`
+	Reason             string       `json:"reason"`
+	DebitsFrozen       bool         `json:"debits_frozen"`
+	CreditsFrozen      bool         `json:"credits_frozen"`
+	SweepAccountNumber int64        `json:"sweep_account_number,omitempty"`
+	SweptAmount        money.Amount `json:"swept_amount" swaggertype:"number"`
+	CreatedBy          string       `json:"created_by"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+type GetAccountEventsResponse struct {
+	Status AccountStatusResponse `json:"status"`
+	Events []AccountEventItem    `json:"events"`
 }
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// CreateBeneficiaryRequest saves a receiver by its account number or its IBAN. The holder name is optional,
+// if it is given it has to match the name of the holder of the account.
+// A trusted beneficiary is saved untrusted until the user approves the trust.
+type CreateBeneficiaryRequest struct {
+	Nickname      string `json:"nickname" example:"Mom"`
+	AccountNumber int64  `json:"account_number,omitempty"`
+	IBAN          string `json:"iban,omitempty" example:"TR33 0006 1005 1978 6457 8413 26"`
+	HolderName    string `json:"holder_name,omitempty" example:"Jane Doe"`
+	Trusted       bool   `json:"trusted"`
+}
+
+// UpdateBeneficiaryRequest changes the nickname or the trust of a beneficiary, the fields which are not given stay the same.
+// Trust waits for the approval of the user.
+type UpdateBeneficiaryRequest struct {
+	Id       string  `json:"-"`
+	Nickname *string `json:"nickname,omitempty"`
+	Trusted  *bool   `json:"trusted,omitempty"`
+}
+
+type BeneficiaryItem struct {
+	Id            string    `json:"id"`
+	Nickname      string    `json:"nickname"`
+	AccountNumber int64     `json:"account_number"`
+	IBAN          string    `json:"iban,omitempty"`
+	HolderName    string    `json:"holder_name"`
+	Trusted       bool      `json:"trusted"`
+	TrustPending  bool      `json:"trust_pending"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+type CreateBulkTransferRequest struct {
+	FromAccountNumber int64  `form:"from_account_number"`
+	FileName          string `form:"-"`
+	File              []byte `form:"-"`
+}
+
+// BulkTransferRow is a row of a bulk transfer file. Error is the message key of the validation error of the row
+// in a preview or of the failure of the executed row in a report.
+type BulkTransferRow struct {
+	Row               int          `json:"row"`
+	TransferId        string       `json:"transfer_id,omitempty"`
+	ToAccountNumber   int64        `json:"to_account_number"`
+	ToIBAN            string       `json:"to_iban,omitempty"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	ConvertedAmount   money.Amount `json:"converted_amount" swaggertype:"number"`
+	ConvertedCurrency string       `json:"converted_currency,omitempty"`
+	TransactionFee    money.Amount `json:"transaction_fee" swaggertype:"number"`
+	Note              string       `json:"note"`
+	Status            string       `json:"status,omitempty" enums:"pending_approval,approved,executed,rejected,expired,cancelled"`
+	Error             string       `json:"error,omitempty"`
+}
+
+type BulkTransferItem struct {
+	Id                string       `json:"id,omitempty"`
+	FromAccountNumber int64        `json:"from_account_number"`
+	FileName          string       `json:"file_name"`
+	Currency          string       `json:"currency"`
+	RowCount          int          `json:"row_count"`
+	TotalAmount       money.Amount `json:"total_amount" swaggertype:"number"`
+	TotalFee          money.Amount `json:"total_fee" swaggertype:"number"`
+	Status            string       `json:"status,omitempty" enums:"pending_approval,processing,completed,rejected,expired,cancelled"`
+	ApprovalMethod    string       `json:"approval_method,omitempty"`
+	ExpiresAt         *time.Time   `json:"expires_at,omitempty"`
+	ApprovedAt        *time.Time   `json:"approved_at,omitempty"`
+	CompletedAt       *time.Time   `json:"completed_at,omitempty"`
+	ExecutedCount     int          `json:"executed_count"`
+	FailedCount       int          `json:"failed_count"`
+	CreatedAt         time.Time    `json:"created_at"`
+
+	// Rows are returned with the preview of a new bulk transfer and with the report of a single bulk transfer
+	Rows []BulkTransferRow `json:"rows,omitempty"`
+}
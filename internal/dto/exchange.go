@@ -0,0 +1,17 @@
+package dto
+
+import "tek-bank/pkg/money"
+
+type ExchangeRateItem struct {
+	Currency string     `json:"currency"`
+	Rate     money.Rate `json:"rate" swaggertype:"number"`
+}
+
+type GetExchangeRatesResponse struct {
+	BaseCurrency string             `json:"base_currency"`
+	Rates        []ExchangeRateItem `json:"rates"`
+}
+
+type UpdateExchangeRatesRequest struct {
+	Rates []ExchangeRateItem `json:"rates"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "tek-bank/pkg/money"
+
+type FeeTierItem struct {
+	UpTo    money.Amount `json:"up_to" swaggertype:"number"`
+	FlatFee money.Amount `json:"flat_fee" swaggertype:"number"`
+	Rate    money.Rate   `json:"rate" swaggertype:"number"`
+}
+
+type FeeScheduleItem struct {
+	Id              string        `json:"id,omitempty"`
+	Name            string        `json:"name" example:"Premium"`
+	Segment         string        `json:"segment" example:"premium"`
+	AccountType     string        `json:"account_type" example:"current"`
+	Type            string        `json:"type" enums:"flat,percentage,tiered"`
+	FlatFee         money.Amount  `json:"flat_fee" swaggertype:"number"`
+	Rate            money.Rate    `json:"rate" swaggertype:"number" example:"0.001"`
+	MinFee          money.Amount  `json:"min_fee" swaggertype:"number"`
+	MaxFee          money.Amount  `json:"max_fee" swaggertype:"number"`
+	FreeOwnAccounts bool          `json:"free_own_accounts"`
+	Tiers           []FeeTierItem `json:"tiers"`
+}
+
+type GetFeeSchedulesResponse struct {
+	BaseCurrency string            `json:"base_currency"`
+	Schedules    []FeeScheduleItem `json:"schedules"`
+}
+
+type UpdateSegmentRequest struct {
+	UserId  string `json:"-"`
+	Segment string `json:"segment" enums:"retail,premium,business"`
+}
+
+type GetFeeRevenueRequest struct {
+	From string `query:"from" example:"2024-01-01"`
+	To   string `query:"to" example:"2024-01-31"`
+}
+
+type FeeRevenueItem struct {
+	Date     string       `json:"date"`
+	Currency string       `json:"currency"`
+	Amount   money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type FeeRevenueTotal struct {
+	Currency string       `json:"currency"`
+	Amount   money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type GetFeeRevenueResponse struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Days   []FeeRevenueItem  `json:"days"`
+	Totals []FeeRevenueTotal `json:"totals"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+type PlaceHoldRequest struct {
+	AccountNumber int64        `json:"-"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
+	Type          string       `json:"type" enums:"legal,manual"`
+	Reason        string       `json:"reason" example:"Court order 2024/123"`
+
+	// The hold ends by itself at this time, without it the hold stays until it is released
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type ReleaseHoldRequest struct {
+	Id     string `json:"-"`
+	Reason string `json:"reason" example:"Court order lifted"`
+}
+
+type HoldItem struct {
+	Id            string       `json:"id"`
+	Type          string       `json:"type" enums:"transfer,legal,manual"`
+	Amount        money.Amount `json:"amount" swaggertype:"number"`
+	Currency      string       `json:"currency"`
+	Reason        string       `json:"reason,omitempty"`
+	TransferId    string       `json:"transfer_id,omitempty"`
+	Status        string       `json:"status" enums:"active,captured,released,expired"`
+	ExpiresAt     *time.Time   `json:"expires_at,omitempty"`
+	ReleasedAt    *time.Time   `json:"released_at,omitempty"`
+	ReleaseReason string       `json:"release_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type GetHoldsResponse struct {
+	AccountNumber    int64        `json:"account_number"`
+	Balance          money.Amount `json:"balance" swaggertype:"number"`
+	AvailableBalance money.Amount `json:"available_balance" swaggertype:"number"`
+	HeldAmount       money.Amount `json:"held_amount" swaggertype:"number"`
+	Currency         string       `json:"currency"`
+	Holds            []HoldItem   `json:"holds"`
+}
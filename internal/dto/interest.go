@@ -0,0 +1,15 @@
+package dto
+
+import "tek-bank/pkg/money"
+
+type InterestRateItem struct {
+	Id          string     `json:"id,omitempty"`
+	AccountType string     `json:"account_type" enums:"current,savings,time_deposit"`
+	Currency    string     `json:"currency" example:"TRY"`
+	Rate        money.Rate `json:"rate" swaggertype:"number" example:"0.35"`
+}
+
+type GetInterestRatesResponse struct {
+	DaysInYear int                `json:"days_in_year"`
+	Rates      []InterestRateItem `json:"rates"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "tek-bank/pkg/money"
+
+type TransferLimitItem struct {
+	Id      string       `json:"id,omitempty"`
+	Level   string       `json:"level" example:"customer"`
+	ScopeId string       `json:"scope_id"`
+	Period  string       `json:"period" example:"daily"`
+	Amount  money.Amount `json:"amount" swaggertype:"number"`
+}
+
+type GetTransferLimitsResponse struct {
+	BaseCurrency string              `json:"base_currency"`
+	Limits       []TransferLimitItem `json:"limits"`
+}
+
+type UpdateTransferLimitsRequest struct {
+	Limits []TransferLimitItem `json:"limits"`
+}
+
+type RemainingLimitItem struct {
+	Level     string       `json:"level"`
+	Period    string       `json:"period"`
+	Limit     money.Amount `json:"limit" swaggertype:"number"`
+	Used      money.Amount `json:"used" swaggertype:"number"`
+	Remaining money.Amount `json:"remaining" swaggertype:"number"`
+}
+
+type GetRemainingLimitsResponse struct {
+	AccountNumber int64                `json:"account_number"`
+	BaseCurrency  string               `json:"base_currency"`
+	Limits        []RemainingLimitItem `json:"limits"`
+}
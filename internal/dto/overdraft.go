@@ -0,0 +1,22 @@
+package dto
+
+import "tek-bank/pkg/money"
+
+type SetOverdraftRequest struct {
+	AccountNumber int64 `json:"-"`
+
+	// Limit is how far the balance may go below zero, zero removes the overdraft
+	Limit money.Amount `json:"limit" swaggertype:"number" example:"5000"`
+
+	// Rate is the yearly interest rate charged daily on the negative balance, 0.6 is 60%
+	Rate money.Rate `json:"rate" swaggertype:"number" example:"0.6"`
+}
+
+type OverdraftResponse struct {
+	AccountNumber    int64        `json:"account_number"`
+	Balance          money.Amount `json:"balance" swaggertype:"number"`
+	AvailableBalance money.Amount `json:"available_balance" swaggertype:"number"`
+	OverdraftLimit   money.Amount `json:"overdraft_limit" swaggertype:"number"`
+	OverdraftRate    money.Rate   `json:"overdraft_rate" swaggertype:"number"`
+	Currency         string       `json:"currency"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// CreatePaymentRequestRequest asks another customer for money, the payer is given by an account number
+// or by a customer number. The money is paid to an account of the requester in its currency.
+type CreatePaymentRequestRequest struct {
+	ToAccountNumber     int64        `json:"to_account_number"`
+	PayerAccountNumber  int64        `json:"payer_account_number,omitempty"`
+	PayerCustomerNumber int64        `json:"payer_customer_number,omitempty"`
+	Amount              money.Amount `json:"amount" swaggertype:"number"`
+	Note                string       `json:"note" example:"Dinner"`
+}
+
+// AcceptPaymentRequestRequest pays the payment request from an account of the payer,
+// requests sent to an account are paid from that account if no account is given
+type AcceptPaymentRequestRequest struct {
+	Id                string `json:"-"`
+	FromAccountNumber int64  `json:"from_account_number,omitempty"`
+}
+
+type DeclinePaymentRequestRequest struct {
+	Id     string `json:"-"`
+	Reason string `json:"reason" example:"I already paid"`
+}
+
+type PaymentRequestItem struct {
+	Id                      string       `json:"id"`
+	RequesterName           string       `json:"requester_name"`
+	RequesterCustomerNumber int64        `json:"requester_customer_number"`
+	ToAccountNumber         int64        `json:"to_account_number"`
+	PayerName               string       `json:"payer_name,omitempty"`
+	PayerCustomerNumber     int64        `json:"payer_customer_number,omitempty"`
+	PayerAccountNumber      int64        `json:"payer_account_number,omitempty"`
+	Amount                  money.Amount `json:"amount" swaggertype:"number"`
+	Currency                string       `json:"currency"`
+	Note                    string       `json:"note"`
+	Status                  string       `json:"status" enums:"pending,paying,accepted,declined,cancelled,expired"`
+	ExpiresAt               time.Time    `json:"expires_at"`
+	TransferId              string       `json:"transfer_id,omitempty"`
+	DeclineReason           string       `json:"decline_reason,omitempty"`
+	AnsweredAt              *time.Time   `json:"answered_at,omitempty"`
+	CreatedAt               time.Time    `json:"created_at"`
+
+	// Transfer is returned when the request is accepted, the request is paying while it waits for the approval of the payer
+	Transfer *TransferItem `json:"transfer,omitempty"`
+}
@@ -1,29 +1,77 @@
 package dto
 
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
 type AccountItem struct {
-	Id            string  `json:"id"`
-	AccountNumber int64   `json:"account_number"`
-	IBAN          string  `json:"iban"`
-	Balance       float64 `json:"balance"`
+	Id            string `json:"id"`
+	AccountNumber int64  `json:"account_number"`
+	IBAN          string `json:"iban"`
+	Type          string `json:"type" enums:"current,savings,time_deposit"`
+
+	// Balance is the ledger balance, the available balance adds the unused overdraft and is without the held amount
+	Balance          money.Amount `json:"balance" swaggertype:"number"`
+	AvailableBalance money.Amount `json:"available_balance" swaggertype:"number"`
+	HeldAmount       money.Amount `json:"held_amount" swaggertype:"number"`
+	OverdraftLimit   money.Amount `json:"overdraft_limit" swaggertype:"number"`
+	Currency         string       `json:"currency"`
+	Status           string       `json:"status" enums:"active,frozen,closed"`
+
+	// AccruedInterest is the interest earned but not paid yet, it is paid at the start of the next month
+	AccruedInterest money.Amount `json:"accrued_interest" swaggertype:"number"`
 }
 
 type GetProfileResponse struct {
-	Id          string        `json:"id"`
-	FirstName   string        `json:"first_name"`
-	LastName    string        `json:"last_name"`
-	Email       string        `json:"email"`
-	PhoneNumber string        `json:"phone_number"`
-	AccountList []AccountItem `json:"account_list"`
+	Id             string        `json:"id"`
+	FirstName      string        `json:"first_name"`
+	LastName       string        `json:"last_name"`
+	Email          string        `json:"email"`
+	PhoneNumber    string        `json:"phone_number"`
+	ApprovalMethod string        `json:"approval_method"`
+	AccountList    []AccountItem `json:"account_list"`
 }
 
 type GetTransferHistoryRequest struct {
-	AccountNumber int64 `json:"-"`
+	AccountNumber int64  `query:"accountNumber"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit"`
+	From          string `query:"from" example:"2024-01-01"`
+	To            string `query:"to" example:"2024-01-31"`
+	Direction     string `query:"direction" enums:"in,out"`
+	MinAmount     string `query:"min_amount" example:"10.00"`
+	MaxAmount     string `query:"max_amount" example:"500.00"`
+	Note          string `query:"note"`
 }
 
 type GetTransferHistoryResponse struct {
-	Id     string  `json:"id"`
-	From   int64   `json:"from"`
-	To     int64   `json:"to"`
-	Note   string  `json:"note"`
-	Amount float64 `json:"amount"`
+	Id                        string       `json:"id"`
+	Type                      string       `json:"type"`
+	Direction                 string       `json:"direction" enums:"in,out"`
+	From                      int64        `json:"from"`
+	To                        int64        `json:"to"`
+	CounterpartyAccountNumber int64        `json:"counterparty_account_number"`
+	CounterpartyIBAN          string       `json:"counterparty_iban"`
+	CounterpartyName          string       `json:"counterparty_name"`
+	Note                      string       `json:"note"`
+	Amount                    money.Amount `json:"amount" swaggertype:"number"`
+	Currency                  string       `json:"currency"`
+	ExchangeRate              *money.Rate  `json:"exchange_rate,omitempty" swaggertype:"number"`
+	FeeRule                   string       `json:"fee_rule,omitempty"`
+	CreatedAt                 time.Time    `json:"created_at"`
+
+	// Balance of the account after the entry, entries from before the ledger have none
+	Balance *money.Amount `json:"balance,omitempty" swaggertype:"number"`
+}
+
+type GetTransferHistoryPageResponse struct {
+	Items []GetTransferHistoryResponse `json:"items"`
+
+	// NextCursor returns the next page when it is sent as the cursor, it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type UpdateApprovalMethodRequest struct {
+	ApprovalMethod string `json:"approval_method" enums:"email_link,email_code,sms_code"`
 }
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+type CreateScheduledTransferRequest struct {
+	Note              string       `json:"note"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	FromAccountNumber int64        `json:"from_account_number"`
+	ToAccountNumber   int64        `json:"to_account_number"`
+	ToIBAN            string       `json:"to_iban" example:"TR33 0006 1005 1978 6457 8413 26"`
+	Frequency         string       `json:"frequency" enums:"once,daily,weekly,monthly"`
+	StartDate         string       `json:"start_date" example:"2024-07-01"`
+	EndDate           string       `json:"end_date" example:"2025-06-30"`
+	NonBusinessDay    string       `json:"non_business_day" enums:"shift,skip"`
+}
+
+// UpdateScheduledTransferRequest changes the given fields only, the schedule itself can not be changed
+type UpdateScheduledTransferRequest struct {
+	Id      string       `json:"-"`
+	Note    *string      `json:"note"`
+	Amount  money.Amount `json:"amount" swaggertype:"number"`
+	EndDate *string      `json:"end_date" example:"2025-06-30"`
+}
+
+type ScheduledTransferItem struct {
+	Id                string       `json:"id"`
+	FromAccountNumber int64        `json:"from_account_number"`
+	ToAccountNumber   int64        `json:"to_account_number"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	Note              string       `json:"note"`
+	Frequency         string       `json:"frequency"`
+	NonBusinessDay    string       `json:"non_business_day"`
+	StartDate         string       `json:"start_date"`
+	EndDate           string       `json:"end_date,omitempty"`
+	Status            string       `json:"status"`
+	NextRunAt         *time.Time   `json:"next_run_at,omitempty"`
+	Attempts          int          `json:"attempts"`
+	LastRunAt         *time.Time   `json:"last_run_at,omitempty"`
+	LastError         string       `json:"last_error,omitempty"`
+}
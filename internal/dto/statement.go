@@ -0,0 +1,16 @@
+package dto
+
+type StatementRequest struct {
+	AccountNumber int64  `json:"-"`
+	Language      string `json:"-"`
+	From          string `json:"from" query:"from" example:"2024-01-01"`
+	To            string `json:"to" query:"to" example:"2024-01-31"`
+	Format        string `json:"format" query:"format" enums:"csv,pdf"`
+}
+
+// StatementFile is a generated statement which is downloaded or sent as an e-mail attachment
+type StatementFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"tek-bank/pkg/money"
+	"time"
+)
+
+type TransferItem struct {
+	Id                string       `json:"id"`
+	FromAccountNumber int64        `json:"from_account_number"`
+	ToAccountNumber   int64        `json:"to_account_number"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	Currency          string       `json:"currency"`
+	ConvertedAmount   money.Amount `json:"converted_amount" swaggertype:"number"`
+	ConvertedCurrency string       `json:"converted_currency"`
+	ExchangeRate      money.Rate   `json:"exchange_rate" swaggertype:"number"`
+	TransactionFee    money.Amount `json:"transaction_fee" swaggertype:"number"`
+	FeeRule           string       `json:"fee_rule"`
+	Note              string       `json:"note"`
+	Status            string       `json:"status"`
+	ApprovalMethod    string       `json:"approval_method"`
+	BeneficiaryId     string       `json:"beneficiary_id,omitempty"`
+	ExpiresAt         *time.Time   `json:"expires_at,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+type ApproveTransferRequest struct {
+	Id   string `json:"-"`
+	Code string `json:"code" example:"123456"`
+}
+
+type ReverseTransferRequest struct {
+	TransferHistoryId string       `json:"-"`
+	Language          string       `json:"-"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	RefundFee         bool         `json:"refund_fee"`
+	Reason            string       `json:"reason" example:"Sent to the wrong account"`
+	Force             bool         `json:"force"`
+
+	// Reference of the reversal given by the admin, like a ticket number. A transfer is reversed once with a reference.
+	Reference string `json:"reference" example:"TICKET-4711"`
+}
+
+type ReverseTransferResponse struct {
+	TransferHistoryId string       `json:"transfer_history_id"`
+	JournalId         string       `json:"journal_id"`
+	Amount            money.Amount `json:"amount" swaggertype:"number"`
+	Currency          string       `json:"currency"`
+	ConvertedAmount   money.Amount `json:"converted_amount" swaggertype:"number"`
+	ConvertedCurrency string       `json:"converted_currency"`
+	RefundedFee       money.Amount `json:"refunded_fee" swaggertype:"number"`
+	RemainingAmount   money.Amount `json:"remaining_amount" swaggertype:"number"`
+	Forced            bool         `json:"forced"`
+	Reference         string       `json:"reference"`
+}
@@ -112,3 +112,20 @@ func CreateMsg(ctx *fiber.Ctx, messageId string, templateData ...map[string]stri
 
 	return msg
 }
+
+// CreateMsgWithLanguage is a helper function for creating message in the given language,
+// it is used where there is no request context like e-mails
+func CreateMsgWithLanguage(lang string, messageId string, templateData ...map[string]string) string {
+
+	loc := i18n.NewLocalizer(bundle, lang)
+
+	config := &i18n.LocalizeConfig{
+		MessageID: messageId,
+	}
+
+	if templateData != nil {
+		config.TemplateData = templateData[0]
+	}
+
+	return loc.MustLocalize(config)
+}
@@ -1,18 +1,145 @@
 package messages
 
 var (
-	UnexpectedError             = "unexpected_error"
-	UserAlreadyExists           = "user_already_exists"
-	PasswordsDoNotMatch         = "passwords_do_not_match"
-	PasswordIncorrect           = "password_incorrect"
-	InvalidLoginCredentials     = "invalid_login_credentials"
-	UserNotFound                = "user_not_found"
-	InvalidCreateAccountRequest = "invalid_create_account_request"
-	AccountCreated              = "account_created"
-	AccountNotFound             = "account_not_found"
-	InSufficientBalance         = "insufficient_balance"
-	TransferApproved            = "transfer_approved"
-	Unauthorized                = "unauthorized"
-	BadRequest                  = "bad_request"
-	TransactionFailed           = "transaction_failed"
+	UnexpectedError                      = "unexpected_error"
+	UserAlreadyExists                    = "user_already_exists"
+	PasswordsDoNotMatch                  = "passwords_do_not_match"
+	PasswordIncorrect                    = "password_incorrect"
+	InvalidLoginCredentials              = "invalid_login_credentials"
+	UserNotFound                         = "user_not_found"
+	InvalidCreateAccountRequest          = "invalid_create_account_request"
+	AccountCreated                       = "account_created"
+	AccountNotFound                      = "account_not_found"
+	InSufficientBalance                  = "insufficient_balance"
+	TransferApproved                     = "transfer_approved"
+	Unauthorized                         = "unauthorized"
+	BadRequest                           = "bad_request"
+	TransactionFailed                    = "transaction_failed"
+	InvalidAmount                        = "invalid_amount"
+	InvalidExchangeRate                  = "invalid_exchange_rate"
+	UnsupportedCurrency                  = "unsupported_currency"
+	InvalidIdempotencyKey                = "invalid_idempotency_key"
+	IdempotencyKeyInProgress             = "idempotency_key_in_progress"
+	IdempotencyKeyReused                 = "idempotency_key_reused"
+	DailyWithdrawalLimitExceeded         = "daily_withdrawal_limit_exceeded"
+	WithdrawalMailSubject                = "withdrawal_mail_subject"
+	WithdrawalMailBody                   = "withdrawal_mail_body"
+	InvalidIBAN                          = "invalid_iban"
+	IBANNotFound                         = "iban_not_found"
+	UnsupportedCountry                   = "unsupported_country"
+	InvalidSchedule                      = "invalid_schedule"
+	ScheduledTransferNotFound            = "scheduled_transfer_not_found"
+	ScheduledTransferNotActive           = "scheduled_transfer_not_active"
+	TransferNotFound                     = "transfer_not_found"
+	TransferNotPending                   = "transfer_not_pending"
+	TransferExpired                      = "transfer_expired"
+	TransferRejected                     = "transfer_rejected"
+	InvalidTransferCode                  = "invalid_transfer_code"
+	TransferCodeAttemptsExceeded         = "transfer_code_attempts_exceeded"
+	InvalidApprovalMethod                = "invalid_approval_method"
+	ApprovalMethodUpdated                = "approval_method_updated"
+	TransferTransactionLimitExceeded     = "transfer_transaction_limit_exceeded"
+	TransferDailyLimitExceeded           = "transfer_daily_limit_exceeded"
+	TransferMonthlyLimitExceeded         = "transfer_monthly_limit_exceeded"
+	InvalidTransferLimit                 = "invalid_transfer_limit"
+	TransferLimitNotFound                = "transfer_limit_not_found"
+	InvalidFeeSchedule                   = "invalid_fee_schedule"
+	FeeScheduleNotFound                  = "fee_schedule_not_found"
+	DefaultFeeScheduleRequired           = "default_fee_schedule_required"
+	InvalidSegment                       = "invalid_segment"
+	SegmentUpdated                       = "segment_updated"
+	InvalidDateRange                     = "invalid_date_range"
+	ReversalReasonRequired               = "reversal_reason_required"
+	InvalidReversal                      = "invalid_reversal"
+	InvalidReversalAmount                = "invalid_reversal_amount"
+	TransferAlreadyReversed              = "transfer_already_reversed"
+	InvalidFeeRefund                     = "invalid_fee_refund"
+	ReversalForceNotAllowed              = "reversal_force_not_allowed"
+	ReceiverInsufficientBalance          = "receiver_insufficient_balance"
+	TransferReversedMailSubject          = "transfer_reversed_mail_subject"
+	TransferReversedMailBody             = "transfer_reversed_mail_body"
+	InvalidHistoryFilter                 = "invalid_history_filter"
+	InvalidCursor                        = "invalid_cursor"
+	StatementTitle                       = "statement_title"
+	StatementAccount                     = "statement_account"
+	StatementPeriod                      = "statement_period"
+	StatementCurrency                    = "statement_currency"
+	StatementOpeningBalance              = "statement_opening_balance"
+	StatementClosingBalance              = "statement_closing_balance"
+	StatementDate                        = "statement_date"
+	StatementType                        = "statement_type"
+	StatementDescription                 = "statement_description"
+	StatementCounterparty                = "statement_counterparty"
+	StatementAmount                      = "statement_amount"
+	StatementBalance                     = "statement_balance"
+	StatementDeposit                     = "statement_deposit"
+	StatementWithdrawal                  = "statement_withdrawal"
+	StatementTransfer                    = "statement_transfer"
+	StatementFee                         = "statement_fee"
+	StatementReversal                    = "statement_reversal"
+	StatementFeeRefund                   = "statement_fee_refund"
+	StatementMailSubject                 = "statement_mail_subject"
+	StatementMailBody                    = "statement_mail_body"
+	StatementSent                        = "statement_sent"
+	InvalidStatementFormat               = "invalid_statement_format"
+	InvalidBalanceTime                   = "invalid_balance_time"
+	AccountFrozen                        = "account_frozen"
+	AccountClosed                        = "account_closed"
+	AccountNotFrozen                     = "account_not_frozen"
+	AccountReasonRequired                = "account_reason_required"
+	InvalidFreeze                        = "invalid_freeze"
+	AccountBalanceNotZero                = "account_balance_not_zero"
+	InvalidSweepAccount                  = "invalid_sweep_account"
+	UserInactive                         = "user_inactive"
+	StatementSweep                       = "statement_sweep"
+	AccountHasHolds                      = "account_has_holds"
+	HoldNotFound                         = "hold_not_found"
+	HoldNotActive                        = "hold_not_active"
+	InvalidHold                          = "invalid_hold"
+	InvalidAccountType                   = "invalid_account_type"
+	InvalidInterestRate                  = "invalid_interest_rate"
+	InterestRateNotFound                 = "interest_rate_not_found"
+	StatementInterest                    = "statement_interest"
+	InvalidOverdraft                     = "invalid_overdraft"
+	OverdraftNotAllowed                  = "overdraft_not_allowed"
+	OverdraftStartedMailSubject          = "overdraft_started_mail_subject"
+	OverdraftStartedMailBody             = "overdraft_started_mail_body"
+	OverdraftExceededMailSubject         = "overdraft_exceeded_mail_subject"
+	OverdraftExceededMailBody            = "overdraft_exceeded_mail_body"
+	StatementOverdraftInterest           = "statement_overdraft_interest"
+	BulkTransferNotFound                 = "bulk_transfer_not_found"
+	InvalidBulkTransferFile              = "invalid_bulk_transfer_file"
+	BulkTransferTooManyRows              = "bulk_transfer_too_many_rows"
+	BulkTransferRowsInvalid              = "bulk_transfer_rows_invalid"
+	BulkTransferApproved                 = "bulk_transfer_approved"
+	BulkTransferReportMailSubject        = "bulk_transfer_report_mail_subject"
+	BulkTransferReportMailBody           = "bulk_transfer_report_mail_body"
+	PaymentRequestNotFound               = "payment_request_not_found"
+	PaymentRequestNotPending             = "payment_request_not_pending"
+	PaymentRequestExpired                = "payment_request_expired"
+	InvalidPaymentRequest                = "invalid_payment_request"
+	PaymentRequestCurrencyMismatch       = "payment_request_currency_mismatch"
+	PaymentRequestMailSubject            = "payment_request_mail_subject"
+	PaymentRequestMailBody               = "payment_request_mail_body"
+	PaymentRequestAcceptedMailSubject    = "payment_request_accepted_mail_subject"
+	PaymentRequestAcceptedMailBody       = "payment_request_accepted_mail_body"
+	PaymentRequestDeclinedMailSubject    = "payment_request_declined_mail_subject"
+	PaymentRequestDeclinedMailBody       = "payment_request_declined_mail_body"
+	PaymentRequestExpiredMailSubject     = "payment_request_expired_mail_subject"
+	PaymentRequestExpiredMailBody        = "payment_request_expired_mail_body"
+	PaymentRequestAccountMismatch        = "payment_request_account_mismatch"
+	BeneficiaryNotFound                  = "beneficiary_not_found"
+	BeneficiaryAlreadyExists             = "beneficiary_already_exists"
+	BeneficiaryHolderMismatch            = "beneficiary_holder_mismatch"
+	InvalidBeneficiary                   = "invalid_beneficiary"
+	ReversalReferenceRequired            = "reversal_reference_required"
+	ReversalReferenceUsed                = "reversal_reference_used"
+	PaymentRequestUnpaidMailSubject      = "payment_request_unpaid_mail_subject"
+	PaymentRequestUnpaidMailBody         = "payment_request_unpaid_mail_body"
+	PaymentRequestLimitExceeded          = "payment_request_limit_exceeded"
+	BeneficiaryTrustNotPending           = "beneficiary_trust_not_pending"
+	BeneficiaryTrustExpired              = "beneficiary_trust_expired"
+	BeneficiaryTrustCodeAttemptsExceeded = "beneficiary_trust_code_attempts_exceeded"
+	BeneficiaryTrustApproved             = "beneficiary_trust_approved"
+	TrustedBeneficiaryLimitExceeded      = "trusted_beneficiary_limit_exceeded"
 )
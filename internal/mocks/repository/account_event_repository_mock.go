@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: AccountEventRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/account_event_repository_mock.go -package=repository tek-bank/internal/db/repository AccountEventRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockAccountEventRepository is a mock of AccountEventRepository interface.
+type MockAccountEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountEventRepositoryMockRecorder
+}
+
+// MockAccountEventRepositoryMockRecorder is the mock recorder for MockAccountEventRepository.
+type MockAccountEventRepositoryMockRecorder struct {
+	mock *MockAccountEventRepository
+}
+
+// NewMockAccountEventRepository creates a new mock instance.
+func NewMockAccountEventRepository(ctrl *gomock.Controller) *MockAccountEventRepository {
+	mock := &MockAccountEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountEventRepository) EXPECT() *MockAccountEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccountEventRepository) Create(arg0 models.AccountEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccountEventRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountEventRepository)(nil).Create), arg0)
+}
+
+// FindByAccountId mocks base method.
+func (m *MockAccountEventRepository) FindByAccountId(arg0 string) ([]models.AccountEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAccountId", arg0)
+	ret0, _ := ret[0].([]models.AccountEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAccountId indicates an expected call of FindByAccountId.
+func (mr *MockAccountEventRepositoryMockRecorder) FindByAccountId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAccountId", reflect.TypeOf((*MockAccountEventRepository)(nil).FindByAccountId), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockAccountEventRepository) WithTx(arg0 *gorm.DB) repository.AccountEventRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.AccountEventRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockAccountEventRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockAccountEventRepository)(nil).WithTx), arg0)
+}
@@ -10,10 +10,10 @@
 package repository
 
 import (
-	context "context"
 	reflect "reflect"
 	models "tek-bank/internal/db/models"
 	repository "tek-bank/internal/db/repository"
+	money "tek-bank/pkg/money"
 
 	gomock "go.uber.org/mock/gomock"
 	gorm "gorm.io/gorm"
@@ -57,20 +57,6 @@ func (mr *MockAccountRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountRepository)(nil).Create), arg0)
 }
 
-// DeleteToken mocks base method.
-func (m *MockAccountRepository) DeleteToken(arg0 context.Context, arg1 string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteToken", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// DeleteToken indicates an expected call of DeleteToken.
-func (mr *MockAccountRepositoryMockRecorder) DeleteToken(arg0, arg1 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockAccountRepository)(nil).DeleteToken), arg0, arg1)
-}
-
 // FindByAccountNumber mocks base method.
 func (m *MockAccountRepository) FindByAccountNumber(arg0 int64) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -116,47 +102,66 @@ func (mr *MockAccountRepositoryMockRecorder) FindByOwnerId(arg0 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwnerId", reflect.TypeOf((*MockAccountRepository)(nil).FindByOwnerId), arg0)
 }
 
-// GetToken mocks base method.
-func (m *MockAccountRepository) GetToken(arg0 context.Context, arg1 string) (*string, error) {
+// FindInternal mocks base method.
+func (m *MockAccountRepository) FindInternal(arg0 string, arg1 money.Currency) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInternal", arg0, arg1)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInternal indicates an expected call of FindInternal.
+func (mr *MockAccountRepositoryMockRecorder) FindInternal(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInternal", reflect.TypeOf((*MockAccountRepository)(nil).FindInternal), arg0, arg1)
+}
+
+// Lock mocks base method.
+func (m *MockAccountRepository) Lock(arg0 ...string) ([]models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetToken", arg0, arg1)
-	ret0, _ := ret[0].(*string)
+	varargs := []any{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Lock", varargs...)
+	ret0, _ := ret[0].([]models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetToken indicates an expected call of GetToken.
-func (mr *MockAccountRepositoryMockRecorder) GetToken(arg0, arg1 any) *gomock.Call {
+// Lock indicates an expected call of Lock.
+func (mr *MockAccountRepositoryMockRecorder) Lock(arg0 ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*MockAccountRepository)(nil).GetToken), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockAccountRepository)(nil).Lock), arg0...)
 }
 
-// SetToken mocks base method.
-func (m *MockAccountRepository) SetToken(arg0 context.Context, arg1, arg2 string) error {
+// UpdateOverdraft mocks base method.
+func (m *MockAccountRepository) UpdateOverdraft(arg0 models.Account) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetToken", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateOverdraft", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetToken indicates an expected call of SetToken.
-func (mr *MockAccountRepositoryMockRecorder) SetToken(arg0, arg1, arg2 any) *gomock.Call {
+// UpdateOverdraft indicates an expected call of UpdateOverdraft.
+func (mr *MockAccountRepositoryMockRecorder) UpdateOverdraft(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetToken", reflect.TypeOf((*MockAccountRepository)(nil).SetToken), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOverdraft", reflect.TypeOf((*MockAccountRepository)(nil).UpdateOverdraft), arg0)
 }
 
-// UpdateBalance mocks base method.
-func (m *MockAccountRepository) UpdateBalance(arg0 float64, arg1 string) error {
+// UpdateState mocks base method.
+func (m *MockAccountRepository) UpdateState(arg0 models.Account) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBalance", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateState", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateBalance indicates an expected call of UpdateBalance.
-func (mr *MockAccountRepositoryMockRecorder) UpdateBalance(arg0, arg1 any) *gomock.Call {
+// UpdateState indicates an expected call of UpdateState.
+func (mr *MockAccountRepositoryMockRecorder) UpdateState(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockAccountRepository)(nil).UpdateBalance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateState", reflect.TypeOf((*MockAccountRepository)(nil).UpdateState), arg0)
 }
 
 // WithTx mocks base method.
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: BeneficiaryEventRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/beneficiary_event_repository_mock.go -package=repository tek-bank/internal/db/repository BeneficiaryEventRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockBeneficiaryEventRepository is a mock of BeneficiaryEventRepository interface.
+type MockBeneficiaryEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBeneficiaryEventRepositoryMockRecorder
+}
+
+// MockBeneficiaryEventRepositoryMockRecorder is the mock recorder for MockBeneficiaryEventRepository.
+type MockBeneficiaryEventRepositoryMockRecorder struct {
+	mock *MockBeneficiaryEventRepository
+}
+
+// NewMockBeneficiaryEventRepository creates a new mock instance.
+func NewMockBeneficiaryEventRepository(ctrl *gomock.Controller) *MockBeneficiaryEventRepository {
+	mock := &MockBeneficiaryEventRepository{ctrl: ctrl}
+	mock.recorder = &MockBeneficiaryEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBeneficiaryEventRepository) EXPECT() *MockBeneficiaryEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBeneficiaryEventRepository) Create(arg0 models.BeneficiaryEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBeneficiaryEventRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBeneficiaryEventRepository)(nil).Create), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockBeneficiaryEventRepository) WithTx(arg0 *gorm.DB) repository.BeneficiaryEventRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.BeneficiaryEventRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockBeneficiaryEventRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockBeneficiaryEventRepository)(nil).WithTx), arg0)
+}
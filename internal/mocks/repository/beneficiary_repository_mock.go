@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: BeneficiaryRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/beneficiary_repository_mock.go -package=repository tek-bank/internal/db/repository BeneficiaryRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockBeneficiaryRepository is a mock of BeneficiaryRepository interface.
+type MockBeneficiaryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBeneficiaryRepositoryMockRecorder
+}
+
+// MockBeneficiaryRepositoryMockRecorder is the mock recorder for MockBeneficiaryRepository.
+type MockBeneficiaryRepositoryMockRecorder struct {
+	mock *MockBeneficiaryRepository
+}
+
+// NewMockBeneficiaryRepository creates a new mock instance.
+func NewMockBeneficiaryRepository(ctrl *gomock.Controller) *MockBeneficiaryRepository {
+	mock := &MockBeneficiaryRepository{ctrl: ctrl}
+	mock.recorder = &MockBeneficiaryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBeneficiaryRepository) EXPECT() *MockBeneficiaryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBeneficiaryRepository) Create(arg0 models.Beneficiary) (*models.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBeneficiaryRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBeneficiaryRepository)(nil).Create), arg0)
+}
+
+// Delete mocks base method.
+func (m *MockBeneficiaryRepository) Delete(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBeneficiaryRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBeneficiaryRepository)(nil).Delete), arg0)
+}
+
+// FindById mocks base method.
+func (m *MockBeneficiaryRepository) FindById(arg0 string) (*models.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*models.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockBeneficiaryRepositoryMockRecorder) FindById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockBeneficiaryRepository)(nil).FindById), arg0)
+}
+
+// FindByOwnerId mocks base method.
+func (m *MockBeneficiaryRepository) FindByOwnerId(arg0 string) ([]models.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwnerId", arg0)
+	ret0, _ := ret[0].([]models.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwnerId indicates an expected call of FindByOwnerId.
+func (mr *MockBeneficiaryRepositoryMockRecorder) FindByOwnerId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwnerId", reflect.TypeOf((*MockBeneficiaryRepository)(nil).FindByOwnerId), arg0)
+}
+
+// LockById mocks base method.
+func (m *MockBeneficiaryRepository) LockById(arg0 string) (*models.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockById", arg0)
+	ret0, _ := ret[0].(*models.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockById indicates an expected call of LockById.
+func (mr *MockBeneficiaryRepositoryMockRecorder) LockById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockById", reflect.TypeOf((*MockBeneficiaryRepository)(nil).LockById), arg0)
+}
+
+// LockByTrustTokenHash mocks base method.
+func (m *MockBeneficiaryRepository) LockByTrustTokenHash(arg0 string) (*models.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByTrustTokenHash", arg0)
+	ret0, _ := ret[0].(*models.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByTrustTokenHash indicates an expected call of LockByTrustTokenHash.
+func (mr *MockBeneficiaryRepositoryMockRecorder) LockByTrustTokenHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByTrustTokenHash", reflect.TypeOf((*MockBeneficiaryRepository)(nil).LockByTrustTokenHash), arg0)
+}
+
+// Update mocks base method.
+func (m *MockBeneficiaryRepository) Update(arg0 models.Beneficiary) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBeneficiaryRepositoryMockRecorder) Update(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBeneficiaryRepository)(nil).Update), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockBeneficiaryRepository) WithTx(arg0 *gorm.DB) repository.BeneficiaryRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.BeneficiaryRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockBeneficiaryRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockBeneficiaryRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: BulkTransferRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/bulk_transfer_repository_mock.go -package=repository tek-bank/internal/db/repository BulkTransferRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockBulkTransferRepository is a mock of BulkTransferRepository interface.
+type MockBulkTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkTransferRepositoryMockRecorder
+}
+
+// MockBulkTransferRepositoryMockRecorder is the mock recorder for MockBulkTransferRepository.
+type MockBulkTransferRepositoryMockRecorder struct {
+	mock *MockBulkTransferRepository
+}
+
+// NewMockBulkTransferRepository creates a new mock instance.
+func NewMockBulkTransferRepository(ctrl *gomock.Controller) *MockBulkTransferRepository {
+	mock := &MockBulkTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockBulkTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulkTransferRepository) EXPECT() *MockBulkTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBulkTransferRepository) Create(arg0 models.BulkTransfer) (*models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBulkTransferRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBulkTransferRepository)(nil).Create), arg0)
+}
+
+// FindById mocks base method.
+func (m *MockBulkTransferRepository) FindById(arg0 string) (*models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockBulkTransferRepositoryMockRecorder) FindById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockBulkTransferRepository)(nil).FindById), arg0)
+}
+
+// FindByOwnerId mocks base method.
+func (m *MockBulkTransferRepository) FindByOwnerId(arg0 string) ([]models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwnerId", arg0)
+	ret0, _ := ret[0].([]models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwnerId indicates an expected call of FindByOwnerId.
+func (mr *MockBulkTransferRepositoryMockRecorder) FindByOwnerId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwnerId", reflect.TypeOf((*MockBulkTransferRepository)(nil).FindByOwnerId), arg0)
+}
+
+// LockById mocks base method.
+func (m *MockBulkTransferRepository) LockById(arg0 string) (*models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockById", arg0)
+	ret0, _ := ret[0].(*models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockById indicates an expected call of LockById.
+func (mr *MockBulkTransferRepositoryMockRecorder) LockById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockById", reflect.TypeOf((*MockBulkTransferRepository)(nil).LockById), arg0)
+}
+
+// LockByTokenHash mocks base method.
+func (m *MockBulkTransferRepository) LockByTokenHash(arg0 string) (*models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByTokenHash", arg0)
+	ret0, _ := ret[0].(*models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByTokenHash indicates an expected call of LockByTokenHash.
+func (mr *MockBulkTransferRepositoryMockRecorder) LockByTokenHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByTokenHash", reflect.TypeOf((*MockBulkTransferRepository)(nil).LockByTokenHash), arg0)
+}
+
+// LockExpired mocks base method.
+func (m *MockBulkTransferRepository) LockExpired(arg0 time.Time, arg1 int) ([]models.BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockExpired", arg0, arg1)
+	ret0, _ := ret[0].([]models.BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockExpired indicates an expected call of LockExpired.
+func (mr *MockBulkTransferRepositoryMockRecorder) LockExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockExpired", reflect.TypeOf((*MockBulkTransferRepository)(nil).LockExpired), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockBulkTransferRepository) Update(arg0 models.BulkTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBulkTransferRepositoryMockRecorder) Update(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBulkTransferRepository)(nil).Update), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockBulkTransferRepository) WithTx(arg0 *gorm.DB) repository.BulkTransferRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.BulkTransferRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockBulkTransferRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockBulkTransferRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: FeeScheduleRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/fee_schedule_repository_mock.go -package=repository tek-bank/internal/db/repository FeeScheduleRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockFeeScheduleRepository is a mock of FeeScheduleRepository interface.
+type MockFeeScheduleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeeScheduleRepositoryMockRecorder
+}
+
+// MockFeeScheduleRepositoryMockRecorder is the mock recorder for MockFeeScheduleRepository.
+type MockFeeScheduleRepositoryMockRecorder struct {
+	mock *MockFeeScheduleRepository
+}
+
+// NewMockFeeScheduleRepository creates a new mock instance.
+func NewMockFeeScheduleRepository(ctrl *gomock.Controller) *MockFeeScheduleRepository {
+	mock := &MockFeeScheduleRepository{ctrl: ctrl}
+	mock.recorder = &MockFeeScheduleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeeScheduleRepository) EXPECT() *MockFeeScheduleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockFeeScheduleRepository) Delete(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFeeScheduleRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFeeScheduleRepository)(nil).Delete), arg0)
+}
+
+// FindAll mocks base method.
+func (m *MockFeeScheduleRepository) FindAll() ([]models.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]models.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockFeeScheduleRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockFeeScheduleRepository)(nil).FindAll))
+}
+
+// FindApplicable mocks base method.
+func (m *MockFeeScheduleRepository) FindApplicable(arg0, arg1 string) ([]models.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicable", arg0, arg1)
+	ret0, _ := ret[0].([]models.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicable indicates an expected call of FindApplicable.
+func (mr *MockFeeScheduleRepositoryMockRecorder) FindApplicable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicable", reflect.TypeOf((*MockFeeScheduleRepository)(nil).FindApplicable), arg0, arg1)
+}
+
+// FindById mocks base method.
+func (m *MockFeeScheduleRepository) FindById(arg0 string) (*models.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*models.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockFeeScheduleRepositoryMockRecorder) FindById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockFeeScheduleRepository)(nil).FindById), arg0)
+}
+
+// Upsert mocks base method.
+func (m *MockFeeScheduleRepository) Upsert(arg0 models.FeeSchedule) (*models.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0)
+	ret0, _ := ret[0].(*models.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockFeeScheduleRepositoryMockRecorder) Upsert(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockFeeScheduleRepository)(nil).Upsert), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockFeeScheduleRepository) WithTx(arg0 *gorm.DB) repository.FeeScheduleRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.FeeScheduleRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockFeeScheduleRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockFeeScheduleRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,249 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: InterestRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/interest_repository_mock.go -package=repository tek-bank/internal/db/repository InterestRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockInterestRepository is a mock of InterestRepository interface.
+type MockInterestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInterestRepositoryMockRecorder
+}
+
+// MockInterestRepositoryMockRecorder is the mock recorder for MockInterestRepository.
+type MockInterestRepositoryMockRecorder struct {
+	mock *MockInterestRepository
+}
+
+// NewMockInterestRepository creates a new mock instance.
+func NewMockInterestRepository(ctrl *gomock.Controller) *MockInterestRepository {
+	mock := &MockInterestRepository{ctrl: ctrl}
+	mock.recorder = &MockInterestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInterestRepository) EXPECT() *MockInterestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accrue mocks base method.
+func (m *MockInterestRepository) Accrue(arg0, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accrue", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accrue indicates an expected call of Accrue.
+func (mr *MockInterestRepositoryMockRecorder) Accrue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accrue", reflect.TypeOf((*MockInterestRepository)(nil).Accrue), arg0, arg1)
+}
+
+// CreateCharge mocks base method.
+func (m *MockInterestRepository) CreateCharge(arg0 models.InterestAccrual) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCharge", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCharge indicates an expected call of CreateCharge.
+func (mr *MockInterestRepositoryMockRecorder) CreateCharge(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCharge", reflect.TypeOf((*MockInterestRepository)(nil).CreateCharge), arg0)
+}
+
+// DeleteRate mocks base method.
+func (m *MockInterestRepository) DeleteRate(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRate indicates an expected call of DeleteRate.
+func (mr *MockInterestRepositoryMockRecorder) DeleteRate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRate", reflect.TypeOf((*MockInterestRepository)(nil).DeleteRate), arg0)
+}
+
+// FindAccrualStart mocks base method.
+func (m *MockInterestRepository) FindAccrualStart() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAccrualStart")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAccrualStart indicates an expected call of FindAccrualStart.
+func (mr *MockInterestRepositoryMockRecorder) FindAccrualStart() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAccrualStart", reflect.TypeOf((*MockInterestRepository)(nil).FindAccrualStart))
+}
+
+// FindDue mocks base method.
+func (m *MockInterestRepository) FindDue(arg0 time.Time, arg1 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockInterestRepositoryMockRecorder) FindDue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockInterestRepository)(nil).FindDue), arg0, arg1)
+}
+
+// FindOverdraftStart mocks base method.
+func (m *MockInterestRepository) FindOverdraftStart() (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOverdraftStart")
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOverdraftStart indicates an expected call of FindOverdraftStart.
+func (mr *MockInterestRepositoryMockRecorder) FindOverdraftStart() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverdraftStart", reflect.TypeOf((*MockInterestRepository)(nil).FindOverdraftStart))
+}
+
+// FindOverdrawn mocks base method.
+func (m *MockInterestRepository) FindOverdrawn(arg0, arg1 time.Time, arg2 int) ([]repository.OverdrawnDay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOverdrawn", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repository.OverdrawnDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOverdrawn indicates an expected call of FindOverdrawn.
+func (mr *MockInterestRepositoryMockRecorder) FindOverdrawn(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverdrawn", reflect.TypeOf((*MockInterestRepository)(nil).FindOverdrawn), arg0, arg1, arg2)
+}
+
+// FindRates mocks base method.
+func (m *MockInterestRepository) FindRates() ([]models.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRates")
+	ret0, _ := ret[0].([]models.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRates indicates an expected call of FindRates.
+func (mr *MockInterestRepositoryMockRecorder) FindRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRates", reflect.TypeOf((*MockInterestRepository)(nil).FindRates))
+}
+
+// FindUnpaid mocks base method.
+func (m *MockInterestRepository) FindUnpaid(arg0 []string) ([]models.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnpaid", arg0)
+	ret0, _ := ret[0].([]models.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnpaid indicates an expected call of FindUnpaid.
+func (mr *MockInterestRepositoryMockRecorder) FindUnpaid(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnpaid", reflect.TypeOf((*MockInterestRepository)(nil).FindUnpaid), arg0)
+}
+
+// LockUnpaid mocks base method.
+func (m *MockInterestRepository) LockUnpaid(arg0 string, arg1 time.Time) ([]models.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUnpaid", arg0, arg1)
+	ret0, _ := ret[0].([]models.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockUnpaid indicates an expected call of LockUnpaid.
+func (mr *MockInterestRepositoryMockRecorder) LockUnpaid(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUnpaid", reflect.TypeOf((*MockInterestRepository)(nil).LockUnpaid), arg0, arg1)
+}
+
+// MarkOverdraftChecked mocks base method.
+func (m *MockInterestRepository) MarkOverdraftChecked(arg0, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOverdraftChecked", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOverdraftChecked indicates an expected call of MarkOverdraftChecked.
+func (mr *MockInterestRepositoryMockRecorder) MarkOverdraftChecked(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOverdraftChecked", reflect.TypeOf((*MockInterestRepository)(nil).MarkOverdraftChecked), arg0, arg1)
+}
+
+// MarkPaid mocks base method.
+func (m *MockInterestRepository) MarkPaid(arg0 []string, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaid", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPaid indicates an expected call of MarkPaid.
+func (mr *MockInterestRepositoryMockRecorder) MarkPaid(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaid", reflect.TypeOf((*MockInterestRepository)(nil).MarkPaid), arg0, arg1, arg2)
+}
+
+// UpsertRate mocks base method.
+func (m *MockInterestRepository) UpsertRate(arg0 models.InterestRate) (*models.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRate", arg0)
+	ret0, _ := ret[0].(*models.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertRate indicates an expected call of UpsertRate.
+func (mr *MockInterestRepositoryMockRecorder) UpsertRate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRate", reflect.TypeOf((*MockInterestRepository)(nil).UpsertRate), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockInterestRepository) WithTx(arg0 *gorm.DB) repository.InterestRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.InterestRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockInterestRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockInterestRepository)(nil).WithTx), arg0)
+}
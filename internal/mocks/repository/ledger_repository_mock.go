@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: LedgerRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/ledger_repository_mock.go -package=repository tek-bank/internal/db/repository LedgerRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	money "tek-bank/pkg/money"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BalanceAt mocks base method.
+func (m *MockLedgerRepository) BalanceAt(arg0 string, arg1 time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", arg0, arg1)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockLedgerRepositoryMockRecorder) BalanceAt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockLedgerRepository)(nil).BalanceAt), arg0, arg1)
+}
+
+// CreateSnapshots mocks base method.
+func (m *MockLedgerRepository) CreateSnapshots(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshots", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshots indicates an expected call of CreateSnapshots.
+func (mr *MockLedgerRepositoryMockRecorder) CreateSnapshots(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshots", reflect.TypeOf((*MockLedgerRepository)(nil).CreateSnapshots), arg0)
+}
+
+// FindPostings mocks base method.
+func (m *MockLedgerRepository) FindPostings(arg0 string, arg1, arg2 time.Time) ([]repository.AccountPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostings", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repository.AccountPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostings indicates an expected call of FindPostings.
+func (mr *MockLedgerRepositoryMockRecorder) FindPostings(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostings", reflect.TypeOf((*MockLedgerRepository)(nil).FindPostings), arg0, arg1, arg2)
+}
+
+// FindPostingsByJournalIds mocks base method.
+func (m *MockLedgerRepository) FindPostingsByJournalIds(arg0 string, arg1 []string) ([]repository.AccountPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPostingsByJournalIds", arg0, arg1)
+	ret0, _ := ret[0].([]repository.AccountPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPostingsByJournalIds indicates an expected call of FindPostingsByJournalIds.
+func (mr *MockLedgerRepositoryMockRecorder) FindPostingsByJournalIds(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPostingsByJournalIds", reflect.TypeOf((*MockLedgerRepository)(nil).FindPostingsByJournalIds), arg0, arg1)
+}
+
+// Post mocks base method.
+func (m *MockLedgerRepository) Post(arg0 models.Journal) (*models.Journal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Post", arg0)
+	ret0, _ := ret[0].(*models.Journal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Post indicates an expected call of Post.
+func (mr *MockLedgerRepositoryMockRecorder) Post(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Post", reflect.TypeOf((*MockLedgerRepository)(nil).Post), arg0)
+}
+
+// SumInternalByDay mocks base method.
+func (m *MockLedgerRepository) SumInternalByDay(arg0 string, arg1, arg2 time.Time) ([]repository.DailyTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumInternalByDay", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]repository.DailyTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumInternalByDay indicates an expected call of SumInternalByDay.
+func (mr *MockLedgerRepositoryMockRecorder) SumInternalByDay(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumInternalByDay", reflect.TypeOf((*MockLedgerRepository)(nil).SumInternalByDay), arg0, arg1, arg2)
+}
+
+// SumPostings mocks base method.
+func (m *MockLedgerRepository) SumPostings(arg0, arg1 string, arg2 time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumPostings", arg0, arg1, arg2)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumPostings indicates an expected call of SumPostings.
+func (mr *MockLedgerRepositoryMockRecorder) SumPostings(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumPostings", reflect.TypeOf((*MockLedgerRepository)(nil).SumPostings), arg0, arg1, arg2)
+}
+
+// WithTx mocks base method.
+func (m *MockLedgerRepository) WithTx(arg0 *gorm.DB) repository.LedgerRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.LedgerRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockLedgerRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockLedgerRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: PaymentRequestRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/payment_request_repository_mock.go -package=repository tek-bank/internal/db/repository PaymentRequestRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockPaymentRequestRepository is a mock of PaymentRequestRepository interface.
+type MockPaymentRequestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRequestRepositoryMockRecorder
+}
+
+// MockPaymentRequestRepositoryMockRecorder is the mock recorder for MockPaymentRequestRepository.
+type MockPaymentRequestRepositoryMockRecorder struct {
+	mock *MockPaymentRequestRepository
+}
+
+// NewMockPaymentRequestRepository creates a new mock instance.
+func NewMockPaymentRequestRepository(ctrl *gomock.Controller) *MockPaymentRequestRepository {
+	mock := &MockPaymentRequestRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRequestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRequestRepository) EXPECT() *MockPaymentRequestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByRequesterIdSince mocks base method.
+func (m *MockPaymentRequestRepository) CountByRequesterIdSince(arg0 string, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByRequesterIdSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByRequesterIdSince indicates an expected call of CountByRequesterIdSince.
+func (mr *MockPaymentRequestRepositoryMockRecorder) CountByRequesterIdSince(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByRequesterIdSince", reflect.TypeOf((*MockPaymentRequestRepository)(nil).CountByRequesterIdSince), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockPaymentRequestRepository) Create(arg0 models.PaymentRequest) (*models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentRequestRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRequestRepository)(nil).Create), arg0)
+}
+
+// FindById mocks base method.
+func (m *MockPaymentRequestRepository) FindById(arg0 string) (*models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockPaymentRequestRepositoryMockRecorder) FindById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockPaymentRequestRepository)(nil).FindById), arg0)
+}
+
+// FindByRequesterId mocks base method.
+func (m *MockPaymentRequestRepository) FindByRequesterId(arg0 string) ([]models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByRequesterId", arg0)
+	ret0, _ := ret[0].([]models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByRequesterId indicates an expected call of FindByRequesterId.
+func (mr *MockPaymentRequestRepositoryMockRecorder) FindByRequesterId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByRequesterId", reflect.TypeOf((*MockPaymentRequestRepository)(nil).FindByRequesterId), arg0)
+}
+
+// FindPendingByPayerId mocks base method.
+func (m *MockPaymentRequestRepository) FindPendingByPayerId(arg0 string, arg1 time.Time) ([]models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingByPayerId", arg0, arg1)
+	ret0, _ := ret[0].([]models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingByPayerId indicates an expected call of FindPendingByPayerId.
+func (mr *MockPaymentRequestRepositoryMockRecorder) FindPendingByPayerId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingByPayerId", reflect.TypeOf((*MockPaymentRequestRepository)(nil).FindPendingByPayerId), arg0, arg1)
+}
+
+// LockById mocks base method.
+func (m *MockPaymentRequestRepository) LockById(arg0 string) (*models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockById", arg0)
+	ret0, _ := ret[0].(*models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockById indicates an expected call of LockById.
+func (mr *MockPaymentRequestRepositoryMockRecorder) LockById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockById", reflect.TypeOf((*MockPaymentRequestRepository)(nil).LockById), arg0)
+}
+
+// LockExpired mocks base method.
+func (m *MockPaymentRequestRepository) LockExpired(arg0 time.Time, arg1 int) ([]models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockExpired", arg0, arg1)
+	ret0, _ := ret[0].([]models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockExpired indicates an expected call of LockExpired.
+func (mr *MockPaymentRequestRepositoryMockRecorder) LockExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockExpired", reflect.TypeOf((*MockPaymentRequestRepository)(nil).LockExpired), arg0, arg1)
+}
+
+// LockPaying mocks base method.
+func (m *MockPaymentRequestRepository) LockPaying(arg0 []string, arg1 int) ([]models.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPaying", arg0, arg1)
+	ret0, _ := ret[0].([]models.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPaying indicates an expected call of LockPaying.
+func (mr *MockPaymentRequestRepositoryMockRecorder) LockPaying(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPaying", reflect.TypeOf((*MockPaymentRequestRepository)(nil).LockPaying), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockPaymentRequestRepository) Update(arg0 models.PaymentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPaymentRequestRepositoryMockRecorder) Update(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentRequestRepository)(nil).Update), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockPaymentRequestRepository) WithTx(arg0 *gorm.DB) repository.PaymentRequestRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.PaymentRequestRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockPaymentRequestRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockPaymentRequestRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: ScheduledTransferRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/scheduled_transfer_repository_mock.go -package=repository tek-bank/internal/db/repository ScheduledTransferRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockScheduledTransferRepository is a mock of ScheduledTransferRepository interface.
+type MockScheduledTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledTransferRepositoryMockRecorder
+}
+
+// MockScheduledTransferRepositoryMockRecorder is the mock recorder for MockScheduledTransferRepository.
+type MockScheduledTransferRepositoryMockRecorder struct {
+	mock *MockScheduledTransferRepository
+}
+
+// NewMockScheduledTransferRepository creates a new mock instance.
+func NewMockScheduledTransferRepository(ctrl *gomock.Controller) *MockScheduledTransferRepository {
+	mock := &MockScheduledTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduledTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledTransferRepository) EXPECT() *MockScheduledTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScheduledTransferRepository) Create(arg0 models.ScheduledTransfer) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduledTransferRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledTransferRepository)(nil).Create), arg0)
+}
+
+// FindById mocks base method.
+func (m *MockScheduledTransferRepository) FindById(arg0 string) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockScheduledTransferRepositoryMockRecorder) FindById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockScheduledTransferRepository)(nil).FindById), arg0)
+}
+
+// FindByOwnerId mocks base method.
+func (m *MockScheduledTransferRepository) FindByOwnerId(arg0 string) ([]models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwnerId", arg0)
+	ret0, _ := ret[0].([]models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwnerId indicates an expected call of FindByOwnerId.
+func (mr *MockScheduledTransferRepositoryMockRecorder) FindByOwnerId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwnerId", reflect.TypeOf((*MockScheduledTransferRepository)(nil).FindByOwnerId), arg0)
+}
+
+// FindDueIds mocks base method.
+func (m *MockScheduledTransferRepository) FindDueIds(arg0 time.Time, arg1 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueIds", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueIds indicates an expected call of FindDueIds.
+func (mr *MockScheduledTransferRepositoryMockRecorder) FindDueIds(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueIds", reflect.TypeOf((*MockScheduledTransferRepository)(nil).FindDueIds), arg0, arg1)
+}
+
+// LockDue mocks base method.
+func (m *MockScheduledTransferRepository) LockDue(arg0 string, arg1 time.Time) (*models.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDue", arg0, arg1)
+	ret0, _ := ret[0].(*models.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDue indicates an expected call of LockDue.
+func (mr *MockScheduledTransferRepositoryMockRecorder) LockDue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDue", reflect.TypeOf((*MockScheduledTransferRepository)(nil).LockDue), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockScheduledTransferRepository) Update(arg0 models.ScheduledTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScheduledTransferRepositoryMockRecorder) Update(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledTransferRepository)(nil).Update), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockScheduledTransferRepository) WithTx(arg0 *gorm.DB) repository.ScheduledTransferRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.ScheduledTransferRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockScheduledTransferRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockScheduledTransferRepository)(nil).WithTx), arg0)
+}
@@ -12,8 +12,10 @@ package repository
 import (
 	reflect "reflect"
 	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
 
 	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
 )
 
 // MockTransferHistoryRepository is a mock of TransferHistoryRepository interface.
@@ -54,7 +56,7 @@ func (mr *MockTransferHistoryRepositoryMockRecorder) Create(arg0 any) *gomock.Ca
 }
 
 // FetchByAccountNumber mocks base method.
-func (m *MockTransferHistoryRepository) FetchByAccountNumber(arg0 int64) ([]models.TransferHistory, error) {
+func (m *MockTransferHistoryRepository) FetchByAccountNumber(arg0 repository.TransferHistoryFilter) ([]models.TransferHistory, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FetchByAccountNumber", arg0)
 	ret0, _ := ret[0].([]models.TransferHistory)
@@ -67,3 +69,77 @@ func (mr *MockTransferHistoryRepositoryMockRecorder) FetchByAccountNumber(arg0 a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByAccountNumber", reflect.TypeOf((*MockTransferHistoryRepository)(nil).FetchByAccountNumber), arg0)
 }
+
+// FindByJournalId mocks base method.
+func (m *MockTransferHistoryRepository) FindByJournalId(arg0 string) ([]models.TransferHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByJournalId", arg0)
+	ret0, _ := ret[0].([]models.TransferHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByJournalId indicates an expected call of FindByJournalId.
+func (mr *MockTransferHistoryRepositoryMockRecorder) FindByJournalId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByJournalId", reflect.TypeOf((*MockTransferHistoryRepository)(nil).FindByJournalId), arg0)
+}
+
+// FindByJournalIds mocks base method.
+func (m *MockTransferHistoryRepository) FindByJournalIds(arg0 []string) ([]models.TransferHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByJournalIds", arg0)
+	ret0, _ := ret[0].([]models.TransferHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByJournalIds indicates an expected call of FindByJournalIds.
+func (mr *MockTransferHistoryRepositoryMockRecorder) FindByJournalIds(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByJournalIds", reflect.TypeOf((*MockTransferHistoryRepository)(nil).FindByJournalIds), arg0)
+}
+
+// FindReversals mocks base method.
+func (m *MockTransferHistoryRepository) FindReversals(arg0 string) ([]models.TransferHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReversals", arg0)
+	ret0, _ := ret[0].([]models.TransferHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReversals indicates an expected call of FindReversals.
+func (mr *MockTransferHistoryRepositoryMockRecorder) FindReversals(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReversals", reflect.TypeOf((*MockTransferHistoryRepository)(nil).FindReversals), arg0)
+}
+
+// LockById mocks base method.
+func (m *MockTransferHistoryRepository) LockById(arg0 string) (*models.TransferHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockById", arg0)
+	ret0, _ := ret[0].(*models.TransferHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockById indicates an expected call of LockById.
+func (mr *MockTransferHistoryRepositoryMockRecorder) LockById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockById", reflect.TypeOf((*MockTransferHistoryRepository)(nil).LockById), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockTransferHistoryRepository) WithTx(arg0 *gorm.DB) repository.TransferHistoryRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.TransferHistoryRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTransferHistoryRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransferHistoryRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: TransferLimitRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/transfer_limit_repository_mock.go -package=repository tek-bank/internal/db/repository TransferLimitRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockTransferLimitRepository is a mock of TransferLimitRepository interface.
+type MockTransferLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferLimitRepositoryMockRecorder
+}
+
+// MockTransferLimitRepositoryMockRecorder is the mock recorder for MockTransferLimitRepository.
+type MockTransferLimitRepositoryMockRecorder struct {
+	mock *MockTransferLimitRepository
+}
+
+// NewMockTransferLimitRepository creates a new mock instance.
+func NewMockTransferLimitRepository(ctrl *gomock.Controller) *MockTransferLimitRepository {
+	mock := &MockTransferLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferLimitRepository) EXPECT() *MockTransferLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTransferLimitRepository) Delete(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransferLimitRepositoryMockRecorder) Delete(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransferLimitRepository)(nil).Delete), arg0)
+}
+
+// FindAll mocks base method.
+func (m *MockTransferLimitRepository) FindAll() ([]models.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]models.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockTransferLimitRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTransferLimitRepository)(nil).FindAll))
+}
+
+// FindApplicable mocks base method.
+func (m *MockTransferLimitRepository) FindApplicable(arg0 string, arg1 int64) ([]models.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicable", arg0, arg1)
+	ret0, _ := ret[0].([]models.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicable indicates an expected call of FindApplicable.
+func (mr *MockTransferLimitRepositoryMockRecorder) FindApplicable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicable", reflect.TypeOf((*MockTransferLimitRepository)(nil).FindApplicable), arg0, arg1)
+}
+
+// Upsert mocks base method.
+func (m *MockTransferLimitRepository) Upsert(arg0 []models.TransferLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockTransferLimitRepositoryMockRecorder) Upsert(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockTransferLimitRepository)(nil).Upsert), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockTransferLimitRepository) WithTx(arg0 *gorm.DB) repository.TransferLimitRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.TransferLimitRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTransferLimitRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransferLimitRepository)(nil).WithTx), arg0)
+}
@@ -0,0 +1,252 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/db/repository (interfaces: TransferRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../../mocks/repository/transfer_repository_mock.go -package=repository tek-bank/internal/db/repository TransferRepository
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	models "tek-bank/internal/db/models"
+	repository "tek-bank/internal/db/repository"
+	money "tek-bank/pkg/money"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockTransferRepository is a mock of TransferRepository interface.
+type MockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepositoryMockRecorder
+}
+
+// MockTransferRepositoryMockRecorder is the mock recorder for MockTransferRepository.
+type MockTransferRepositoryMockRecorder struct {
+	mock *MockTransferRepository
+}
+
+// NewMockTransferRepository creates a new mock instance.
+func NewMockTransferRepository(ctrl *gomock.Controller) *MockTransferRepository {
+	mock := &MockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApproveBulkRows mocks base method.
+func (m *MockTransferRepository) ApproveBulkRows(arg0 string, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveBulkRows", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveBulkRows indicates an expected call of ApproveBulkRows.
+func (mr *MockTransferRepositoryMockRecorder) ApproveBulkRows(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveBulkRows", reflect.TypeOf((*MockTransferRepository)(nil).ApproveBulkRows), arg0, arg1)
+}
+
+// CountBulkRows mocks base method.
+func (m *MockTransferRepository) CountBulkRows(arg0, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBulkRows", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBulkRows indicates an expected call of CountBulkRows.
+func (mr *MockTransferRepositoryMockRecorder) CountBulkRows(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBulkRows", reflect.TypeOf((*MockTransferRepository)(nil).CountBulkRows), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockTransferRepository) Create(arg0 models.Transfer) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransferRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransferRepository)(nil).Create), arg0)
+}
+
+// ExpirePending mocks base method.
+func (m *MockTransferRepository) ExpirePending(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePending", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePending indicates an expected call of ExpirePending.
+func (mr *MockTransferRepositoryMockRecorder) ExpirePending(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePending", reflect.TypeOf((*MockTransferRepository)(nil).ExpirePending), arg0)
+}
+
+// FindApprovedBulkRows mocks base method.
+func (m *MockTransferRepository) FindApprovedBulkRows(arg0 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApprovedBulkRows", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApprovedBulkRows indicates an expected call of FindApprovedBulkRows.
+func (mr *MockTransferRepositoryMockRecorder) FindApprovedBulkRows(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApprovedBulkRows", reflect.TypeOf((*MockTransferRepository)(nil).FindApprovedBulkRows), arg0)
+}
+
+// FindByBulkTransferId mocks base method.
+func (m *MockTransferRepository) FindByBulkTransferId(arg0 string) ([]models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByBulkTransferId", arg0)
+	ret0, _ := ret[0].([]models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByBulkTransferId indicates an expected call of FindByBulkTransferId.
+func (mr *MockTransferRepositoryMockRecorder) FindByBulkTransferId(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByBulkTransferId", reflect.TypeOf((*MockTransferRepository)(nil).FindByBulkTransferId), arg0)
+}
+
+// FindPendingByOwnerId mocks base method.
+func (m *MockTransferRepository) FindPendingByOwnerId(arg0 string, arg1 time.Time) ([]models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingByOwnerId", arg0, arg1)
+	ret0, _ := ret[0].([]models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingByOwnerId indicates an expected call of FindPendingByOwnerId.
+func (mr *MockTransferRepositoryMockRecorder) FindPendingByOwnerId(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingByOwnerId", reflect.TypeOf((*MockTransferRepository)(nil).FindPendingByOwnerId), arg0, arg1)
+}
+
+// LockById mocks base method.
+func (m *MockTransferRepository) LockById(arg0 string) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockById", arg0)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockById indicates an expected call of LockById.
+func (mr *MockTransferRepositoryMockRecorder) LockById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockById", reflect.TypeOf((*MockTransferRepository)(nil).LockById), arg0)
+}
+
+// LockByTokenHash mocks base method.
+func (m *MockTransferRepository) LockByTokenHash(arg0 string) (*models.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByTokenHash", arg0)
+	ret0, _ := ret[0].(*models.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByTokenHash indicates an expected call of LockByTokenHash.
+func (mr *MockTransferRepositoryMockRecorder) LockByTokenHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByTokenHash", reflect.TypeOf((*MockTransferRepository)(nil).LockByTokenHash), arg0)
+}
+
+// SumExecutedByAccount mocks base method.
+func (m *MockTransferRepository) SumExecutedByAccount(arg0 int64, arg1 time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumExecutedByAccount", arg0, arg1)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumExecutedByAccount indicates an expected call of SumExecutedByAccount.
+func (mr *MockTransferRepositoryMockRecorder) SumExecutedByAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumExecutedByAccount", reflect.TypeOf((*MockTransferRepository)(nil).SumExecutedByAccount), arg0, arg1)
+}
+
+// SumExecutedByOwner mocks base method.
+func (m *MockTransferRepository) SumExecutedByOwner(arg0 string, arg1 time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumExecutedByOwner", arg0, arg1)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumExecutedByOwner indicates an expected call of SumExecutedByOwner.
+func (mr *MockTransferRepositoryMockRecorder) SumExecutedByOwner(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumExecutedByOwner", reflect.TypeOf((*MockTransferRepository)(nil).SumExecutedByOwner), arg0, arg1)
+}
+
+// SumExecutedTrustedByOwner mocks base method.
+func (m *MockTransferRepository) SumExecutedTrustedByOwner(arg0 string, arg1 time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumExecutedTrustedByOwner", arg0, arg1)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumExecutedTrustedByOwner indicates an expected call of SumExecutedTrustedByOwner.
+func (mr *MockTransferRepositoryMockRecorder) SumExecutedTrustedByOwner(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumExecutedTrustedByOwner", reflect.TypeOf((*MockTransferRepository)(nil).SumExecutedTrustedByOwner), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockTransferRepository) Update(arg0 models.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTransferRepositoryMockRecorder) Update(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTransferRepository)(nil).Update), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockTransferRepository) WithTx(arg0 *gorm.DB) repository.TransferRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(repository.TransferRepository)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockTransferRepositoryMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockTransferRepository)(nil).WithTx), arg0)
+}
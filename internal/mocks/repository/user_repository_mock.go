@@ -73,6 +73,21 @@ func (mr *MockUserRepositoryMockRecorder) FindAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepository)(nil).FindAll))
 }
 
+// FindByCustomerNumber mocks base method.
+func (m *MockUserRepository) FindByCustomerNumber(arg0 int64) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerNumber", arg0)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerNumber indicates an expected call of FindByCustomerNumber.
+func (mr *MockUserRepositoryMockRecorder) FindByCustomerNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerNumber", reflect.TypeOf((*MockUserRepository)(nil).FindByCustomerNumber), arg0)
+}
+
 // FindByEmail mocks base method.
 func (m *MockUserRepository) FindByEmail(arg0 string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +148,20 @@ func (mr *MockUserRepositoryMockRecorder) GetTokenBlacklist(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenBlacklist", reflect.TypeOf((*MockUserRepository)(nil).GetTokenBlacklist), arg0, arg1)
 }
 
+// Lock mocks base method.
+func (m *MockUserRepository) Lock(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockUserRepositoryMockRecorder) Lock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockUserRepository)(nil).Lock), arg0)
+}
+
 // SetTokenBlacklist mocks base method.
 func (m *MockUserRepository) SetTokenBlacklist(arg0 *context.Context, arg1, arg2 string, arg3 time.Duration) error {
 	m.ctrl.T.Helper()
@@ -161,6 +190,34 @@ func (mr *MockUserRepositoryMockRecorder) SoftDelete(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDelete", reflect.TypeOf((*MockUserRepository)(nil).SoftDelete), arg0)
 }
 
+// UpdateApprovalMethod mocks base method.
+func (m *MockUserRepository) UpdateApprovalMethod(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApprovalMethod", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApprovalMethod indicates an expected call of UpdateApprovalMethod.
+func (mr *MockUserRepositoryMockRecorder) UpdateApprovalMethod(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalMethod", reflect.TypeOf((*MockUserRepository)(nil).UpdateApprovalMethod), arg0, arg1)
+}
+
+// UpdateSegment mocks base method.
+func (m *MockUserRepository) UpdateSegment(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSegment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSegment indicates an expected call of UpdateSegment.
+func (mr *MockUserRepositoryMockRecorder) UpdateSegment(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegment", reflect.TypeOf((*MockUserRepository)(nil).UpdateSegment), arg0, arg1)
+}
+
 // WithTx mocks base method.
 func (m *MockUserRepository) WithTx(arg0 *gorm.DB) repository.UserRepository {
 	m.ctrl.T.Helper()
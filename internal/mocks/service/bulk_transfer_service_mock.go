@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tek-bank/internal/service (interfaces: BulkTransferService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/service/bulk_transfer_service_mock.go -package=service tek-bank/internal/service BulkTransferService
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+	dto "tek-bank/internal/dto"
+	service "tek-bank/internal/service"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockBulkTransferService is a mock of BulkTransferService interface.
+type MockBulkTransferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkTransferServiceMockRecorder
+}
+
+// MockBulkTransferServiceMockRecorder is the mock recorder for MockBulkTransferService.
+type MockBulkTransferServiceMockRecorder struct {
+	mock *MockBulkTransferService
+}
+
+// NewMockBulkTransferService creates a new mock instance.
+func NewMockBulkTransferService(ctrl *gomock.Controller) *MockBulkTransferService {
+	mock := &MockBulkTransferService{ctrl: ctrl}
+	mock.recorder = &MockBulkTransferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulkTransferService) EXPECT() *MockBulkTransferServiceMockRecorder {
+	return m.recorder
+}
+
+// ApproveBulkTransfer mocks base method.
+func (m *MockBulkTransferService) ApproveBulkTransfer(arg0 context.Context, arg1 dto.ApproveTransferRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveBulkTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveBulkTransfer indicates an expected call of ApproveBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) ApproveBulkTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).ApproveBulkTransfer), arg0, arg1)
+}
+
+// BulkTransferApproval mocks base method.
+func (m *MockBulkTransferService) BulkTransferApproval(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkTransferApproval", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkTransferApproval indicates an expected call of BulkTransferApproval.
+func (mr *MockBulkTransferServiceMockRecorder) BulkTransferApproval(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkTransferApproval", reflect.TypeOf((*MockBulkTransferService)(nil).BulkTransferApproval), arg0, arg1)
+}
+
+// CancelBulkTransfer mocks base method.
+func (m *MockBulkTransferService) CancelBulkTransfer(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBulkTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelBulkTransfer indicates an expected call of CancelBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) CancelBulkTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).CancelBulkTransfer), arg0, arg1)
+}
+
+// CreateBulkTransfer mocks base method.
+func (m *MockBulkTransferService) CreateBulkTransfer(arg0 context.Context, arg1 dto.CreateBulkTransferRequest) (*dto.BulkTransferItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBulkTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*dto.BulkTransferItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBulkTransfer indicates an expected call of CreateBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) CreateBulkTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).CreateBulkTransfer), arg0, arg1)
+}
+
+// ExecuteBulkTransferRow mocks base method.
+func (m *MockBulkTransferService) ExecuteBulkTransferRow(arg0 string, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteBulkTransferRow", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteBulkTransferRow indicates an expected call of ExecuteBulkTransferRow.
+func (mr *MockBulkTransferServiceMockRecorder) ExecuteBulkTransferRow(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBulkTransferRow", reflect.TypeOf((*MockBulkTransferService)(nil).ExecuteBulkTransferRow), arg0, arg1)
+}
+
+// ExpireBulkTransfers mocks base method.
+func (m *MockBulkTransferService) ExpireBulkTransfers(arg0 time.Time, arg1 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBulkTransfers", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireBulkTransfers indicates an expected call of ExpireBulkTransfers.
+func (mr *MockBulkTransferServiceMockRecorder) ExpireBulkTransfers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBulkTransfers", reflect.TypeOf((*MockBulkTransferService)(nil).ExpireBulkTransfers), arg0, arg1)
+}
+
+// FindBulkTransferRows mocks base method.
+func (m *MockBulkTransferService) FindBulkTransferRows(arg0 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBulkTransferRows", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBulkTransferRows indicates an expected call of FindBulkTransferRows.
+func (mr *MockBulkTransferServiceMockRecorder) FindBulkTransferRows(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBulkTransferRows", reflect.TypeOf((*MockBulkTransferService)(nil).FindBulkTransferRows), arg0)
+}
+
+// GetBulkTransfer mocks base method.
+func (m *MockBulkTransferService) GetBulkTransfer(arg0 context.Context, arg1 string) (*dto.BulkTransferItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkTransfer", arg0, arg1)
+	ret0, _ := ret[0].(*dto.BulkTransferItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkTransfer indicates an expected call of GetBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) GetBulkTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).GetBulkTransfer), arg0, arg1)
+}
+
+// ListBulkTransfers mocks base method.
+func (m *MockBulkTransferService) ListBulkTransfers(arg0 context.Context) ([]dto.BulkTransferItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBulkTransfers", arg0)
+	ret0, _ := ret[0].([]dto.BulkTransferItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBulkTransfers indicates an expected call of ListBulkTransfers.
+func (mr *MockBulkTransferServiceMockRecorder) ListBulkTransfers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBulkTransfers", reflect.TypeOf((*MockBulkTransferService)(nil).ListBulkTransfers), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockBulkTransferService) WithTx(arg0 *gorm.DB) service.BulkTransferService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(service.BulkTransferService)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockBulkTransferServiceMockRecorder) WithTx(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockBulkTransferService)(nil).WithTx), arg0)
+}
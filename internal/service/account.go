@@ -2,17 +2,23 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
 	"tek-bank/internal/db/models"
 	"tek-bank/internal/db/repository"
 	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
 	"tek-bank/internal/i18n/messages"
-	"tek-bank/pkg/converter"
 	"tek-bank/pkg/crypto"
 	"tek-bank/pkg/enum"
 	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/iban"
+	"tek-bank/pkg/money"
+	"tek-bank/pkg/sms"
 	"time"
 )
 
@@ -20,41 +26,453 @@ type AccountService interface {
 	RegisterAccount(ctx context.Context, request dto.RegisterAccountRequest) error
 	CreateNewAccount(ctx context.Context, request dto.CreateNewAccountRequest) (*dto.CreateNewAccountResponse, error)
 	AddMoney(ctx context.Context, request dto.AddMoneyRequest) (*dto.AddMoneyResponse, error)
-	TransferMoney(ctx context.Context, request dto.TransferMoneyRequest) error
+	Withdraw(ctx context.Context, request dto.WithdrawRequest) (*dto.WithdrawResponse, error)
+	TransferMoney(ctx context.Context, request dto.TransferMoneyRequest) (*dto.TransferItem, error)
 	TransferApproval(ctx context.Context, token string) error
+	ApproveTransfer(ctx context.Context, request dto.ApproveTransferRequest) error
+	ExecuteTransfer(ctx context.Context, ownerId string, request dto.TransferMoneyRequest) error
+	ReverseTransfer(ctx context.Context, request dto.ReverseTransferRequest) (*dto.ReverseTransferResponse, error)
+	FreezeAccount(ctx context.Context, request dto.FreezeAccountRequest) (*dto.AccountStatusResponse, error)
+	UnfreezeAccount(ctx context.Context, request dto.UnfreezeAccountRequest) (*dto.AccountStatusResponse, error)
+	CloseAccount(ctx context.Context, request dto.CloseAccountRequest) (*dto.AccountStatusResponse, error)
+	GetAccountEvents(ctx context.Context, accountNumber int64) (*dto.GetAccountEventsResponse, error)
+	PayInterest(accountId string, now time.Time) error
+	SetOverdraft(ctx context.Context, request dto.SetOverdraftRequest) (*dto.OverdraftResponse, error)
+	ChargeOverdraftInterest(accountId string, day time.Time, now time.Time) error
+
+	// The helpers of the transfers shared with the bulk transfers
+	transferExecutor
 
 	WithTx(trxHandle *gorm.DB) AccountService
 }
 
+// transferExecutor is the part of the account service the bulk transfers share: the checks of the sender, the quote,
+// the approval and the execution of a transfer and the changes of its status and hold
+type transferExecutor interface {
+	ownedAccount(ctx context.Context, accountNumber int64) (*models.Account, error)
+	lockAccounts(accounts ...*models.Account) error
+	availableBalance(account *models.Account, now time.Time) (money.Amount, error)
+	quoteTransfer(senderAccount, receiverAccount *models.Account, amount money.Amount, note string) (*models.Transfer, error)
+	newApproval(method string, now time.Time) (*approval, error)
+	executeTransfer(content *models.Transfer) (*models.Account, *models.Account, error)
+	changeTransferStatus(transfer *models.Transfer, status string, now time.Time) error
+	releaseTransferHold(transfer *models.Transfer, status string, now time.Time) error
+	sendMail(contents ...gomailer.Content) error
+}
+
 type accountService struct {
 	accountRepository         repository.AccountRepository
 	userRepository            repository.UserRepository
 	transferHistoryRepository repository.TransferHistoryRepository
+	ledgerRepository          repository.LedgerRepository
+	exchangeRateRepository    repository.ExchangeRateRepository
+	transferRepository        repository.TransferRepository
+	transferLimitRepository   repository.TransferLimitRepository
+	feeScheduleRepository     repository.FeeScheduleRepository
+	accountEventRepository    repository.AccountEventRepository
+	holdRepository            repository.HoldRepository
+	interestRepository        repository.InterestRepository
+	beneficiaryRepository     repository.BeneficiaryRepository
+	notificationRepository    repository.NotificationRepository
 	pkgCrypto                 crypto.Crypto
-	pkgConverter              converter.Converter
+	pkgMailer                 gomailer.Mailer
+	pkgSMS                    sms.Sender
+
+	// Up to which amount a day transfers to trusted beneficiaries are executed without an approval, read at the start
+	trustedBeneficiaryLimit money.Amount
+
+	// Transaction of the service, an approved transfer is executed in a savepoint of it
+	tx *gorm.DB
 }
 
 func NewAccountService(
 	accountRepository repository.AccountRepository,
 	userRepository repository.UserRepository,
 	transferHistoryRepository repository.TransferHistoryRepository,
+	ledgerRepository repository.LedgerRepository,
+	exchangeRateRepository repository.ExchangeRateRepository,
+	transferRepository repository.TransferRepository,
+	transferLimitRepository repository.TransferLimitRepository,
+	feeScheduleRepository repository.FeeScheduleRepository,
+	accountEventRepository repository.AccountEventRepository,
+	holdRepository repository.HoldRepository,
+	interestRepository repository.InterestRepository,
+	beneficiaryRepository repository.BeneficiaryRepository,
+	notificationRepository repository.NotificationRepository,
 	pkgCrypto crypto.Crypto,
-	pkgConverter converter.Converter,
+	pkgMailer gomailer.Mailer,
+	pkgSMS sms.Sender,
 ) AccountService {
 	return &accountService{
 		accountRepository:         accountRepository,
 		userRepository:            userRepository,
 		transferHistoryRepository: transferHistoryRepository,
+		ledgerRepository:          ledgerRepository,
+		exchangeRateRepository:    exchangeRateRepository,
+		transferRepository:        transferRepository,
+		transferLimitRepository:   transferLimitRepository,
+		feeScheduleRepository:     feeScheduleRepository,
+		accountEventRepository:    accountEventRepository,
+		holdRepository:            holdRepository,
+		interestRepository:        interestRepository,
+		beneficiaryRepository:     beneficiaryRepository,
+		notificationRepository:    notificationRepository,
 		pkgCrypto:                 pkgCrypto,
-		pkgConverter:              pkgConverter,
+		pkgMailer:                 pkgMailer,
+		pkgSMS:                    pkgSMS,
+		trustedBeneficiaryLimit:   trustedBeneficiaryLimit(),
 	}
 }
 
+// WithTx returns a copy of the service which runs on the transaction of the request
 func (s *accountService) WithTx(trxHandle *gorm.DB) AccountService {
-	s.accountRepository = s.accountRepository.WithTx(trxHandle)
-	s.userRepository = s.userRepository.WithTx(trxHandle)
-	return s
+	return s.withTx(trxHandle)
+}
+
+func (s *accountService) withTx(trxHandle *gorm.DB) *accountService {
+	clone := *s
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	clone.userRepository = s.userRepository.WithTx(trxHandle)
+	clone.transferHistoryRepository = s.transferHistoryRepository.WithTx(trxHandle)
+	clone.ledgerRepository = s.ledgerRepository.WithTx(trxHandle)
+	clone.exchangeRateRepository = s.exchangeRateRepository.WithTx(trxHandle)
+	clone.transferRepository = s.transferRepository.WithTx(trxHandle)
+	clone.transferLimitRepository = s.transferLimitRepository.WithTx(trxHandle)
+	clone.feeScheduleRepository = s.feeScheduleRepository.WithTx(trxHandle)
+	clone.accountEventRepository = s.accountEventRepository.WithTx(trxHandle)
+	clone.holdRepository = s.holdRepository.WithTx(trxHandle)
+	clone.beneficiaryRepository = s.beneficiaryRepository.WithTx(trxHandle)
+	clone.interestRepository = s.interestRepository.WithTx(trxHandle)
+	clone.notificationRepository = s.notificationRepository.WithTx(trxHandle)
+	clone.tx = trxHandle
+	return &clone
+}
+
+// accountCurrency validates the requested currency of a new account, the base currency is the default
+func (s *accountService) accountCurrency(code string) (money.Currency, error) {
+	if code == "" {
+		return money.DefaultCurrency, nil
+	}
+
+	currency, err := money.ParseCurrency(code)
+	if err != nil {
+		return "", errors.New(messages.UnsupportedCurrency)
+	}
+
+	if currency == money.DefaultCurrency {
+		return currency, nil
+	}
+
+	// Only currencies with a rate can be exchanged
+	_, err = s.exchangeRateRepository.FindByCurrency(currency)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errors.New(messages.UnsupportedCurrency)
+	}
+	if err != nil {
+		return "", errors.New(messages.UnexpectedError)
+	}
+
+	return currency, nil
+}
+
+// exchangeRate returns the rate to convert an amount between two currencies over the base currency
+func (s *accountService) exchangeRate(from, to money.Currency) (money.Rate, error) {
+	if from == to {
+		return money.OneRate, nil
+	}
+
+	fromRate, err := s.baseRate(from)
+	if err != nil {
+		return money.Rate{}, err
+	}
+
+	toRate, err := s.baseRate(to)
+	if err != nil {
+		return money.Rate{}, err
+	}
+
+	return fromRate.Div(toRate), nil
+}
+
+// baseRate returns the value of one unit of the currency in the base currency
+func (s *accountService) baseRate(currency money.Currency) (money.Rate, error) {
+	if currency == money.DefaultCurrency {
+		return money.OneRate, nil
+	}
+
+	rate, err := s.exchangeRateRepository.FindByCurrency(currency)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return money.Rate{}, errors.New(messages.UnsupportedCurrency)
+	}
+	if err != nil {
+		return money.Rate{}, errors.New(messages.UnexpectedError)
+	}
+
+	return rate.Rate, nil
+}
+
+// ownedAccount finds the account and makes sure it belongs to the current user
+func (s *accountService) ownedAccount(ctx context.Context, accountNumber int64) (*models.Account, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	return account, nil
+}
+
+// receiverAccount finds the receiver of a transfer by its IBAN or its account number
+func (s *accountService) receiverAccount(request dto.TransferMoneyRequest) (*models.Account, error) {
+	return findReceiverAccount(s.accountRepository, request.ToAccountNumber, request.ToIBAN)
+}
+
+// findReceiverAccount finds the customer account of the IBAN or, without an IBAN, of the account number.
+// Internal accounts of the bank can not receive transfers.
+func findReceiverAccount(accountRepository repository.AccountRepository, accountNumber int64, toIBAN string) (*models.Account, error) {
+	if toIBAN == "" {
+		account, err := accountRepository.FindByAccountNumber(accountNumber)
+		if err != nil || account.IsInternal {
+			return nil, errors.New(messages.AccountNotFound)
+		}
+		return account, nil
+	}
+
+	if err := iban.Validate(toIBAN); err != nil {
+		return nil, errors.New(messages.InvalidIBAN)
+	}
+
+	// Transfers to other banks are not supported, the IBAN has to belong to an account of the bank
+	account, err := accountRepository.FindByIBAN(iban.Normalize(toIBAN))
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && account.IsInternal) {
+		return nil, errors.New(messages.IBANNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if accountNumber != 0 && accountNumber != account.AccountNumber {
+		return nil, errors.New(messages.BadRequest)
+	}
 
+	return account, nil
+}
+
+// dailyWithdrawalLimit returns the daily withdrawal limit of the account in its own currency
+func (s *accountService) dailyWithdrawalLimit(account *models.Account) (money.Amount, error) {
+	if account.DailyWithdrawalLimit.IsPositive() {
+		return account.DailyWithdrawalLimit, nil
+	}
+
+	rate, err := s.exchangeRate(money.DefaultCurrency, account.Currency)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	// Rounded down, so the converted limit is never more than the default limit
+	return enum.DailyWithdrawalLimit.Mul(rate.Rat(), money.Down), nil
+}
+
+// checkTransferLimits makes sure a transfer of the base currency amount from the account stays within its limits.
+// The owner is locked first, the customer and global limits count the transfers from all accounts of the owner
+// and only the sender account is locked by the caller.
+func (s *accountService) checkTransferLimits(account *models.Account, baseAmount money.Amount, now time.Time) error {
+	if err := s.userRepository.Lock(account.OwnerId); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	usages, err := transferLimitUsages(s.transferLimitRepository, s.transferRepository, account, now)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return exceededLimit(usages, baseAmount)
+}
+
+// transferFee returns the fee of a transfer between the accounts in the base currency and the rule it was calculated with
+func (s *accountService) transferFee(senderAccount, receiverAccount *models.Account, baseAmount money.Amount) (money.Amount, string, error) {
+	schedules, err := s.feeScheduleRepository.FindApplicable(senderAccount.Owner.Segment, senderAccount.Type)
+	if err != nil {
+		return money.Zero, "", errors.New(messages.UnexpectedError)
+	}
+
+	schedule := selectFeeSchedule(schedules)
+	if schedule == nil {
+		return money.Zero, "", errors.New(messages.UnexpectedError)
+	}
+
+	if schedule.FreeOwnAccounts && senderAccount.OwnerId == receiverAccount.OwnerId {
+		return money.Zero, ownAccountsFeeRule(*schedule), nil
+	}
+
+	fee, rule := calculateFee(*schedule, baseAmount)
+	return fee, rule, nil
+}
+
+// lockAccounts locks the accounts until the end of the transaction and refreshes their balances and states
+func (s *accountService) lockAccounts(accounts ...*models.Account) error {
+	ids := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		ids = append(ids, account.Id)
+	}
+
+	lockedAccounts, err := s.accountRepository.Lock(ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, lockedAccount := range lockedAccounts {
+		for _, account := range accounts {
+			if account.Id == lockedAccount.Id {
+				account.Balance = lockedAccount.Balance
+				account.DebitsFrozen = lockedAccount.DebitsFrozen
+				account.CreditsFrozen = lockedAccount.CreditsFrozen
+				account.IsActive = lockedAccount.IsActive
+				account.OverdraftLimit = lockedAccount.OverdraftLimit
+				account.OverdraftRate = lockedAccount.OverdraftRate
+			}
+		}
+	}
+
+	return nil
+}
+
+// heldAmount returns the sum of the active holds of the account
+func (s *accountService) heldAmount(account *models.Account, now time.Time) (money.Amount, error) {
+	held, err := s.holdRepository.SumActive([]string{account.Id}, now)
+	if err != nil {
+		return money.Zero, errors.New(messages.UnexpectedError)
+	}
+
+	return held[account.Id], nil
+}
+
+// availableBalance returns the money the account can spend, its balance and the unused part of its overdraft
+// without its active holds
+func (s *accountService) availableBalance(account *models.Account, now time.Time) (money.Amount, error) {
+	held, err := s.heldAmount(account, now)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	return account.Balance + account.OverdraftLimit - held, nil
+}
+
+// releaseTransferHold ends the hold of a transfer which was not executed
+func (s *accountService) releaseTransferHold(transfer *models.Transfer, status string, now time.Time) error {
+	if err := s.holdRepository.ReleaseByTransferId(transfer.Id, status, transfer.FailureReason, now); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	return nil
+}
+
+// internalAccount finds the internal account of the bank for the code and currency,
+// accounts of currencies other than the base currency are created on first use
+func (s *accountService) internalAccount(code string, currency money.Currency) (*models.Account, error) {
+	account, err := s.accountRepository.FindInternal(code, currency)
+	if err == nil {
+		return account, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	systemUser, err := s.userRepository.FindByEmail(enum.SystemUserEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createAccount(models.Account{
+		IBAN:         fmt.Sprintf("TEKBANK-INTERNAL-%s-%s", strings.ToUpper(code), currency),
+		OwnerId:      systemUser.Id,
+		Currency:     currency,
+		IsInternal:   true,
+		InternalCode: code,
+		CreatedBy:    systemUser.Id,
+		UpdatedBy:    systemUser.Id,
+	}, "")
+}
+
+// createAccount creates the account with a new account number and a new IBAN of the country,
+// internal accounts keep their IBAN. The numbers are generated again if they are already used.
+func (s *accountService) createAccount(account models.Account, isoCountryCode string) (*models.Account, error) {
+	if isoCountryCode == "" {
+		isoCountryCode = enum.DefaultCountryCode
+	}
+
+	for attempt := 0; attempt < enum.NumberAttempts; attempt++ {
+		if !account.IsInternal {
+			iban, err := s.pkgCrypto.RandomIBAN(isoCountryCode)
+			if err != nil {
+				return nil, errors.New(messages.UnsupportedCountry)
+			}
+			account.IBAN = iban
+		}
+		account.AccountNumber = s.pkgCrypto.RandomNumber()
+
+		createdAccount, err := s.accountRepository.Create(account)
+		if errors.Is(err, repository.ErrDuplicateNumber) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		return createdAccount, nil
+	}
+
+	return nil, errors.New(messages.UnexpectedError)
+}
+
+// createUser creates the user with a new customer number, the number is generated again if it is already used
+func (s *accountService) createUser(user models.User) (*models.User, error) {
+	for attempt := 0; attempt < enum.NumberAttempts; attempt++ {
+		user.CustomerNumber = s.pkgCrypto.RandomNumber()
+
+		createdUser, err := s.userRepository.Create(user)
+		if errors.Is(err, repository.ErrDuplicateNumber) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		return createdUser, nil
+	}
+
+	return nil, errors.New(messages.UnexpectedError)
+}
+
+// sendMail sends the mail in the background and waits at most two seconds for the result
+func (s *accountService) sendMail(contents ...gomailer.Content) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		for _, content := range contents {
+			if err := s.pkgMailer.Send(content); err != nil {
+				errCh <- err
+				return
+			}
+		}
+		errCh <- nil
+	}()
+
+	// Use a select statement to wait for an error or a timeout
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(2 * time.Second):
+		return nil
+	}
 }
 
 func (s *accountService) RegisterAccount(ctx context.Context, request dto.RegisterAccountRequest) error {
@@ -69,6 +487,11 @@ func (s *accountService) RegisterAccount(ctx context.Context, request dto.Regist
 		return errors.New(messages.UnexpectedError)
 	}
 
+	currency, err := s.accountCurrency(request.Currency)
+	if err != nil {
+		return err
+	}
+
 	randomPassword := s.pkgCrypto.RandomPassword()
 
 	// Hash the password
@@ -80,7 +503,6 @@ func (s *accountService) RegisterAccount(ctx context.Context, request dto.Regist
 	// Register a new authware
 	user := models.User{
 		IdentityNumber: request.IdentityNumber,
-		CustomerNumber: s.pkgCrypto.RandomNumber(),
 		FirstName:      request.FirstName,
 		LastName:       request.LastName,
 		Email:          request.Email,
@@ -88,55 +510,35 @@ func (s *accountService) RegisterAccount(ctx context.Context, request dto.Regist
 		Password:       hashedPassword,
 	}
 
-	createdUser, err := s.userRepository.Create(user)
+	createdUser, err := s.createUser(user)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return err
 	}
 
 	// Create a new account for the user
 	account := models.Account{
-		IBAN:          s.pkgCrypto.RandomIBAN(request.ISOCountryCode),
-		OwnerId:       createdUser.Id,
-		AccountNumber: s.pkgCrypto.RandomNumber(),
-		Balance:       0,
-		CreatedBy:     createdUser.Id,
-		UpdatedBy:     createdUser.Id,
+		OwnerId:   createdUser.Id,
+		Balance:   0,
+		Currency:  currency,
+		CreatedBy: createdUser.Id,
+		UpdatedBy: createdUser.Id,
 	}
 
-	_, err = s.accountRepository.Create(account)
+	_, err = s.createAccount(account, request.ISOCountryCode)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return err
 	}
 
-	errCh := make(chan error)
-
-	go func() {
-		// Send the password to the user's email
-		content := gomailer.Content{
-			Subject: "TEK Bank - First Password",
-			Body:    "Welcome to TEK Bank! Your first password is: " + randomPassword + ". Please change your password after you login.",
-			To:      []string{user.Email},
-		}
-
-		err = gomailer.SendMail(content)
-		if err != nil {
-			errCh <- err
-		}
-	}()
-
-	// Use a select statement to wait for an error or a timeout
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return errors.New(messages.UnexpectedError)
-		}
-	case <-time.After(2 * time.Second):
-		break
+	// Send the password to the user's email
+	err = s.sendMail(gomailer.Content{
+		Subject: "TEK Bank - First Password",
+		Body:    "Welcome to TEK Bank! Your first password is: " + randomPassword + ". Please change your password after you login.",
+		To:      []string{user.Email},
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
 	}
 
-	// Close the error channel
-	close(errCh)
-
 	return nil
 }
 
@@ -152,31 +554,43 @@ func (s *accountService) CreateNewAccount(ctx context.Context, request dto.Creat
 		return nil, errors.New(messages.UnexpectedError)
 	}
 
-	// Create a random IBAN for the user
-	iban := s.pkgCrypto.RandomIBAN(request.ISOCountryCode)
+	currency, err := s.accountCurrency(request.Currency)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create a new account for the user
+	accountType := request.Type
+	if accountType == "" {
+		accountType = enum.AccountTypeCurrent
+	}
+	if !isValidAccountType(accountType) {
+		return nil, errors.New(messages.InvalidAccountType)
+	}
+
+	// Create a new account for the user with a random IBAN
 	account := models.Account{
-		IBAN:          iban,
-		OwnerId:       request.UserId,
-		Balance:       0,
-		AccountNumber: s.pkgCrypto.RandomNumber(),
-		CreatedBy:     request.UserId,
-		UpdatedBy:     request.UserId,
+		OwnerId:   request.UserId,
+		Balance:   0,
+		Currency:  currency,
+		Type:      accountType,
+		CreatedBy: request.UserId,
+		UpdatedBy: request.UserId,
 	}
 
-	createdAccount, err := s.accountRepository.Create(account)
+	createdAccount, err := s.createAccount(account, request.ISOCountryCode)
 	if err != nil {
-		return nil, errors.New(messages.UnexpectedError)
+		return nil, err
 	}
 
 	response := &dto.CreateNewAccountResponse{
 		UserId:        createdAccount.OwnerId,
 		IBAN:          createdAccount.IBAN,
-		AccountNumber: s.pkgCrypto.RandomNumber(),
+		AccountNumber: createdAccount.AccountNumber,
+		Type:          createdAccount.Type,
 		FirstName:     createdAccount.Owner.FirstName,
 		LastName:      createdAccount.Owner.LastName,
 		Balance:       createdAccount.Balance,
+		Currency:      createdAccount.Currency.String(),
 		IsActive:      createdAccount.IsActive,
 	}
 
@@ -184,14 +598,37 @@ func (s *accountService) CreateNewAccount(ctx context.Context, request dto.Creat
 }
 
 func (s *accountService) AddMoney(ctx context.Context, request dto.AddMoneyRequest) (*dto.AddMoneyResponse, error) {
+	if !request.Amount.IsPositive() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+
 	// Check if the account exists
 	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
 	if err != nil {
 		return nil, errors.New(messages.AccountNotFound)
 	}
 
+	if err := checkCredit(account); err != nil {
+		return nil, err
+	}
+
+	// The deposited money comes from the cash account of the bank
+	cashAccount, err := s.internalAccount(enum.CashAccountCode, account.Currency)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
 	// Add money to the account
-	err = s.accountRepository.UpdateBalance(account.Balance+request.Amount, account.Id)
+	_, err = s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeDeposit,
+		Description: "Deposit",
+		CreatedBy:   account.OwnerId,
+		UpdatedBy:   account.OwnerId,
+		Postings: []models.Posting{
+			{AccountId: cashAccount.Id, Amount: -request.Amount, Currency: account.Currency},
+			{AccountId: account.Id, Amount: request.Amount, Currency: account.Currency},
+		},
+	})
 	if err != nil {
 		return nil, errors.New(messages.UnexpectedError)
 	}
@@ -204,229 +641,776 @@ func (s *accountService) AddMoney(ctx context.Context, request dto.AddMoneyReque
 	response := &dto.AddMoneyResponse{
 		CustomerNumber: updatedAccount.Owner.CustomerNumber,
 		Balance:        updatedAccount.Balance,
+		Currency:       updatedAccount.Currency.String(),
 	}
 
 	return response, nil
 }
 
-func (s *accountService) TransferMoney(ctx context.Context, request dto.TransferMoneyRequest) error {
-	// Check if the sender account exists
-	senderAccount, err := s.accountRepository.FindByAccountNumber(request.FromAccountNumber)
+// Withdraw takes money out of an account of the current user, like withdrawing money from an ATM
+func (s *accountService) Withdraw(ctx context.Context, request dto.WithdrawRequest) (*dto.WithdrawResponse, error) {
+	if !request.Amount.IsPositive() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+
+	account, err := s.ownedAccount(ctx, request.AccountNumber)
 	if err != nil {
-		return errors.New(messages.AccountNotFound)
+		return nil, err
 	}
 
-	// Check if the sender account has enough balance
-	totalAmount := request.Amount + enum.TransferFee
-	if senderAccount.Balance < totalAmount {
-		return errors.New(messages.InSufficientBalance)
+	// Lock the account, so concurrent withdrawals can not pass the balance and limit checks together
+	err = s.lockAccounts(account)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if err := checkDebit(account); err != nil {
+		return nil, err
 	}
 
-	// Create a token for the transaction approval
-	token, err := s.pkgCrypto.GenerateToken(32)
+	now := time.Now()
+	available, err := s.availableBalance(account, now)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, err
 	}
 
-	// Save token to Redis
-	value := struct {
-		FromAccountNumber int64
-		ToAccountNumber   int64
-		Amount            float64
-		TransactionFee    float64
-		Note              string
-		Token             string
-	}{
-		FromAccountNumber: request.FromAccountNumber,
-		ToAccountNumber:   request.ToAccountNumber,
-		Amount:            request.Amount,
-		Note:              request.Note,
-		TransactionFee:    enum.TransferFee,
-		Token:             token,
+	if available < request.Amount {
+		return nil, errors.New(messages.InSufficientBalance)
 	}
 
-	content, err := s.pkgConverter.Stos(value)
+	limit, err := s.dailyWithdrawalLimit(account)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, err
 	}
 
-	// One hour expiration time
-	err = s.accountRepository.SetToken(context.Background(), token, *content)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Withdrawals are negative postings on the account
+	withdrawnToday, err := s.ledgerRepository.SumPostings(account.Id, enum.JournalTypeWithdrawal, startOfDay)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, errors.New(messages.UnexpectedError)
 	}
 
-	transferApprovalLink := fmt.Sprintf("http://localhost/v1/account/transfer-approval?token=%s", token)
-	var body string = `
-			<body>
-				<p>Your Account Number: <strong>` + fmt.Sprint(request.FromAccountNumber) + `</strong></p>
-				<p>Receiver Account Number: <strong>` + fmt.Sprint(request.ToAccountNumber) + `</strong></p>
-				<p>Amount: <strong>` + fmt.Sprint(request.Amount) + `</strong></p>
-				<p>Fee: <strong>` + fmt.Sprint(enum.TransferFee) + `</strong></p>
-				<p>You have a new transfer request. Please click the link below to approve the transaction.</p>
-				<p><a href="` + transferApprovalLink + `">` + transferApprovalLink + `</a></p>
-				<p>If you did not request a transfer, please ignore this email.</p>
-				<br>
-				<p>Best Regards,</p>
-			</body>
-	`
+	remainingLimit := limit + withdrawnToday
+	if request.Amount > remainingLimit {
+		return nil, errors.New(messages.DailyWithdrawalLimitExceeded)
+	}
 
-	errCh := make(chan error)
+	// The withdrawn money goes to the cash account of the bank
+	cashAccount, err := s.internalAccount(enum.CashAccountCode, account.Currency)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
 
-	go func() {
-		// Send the password to the user's email
-		content := gomailer.Content{
-			Subject: "TEK Bank - Transfer Approval",
-			Body:    body,
-			To:      []string{senderAccount.Owner.Email},
-		}
+	journal, err := s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeWithdrawal,
+		Description: "Withdrawal",
+		CreatedBy:   account.OwnerId,
+		UpdatedBy:   account.OwnerId,
+		Postings: []models.Posting{
+			{AccountId: account.Id, Amount: -request.Amount, Currency: account.Currency},
+			{AccountId: cashAccount.Id, Amount: request.Amount, Currency: account.Currency},
+		},
+	})
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		return nil, errors.New(messages.InSufficientBalance)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
 
-		err = gomailer.SendMail(content)
-		if err != nil {
-			errCh <- err
-		}
-	}()
+	err = s.transferHistoryRepository.Create([]models.TransferHistory{{
+		From:              account.AccountNumber,
+		To:                cashAccount.AccountNumber,
+		Amount:            request.Amount,
+		Currency:          account.Currency,
+		ConvertedAmount:   request.Amount,
+		ConvertedCurrency: account.Currency,
+		ExchangeRate:      money.OneRate,
+		Note:              "Withdrawal",
+		Type:              enum.JournalTypeWithdrawal,
+		JournalId:         journal.Id,
+		CreatedBy:         account.OwnerId,
+		UpdatedBy:         account.OwnerId,
+	}})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
 
-	// Use a select statement to wait for an error or a timeout
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return errors.New(messages.UnexpectedError)
-		}
-	case <-time.After(2 * time.Second):
-		break
+	balance := account.Balance - request.Amount
+
+	err = s.sendMail(gomailer.Content{
+		Subject: i18n.CreateMsgWithLanguage(request.Language, messages.WithdrawalMailSubject),
+		Body: i18n.CreateMsgWithLanguage(request.Language, messages.WithdrawalMailBody, map[string]string{
+			"Amount":        money.New(request.Amount, account.Currency).String(),
+			"AccountNumber": fmt.Sprint(account.AccountNumber),
+			"Balance":       money.New(balance, account.Currency).String(),
+		}),
+		To: []string{account.Owner.Email},
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	previous := overdraftState(account.Balance, account.OverdraftLimit)
+	account.Balance = balance
+	if err := s.notifyOverdraft(account, previous, request.Language); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
 	}
 
-	// Close the error channel
-	close(errCh)
+	response := &dto.WithdrawResponse{
+		AccountNumber:       account.AccountNumber,
+		Balance:             balance,
+		Currency:            account.Currency.String(),
+		DailyLimitRemaining: remainingLimit - request.Amount,
+	}
 
-	return nil
+	return response, nil
 }
 
-// TransferApproval approves the transaction
-func (s *accountService) TransferApproval(ctx context.Context, token string) error {
-	// Get the token from Redis
-	value, err := s.accountRepository.GetToken(context.Background(), token)
+// quoteTransfer fixes the converted amount, the exchange rate and the fee of a transfer between the accounts
+func (s *accountService) quoteTransfer(senderAccount, receiverAccount *models.Account, amount money.Amount, note string) (*models.Transfer, error) {
+	// The amount is given in the currency of the sender and converted to the currency of the receiver
+	exchangeRate, err := s.exchangeRate(senderAccount.Currency, receiverAccount.Currency)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, err
 	}
 
-	var content struct {
-		FromAccountNumber int64
-		ToAccountNumber   int64
-		Amount            float64
-		TransactionFee    float64
-		Note              string
-		Token             string
+	// The fee is defined in the base currency and charged in the currency of the sender
+	feeRate, err := s.exchangeRate(money.DefaultCurrency, senderAccount.Currency)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.pkgConverter.Stom(*value, &content)
+	// The limits and the fee schedules are defined in the base currency
+	baseRate, err := s.baseRate(senderAccount.Currency)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, err
 	}
 
+	baseAmount := baseRate.Convert(amount)
+	fee, feeRule, err := s.transferFee(senderAccount, receiverAccount, baseAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Transfer{
+		OwnerId:           senderAccount.OwnerId,
+		FromAccountNumber: senderAccount.AccountNumber,
+		ToAccountNumber:   receiverAccount.AccountNumber,
+		Amount:            amount,
+		Currency:          senderAccount.Currency,
+		ConvertedAmount:   exchangeRate.Convert(amount),
+		ConvertedCurrency: receiverAccount.Currency,
+		ExchangeRate:      exchangeRate,
+		TransactionFee:    feeRate.Convert(fee),
+		FeeRule:           feeRule,
+		BaseAmount:        baseAmount,
+		Note:              note,
+		CreatedBy:         senderAccount.OwnerId,
+		UpdatedBy:         senderAccount.OwnerId,
+	}, nil
+}
+
+// executeTransfer moves the money of an approved transfer and writes its history, the journal is set on the
+// transfer. The sender and the receiver accounts are returned for the notifications.
+func (s *accountService) executeTransfer(content *models.Transfer) (*models.Account, *models.Account, error) {
 	// Check if the sender account exists
 	senderAccount, err := s.accountRepository.FindByAccountNumber(content.FromAccountNumber)
 	if err != nil {
-		return errors.New(messages.AccountNotFound)
+		return nil, nil, errors.New(messages.AccountNotFound)
 	}
 
 	// Check if the receiver account exists
 	receiverAccount, err := s.accountRepository.FindByAccountNumber(content.ToAccountNumber)
 	if err != nil {
-		return errors.New(messages.AccountNotFound)
+		return nil, nil, errors.New(messages.AccountNotFound)
+	}
+
+	// Lock both accounts, so the balance check below stays valid until the transaction ends
+	err = s.lockAccounts(senderAccount, receiverAccount)
+	if err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
+	}
+
+	// The accounts may have been frozen or closed since the transfer was requested
+	if err := checkDebit(senderAccount); err != nil {
+		return nil, nil, err
+	}
+	if err := checkCredit(receiverAccount); err != nil {
+		return nil, nil, err
+	}
+
+	// The hold of the transfer is captured, the holds of other transfers and admins still count
+	now := time.Now()
+	if content.Id != "" {
+		if err := s.holdRepository.ReleaseByTransferId(content.Id, enum.HoldCaptured, "", now); err != nil {
+			return nil, nil, errors.New(messages.UnexpectedError)
+		}
 	}
 
 	// Check if the sender account has enough balance
+	available, err := s.availableBalance(senderAccount, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	totalAmount := content.Amount + content.TransactionFee
-	if senderAccount.Balance < totalAmount {
-		return errors.New(messages.InSufficientBalance)
+	if available < totalAmount {
+		return nil, nil, errors.New(messages.InSufficientBalance)
 	}
 
-	// Deduct the amount from the sender account
-	err = s.accountRepository.UpdateBalance(senderAccount.Balance-totalAmount, senderAccount.Id)
+	// Check the limits again, other transfers of the sender may have been executed since it was requested
+	err = s.checkTransferLimits(senderAccount, content.BaseAmount, now)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, nil, err
+	}
+
+	// Other transfers to trusted beneficiaries may have used the limit since it was checked, the owner is locked now
+	if content.ApprovalMethod == enum.ApprovalTrustedBeneficiary {
+		withinLimit, err := s.withinTrustedBeneficiaryLimit(senderAccount.OwnerId, content.BaseAmount, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !withinLimit {
+			return nil, nil, errors.New(messages.TrustedBeneficiaryLimitExceeded)
+		}
 	}
 
-	// Add the amount to the receiver account
-	err = s.accountRepository.UpdateBalance(receiverAccount.Balance+content.Amount, receiverAccount.Id)
+	// The fee is collected in the fee income account of the bank
+	feeAccount, err := s.internalAccount(enum.FeeIncomeAccountCode, content.Currency)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, nil, errors.New(messages.UnexpectedError)
+	}
+
+	postings := []models.Posting{
+		{AccountId: senderAccount.Id, Amount: -content.Amount, Currency: content.Currency},
+		{AccountId: receiverAccount.Id, Amount: content.ConvertedAmount, Currency: content.ConvertedCurrency},
+	}
+
+	// Free transfers have no fee postings
+	if content.TransactionFee.IsPositive() {
+		postings = append(postings,
+			models.Posting{AccountId: senderAccount.Id, Amount: -content.TransactionFee, Currency: content.Currency},
+			models.Posting{AccountId: feeAccount.Id, Amount: content.TransactionFee, Currency: content.Currency},
+		)
+	}
+
+	// The bank buys the currency of the sender and sells the currency of the receiver
+	if content.Currency != content.ConvertedCurrency {
+		fromPosition, err := s.internalAccount(enum.FxPositionAccountCode, content.Currency)
+		if err != nil {
+			return nil, nil, errors.New(messages.UnexpectedError)
+		}
+
+		toPosition, err := s.internalAccount(enum.FxPositionAccountCode, content.ConvertedCurrency)
+		if err != nil {
+			return nil, nil, errors.New(messages.UnexpectedError)
+		}
+
+		postings = append(postings,
+			models.Posting{AccountId: fromPosition.Id, Amount: content.Amount, Currency: content.Currency},
+			models.Posting{AccountId: toPosition.Id, Amount: -content.ConvertedAmount, Currency: content.ConvertedCurrency},
+		)
+	}
+
+	// Move the amount and the fee in one journal
+	journal, err := s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeTransfer,
+		Description: content.Note,
+		CreatedBy:   senderAccount.OwnerId,
+		UpdatedBy:   senderAccount.OwnerId,
+		Postings:    postings,
+	})
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		return nil, nil, errors.New(messages.InSufficientBalance)
+	}
+	if err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
 	}
 
 	// Multiple insertions to transfer history table
 	var transferHistories []models.TransferHistory
 	transferHistories = append(transferHistories, models.TransferHistory{
-		From:      content.FromAccountNumber,
-		To:        content.ToAccountNumber,
-		Amount:    content.Amount,
-		Note:      content.Note,
-		CreatedBy: senderAccount.OwnerId,
-		UpdatedBy: senderAccount.OwnerId,
+		From:              content.FromAccountNumber,
+		To:                content.ToAccountNumber,
+		Amount:            content.Amount,
+		Currency:          content.Currency,
+		ConvertedAmount:   content.ConvertedAmount,
+		ConvertedCurrency: content.ConvertedCurrency,
+		ExchangeRate:      content.ExchangeRate,
+		Note:              content.Note,
+		Type:              enum.JournalTypeTransfer,
+		JournalId:         journal.Id,
+		CreatedBy:         senderAccount.OwnerId,
+		UpdatedBy:         senderAccount.OwnerId,
 	})
 
-	transferHistories = append(transferHistories, models.TransferHistory{
-		From:      content.FromAccountNumber,
-		To:        content.ToAccountNumber,
-		Amount:    content.TransactionFee,
-		Note:      "Transaction Fee",
-		IsFee:     true,
-		CreatedBy: senderAccount.OwnerId,
-		UpdatedBy: senderAccount.OwnerId,
-	})
+	if content.TransactionFee.IsPositive() {
+		transferHistories = append(transferHistories, models.TransferHistory{
+			From:              content.FromAccountNumber,
+			To:                feeAccount.AccountNumber,
+			Amount:            content.TransactionFee,
+			Currency:          content.Currency,
+			ConvertedAmount:   content.TransactionFee,
+			ConvertedCurrency: content.Currency,
+			ExchangeRate:      money.OneRate,
+			Note:              "Transaction Fee",
+			IsFee:             true,
+			FeeRule:           content.FeeRule,
+			Type:              enum.JournalTypeTransfer,
+			JournalId:         journal.Id,
+			CreatedBy:         senderAccount.OwnerId,
+			UpdatedBy:         senderAccount.OwnerId,
+		})
+	}
 
 	err = s.transferHistoryRepository.Create(transferHistories)
 	if err != nil {
-		return errors.New(messages.UnexpectedError)
+		return nil, nil, errors.New(messages.UnexpectedError)
 	}
 
-	// Delete the token from Redis
-	err = s.accountRepository.DeleteToken(context.Background(), token)
-	if err != nil {
-		return errors.New(messages.UnexpectedError)
+	content.JournalId = journal.Id
+
+	// The sender is told when the transfer starts using the overdraft or goes beyond it
+	previous := overdraftState(senderAccount.Balance, senderAccount.OverdraftLimit)
+	senderAccount.Balance -= totalAmount
+	if err := s.notifyOverdraft(senderAccount, previous, ""); err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
 	}
 
-	errCh := make(chan error)
+	return senderAccount, receiverAccount, nil
+}
 
-	// Send an email to the sender and receiver
-	go func() {
-		// Send the password to the user's email
-		contentSender := gomailer.Content{
+// notifyTransfer sends an email to the sender and the receiver of a completed transfer
+func (s *accountService) notifyTransfer(senderAccount, receiverAccount *models.Account) error {
+	return s.sendMail(
+		gomailer.Content{
 			Subject: "TEK Bank - Transfer Approval",
 			Body:    "Your transfer has been successfully completed.",
 			To:      []string{senderAccount.Owner.Email},
-		}
+		},
+		gomailer.Content{
+			Subject: "TEK Bank - Transfer Approval",
+			Body:    "You have received a new transfer.",
+			To:      []string{receiverAccount.Owner.Email},
+		},
+	)
+}
 
-		err = gomailer.SendMail(contentSender)
+// TransferMoney creates a transfer waiting for the approval of the sender. Depending on the approval method
+// of the sender, an approval link is sent via e-mail or a one-time code via e-mail or SMS.
+// Transfers to trusted beneficiaries up to the daily limit of the bank are executed at once.
+func (s *accountService) TransferMoney(ctx context.Context, request dto.TransferMoneyRequest) (*dto.TransferItem, error) {
+	if !request.Amount.IsPositive() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+
+	// Check if the sender account exists and belongs to the current user
+	senderAccount, err := s.ownedAccount(ctx, request.FromAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// A saved beneficiary of the sender replaces the receiver of the request
+	var beneficiary *models.Beneficiary
+	if request.BeneficiaryId != "" {
+		beneficiary, err = ownedBeneficiary(s.beneficiaryRepository, senderAccount.OwnerId, request.BeneficiaryId)
 		if err != nil {
-			errCh <- err
+			return nil, err
+		}
+		if request.ToIBAN != "" || (request.ToAccountNumber != 0 && request.ToAccountNumber != beneficiary.AccountNumber) {
+			return nil, errors.New(messages.BadRequest)
 		}
+		request.ToAccountNumber = beneficiary.AccountNumber
+	}
 
-		contentReceiver := gomailer.Content{
-			Subject: "TEK Bank - Transfer Approval",
-			Body:    "You have received a new transfer.",
-			To:      []string{receiverAccount.Owner.Email},
+	// Check if the receiver account exists
+	receiverAccount, err := s.receiverAccount(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Frozen and closed accounts are checked again when the transfer is executed
+	if err := checkDebit(senderAccount); err != nil {
+		return nil, err
+	}
+	if err := checkCredit(receiverAccount); err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.quoteTransfer(senderAccount, receiverAccount, request.Amount, request.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transfers to trusted beneficiaries up to the daily limit of the bank need no approval,
+	// the balance and the limits are checked when they are executed
+	if beneficiary != nil {
+		transfer.BeneficiaryId = beneficiary.Id
+		withinLimit := false
+		if beneficiary.Trusted {
+			withinLimit, err = s.withinTrustedBeneficiaryLimit(senderAccount.OwnerId, transfer.BaseAmount, time.Now())
+			if err != nil {
+				return nil, err
+			}
 		}
+		if withinLimit {
+			transfer.ApprovalMethod = enum.ApprovalTrustedBeneficiary
+			executedTransfer, err := s.executeAtOnce(transfer)
+			if err != nil {
+				return nil, err
+			}
+
+			item := transferItem(*executedTransfer)
+			return &item, nil
+		}
+	}
+
+	// Lock the sender, so concurrent requests can not reserve the same money
+	err = s.lockAccounts(senderAccount)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// Check if the sender account has enough balance, the money of the other pending transfers is held
+	now := time.Now()
+	available, err := s.availableBalance(senderAccount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAmount := transfer.Amount + transfer.TransactionFee
+	if available < totalAmount {
+		return nil, errors.New(messages.InSufficientBalance)
+	}
+
+	err = s.checkTransferLimits(senderAccount, transfer.BaseAmount, now)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.newApproval(senderAccount.Owner.ApprovalMethod, now)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Status = enum.TransferPendingApproval
+	transfer.ApprovalMethod = approval.method
+	transfer.TokenHash = approval.tokenHash
+	transfer.CodeHash = approval.codeHash
+	transfer.ExpiresAt = &approval.expiresAt
+
+	createdTransfer, err := s.transferRepository.Create(*transfer)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// The amount and the fee are held until the transfer is executed, rejected, expired or cancelled
+	_, err = s.holdRepository.Create(models.Hold{
+		AccountId:  senderAccount.Id,
+		Amount:     totalAmount,
+		Currency:   senderAccount.Currency,
+		Type:       enum.HoldTypeTransfer,
+		TransferId: createdTransfer.Id,
+		Status:     enum.HoldActive,
+		ExpiresAt:  createdTransfer.ExpiresAt,
+		CreatedBy:  senderAccount.OwnerId,
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	err = s.sendTransferApproval(senderAccount, receiverAccount, createdTransfer, approval.secret)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := transferItem(*createdTransfer)
+	return &item, nil
+}
+
+// approval is the secret of a new approval, the token of the approval link or the one-time code.
+// Only the hash of the secret is stored.
+type approval struct {
+	method    string
+	secret    string
+	tokenHash string
+	codeHash  string
+	expiresAt time.Time
+}
 
-		err = gomailer.SendMail(contentReceiver)
+// newApproval creates the secret for the approval method of the user, unknown methods use the approval link
+func (s *accountService) newApproval(method string, now time.Time) (*approval, error) {
+	switch method {
+	case enum.ApprovalMethodEmailCode, enum.ApprovalMethodSMSCode:
+		secret, err := s.pkgCrypto.RandomCode(enum.TransferCodeLength)
 		if err != nil {
-			errCh <- err
+			return nil, errors.New(messages.UnexpectedError)
 		}
-	}()
-
-	// Use a select statement to wait for an error or a timeout
-	select {
-	case err := <-errCh:
+		return &approval{method: method, secret: secret, codeHash: hashToken(secret), expiresAt: now.Add(enum.TransferCodeTTL)}, nil
+	default:
+		secret, err := s.pkgCrypto.GenerateToken(32)
 		if err != nil {
-			return errors.New(messages.UnexpectedError)
+			return nil, errors.New(messages.UnexpectedError)
 		}
-	case <-time.After(2 * time.Second):
-		break
+		return &approval{method: enum.ApprovalMethodEmailLink, secret: secret, tokenHash: hashToken(secret), expiresAt: now.Add(enum.TransferApprovalTTL)}, nil
+	}
+}
+
+// sendTransferApproval sends the approval link or the one-time code of the transfer to the sender
+func (s *accountService) sendTransferApproval(senderAccount, receiverAccount *models.Account, transfer *models.Transfer, secret string) error {
+	if transfer.ApprovalMethod == enum.ApprovalMethodSMSCode {
+		return s.pkgSMS.Send(senderAccount.Owner.PhoneNumber, fmt.Sprintf(
+			"TEK Bank: %s is your code to approve the transfer of %s to %d. It is valid for %d minutes.",
+			secret, money.New(transfer.Amount, transfer.Currency), receiverAccount.AccountNumber, int(enum.TransferCodeTTL.Minutes()),
+		))
+	}
+
+	var exchange string
+	if transfer.Currency != transfer.ConvertedCurrency {
+		exchange = `
+				<p>Exchange Rate: <strong>` + transfer.ExchangeRate.String() + `</strong></p>
+				<p>Receiver Gets: <strong>` + money.New(transfer.ConvertedAmount, transfer.ConvertedCurrency).String() + `</strong></p>`
+	}
+
+	var approval string
+	if transfer.ApprovalMethod == enum.ApprovalMethodEmailCode {
+		approval = `
+				<p>You have a new transfer request. Please approve the transaction with the code below.</p>
+				<p><strong>` + secret + `</strong></p>
+				<p>The code is valid until ` + transfer.ExpiresAt.Format(time.RFC1123) + `.</p>`
+	} else {
+		transferApprovalLink := fmt.Sprintf("http://localhost/v1/account/transfer-approval?token=%s", secret)
+		approval = `
+				<p>You have a new transfer request. Please click the link below to approve the transaction.</p>
+				<p><a href="` + transferApprovalLink + `">` + transferApprovalLink + `</a></p>
+				<p>The link is valid until ` + transfer.ExpiresAt.Format(time.RFC1123) + `.</p>`
+	}
+
+	var body string = `
+			<body>
+				<p>Your Account Number: <strong>` + fmt.Sprint(senderAccount.AccountNumber) + `</strong></p>
+				<p>Receiver Account Number: <strong>` + fmt.Sprint(receiverAccount.AccountNumber) + `</strong></p>
+				<p>Receiver IBAN: <strong>` + iban.Format(receiverAccount.IBAN) + `</strong></p>
+				<p>Amount: <strong>` + money.New(transfer.Amount, transfer.Currency).String() + `</strong></p>
+				<p>Fee: <strong>` + money.New(transfer.TransactionFee, transfer.Currency).String() + `</strong> (` + transfer.FeeRule + `)</p>` + exchange + approval + `
+				<p>If you did not request a transfer, please ignore this email.</p>
+				<br>
+				<p>Best Regards,</p>
+			</body>
+	`
+
+	return s.sendMail(gomailer.Content{
+		Subject: "TEK Bank - Transfer Approval",
+		Body:    body,
+		To:      []string{senderAccount.Owner.Email},
+	})
+}
+
+// TransferApproval executes the pending transfer of the approval link token
+func (s *accountService) TransferApproval(ctx context.Context, token string) error {
+	if s.tx == nil {
+		return errors.New(messages.TransactionFailed)
+	}
+
+	transfer, err := s.transferRepository.LockByTokenHash(hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.TransferNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return s.approveTransfer(transfer, time.Now())
+}
+
+// ApproveTransfer executes the pending transfer of the current user with its one-time code.
+// Wrong codes are counted, the transfer is rejected after too many of them.
+func (s *accountService) ApproveTransfer(ctx context.Context, request dto.ApproveTransferRequest) error {
+	if s.tx == nil {
+		return errors.New(messages.TransactionFailed)
+	}
+
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	// The rows of bulk transfers are approved with their bulk transfer
+	transfer, err := s.transferRepository.LockById(request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (transfer.OwnerId != currentUser.Id || transfer.BulkTransferId != "")) {
+		return errors.New(messages.TransferNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	// Transfers approved with a link have no code
+	if transfer.CodeHash == "" {
+		return errors.New(messages.InvalidTransferCode)
+	}
+
+	now := time.Now()
+	if transfer.Status == enum.TransferPendingApproval && !isTransferExpired(transfer, now) &&
+		subtle.ConstantTimeCompare([]byte(hashToken(request.Code)), []byte(transfer.CodeHash)) != 1 {
+		transfer.CodeAttempts++
+		if transfer.CodeAttempts < enum.TransferCodeMaxAttempts {
+			if err := s.transferRepository.Update(*transfer); err != nil {
+				return errors.New(messages.UnexpectedError)
+			}
+			return errors.New(messages.InvalidTransferCode)
+		}
+
+		transfer.FailureReason = messages.TransferCodeAttemptsExceeded
+		if err := s.changeTransferStatus(transfer, enum.TransferRejected, now); err != nil {
+			return err
+		}
+		if err := s.releaseTransferHold(transfer, enum.HoldReleased, now); err != nil {
+			return err
+		}
+		return errors.New(messages.TransferCodeAttemptsExceeded)
+	}
+
+	return s.approveTransfer(transfer, now)
+}
+
+// approveTransfer approves and executes the pending transfer. A transfer which can not be executed anymore,
+// because an account is gone, frozen or closed or its currency is not supported, is rejected. The rejection and the expiry are
+// recorded even though an error is returned, the caller should keep the changes of the transaction for them.
+func (s *accountService) approveTransfer(transfer *models.Transfer, now time.Time) error {
+	if transfer.Status == enum.TransferPendingApproval && isTransferExpired(transfer, now) {
+		if err := s.changeTransferStatus(transfer, enum.TransferExpired, now); err != nil {
+			return err
+		}
+		if err := s.releaseTransferHold(transfer, enum.HoldExpired, now); err != nil {
+			return err
+		}
+		return errors.New(messages.TransferExpired)
+	}
+
+	if err := s.changeTransferStatus(transfer, enum.TransferApproved, now); err != nil {
+		return err
+	}
+
+	// The transfer runs in a savepoint, so a rejected transfer leaves no money movement behind
+	var senderAccount, receiverAccount *models.Account
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		senderAccount, receiverAccount, err = s.withTx(tx).executeTransfer(transfer)
+		return err
+	})
+	if err != nil {
+		if err.Error() != messages.AccountNotFound && err.Error() != messages.UnsupportedCurrency &&
+			err.Error() != messages.AccountFrozen && err.Error() != messages.AccountClosed {
+			return err
+		}
+
+		transfer.FailureReason = err.Error()
+		if err := s.changeTransferStatus(transfer, enum.TransferRejected, now); err != nil {
+			return err
+		}
+		if err := s.releaseTransferHold(transfer, enum.HoldReleased, now); err != nil {
+			return err
+		}
+		return errors.New(messages.TransferRejected)
+	}
+
+	if err := s.changeTransferStatus(transfer, enum.TransferExecuted, now); err != nil {
+		return err
 	}
 
-	// Close the error channel
-	close(errCh)
+	// Send an email to the sender and receiver
+	err = s.notifyTransfer(senderAccount, receiverAccount)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
 
 	return nil
 }
+
+// changeTransferStatus moves the transfer to the status and saves it, invalid changes are refused
+func (s *accountService) changeTransferStatus(transfer *models.Transfer, status string, now time.Time) error {
+	if err := transitionTransfer(transfer, status, now); err != nil {
+		return err
+	}
+
+	if err := s.transferRepository.Update(*transfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// ExecuteTransfer executes a transfer of the owner right away without asking for an approval,
+// it is used for transfers the owner approved before like scheduled transfers
+func (s *accountService) ExecuteTransfer(ctx context.Context, ownerId string, request dto.TransferMoneyRequest) error {
+	if !request.Amount.IsPositive() {
+		return errors.New(messages.InvalidAmount)
+	}
+
+	senderAccount, err := s.accountRepository.FindByAccountNumber(request.FromAccountNumber)
+	if err != nil {
+		return errors.New(messages.AccountNotFound)
+	}
+
+	if senderAccount.OwnerId != ownerId {
+		return errors.New(messages.Unauthorized)
+	}
+
+	receiverAccount, err := s.receiverAccount(request)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := s.quoteTransfer(senderAccount, receiverAccount, request.Amount, request.Note)
+	if err != nil {
+		return err
+	}
+
+	// The transfer was approved before
+	_, err = s.executeAtOnce(transfer)
+	return err
+}
+
+// withinTrustedBeneficiaryLimit reports whether a transfer of the owner to a trusted beneficiary can be executed
+// without an approval. The transfers executed without an approval in the period of the limit count against it.
+func (s *accountService) withinTrustedBeneficiaryLimit(ownerId string, amount money.Amount, now time.Time) (bool, error) {
+	if amount > s.trustedBeneficiaryLimit {
+		return false, nil
+	}
+
+	used, err := s.transferRepository.SumExecutedTrustedByOwner(ownerId, periodStart(enum.TrustedBeneficiaryLimitPeriod, now))
+	if err != nil {
+		return false, errors.New(messages.UnexpectedError)
+	}
+
+	return used+amount <= s.trustedBeneficiaryLimit, nil
+}
+
+// executeAtOnce executes a transfer which needs no approval, it is recorded as approved and executed at once
+func (s *accountService) executeAtOnce(transfer *models.Transfer) (*models.Transfer, error) {
+	senderAccount, receiverAccount, err := s.executeTransfer(transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer.Status = enum.TransferApproved
+	transfer.ApprovedAt = &now
+	if err := transitionTransfer(transfer, enum.TransferExecuted, now); err != nil {
+		return nil, err
+	}
+
+	createdTransfer, err := s.transferRepository.Create(*transfer)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	err = s.notifyTransfer(senderAccount, receiverAccount)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return createdTransfer, nil
+}
@@ -6,13 +6,23 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"tek-bank/cmd/api/middleware/authware"
 	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
 	"tek-bank/internal/dto"
 	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
 	"tek-bank/internal/mocks/repository"
-	"tek-bank/mocks/converter"
 	"tek-bank/mocks/crypto"
+	"tek-bank/mocks/gomailer"
+	"tek-bank/mocks/sms"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/iban"
+	"tek-bank/pkg/money"
 	"testing"
+	"time"
 )
 
 var mockData = []models.User{
@@ -24,6 +34,7 @@ var mockData = []models.User{
 		Password:       "password",
 		FirstName:      "John",
 		LastName:       "Doe",
+		IsActive:       true,
 	},
 	{
 		Id:             "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1c",
@@ -33,6 +44,7 @@ var mockData = []models.User{
 		Password:       "password",
 		FirstName:      "Jane",
 		LastName:       "Doe",
+		IsActive:       true,
 	},
 }
 
@@ -43,8 +55,11 @@ var mockAccountData = []models.Account{
 		AccountNumber: 1000000001,
 		IBAN:          "US1000000001",
 		Balance:       0,
+		Currency:      money.DefaultCurrency,
+		Type:          enum.AccountTypeSavings,
 		CreatedBy:     "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
 		UpdatedBy:     "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
+		IsActive:      true,
 		Owner:         mockData[0],
 	},
 	{
@@ -53,20 +68,52 @@ var mockAccountData = []models.Account{
 		AccountNumber: 1000000002,
 		IBAN:          "US1000000002",
 		Balance:       0,
+		Currency:      money.DefaultCurrency,
 		CreatedBy:     "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1c",
 		UpdatedBy:     "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1c",
+		IsActive:      true,
 		Owner:         mockData[1],
 	},
 }
 
+var mockCashAccount = models.Account{
+	Id:            "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b10",
+	AccountNumber: 1,
+	IBAN:          "TEKBANK-INTERNAL-CASH",
+	Currency:      money.DefaultCurrency,
+	IsInternal:    true,
+	InternalCode:  enum.CashAccountCode,
+	IsActive:      true,
+}
+
+// mockFeeSchedule is the default fee schedule of the bank
+var mockFeeSchedule = models.FeeSchedule{
+	Name:            "Standard",
+	Type:            enum.FeeTypeFlat,
+	FlatFee:         money.MustParse("4.22"),
+	FreeOwnAccounts: true,
+}
+
 var fiberCtx *fiber.Ctx
 var s AccountService
 
 var userRepoMock *repository.MockUserRepository
 var accountRepoMock *repository.MockAccountRepository
 var transferRepoMock *repository.MockTransferHistoryRepository
+var ledgerRepoMock *repository.MockLedgerRepository
+var exchangeRateRepoMock *repository.MockExchangeRateRepository
+var transferRequestRepoMock *repository.MockTransferRepository
+var transferLimitRepoMock *repository.MockTransferLimitRepository
+var feeScheduleRepoMock *repository.MockFeeScheduleRepository
+var accountEventRepoMock *repository.MockAccountEventRepository
+var holdRepoMock *repository.MockHoldRepository
+var interestRepoMock *repository.MockInterestRepository
+var bulkTransferRepoMock *repository.MockBulkTransferRepository
+var beneficiaryRepoMock *repository.MockBeneficiaryRepository
+var notificationRepoMock *repository.MockNotificationRepository
 var pkgCryptoMock *crypto.MockCrypto
-var pkgConverterMock *converter.MockConverter
+var pkgMailerMock *gomailer.MockMailer
+var pkgSMSMock *sms.MockSender
 
 func setupAccountTest(t *testing.T) func() {
 	ct := gomock.NewController(t)
@@ -83,10 +130,22 @@ func setupAccountTest(t *testing.T) func() {
 	userRepoMock = repository.NewMockUserRepository(ct)
 	accountRepoMock = repository.NewMockAccountRepository(ct)
 	transferRepoMock = repository.NewMockTransferHistoryRepository(ct)
+	ledgerRepoMock = repository.NewMockLedgerRepository(ct)
+	exchangeRateRepoMock = repository.NewMockExchangeRateRepository(ct)
+	transferRequestRepoMock = repository.NewMockTransferRepository(ct)
+	transferLimitRepoMock = repository.NewMockTransferLimitRepository(ct)
+	feeScheduleRepoMock = repository.NewMockFeeScheduleRepository(ct)
+	accountEventRepoMock = repository.NewMockAccountEventRepository(ct)
+	holdRepoMock = repository.NewMockHoldRepository(ct)
+	interestRepoMock = repository.NewMockInterestRepository(ct)
+	bulkTransferRepoMock = repository.NewMockBulkTransferRepository(ct)
+	beneficiaryRepoMock = repository.NewMockBeneficiaryRepository(ct)
+	notificationRepoMock = repository.NewMockNotificationRepository(ct)
 	pkgCryptoMock = crypto.NewMockCrypto(ct)
-	pkgConverterMock = converter.NewMockConverter(ct)
+	pkgMailerMock = gomailer.NewMockMailer(ct)
+	pkgSMSMock = sms.NewMockSender(ct)
 
-	s = NewAccountService(accountRepoMock, userRepoMock, transferRepoMock, pkgCryptoMock, pkgConverterMock)
+	s = NewAccountService(accountRepoMock, userRepoMock, transferRepoMock, ledgerRepoMock, exchangeRateRepoMock, transferRequestRepoMock, transferLimitRepoMock, feeScheduleRepoMock, accountEventRepoMock, holdRepoMock, interestRepoMock, beneficiaryRepoMock, notificationRepoMock, pkgCryptoMock, pkgMailerMock, pkgSMSMock)
 	return func() {
 		s = nil
 		defer ct.Finish()
@@ -123,25 +182,25 @@ func TestAccountService_RegisterAccount_Success(t *testing.T) {
 	}
 
 	userRepoMock.EXPECT().Create(user).Return(&user, nil).Times(1)
-	pkgCryptoMock.EXPECT().RandomIBAN("US").Return("US1000000003").Times(1)
+	pkgCryptoMock.EXPECT().RandomIBAN("US").Return("US1000000003", nil).Times(1)
 
 	account := models.Account{
 		OwnerId:       user.Id,
 		AccountNumber: 1000000003,
 		IBAN:          "US1000000003",
 		Balance:       0,
+		Currency:      money.DefaultCurrency,
 		CreatedBy:     user.Id,
 		UpdatedBy:     user.Id,
 	}
 
 	accountRepoMock.EXPECT().Create(account).Return(&account, nil).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err != nil {
 		t.Errorf("Error was not expected: %v", err)
 	}
-
-	assert.Equal(t, status, fiber.StatusCreated)
 }
 
 func TestAccountService_RegisterAccount_AlreadyExists(t *testing.T) {
@@ -160,12 +219,12 @@ func TestAccountService_RegisterAccount_AlreadyExists(t *testing.T) {
 	// Test logic here
 	userRepoMock.EXPECT().FindByEmail(request.Email).Return(&models.User{}, nil)
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusConflict)
+	assert.Equal(t, messages.UserAlreadyExists, err.Error())
 }
 
 func TestAccountService_RegisterAccount_UnexpectedError(t *testing.T) {
@@ -184,12 +243,12 @@ func TestAccountService_RegisterAccount_UnexpectedError(t *testing.T) {
 	// Test logic here
 	userRepoMock.EXPECT().FindByEmail(request.Email).Return(&models.User{}, errors.New("unexpected error"))
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusInternalServerError)
+	assert.Equal(t, messages.UnexpectedError, err.Error())
 }
 
 func TestAccountService_RegisterAccount_ErrorHashingPassword(t *testing.T) {
@@ -210,12 +269,12 @@ func TestAccountService_RegisterAccount_ErrorHashingPassword(t *testing.T) {
 	pkgCryptoMock.EXPECT().RandomPassword().Return("password").Times(1)
 	pkgCryptoMock.EXPECT().HashPassword("password").Return("", errors.New("error hashing password")).Times(1)
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusInternalServerError)
+	assert.Equal(t, messages.UnexpectedError, err.Error())
 }
 
 func TestAccountService_RegisterAccount_ErrorCreatingUser(t *testing.T) {
@@ -249,12 +308,12 @@ func TestAccountService_RegisterAccount_ErrorCreatingUser(t *testing.T) {
 
 	userRepoMock.EXPECT().Create(user).Return(nil, errors.New("error creating user")).Times(1)
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusInternalServerError)
+	assert.Equal(t, messages.UnexpectedError, err.Error())
 }
 
 func TestAccountService_RegisterAccount_ErrorCreatingAccount(t *testing.T) {
@@ -287,25 +346,26 @@ func TestAccountService_RegisterAccount_ErrorCreatingAccount(t *testing.T) {
 	}
 
 	userRepoMock.EXPECT().Create(user).Return(&user, nil).Times(1)
-	pkgCryptoMock.EXPECT().RandomIBAN("US").Return("US1000000003").Times(1)
+	pkgCryptoMock.EXPECT().RandomIBAN("US").Return("US1000000003", nil).Times(1)
 
 	account := models.Account{
 		OwnerId:       user.Id,
 		AccountNumber: 1000000003,
 		IBAN:          "US1000000003",
 		Balance:       0,
+		Currency:      money.DefaultCurrency,
 		CreatedBy:     user.Id,
 		UpdatedBy:     user.Id,
 	}
 
 	accountRepoMock.EXPECT().Create(account).Return(nil, errors.New("error creating account")).Times(1)
 
-	status, err := s.RegisterAccount(fiberCtx, request)
+	err := s.RegisterAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusInternalServerError)
+	assert.Equal(t, messages.UnexpectedError, err.Error())
 }
 
 func TestAccountService_CreateNewAccount_Success(t *testing.T) {
@@ -315,34 +375,92 @@ func TestAccountService_CreateNewAccount_Success(t *testing.T) {
 	request := dto.CreateNewAccountRequest{
 		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
 		ISOCountryCode: "US",
+		Type:           enum.AccountTypeSavings,
 	}
 
 	// Test logic here
 	userRepoMock.EXPECT().FindByID(request.UserId).Return(&mockData[0], nil).Times(1)
-	pkgCryptoMock.EXPECT().RandomIBAN(request.ISOCountryCode).Return("US1000000001").Times(1)
-	pkgCryptoMock.EXPECT().RandomNumber().Return(int64(1000000001)).Times(2)
+	pkgCryptoMock.EXPECT().RandomIBAN(request.ISOCountryCode).Return("US1000000001", nil).Times(1)
+	pkgCryptoMock.EXPECT().RandomNumber().Return(int64(1000000001)).Times(1)
 
 	account := models.Account{
 		OwnerId:       request.UserId,
 		AccountNumber: 1000000001,
 		IBAN:          "US1000000001",
 		Balance:       0,
+		Currency:      money.DefaultCurrency,
+		Type:          enum.AccountTypeSavings,
 		CreatedBy:     request.UserId,
 		UpdatedBy:     request.UserId,
 	}
 
 	accountRepoMock.EXPECT().Create(account).Return(&account, nil).Times(1)
 
-	response, status, err := s.CreateNewAccount(fiberCtx, request)
+	response, err := s.CreateNewAccount(fiberCtx.Context(), request)
 	if err != nil {
 		t.Errorf("Error was not expected: %v", err)
 	}
-
-	assert.Equal(t, status, fiber.StatusCreated)
 	assert.Equal(t, response.AccountNumber, account.AccountNumber)
 	assert.Equal(t, response.IBAN, account.IBAN)
 	assert.Equal(t, response.Balance, account.Balance)
 	assert.Equal(t, response.UserId, account.OwnerId)
+	assert.Equal(t, enum.AccountTypeSavings, response.Type)
+}
+
+func TestAccountService_CreateNewAccount_DuplicateNumber(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	request := dto.CreateNewAccountRequest{
+		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
+		ISOCountryCode: "TR",
+	}
+
+	account := models.Account{
+		OwnerId:       request.UserId,
+		AccountNumber: 100000000026,
+		IBAN:          "TR680009900000000000000002",
+		Balance:       0,
+		Currency:      money.DefaultCurrency,
+		Type:          enum.AccountTypeCurrent,
+		CreatedBy:     request.UserId,
+		UpdatedBy:     request.UserId,
+	}
+
+	// The first generated numbers are already used, the second ones are free
+	userRepoMock.EXPECT().FindByID(request.UserId).Return(&mockData[0], nil).Times(1)
+	gomock.InOrder(
+		pkgCryptoMock.EXPECT().RandomIBAN("TR").Return("TR950009900000000000000001", nil),
+		pkgCryptoMock.EXPECT().RandomNumber().Return(int64(100000000018)),
+		accountRepoMock.EXPECT().Create(gomock.Any()).Return(nil, dbrepository.ErrDuplicateNumber),
+		pkgCryptoMock.EXPECT().RandomIBAN("TR").Return(account.IBAN, nil),
+		pkgCryptoMock.EXPECT().RandomNumber().Return(account.AccountNumber),
+		accountRepoMock.EXPECT().Create(account).Return(&account, nil),
+	)
+
+	response, err := s.CreateNewAccount(fiberCtx.Context(), request)
+	if err != nil {
+		t.Errorf("Error was not expected: %v", err)
+	}
+	assert.Equal(t, account.AccountNumber, response.AccountNumber)
+	assert.Equal(t, account.IBAN, response.IBAN)
+}
+
+func TestAccountService_CreateNewAccount_UnsupportedCountry(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	request := dto.CreateNewAccountRequest{
+		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
+		ISOCountryCode: "US",
+		Type:           enum.AccountTypeSavings,
+	}
+
+	userRepoMock.EXPECT().FindByID(request.UserId).Return(&mockData[0], nil).Times(1)
+	pkgCryptoMock.EXPECT().RandomIBAN("US").Return("", iban.ErrUnsupportedCountry).Times(1)
+
+	_, err := s.CreateNewAccount(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.UnsupportedCountry)
 }
 
 func TestAccountService_CreateNewAccount_UserNotFound(t *testing.T) {
@@ -352,17 +470,18 @@ func TestAccountService_CreateNewAccount_UserNotFound(t *testing.T) {
 	request := dto.CreateNewAccountRequest{
 		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
 		ISOCountryCode: "US",
+		Type:           enum.AccountTypeSavings,
 	}
 
 	// Test logic here
 	userRepoMock.EXPECT().FindByID(request.UserId).Return(nil, errors.New("record not found")).Times(1)
 
-	response, status, err := s.CreateNewAccount(fiberCtx, request)
+	response, err := s.CreateNewAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusNotFound)
+	assert.Equal(t, messages.UserNotFound, err.Error())
 	assert.Nil(t, response)
 }
 
@@ -373,17 +492,41 @@ func TestAccountService_CreateNewAccount_UnexpectedError(t *testing.T) {
 	request := dto.CreateNewAccountRequest{
 		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
 		ISOCountryCode: "US",
+		Type:           enum.AccountTypeSavings,
 	}
 
 	// Test logic here
 	userRepoMock.EXPECT().FindByID(request.UserId).Return(nil, errors.New("unexpected error")).Times(1)
 
-	response, status, err := s.CreateNewAccount(fiberCtx, request)
+	response, err := s.CreateNewAccount(fiberCtx.Context(), request)
+	if err == nil {
+		t.Errorf("Error was expected")
+	}
+
+	assert.Equal(t, messages.UnexpectedError, err.Error())
+	assert.Nil(t, response)
+}
+
+func TestAccountService_CreateNewAccount_UnsupportedCurrency(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	request := dto.CreateNewAccountRequest{
+		UserId:         "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
+		ISOCountryCode: "US",
+		Currency:       "JPY",
+	}
+
+	// Test logic here
+	userRepoMock.EXPECT().FindByID(request.UserId).Return(&mockData[0], nil).Times(1)
+	exchangeRateRepoMock.EXPECT().FindByCurrency(money.Currency("JPY")).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	response, err := s.CreateNewAccount(fiberCtx.Context(), request)
 	if err == nil {
 		t.Errorf("Error was expected")
 	}
 
-	assert.Equal(t, status, fiber.StatusInternalServerError)
+	assert.Equal(t, messages.UnsupportedCurrency, err.Error())
 	assert.Nil(t, response)
 }
 
@@ -398,24 +541,381 @@ func TestAccountService_AddMoney_Success(t *testing.T) {
 
 	// Test logic here
 	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&mockAccountData[0], nil).Times(1)
-	accountRepoMock.EXPECT().UpdateBalance(mockAccountData[0].Balance+request.Amount, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b").Return(nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.CashAccountCode, money.DefaultCurrency).Return(&mockCashAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeDeposit, journal.Type)
+		assert.Equal(t, []models.Posting{
+			{AccountId: mockCashAccount.Id, Amount: -request.Amount, Currency: money.DefaultCurrency},
+			{AccountId: mockAccountData[0].Id, Amount: request.Amount, Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		return &journal, nil
+	}).Times(1)
 	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&models.Account{
 		Id:            mockAccountData[0].Id,
 		OwnerId:       mockAccountData[0].OwnerId,
 		AccountNumber: mockAccountData[0].AccountNumber,
 		IBAN:          mockAccountData[0].IBAN,
 		Balance:       mockAccountData[0].Balance + request.Amount,
+		Currency:      mockAccountData[0].Currency,
 		CreatedBy:     mockAccountData[0].CreatedBy,
 		UpdatedBy:     mockAccountData[0].UpdatedBy,
 		Owner:         mockData[0],
 	}, nil).Times(1)
 
-	response, status, err := s.AddMoney(fiberCtx, request)
+	response, err := s.AddMoney(fiberCtx.Context(), request)
 	if err != nil {
 		t.Errorf("Error was not expected: %v", err)
 	}
-
-	assert.Equal(t, status, fiber.StatusOK)
 	assert.Equal(t, response.Balance, mockAccountData[0].Balance+request.Amount)
 	assert.Equal(t, response.CustomerNumber, mockData[0].CustomerNumber)
 }
+
+func TestAccountService_Withdraw_Success(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("500")
+
+	request := dto.WithdrawRequest{
+		AccountNumber: account.AccountNumber,
+		Language:      "en",
+		Amount:        money.MustParse("120.50"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	ledgerRepoMock.EXPECT().SumPostings(account.Id, enum.JournalTypeWithdrawal, gomock.Any()).Return(-money.MustParse("9800"), nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.CashAccountCode, money.DefaultCurrency).Return(&mockCashAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeWithdrawal, journal.Type)
+		assert.Equal(t, []models.Posting{
+			{AccountId: account.Id, Amount: -request.Amount, Currency: money.DefaultCurrency},
+			{AccountId: mockCashAccount.Id, Amount: request.Amount, Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(histories []models.TransferHistory) error {
+		assert.Len(t, histories, 1)
+		assert.Equal(t, enum.JournalTypeWithdrawal, histories[0].Type)
+		assert.Equal(t, account.AccountNumber, histories[0].From)
+		assert.Equal(t, mockCashAccount.AccountNumber, histories[0].To)
+		assert.Equal(t, request.Amount, histories[0].Amount)
+		return nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		assert.Equal(t, []string{mockData[0].Email}, content.To)
+		assert.Contains(t, content.Body, "120.50 TRY")
+		return nil
+	}).Times(1)
+
+	response, err := s.Withdraw(fiberCtx.Context(), request)
+	if err != nil {
+		t.Errorf("Error was not expected: %v", err)
+	}
+	assert.Equal(t, money.MustParse("379.50"), response.Balance)
+	assert.Equal(t, money.MustParse("79.50"), response.DailyLimitRemaining)
+}
+
+func TestAccountService_Withdraw_DailyLimitExceeded(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("500")
+
+	request := dto.WithdrawRequest{
+		AccountNumber: account.AccountNumber,
+		Amount:        money.MustParse("200.01"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	ledgerRepoMock.EXPECT().SumPostings(account.Id, enum.JournalTypeWithdrawal, gomock.Any()).Return(-money.MustParse("9800"), nil).Times(1)
+
+	_, err := s.Withdraw(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.DailyWithdrawalLimitExceeded)
+}
+
+func TestAccountService_Withdraw_NotOwner(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id})
+
+	request := dto.WithdrawRequest{
+		AccountNumber: mockAccountData[0].AccountNumber,
+		Amount:        money.MustParse("10"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&mockAccountData[0], nil).Times(1)
+
+	_, err := s.Withdraw(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.Unauthorized)
+}
+
+func TestAccountService_TransferMoney_InvalidIBAN(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: mockAccountData[0].AccountNumber,
+		ToIBAN:            "TR33 0006 1005 1978 6457 8413 27",
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.FromAccountNumber).Return(&mockAccountData[0], nil).Times(1)
+
+	_, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.InvalidIBAN)
+}
+
+func TestAccountService_TransferMoney_IBANNotFound(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: mockAccountData[0].AccountNumber,
+		ToIBAN:            "tr33 0006 1005 1978 6457 8413 26",
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.FromAccountNumber).Return(&mockAccountData[0], nil).Times(1)
+	accountRepoMock.EXPECT().FindByIBAN("TR330006100519786457841326").Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	_, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.IBANNotFound)
+}
+
+func TestAccountService_TransferMoney_CreatesPendingTransfer(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		ToAccountNumber:   mockAccountData[1].AccountNumber,
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.FromAccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(request.ToAccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		// Only the hash of the token is stored
+		assert.Equal(t, enum.TransferPendingApproval, transfer.Status)
+		assert.Equal(t, hashToken("token"), transfer.TokenHash)
+		assert.NotNil(t, transfer.ExpiresAt)
+		assert.Equal(t, mockFeeSchedule.FlatFee, transfer.TransactionFee)
+		assert.Equal(t, "Standard: 4.22 TRY", transfer.FeeRule)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20"
+		return &transfer, nil
+	}).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		// The amount and the fee are held until the transfer is approved
+		assert.Equal(t, enum.HoldTypeTransfer, hold.Type)
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20", hold.TransferId)
+		assert.Equal(t, request.Amount+mockFeeSchedule.FlatFee, hold.Amount)
+		assert.NotNil(t, hold.ExpiresAt)
+		return &hold, nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+
+	response, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20", response.Id)
+	assert.Equal(t, enum.TransferPendingApproval, response.Status)
+}
+
+// withTestTx returns the service running on a transaction, the repositories stay the same mocks
+func withTestTx() AccountService {
+	tx := &gorm.DB{}
+	accountRepoMock.EXPECT().WithTx(tx).Return(accountRepoMock).AnyTimes()
+	userRepoMock.EXPECT().WithTx(tx).Return(userRepoMock).AnyTimes()
+	transferRepoMock.EXPECT().WithTx(tx).Return(transferRepoMock).AnyTimes()
+	ledgerRepoMock.EXPECT().WithTx(tx).Return(ledgerRepoMock).AnyTimes()
+	exchangeRateRepoMock.EXPECT().WithTx(tx).Return(exchangeRateRepoMock).AnyTimes()
+	transferRequestRepoMock.EXPECT().WithTx(tx).Return(transferRequestRepoMock).AnyTimes()
+	transferLimitRepoMock.EXPECT().WithTx(tx).Return(transferLimitRepoMock).AnyTimes()
+	feeScheduleRepoMock.EXPECT().WithTx(tx).Return(feeScheduleRepoMock).AnyTimes()
+	accountEventRepoMock.EXPECT().WithTx(tx).Return(accountEventRepoMock).AnyTimes()
+	holdRepoMock.EXPECT().WithTx(tx).Return(holdRepoMock).AnyTimes()
+	interestRepoMock.EXPECT().WithTx(tx).Return(interestRepoMock).AnyTimes()
+	beneficiaryRepoMock.EXPECT().WithTx(tx).Return(beneficiaryRepoMock).AnyTimes()
+	notificationRepoMock.EXPECT().WithTx(tx).Return(notificationRepoMock).AnyTimes()
+	return s.WithTx(tx)
+}
+
+func TestAccountService_TransferMoney_SMSCode(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+	senderAccount.Owner.ApprovalMethod = enum.ApprovalMethodSMSCode
+	senderAccount.Owner.PhoneNumber = 905550000001
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		ToAccountNumber:   mockAccountData[1].AccountNumber,
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.FromAccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(request.ToAccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	pkgCryptoMock.EXPECT().RandomCode(enum.TransferCodeLength).Return("042137", nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		// The transfer can not be approved with a link
+		assert.Equal(t, hashToken("042137"), transfer.CodeHash)
+		assert.Empty(t, transfer.TokenHash)
+		return &transfer, nil
+	}).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).Return(&models.Hold{}, nil).Times(1)
+	pkgSMSMock.EXPECT().Send(uint64(905550000001), gomock.Any()).DoAndReturn(func(phoneNumber uint64, message string) error {
+		assert.Contains(t, message, "042137")
+		return nil
+	}).Times(1)
+
+	response, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.ApprovalMethodSMSCode, response.ApprovalMethod)
+}
+
+func TestAccountService_TransferMoney_DailyLimitExceeded(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("1000")
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("300"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		ToAccountNumber:   mockAccountData[1].AccountNumber,
+	}
+
+	// The customer limit replaces the global daily limit, 800 of its 1000 are used today
+	limits := []models.TransferLimit{
+		{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodDaily, Amount: money.MustParse("100000")},
+		{Level: enum.LimitLevelCustomer, ScopeId: senderAccount.OwnerId, Period: enum.LimitPeriodDaily, Amount: money.MustParse("1000")},
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.FromAccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(request.ToAccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(limits, nil).Times(1)
+	transferRequestRepoMock.EXPECT().SumExecutedByOwner(senderAccount.OwnerId, gomock.Any()).Return(money.MustParse("800"), nil).Times(1)
+
+	_, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.TransferDailyLimitExceeded)
+}
+
+func TestAccountService_ApproveTransfer_WrongCode(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	expiresAt := time.Now().Add(enum.TransferCodeTTL)
+	transfer := models.Transfer{
+		Id:             "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20",
+		OwnerId:        mockData[0].Id,
+		Status:         enum.TransferPendingApproval,
+		ApprovalMethod: enum.ApprovalMethodSMSCode,
+		CodeHash:       hashToken("042137"),
+		ExpiresAt:      &expiresAt,
+	}
+
+	txService := withTestTx()
+	transferRequestRepoMock.EXPECT().LockById(transfer.Id).Return(&transfer, nil).Times(1)
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, 1, transfer.CodeAttempts)
+		assert.Equal(t, enum.TransferPendingApproval, transfer.Status)
+		return nil
+	}).Times(1)
+
+	err := txService.ApproveTransfer(fiberCtx.Context(), dto.ApproveTransferRequest{Id: transfer.Id, Code: "000000"})
+	assert.EqualError(t, err, messages.InvalidTransferCode)
+}
+
+func TestAccountService_ApproveTransfer_TooManyWrongCodes(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	expiresAt := time.Now().Add(enum.TransferCodeTTL)
+	transfer := models.Transfer{
+		Id:             "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20",
+		OwnerId:        mockData[0].Id,
+		Status:         enum.TransferPendingApproval,
+		ApprovalMethod: enum.ApprovalMethodEmailCode,
+		CodeHash:       hashToken("042137"),
+		CodeAttempts:   enum.TransferCodeMaxAttempts - 1,
+		ExpiresAt:      &expiresAt,
+	}
+
+	txService := withTestTx()
+	transferRequestRepoMock.EXPECT().LockById(transfer.Id).Return(&transfer, nil).Times(1)
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, enum.TransferRejected, transfer.Status)
+		assert.NotNil(t, transfer.RejectedAt)
+		return nil
+	}).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId(transfer.Id, enum.HoldReleased, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	err := txService.ApproveTransfer(fiberCtx.Context(), dto.ApproveTransferRequest{Id: transfer.Id, Code: "000000"})
+	assert.EqualError(t, err, messages.TransferCodeAttemptsExceeded)
+}
+
+func TestAccountService_ApproveTransfer_OtherUser(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id})
+
+	transfer := models.Transfer{
+		Id:       "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b20",
+		OwnerId:  mockData[0].Id,
+		Status:   enum.TransferPendingApproval,
+		CodeHash: hashToken("042137"),
+	}
+
+	txService := withTestTx()
+	transferRequestRepoMock.EXPECT().LockById(transfer.Id).Return(&transfer, nil).Times(1)
+
+	err := txService.ApproveTransfer(fiberCtx.Context(), dto.ApproveTransferRequest{Id: transfer.Id, Code: "042137"})
+	assert.EqualError(t, err, messages.TransferNotFound)
+}
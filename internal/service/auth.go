@@ -46,6 +46,11 @@ func (s *authService) Login(ctx context.Context, request dto.LoginRequest) (*dto
 		return nil, errors.New(messages.PasswordIncorrect)
 	}
 
+	// Users are deactivated when their last account is closed
+	if !user.IsActive {
+		return nil, errors.New(messages.UserInactive)
+	}
+
 	jwtPayload := authware.JWTClaimsPayload{
 		ID:          user.Id,
 		FirstName:   user.FirstName,
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// matchHistoryEntry returns the history entry of the posting, it is the unused entry of the journal
+// with the same amount as seen by the account. Postings without an entry like deposits return nil.
+func matchHistoryEntry(posting repository.AccountPosting, entries []models.TransferHistory, accountNumber int64, used map[string]bool) *models.TransferHistory {
+	for i, entry := range entries {
+		if used[entry.Id] || entry.JournalId == "" || entry.JournalId != posting.JournalId {
+			continue
+		}
+
+		if historyItem(entry, accountNumber).Amount != posting.Amount {
+			continue
+		}
+
+		used[entry.Id] = true
+		return &entries[i]
+	}
+
+	return nil
+}
+
+// historyBalances returns the balance of the account after each of the entries. The postings from the first to the
+// last posting of the entries are replayed on the balance before them, entries without a journal have no balance.
+func historyBalances(ledgerRepository repository.LedgerRepository, account *models.Account, entries []models.TransferHistory) (map[string]money.Amount, error) {
+	balances := make(map[string]money.Amount)
+
+	journalIds := []string{}
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.JournalId != "" && !seen[entry.JournalId] {
+			seen[entry.JournalId] = true
+			journalIds = append(journalIds, entry.JournalId)
+		}
+	}
+	if len(journalIds) == 0 {
+		return balances, nil
+	}
+
+	entryPostings, err := ledgerRepository.FindPostingsByJournalIds(account.Id, journalIds)
+	if err != nil || len(entryPostings) == 0 {
+		return balances, err
+	}
+
+	// The postings are sorted by their creation time
+	from := entryPostings[0].CreatedAt
+	to := entryPostings[len(entryPostings)-1].CreatedAt.Add(time.Microsecond)
+
+	balance, err := ledgerRepository.BalanceAt(account.Id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	postings, err := ledgerRepository.FindPostings(account.Id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, posting := range postings {
+		balance += posting.Amount
+		if entry := matchHistoryEntry(posting, entries, account.AccountNumber, used); entry != nil {
+			balances[entry.Id] = balance
+		}
+	}
+
+	return balances, nil
+}
+
+// balanceTime returns the moment of the requested balance. A day means the end of the day,
+// the current balance is returned without a time.
+func balanceTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		day, err := time.ParseInLocation(dateLayout, value, time.Local)
+		if err != nil {
+			return now, errors.New(messages.InvalidBalanceTime)
+		}
+
+		// The balance of today is the current balance
+		at = day.AddDate(0, 0, 1)
+		if at.After(now) && !day.After(now) {
+			at = now
+		}
+	}
+
+	if at.After(now) {
+		return now, errors.New(messages.InvalidBalanceTime)
+	}
+
+	return at, nil
+}
+
+type BalanceService interface {
+	BalanceAt(ctx context.Context, request dto.GetBalanceRequest) (*dto.GetBalanceResponse, error)
+	CreateSnapshots(now time.Time) (int64, error)
+}
+
+type balanceService struct {
+	accountRepository repository.AccountRepository
+	ledgerRepository  repository.LedgerRepository
+}
+
+func NewBalanceService(accountRepository repository.AccountRepository, ledgerRepository repository.LedgerRepository) BalanceService {
+	return &balanceService{
+		accountRepository: accountRepository,
+		ledgerRepository:  ledgerRepository,
+	}
+}
+
+// BalanceAt returns the balance an account of the current user had at a moment
+func (s *balanceService) BalanceAt(ctx context.Context, request dto.GetBalanceRequest) (*dto.GetBalanceResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	at, err := balanceTime(request.At, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	balance, err := s.ledgerRepository.BalanceAt(account.Id, at)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return &dto.GetBalanceResponse{
+		AccountNumber: account.AccountNumber,
+		At:            at,
+		Balance:       balance,
+		Currency:      account.Currency.String(),
+	}, nil
+}
+
+// CreateSnapshots takes the snapshots of the balances of all accounts at the last midnight
+func (s *balanceService) CreateSnapshots(now time.Time) (int64, error) {
+	return s.ledgerRepository.CreateSnapshots(calendar.StartOfDay(now))
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestBalanceTime(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectedErr string
+	}{
+		{name: "now", value: "", expected: now},
+		{name: "end of a day", value: "2024-01-31", expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)},
+		{name: "today", value: "2024-03-15", expected: now},
+		{name: "moment", value: "2024-03-01T12:00:00Z", expected: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "future day", value: "2024-03-16", expectedErr: messages.InvalidBalanceTime},
+		{name: "future moment", value: "2024-03-17T00:00:00Z", expectedErr: messages.InvalidBalanceTime},
+		{name: "invalid", value: "31.01.2024", expectedErr: messages.InvalidBalanceTime},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			at, err := balanceTime(test.value, now)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.expected.Equal(at), "expected %s, got %s", test.expected, at)
+		})
+	}
+}
+
+func TestHistoryBalances(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	account := mockAccountData[0]
+	from := mockStatementPostings[1].CreatedAt
+	to := from.Add(time.Microsecond)
+
+	// The deposit before the transfer is not on the page, the balance before the transfer comes from the ledger
+	ledgerRepoMock.EXPECT().FindPostingsByJournalIds(account.Id, []string{"j2"}).Return(mockStatementPostings[1:], nil).Times(1)
+	ledgerRepoMock.EXPECT().BalanceAt(account.Id, from).Return(money.MustParse("150"), nil).Times(1)
+	ledgerRepoMock.EXPECT().FindPostings(account.Id, from, to).Return(mockStatementPostings[1:], nil).Times(1)
+
+	balances, err := historyBalances(ledgerRepoMock, &account, mockStatementEntries)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("120"), balances["h1"])
+	assert.Equal(t, money.MustParse("115.78"), balances["h2"])
+}
+
+func TestBalanceService_BalanceAt(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id})
+	balanceService := NewBalanceService(accountRepoMock, ledgerRepoMock)
+
+	account := mockAccountData[0]
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+
+	_, err := balanceService.BalanceAt(fiberCtx.Context(), dto.GetBalanceRequest{AccountNumber: account.AccountNumber, At: "2024-01-31"})
+	assert.EqualError(t, err, messages.Unauthorized)
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"html"
+	"os"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"tek-bank/pkg/sms"
+	"time"
+)
+
+// trustedBeneficiaryLimit returns up to which amount a day transfers to trusted beneficiaries are executed without
+// an approval, in the base currency. Without a valid limit every transfer needs an approval.
+func trustedBeneficiaryLimit() money.Amount {
+	limit, err := money.Parse(os.Getenv("TRUSTED_BENEFICIARY_LIMIT"))
+	if err != nil || limit.IsNegative() {
+		return money.Zero
+	}
+	return limit
+}
+
+type BeneficiaryService interface {
+	Create(ctx context.Context, request dto.CreateBeneficiaryRequest) (*dto.BeneficiaryItem, error)
+	List(ctx context.Context) ([]dto.BeneficiaryItem, error)
+	Get(ctx context.Context, id string) (*dto.BeneficiaryItem, error)
+	Update(ctx context.Context, request dto.UpdateBeneficiaryRequest) (*dto.BeneficiaryItem, error)
+	Delete(ctx context.Context, id string) error
+	TrustApproval(ctx context.Context, token string) error
+	ApproveTrust(ctx context.Context, request dto.ApproveTransferRequest) error
+
+	WithTx(trxHandle *gorm.DB) BeneficiaryService
+}
+
+type beneficiaryService struct {
+	beneficiaryRepository      repository.BeneficiaryRepository
+	beneficiaryEventRepository repository.BeneficiaryEventRepository
+	accountRepository          repository.AccountRepository
+	userRepository             repository.UserRepository
+	accountService             AccountService
+	pkgSMS                     sms.Sender
+}
+
+func NewBeneficiaryService(
+	beneficiaryRepository repository.BeneficiaryRepository,
+	beneficiaryEventRepository repository.BeneficiaryEventRepository,
+	accountRepository repository.AccountRepository,
+	userRepository repository.UserRepository,
+	accountService AccountService,
+	pkgSMS sms.Sender,
+) BeneficiaryService {
+	return &beneficiaryService{
+		beneficiaryRepository:      beneficiaryRepository,
+		beneficiaryEventRepository: beneficiaryEventRepository,
+		accountRepository:          accountRepository,
+		userRepository:             userRepository,
+		accountService:             accountService,
+		pkgSMS:                     pkgSMS,
+	}
+}
+
+func (s *beneficiaryService) WithTx(trxHandle *gorm.DB) BeneficiaryService {
+	clone := *s
+	clone.beneficiaryRepository = s.beneficiaryRepository.WithTx(trxHandle)
+	clone.beneficiaryEventRepository = s.beneficiaryEventRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	clone.userRepository = s.userRepository.WithTx(trxHandle)
+	clone.accountService = s.accountService.WithTx(trxHandle)
+	return &clone
+}
+
+// Create saves a receiver of the current user, the holder name is taken from the account. A trusted beneficiary
+// is saved untrusted, the trust waits for the approval of the user.
+func (s *beneficiaryService) Create(ctx context.Context, request dto.CreateBeneficiaryRequest) (*dto.BeneficiaryItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	nickname := strings.TrimSpace(request.Nickname)
+	if nickname == "" || (request.AccountNumber == 0 && request.IBAN == "") {
+		return nil, errors.New(messages.InvalidBeneficiary)
+	}
+
+	account, err := findReceiverAccount(s.accountRepository, request.AccountNumber, request.IBAN)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	// The name the user knows has to be the name of the holder, the spaces and the case do not matter
+	holderName := fullName(account.Owner)
+	if request.HolderName != "" && !strings.EqualFold(strings.Join(strings.Fields(request.HolderName), " "), holderName) {
+		return nil, errors.New(messages.BeneficiaryHolderMismatch)
+	}
+
+	beneficiary, err := s.beneficiaryRepository.Create(models.Beneficiary{
+		OwnerId:       currentUser.Id,
+		Nickname:      nickname,
+		AccountNumber: account.AccountNumber,
+		IBAN:          account.IBAN,
+		HolderName:    holderName,
+		CreatedBy:     currentUser.Id,
+		UpdatedBy:     currentUser.Id,
+	})
+	if errors.Is(err, repository.ErrDuplicateBeneficiary) {
+		return nil, errors.New(messages.BeneficiaryAlreadyExists)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if request.Trusted {
+		if err := s.requestTrust(beneficiary, currentUser.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	item := beneficiaryItem(*beneficiary)
+	return &item, nil
+}
+
+// List returns the beneficiaries of the current user ordered by their nicknames
+func (s *beneficiaryService) List(ctx context.Context) ([]dto.BeneficiaryItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	beneficiaries, err := s.beneficiaryRepository.FindByOwnerId(currentUser.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	items := []dto.BeneficiaryItem{}
+	for _, beneficiary := range beneficiaries {
+		items = append(items, beneficiaryItem(beneficiary))
+	}
+
+	return items, nil
+}
+
+func (s *beneficiaryService) Get(ctx context.Context, id string) (*dto.BeneficiaryItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	beneficiary, err := ownedBeneficiary(s.beneficiaryRepository, currentUser.Id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	item := beneficiaryItem(*beneficiary)
+	return &item, nil
+}
+
+// Update changes the nickname or the trust of a beneficiary of the current user, the account stays the same.
+// Trust waits for the approval of the user, it is taken back at once.
+func (s *beneficiaryService) Update(ctx context.Context, request dto.UpdateBeneficiaryRequest) (*dto.BeneficiaryItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	beneficiary, err := ownedBeneficiary(s.beneficiaryRepository, currentUser.Id, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestTrust bool
+	if request.Nickname != nil {
+		nickname := strings.TrimSpace(*request.Nickname)
+		if nickname == "" {
+			return nil, errors.New(messages.InvalidBeneficiary)
+		}
+		beneficiary.Nickname = nickname
+	}
+
+	if request.Trusted != nil && *request.Trusted {
+		requestTrust = !beneficiary.Trusted
+	} else if request.Trusted != nil && (beneficiary.Trusted || beneficiary.TrustExpiresAt != nil) {
+		beneficiary.Trusted = false
+		clearTrustApproval(beneficiary)
+		if err := s.createEvent(beneficiary, enum.BeneficiaryActionTrustRevoked, currentUser.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	beneficiary.UpdatedBy = currentUser.Id
+	if err := s.beneficiaryRepository.Update(*beneficiary); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if requestTrust {
+		if err := s.requestTrust(beneficiary, currentUser.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	item := beneficiaryItem(*beneficiary)
+	return &item, nil
+}
+
+// Delete removes a beneficiary of the current user, the transfers made to it are not touched
+func (s *beneficiaryService) Delete(ctx context.Context, id string) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	beneficiary, err := ownedBeneficiary(s.beneficiaryRepository, currentUser.Id, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.beneficiaryRepository.Delete(beneficiary.Id); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// TrustApproval trusts the beneficiary of the approval link token
+func (s *beneficiaryService) TrustApproval(ctx context.Context, token string) error {
+	beneficiary, err := s.beneficiaryRepository.LockByTrustTokenHash(hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.BeneficiaryNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return s.approveTrust(beneficiary, time.Now())
+}
+
+// ApproveTrust trusts a beneficiary of the current user with its one-time code. Wrong codes are counted,
+// the trust is not given after too many of them.
+func (s *beneficiaryService) ApproveTrust(ctx context.Context, request dto.ApproveTransferRequest) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	if _, err := uuid.Parse(request.Id); err != nil {
+		return errors.New(messages.BeneficiaryNotFound)
+	}
+
+	beneficiary, err := s.beneficiaryRepository.LockById(request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && beneficiary.OwnerId != currentUser.Id) {
+		return errors.New(messages.BeneficiaryNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	// Trust approved with a link has no code
+	if beneficiary.TrustExpiresAt != nil && beneficiary.TrustCodeHash == "" {
+		return errors.New(messages.InvalidTransferCode)
+	}
+
+	now := time.Now()
+	if beneficiary.TrustExpiresAt != nil && now.Before(*beneficiary.TrustExpiresAt) &&
+		subtle.ConstantTimeCompare([]byte(hashToken(request.Code)), []byte(beneficiary.TrustCodeHash)) != 1 {
+		beneficiary.TrustCodeAttempts++
+		if beneficiary.TrustCodeAttempts < enum.TransferCodeMaxAttempts {
+			if err := s.beneficiaryRepository.Update(*beneficiary); err != nil {
+				return errors.New(messages.UnexpectedError)
+			}
+			return errors.New(messages.InvalidTransferCode)
+		}
+
+		if err := s.endTrustApproval(beneficiary, enum.BeneficiaryActionTrustRejected); err != nil {
+			return err
+		}
+		return errors.New(messages.BeneficiaryTrustCodeAttemptsExceeded)
+	}
+
+	return s.approveTrust(beneficiary, now)
+}
+
+// approveTrust trusts the beneficiary whose trust waits for approval. The expiry is recorded even though an error
+// is returned, the caller should keep the changes of the transaction for it.
+func (s *beneficiaryService) approveTrust(beneficiary *models.Beneficiary, now time.Time) error {
+	if beneficiary.TrustExpiresAt == nil {
+		return errors.New(messages.BeneficiaryTrustNotPending)
+	}
+
+	if !now.Before(*beneficiary.TrustExpiresAt) {
+		if err := s.endTrustApproval(beneficiary, enum.BeneficiaryActionTrustExpired); err != nil {
+			return err
+		}
+		return errors.New(messages.BeneficiaryTrustExpired)
+	}
+
+	beneficiary.Trusted = true
+	return s.endTrustApproval(beneficiary, enum.BeneficiaryActionTrustApproved)
+}
+
+// endTrustApproval ends the approval of the trust with the action, the approval can not be used anymore
+func (s *beneficiaryService) endTrustApproval(beneficiary *models.Beneficiary, action string) error {
+	if err := s.createEvent(beneficiary, action, beneficiary.OwnerId); err != nil {
+		return err
+	}
+
+	clearTrustApproval(beneficiary)
+	beneficiary.UpdatedBy = beneficiary.OwnerId
+	if err := s.beneficiaryRepository.Update(*beneficiary); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// requestTrust asks the owner to approve the trust of the beneficiary with the approval method of its transfers,
+// a trust asked for before can not be approved anymore
+func (s *beneficiaryService) requestTrust(beneficiary *models.Beneficiary, userId string) error {
+	owner, err := s.userRepository.FindByID(beneficiary.OwnerId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	approval, err := s.accountService.newApproval(owner.ApprovalMethod, time.Now())
+	if err != nil {
+		return err
+	}
+
+	beneficiary.TrustApprovalMethod = approval.method
+	beneficiary.TrustTokenHash = approval.tokenHash
+	beneficiary.TrustCodeHash = approval.codeHash
+	beneficiary.TrustCodeAttempts = 0
+	beneficiary.TrustExpiresAt = &approval.expiresAt
+	beneficiary.UpdatedBy = userId
+	if err := s.beneficiaryRepository.Update(*beneficiary); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	if err := s.createEvent(beneficiary, enum.BeneficiaryActionTrustRequested, userId); err != nil {
+		return err
+	}
+
+	return s.sendTrustApproval(owner, beneficiary, approval.secret)
+}
+
+// sendTrustApproval sends the approval link or the one-time code of the trust of the beneficiary to the owner
+func (s *beneficiaryService) sendTrustApproval(owner *models.User, beneficiary *models.Beneficiary, secret string) error {
+	if beneficiary.TrustApprovalMethod == enum.ApprovalMethodSMSCode {
+		return s.pkgSMS.Send(owner.PhoneNumber, fmt.Sprintf(
+			"TEK Bank: %s is your code to trust the beneficiary %d, its transfers may be executed without an approval. It is valid for %d minutes.",
+			secret, beneficiary.AccountNumber, int(enum.TransferCodeTTL.Minutes()),
+		))
+	}
+
+	var approval string
+	if beneficiary.TrustApprovalMethod == enum.ApprovalMethodEmailCode {
+		approval = `
+				<p>You asked to trust a beneficiary. Please approve it with the code below.</p>
+				<p><strong>` + secret + `</strong></p>
+				<p>The code is valid until ` + beneficiary.TrustExpiresAt.Format(time.RFC1123) + `.</p>`
+	} else {
+		trustApprovalLink := fmt.Sprintf("http://localhost/v1/profile/beneficiary-trust-approval?token=%s", secret)
+		approval = `
+				<p>You asked to trust a beneficiary. Please click the link below to approve it.</p>
+				<p><a href="` + trustApprovalLink + `">` + trustApprovalLink + `</a></p>
+				<p>The link is valid until ` + beneficiary.TrustExpiresAt.Format(time.RFC1123) + `.</p>`
+	}
+
+	var body string = `
+			<body>
+				<p>Beneficiary: <strong>` + html.EscapeString(beneficiary.Nickname) + `</strong></p>
+				<p>Account Number: <strong>` + fmt.Sprint(beneficiary.AccountNumber) + `</strong></p>
+				<p>Holder Name: <strong>` + html.EscapeString(beneficiary.HolderName) + `</strong></p>
+				<p>Transfers to trusted beneficiaries up to the daily limit of the bank are executed without an approval.</p>` + approval + `
+				<p>If you did not ask for it, please ignore this email.</p>
+				<br>
+				<p>Best Regards,</p>
+			</body>
+	`
+
+	return s.accountService.sendMail(gomailer.Content{
+		Subject: "TEK Bank - Beneficiary Trust Approval",
+		Body:    body,
+		To:      []string{owner.Email},
+	})
+}
+
+// createEvent records an action on the trust of the beneficiary in its audit trail
+func (s *beneficiaryService) createEvent(beneficiary *models.Beneficiary, action string, userId string) error {
+	err := s.beneficiaryEventRepository.Create(models.BeneficiaryEvent{
+		BeneficiaryId:  beneficiary.Id,
+		OwnerId:        beneficiary.OwnerId,
+		AccountNumber:  beneficiary.AccountNumber,
+		Action:         action,
+		ApprovalMethod: beneficiary.TrustApprovalMethod,
+		CreatedBy:      userId,
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	return nil
+}
+
+// clearTrustApproval removes the trust waiting for approval, its link and code can not be used anymore
+func clearTrustApproval(beneficiary *models.Beneficiary) {
+	beneficiary.TrustApprovalMethod = ""
+	beneficiary.TrustTokenHash = ""
+	beneficiary.TrustCodeHash = ""
+	beneficiary.TrustCodeAttempts = 0
+	beneficiary.TrustExpiresAt = nil
+}
+
+// ownedBeneficiary returns the beneficiary if it belongs to the user, beneficiaries of other users are not found
+func ownedBeneficiary(beneficiaryRepository repository.BeneficiaryRepository, ownerId string, id string) (*models.Beneficiary, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New(messages.BeneficiaryNotFound)
+	}
+
+	beneficiary, err := beneficiaryRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && beneficiary.OwnerId != ownerId) {
+		return nil, errors.New(messages.BeneficiaryNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return beneficiary, nil
+}
+
+func beneficiaryItem(beneficiary models.Beneficiary) dto.BeneficiaryItem {
+	return dto.BeneficiaryItem{
+		Id:            beneficiary.Id,
+		Nickname:      beneficiary.Nickname,
+		AccountNumber: beneficiary.AccountNumber,
+		IBAN:          beneficiary.IBAN,
+		HolderName:    beneficiary.HolderName,
+		Trusted:       beneficiary.Trusted,
+		TrustPending:  beneficiary.TrustExpiresAt != nil && time.Now().Before(*beneficiary.TrustExpiresAt),
+		CreatedAt:     beneficiary.CreatedAt,
+	}
+}
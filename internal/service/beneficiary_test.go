@@ -0,0 +1,350 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/mocks/repository"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+// mockBeneficiary is a trusted beneficiary of the first user, the account of the second user
+var mockBeneficiary = models.Beneficiary{
+	Id:            "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b70",
+	OwnerId:       "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b1b",
+	Nickname:      "Jane",
+	AccountNumber: 1000000002,
+	HolderName:    "Jane Doe",
+	Trusted:       true,
+}
+
+func TestBeneficiaryService_Create(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	beneficiaryEventRepoMock := repository.NewMockBeneficiaryEventRepository(gomock.NewController(t))
+	beneficiaryService := NewBeneficiaryService(beneficiaryRepoMock, beneficiaryEventRepoMock, accountRepoMock, userRepoMock, s, pkgSMSMock)
+
+	request := dto.CreateBeneficiaryRequest{
+		Nickname:      " Jane ",
+		AccountNumber: mockAccountData[1].AccountNumber,
+		HolderName:    "jane  DOE",
+		Trusted:       true,
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	beneficiaryRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(beneficiary models.Beneficiary) (*models.Beneficiary, error) {
+		assert.Equal(t, mockData[0].Id, beneficiary.OwnerId)
+		assert.Equal(t, "Jane", beneficiary.Nickname)
+		assert.Equal(t, mockAccountData[1].IBAN, beneficiary.IBAN)
+		assert.Equal(t, "Jane Doe", beneficiary.HolderName)
+		assert.False(t, beneficiary.Trusted)
+		beneficiary.Id = mockBeneficiary.Id
+		return &beneficiary, nil
+	}).Times(1)
+
+	// The trust waits for the approval of the user
+	userRepoMock.EXPECT().FindByID(mockData[0].Id).Return(&mockData[0], nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	beneficiaryRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(beneficiary models.Beneficiary) error {
+		assert.False(t, beneficiary.Trusted)
+		assert.Equal(t, enum.ApprovalMethodEmailLink, beneficiary.TrustApprovalMethod)
+		assert.Equal(t, hashToken("token"), beneficiary.TrustTokenHash)
+		assert.NotNil(t, beneficiary.TrustExpiresAt)
+		return nil
+	}).Times(1)
+	beneficiaryEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.BeneficiaryEvent) error {
+		assert.Equal(t, mockBeneficiary.Id, event.BeneficiaryId)
+		assert.Equal(t, enum.BeneficiaryActionTrustRequested, event.Action)
+		return nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		assert.Equal(t, []string{mockData[0].Email}, content.To)
+		assert.Contains(t, content.Body, "beneficiary-trust-approval?token=token")
+		return nil
+	}).Times(1)
+
+	response, err := beneficiaryService.Create(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, mockBeneficiary.Id, response.Id)
+	assert.False(t, response.Trusted)
+	assert.True(t, response.TrustPending)
+
+	// The holder name has to match the holder of the account
+	request.HolderName = "John Doe"
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+
+	_, err = beneficiaryService.Create(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.BeneficiaryHolderMismatch)
+
+	// An account is saved once
+	request.HolderName = ""
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	beneficiaryRepoMock.EXPECT().Create(gomock.Any()).Return(nil, dbrepository.ErrDuplicateBeneficiary).Times(1)
+
+	_, err = beneficiaryService.Create(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.BeneficiaryAlreadyExists)
+}
+
+func TestBeneficiaryService_Get_OtherUser(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id})
+	beneficiaryEventRepoMock := repository.NewMockBeneficiaryEventRepository(gomock.NewController(t))
+	beneficiaryService := NewBeneficiaryService(beneficiaryRepoMock, beneficiaryEventRepoMock, accountRepoMock, userRepoMock, s, pkgSMSMock)
+
+	beneficiaryRepoMock.EXPECT().FindById(mockBeneficiary.Id).Return(&mockBeneficiary, nil).Times(1)
+
+	_, err := beneficiaryService.Get(fiberCtx.Context(), mockBeneficiary.Id)
+	assert.EqualError(t, err, messages.BeneficiaryNotFound)
+}
+
+func TestBeneficiaryService_ApproveTrust(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	beneficiaryEventRepoMock := repository.NewMockBeneficiaryEventRepository(gomock.NewController(t))
+	beneficiaryService := NewBeneficiaryService(beneficiaryRepoMock, beneficiaryEventRepoMock, accountRepoMock, userRepoMock, s, pkgSMSMock)
+
+	expiresAt := time.Now().Add(time.Minute)
+	beneficiary := mockBeneficiary
+	beneficiary.Trusted = false
+	beneficiary.TrustApprovalMethod = enum.ApprovalMethodEmailCode
+	beneficiary.TrustCodeHash = hashToken("123456")
+	beneficiary.TrustExpiresAt = &expiresAt
+
+	// A wrong code is counted
+	beneficiaryRepoMock.EXPECT().LockById(beneficiary.Id).Return(&beneficiary, nil).Times(1)
+	beneficiaryRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.Beneficiary) error {
+		assert.False(t, updated.Trusted)
+		assert.Equal(t, 1, updated.TrustCodeAttempts)
+		return nil
+	}).Times(1)
+
+	err := beneficiaryService.ApproveTrust(fiberCtx.Context(), dto.ApproveTransferRequest{Id: beneficiary.Id, Code: "000000"})
+	assert.EqualError(t, err, messages.InvalidTransferCode)
+
+	// The right code trusts the beneficiary and the approval can not be used again
+	beneficiaryRepoMock.EXPECT().LockById(beneficiary.Id).Return(&beneficiary, nil).Times(1)
+	beneficiaryEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.BeneficiaryEvent) error {
+		assert.Equal(t, enum.BeneficiaryActionTrustApproved, event.Action)
+		assert.Equal(t, enum.ApprovalMethodEmailCode, event.ApprovalMethod)
+		return nil
+	}).Times(1)
+	beneficiaryRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.Beneficiary) error {
+		assert.True(t, updated.Trusted)
+		assert.Empty(t, updated.TrustCodeHash)
+		assert.Nil(t, updated.TrustExpiresAt)
+		return nil
+	}).Times(1)
+
+	err = beneficiaryService.ApproveTrust(fiberCtx.Context(), dto.ApproveTransferRequest{Id: beneficiary.Id, Code: "123456"})
+	assert.NoError(t, err)
+
+	beneficiaryRepoMock.EXPECT().LockById(beneficiary.Id).Return(&beneficiary, nil).Times(1)
+
+	err = beneficiaryService.ApproveTrust(fiberCtx.Context(), dto.ApproveTransferRequest{Id: beneficiary.Id, Code: "123456"})
+	assert.EqualError(t, err, messages.BeneficiaryTrustNotPending)
+
+	// The trust is taken back at once
+	beneficiaryRepoMock.EXPECT().FindById(beneficiary.Id).Return(&beneficiary, nil).Times(1)
+	beneficiaryEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.BeneficiaryEvent) error {
+		assert.Equal(t, enum.BeneficiaryActionTrustRevoked, event.Action)
+		return nil
+	}).Times(1)
+	beneficiaryRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.Beneficiary) error {
+		assert.False(t, updated.Trusted)
+		return nil
+	}).Times(1)
+
+	trusted := false
+	response, err := beneficiaryService.Update(fiberCtx.Context(), dto.UpdateBeneficiaryRequest{Id: beneficiary.Id, Trusted: &trusted})
+	assert.NoError(t, err)
+	assert.False(t, response.Trusted)
+}
+
+func TestBeneficiaryService_TrustApproval_Expired(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	beneficiaryEventRepoMock := repository.NewMockBeneficiaryEventRepository(gomock.NewController(t))
+	beneficiaryService := NewBeneficiaryService(beneficiaryRepoMock, beneficiaryEventRepoMock, accountRepoMock, userRepoMock, s, pkgSMSMock)
+
+	expiresAt := time.Now().Add(-time.Minute)
+	beneficiary := mockBeneficiary
+	beneficiary.Trusted = false
+	beneficiary.TrustApprovalMethod = enum.ApprovalMethodEmailLink
+	beneficiary.TrustTokenHash = hashToken("token")
+	beneficiary.TrustExpiresAt = &expiresAt
+
+	// The expired link does not trust the beneficiary, the expiry is recorded
+	beneficiaryRepoMock.EXPECT().LockByTrustTokenHash(hashToken("token")).Return(&beneficiary, nil).Times(1)
+	beneficiaryEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.BeneficiaryEvent) error {
+		assert.Equal(t, enum.BeneficiaryActionTrustExpired, event.Action)
+		return nil
+	}).Times(1)
+	beneficiaryRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.Beneficiary) error {
+		assert.False(t, updated.Trusted)
+		assert.Empty(t, updated.TrustTokenHash)
+		return nil
+	}).Times(1)
+
+	err := beneficiaryService.TrustApproval(fiberCtx.Context(), "token")
+	assert.EqualError(t, err, messages.BeneficiaryTrustExpired)
+}
+
+func TestAccountService_TransferMoney_TrustedBeneficiary(t *testing.T) {
+	// The limit is read when the service is created
+	t.Setenv("TRUSTED_BENEFICIARY_LIMIT", "50")
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		BeneficiaryId:     mockBeneficiary.Id,
+	}
+
+	// The transfer is executed without an approval
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(2)
+	beneficiaryRepoMock.EXPECT().FindById(mockBeneficiary.Id).Return(&mockBeneficiary, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockBeneficiary.AccountNumber).Return(&mockAccountData[1], nil).Times(2)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id, mockAccountData[1].Id).Return([]models.Account{senderAccount, mockAccountData[1]}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+
+	// The limit is checked before and again when the owner is locked
+	transferRequestRepoMock.EXPECT().SumExecutedTrustedByOwner(senderAccount.OwnerId, gomock.Any()).Return(money.MustParse("40"), nil).Times(2)
+	accountRepoMock.EXPECT().FindInternal(enum.FeeIncomeAccountCode, money.DefaultCurrency).Return(&mockCashAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b71"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		assert.Equal(t, enum.TransferExecuted, transfer.Status)
+		assert.Equal(t, enum.ApprovalTrustedBeneficiary, transfer.ApprovalMethod)
+		assert.Equal(t, mockBeneficiary.Id, transfer.BeneficiaryId)
+		assert.Empty(t, transfer.TokenHash)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b72"
+		return &transfer, nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(2)
+
+	response, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.TransferExecuted, response.Status)
+	assert.Equal(t, mockBeneficiary.Id, response.BeneficiaryId)
+}
+
+func TestAccountService_TransferMoney_TrustedBeneficiaryOverLimit(t *testing.T) {
+	t.Setenv("TRUSTED_BENEFICIARY_LIMIT", "5")
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		BeneficiaryId:     mockBeneficiary.Id,
+	}
+
+	// Another receiver than the beneficiary is refused
+	request.ToAccountNumber = 1000000009
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	beneficiaryRepoMock.EXPECT().FindById(mockBeneficiary.Id).Return(&mockBeneficiary, nil).Times(1)
+
+	_, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.BadRequest)
+
+	// Above the limit the transfer waits for the approval of the sender
+	request.ToAccountNumber = 0
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	beneficiaryRepoMock.EXPECT().FindById(mockBeneficiary.Id).Return(&mockBeneficiary, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockBeneficiary.AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		assert.Equal(t, enum.TransferPendingApproval, transfer.Status)
+		assert.Equal(t, mockBeneficiary.Id, transfer.BeneficiaryId)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b72"
+		return &transfer, nil
+	}).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		return &hold, nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+
+	response, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.TransferPendingApproval, response.Status)
+}
+
+func TestAccountService_TransferMoney_TrustedBeneficiaryDailyLimit(t *testing.T) {
+	t.Setenv("TRUSTED_BENEFICIARY_LIMIT", "50")
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.TransferMoneyRequest{
+		Amount:            money.MustParse("10"),
+		FromAccountNumber: senderAccount.AccountNumber,
+		BeneficiaryId:     mockBeneficiary.Id,
+	}
+
+	// The transfers without an approval of the day add up, above the limit the transfer waits for the approval
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	beneficiaryRepoMock.EXPECT().FindById(mockBeneficiary.Id).Return(&mockBeneficiary, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockBeneficiary.AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	transferRequestRepoMock.EXPECT().SumExecutedTrustedByOwner(senderAccount.OwnerId, gomock.Any()).Return(money.MustParse("45"), nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		assert.Equal(t, enum.TransferPendingApproval, transfer.Status)
+		assert.Equal(t, enum.ApprovalMethodEmailLink, transfer.ApprovalMethod)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b72"
+		return &transfer, nil
+	}).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		return &hold, nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+
+	response, err := s.TransferMoney(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.TransferPendingApproval, response.Status)
+}
@@ -0,0 +1,821 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"tek-bank/pkg/sms"
+	"time"
+)
+
+// Columns of a bulk transfer file, the receiver is given by its account number or its IBAN
+const (
+	bulkColumnAccountNumber = "to_account_number"
+	bulkColumnIBAN          = "to_iban"
+	bulkColumnAmount        = "amount"
+	bulkColumnNote          = "note"
+)
+
+// bulkTransferLine is a row of a bulk transfer file, err is the message key of the error of the row
+type bulkTransferLine struct {
+	row             int
+	toAccountNumber int64
+	toIBAN          string
+	amount          money.Amount
+	note            string
+	err             string
+}
+
+// parseBulkTransferFile reads the rows of a bulk transfer CSV file. The header names the columns in any order,
+// rows which can not be read are returned with their error. A file without a valid header or without rows is invalid.
+func parseBulkTransferFile(content []byte) ([]bulkTransferLine, error) {
+	// Spreadsheet programs start the file with a byte order mark
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New(messages.InvalidBulkTransferFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case bulkColumnAccountNumber, bulkColumnIBAN, bulkColumnAmount, bulkColumnNote:
+		default:
+			return nil, errors.New(messages.InvalidBulkTransferFile)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, errors.New(messages.InvalidBulkTransferFile)
+		}
+		columns[name] = i
+	}
+
+	_, hasAmount := columns[bulkColumnAmount]
+	_, hasAccountNumber := columns[bulkColumnAccountNumber]
+	_, hasIBAN := columns[bulkColumnIBAN]
+	if !hasAmount || (!hasAccountNumber && !hasIBAN) {
+		return nil, errors.New(messages.InvalidBulkTransferFile)
+	}
+
+	var lines []bulkTransferLine
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(messages.InvalidBulkTransferFile)
+		}
+
+		if len(lines) == enum.BulkTransferMaxRows {
+			return nil, errors.New(messages.BulkTransferTooManyRows)
+		}
+
+		row, _ := reader.FieldPos(0)
+		lines = append(lines, parseBulkTransferRecord(record, columns, row))
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New(messages.InvalidBulkTransferFile)
+	}
+
+	return lines, nil
+}
+
+// parseBulkTransferRecord reads a row of a bulk transfer file, row is its line in the file
+func parseBulkTransferRecord(record []string, columns map[string]int, row int) bulkTransferLine {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	line := bulkTransferLine{
+		row:    row,
+		toIBAN: field(bulkColumnIBAN),
+		note:   field(bulkColumnNote),
+	}
+
+	if accountNumber := field(bulkColumnAccountNumber); accountNumber != "" {
+		number, err := strconv.ParseInt(accountNumber, 10, 64)
+		if err != nil {
+			line.err = messages.AccountNotFound
+			return line
+		}
+		line.toAccountNumber = number
+	}
+
+	if line.toAccountNumber == 0 && line.toIBAN == "" {
+		line.err = messages.AccountNotFound
+		return line
+	}
+
+	amount, err := money.Parse(field(bulkColumnAmount))
+	if err != nil || !amount.IsPositive() {
+		line.err = messages.InvalidAmount
+		return line
+	}
+	line.amount = amount
+
+	return line
+}
+
+// isBulkTransferExpired reports whether the time to approve the bulk transfer is over
+func isBulkTransferExpired(bulkTransfer *models.BulkTransfer, now time.Time) bool {
+	return bulkTransfer.ExpiresAt != nil && !now.Before(*bulkTransfer.ExpiresAt)
+}
+
+func bulkTransferItem(bulkTransfer models.BulkTransfer) dto.BulkTransferItem {
+	return dto.BulkTransferItem{
+		Id:                bulkTransfer.Id,
+		FromAccountNumber: bulkTransfer.FromAccountNumber,
+		FileName:          bulkTransfer.FileName,
+		Currency:          bulkTransfer.Currency.String(),
+		RowCount:          bulkTransfer.RowCount,
+		TotalAmount:       bulkTransfer.TotalAmount,
+		TotalFee:          bulkTransfer.TotalFee,
+		Status:            bulkTransfer.Status,
+		ApprovalMethod:    bulkTransfer.ApprovalMethod,
+		ExpiresAt:         bulkTransfer.ExpiresAt,
+		ApprovedAt:        bulkTransfer.ApprovedAt,
+		CompletedAt:       bulkTransfer.CompletedAt,
+		ExecutedCount:     bulkTransfer.ExecutedCount,
+		FailedCount:       bulkTransfer.FailedCount,
+		CreatedAt:         bulkTransfer.CreatedAt,
+	}
+}
+
+func bulkTransferRow(transfer models.Transfer) dto.BulkTransferRow {
+	return dto.BulkTransferRow{
+		Row:               transfer.BulkRow,
+		TransferId:        transfer.Id,
+		ToAccountNumber:   transfer.ToAccountNumber,
+		Amount:            transfer.Amount,
+		ConvertedAmount:   transfer.ConvertedAmount,
+		ConvertedCurrency: transfer.ConvertedCurrency.String(),
+		TransactionFee:    transfer.TransactionFee,
+		Note:              transfer.Note,
+		Status:            transfer.Status,
+		Error:             transfer.FailureReason,
+	}
+}
+
+//go:generate mockgen -destination=../mocks/service/bulk_transfer_service_mock.go -package=service tek-bank/internal/service BulkTransferService
+type BulkTransferService interface {
+	CreateBulkTransfer(ctx context.Context, request dto.CreateBulkTransferRequest) (*dto.BulkTransferItem, error)
+	BulkTransferApproval(ctx context.Context, token string) error
+	ApproveBulkTransfer(ctx context.Context, request dto.ApproveTransferRequest) error
+	CancelBulkTransfer(ctx context.Context, id string) error
+	GetBulkTransfer(ctx context.Context, id string) (*dto.BulkTransferItem, error)
+	ListBulkTransfers(ctx context.Context) ([]dto.BulkTransferItem, error)
+	ExpireBulkTransfers(now time.Time, limit int) (int64, error)
+	FindBulkTransferRows(limit int) ([]string, error)
+	ExecuteBulkTransferRow(id string, now time.Time) error
+
+	WithTx(trxHandle *gorm.DB) BulkTransferService
+}
+
+type bulkTransferService struct {
+	bulkTransferRepository  repository.BulkTransferRepository
+	accountRepository       repository.AccountRepository
+	userRepository          repository.UserRepository
+	transferRepository      repository.TransferRepository
+	transferLimitRepository repository.TransferLimitRepository
+	holdRepository          repository.HoldRepository
+	notificationRepository  repository.NotificationRepository
+	accountService          AccountService
+	pkgSMS                  sms.Sender
+
+	// Transaction of the service, a row is executed in a savepoint of it
+	tx *gorm.DB
+}
+
+// NewBulkTransferService creates the service of the bulk transfers, the rows are quoted, checked against the limits
+// and executed by the account service like single transfers
+func NewBulkTransferService(
+	bulkTransferRepository repository.BulkTransferRepository,
+	accountRepository repository.AccountRepository,
+	userRepository repository.UserRepository,
+	transferRepository repository.TransferRepository,
+	transferLimitRepository repository.TransferLimitRepository,
+	holdRepository repository.HoldRepository,
+	notificationRepository repository.NotificationRepository,
+	accountService AccountService,
+	pkgSMS sms.Sender,
+) BulkTransferService {
+	return &bulkTransferService{
+		bulkTransferRepository:  bulkTransferRepository,
+		accountRepository:       accountRepository,
+		userRepository:          userRepository,
+		transferRepository:      transferRepository,
+		transferLimitRepository: transferLimitRepository,
+		holdRepository:          holdRepository,
+		notificationRepository:  notificationRepository,
+		accountService:          accountService,
+		pkgSMS:                  pkgSMS,
+	}
+}
+
+func (s *bulkTransferService) WithTx(trxHandle *gorm.DB) BulkTransferService {
+	clone := *s
+	clone.bulkTransferRepository = s.bulkTransferRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	clone.userRepository = s.userRepository.WithTx(trxHandle)
+	clone.transferRepository = s.transferRepository.WithTx(trxHandle)
+	clone.transferLimitRepository = s.transferLimitRepository.WithTx(trxHandle)
+	clone.holdRepository = s.holdRepository.WithTx(trxHandle)
+	clone.notificationRepository = s.notificationRepository.WithTx(trxHandle)
+	clone.accountService = s.accountService.WithTx(trxHandle)
+	clone.tx = trxHandle
+	return &clone
+}
+
+// CreateBulkTransfer validates every row of a bulk transfer file and creates the bulk transfer waiting for a single
+// approval of the sender, the amounts and the fees of the rows are held until they are executed. If a row is invalid
+// nothing is created, the preview is returned with the errors of the rows.
+func (s *bulkTransferService) CreateBulkTransfer(ctx context.Context, request dto.CreateBulkTransferRequest) (*dto.BulkTransferItem, error) {
+	lines, err := parseBulkTransferFile(request.File)
+	if err != nil {
+		return nil, err
+	}
+
+	senderAccount, err := s.accountService.ownedAccount(ctx, request.FromAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock the sender, so concurrent requests can not reserve the same money
+	if err := s.accountService.lockAccounts(senderAccount); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if err := checkDebit(senderAccount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usages, err := transferLimitUsages(s.transferLimitRepository, s.transferRepository, senderAccount, now)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	preview := &dto.BulkTransferItem{
+		FromAccountNumber: senderAccount.AccountNumber,
+		FileName:          request.FileName,
+		Currency:          senderAccount.Currency.String(),
+		RowCount:          len(lines),
+		Rows:              make([]dto.BulkTransferRow, 0, len(lines)),
+	}
+
+	var transfers []*models.Transfer
+	baseTotal := money.Zero
+	for _, line := range lines {
+		row := dto.BulkTransferRow{
+			Row:             line.row,
+			ToAccountNumber: line.toAccountNumber,
+			ToIBAN:          line.toIBAN,
+			Amount:          line.amount,
+			Note:            line.note,
+		}
+
+		transfer, err := s.quoteBulkTransferLine(senderAccount, line, usages, baseTotal)
+		if err != nil {
+			row.Error = err.Error()
+			preview.Rows = append(preview.Rows, row)
+			continue
+		}
+
+		transfer.BulkRow = line.row
+		transfers = append(transfers, transfer)
+		baseTotal += transfer.BaseAmount
+
+		row.ToAccountNumber = transfer.ToAccountNumber
+		row.ConvertedAmount = transfer.ConvertedAmount
+		row.ConvertedCurrency = transfer.ConvertedCurrency.String()
+		row.TransactionFee = transfer.TransactionFee
+		preview.Rows = append(preview.Rows, row)
+
+		preview.TotalAmount += transfer.Amount
+		preview.TotalFee += transfer.TransactionFee
+	}
+
+	if len(transfers) != len(lines) {
+		return preview, errors.New(messages.BulkTransferRowsInvalid)
+	}
+
+	// The money of the other pending transfers is held
+	available, err := s.accountService.availableBalance(senderAccount, now)
+	if err != nil {
+		return nil, err
+	}
+	if available < preview.TotalAmount+preview.TotalFee {
+		return preview, errors.New(messages.InSufficientBalance)
+	}
+
+	approval, err := s.accountService.newApproval(senderAccount.Owner.ApprovalMethod, now)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkTransfer, err := s.bulkTransferRepository.Create(models.BulkTransfer{
+		OwnerId:           senderAccount.OwnerId,
+		FromAccountNumber: senderAccount.AccountNumber,
+		FileName:          request.FileName,
+		Currency:          senderAccount.Currency,
+		RowCount:          len(transfers),
+		TotalAmount:       preview.TotalAmount,
+		TotalFee:          preview.TotalFee,
+		Status:            enum.BulkTransferPendingApproval,
+		ApprovalMethod:    approval.method,
+		TokenHash:         approval.tokenHash,
+		CodeHash:          approval.codeHash,
+		ExpiresAt:         &approval.expiresAt,
+		CreatedBy:         senderAccount.OwnerId,
+		UpdatedBy:         senderAccount.OwnerId,
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// The rows are approved with the bulk transfer, their holds do not expire since approved rows wait for the worker.
+	// The holds are released when a row fails or the bulk transfer is not approved.
+	for i, transfer := range transfers {
+		transfer.Status = enum.TransferPendingApproval
+		transfer.ApprovalMethod = approval.method
+		transfer.BulkTransferId = bulkTransfer.Id
+
+		createdTransfer, err := s.transferRepository.Create(*transfer)
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		_, err = s.holdRepository.Create(models.Hold{
+			AccountId:  senderAccount.Id,
+			Amount:     createdTransfer.Amount + createdTransfer.TransactionFee,
+			Currency:   senderAccount.Currency,
+			Type:       enum.HoldTypeTransfer,
+			TransferId: createdTransfer.Id,
+			Status:     enum.HoldActive,
+			CreatedBy:  senderAccount.OwnerId,
+		})
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		preview.Rows[i].TransferId = createdTransfer.Id
+		preview.Rows[i].Status = createdTransfer.Status
+	}
+
+	err = s.sendBulkTransferApproval(senderAccount, bulkTransfer, approval.secret)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := bulkTransferItem(*bulkTransfer)
+	item.Rows = preview.Rows
+	return &item, nil
+}
+
+// quoteBulkTransferLine validates a row of a bulk transfer and quotes its transfer, baseTotal is the base currency
+// amount of the valid rows before it
+func (s *bulkTransferService) quoteBulkTransferLine(senderAccount *models.Account, line bulkTransferLine, usages []limitUsage, baseTotal money.Amount) (*models.Transfer, error) {
+	if line.err != "" {
+		return nil, errors.New(line.err)
+	}
+
+	receiverAccount, err := findReceiverAccount(s.accountRepository, line.toAccountNumber, line.toIBAN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Frozen and closed accounts are checked again when the row is executed
+	if err := checkCredit(receiverAccount); err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.accountService.quoteTransfer(senderAccount, receiverAccount, line.amount, line.note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exceededBulkLimit(usages, transfer.BaseAmount, baseTotal+transfer.BaseAmount); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// sendBulkTransferApproval sends the approval link or the one-time code of the bulk transfer to the sender
+func (s *bulkTransferService) sendBulkTransferApproval(senderAccount *models.Account, bulkTransfer *models.BulkTransfer, secret string) error {
+	if bulkTransfer.ApprovalMethod == enum.ApprovalMethodSMSCode {
+		return s.pkgSMS.Send(senderAccount.Owner.PhoneNumber, fmt.Sprintf(
+			"TEK Bank: %s is your code to approve the bulk transfer of %d transfers with %s from %d. It is valid for %d minutes.",
+			secret, bulkTransfer.RowCount, money.New(bulkTransfer.TotalAmount, bulkTransfer.Currency), senderAccount.AccountNumber, int(enum.TransferCodeTTL.Minutes()),
+		))
+	}
+
+	var approval string
+	if bulkTransfer.ApprovalMethod == enum.ApprovalMethodEmailCode {
+		approval = `
+				<p>You have a new bulk transfer request. Please approve all of its transfers with the code below.</p>
+				<p><strong>` + secret + `</strong></p>
+				<p>The code is valid until ` + bulkTransfer.ExpiresAt.Format(time.RFC1123) + `.</p>`
+	} else {
+		bulkTransferApprovalLink := fmt.Sprintf("http://localhost/v1/account/bulk-transfer-approval?token=%s", secret)
+		approval = `
+				<p>You have a new bulk transfer request. Please click the link below to approve all of its transfers.</p>
+				<p><a href="` + bulkTransferApprovalLink + `">` + bulkTransferApprovalLink + `</a></p>
+				<p>The link is valid until ` + bulkTransfer.ExpiresAt.Format(time.RFC1123) + `.</p>`
+	}
+
+	var body string = `
+			<body>
+				<p>Your Account Number: <strong>` + fmt.Sprint(senderAccount.AccountNumber) + `</strong></p>
+				<p>File: <strong>` + html.EscapeString(bulkTransfer.FileName) + `</strong></p>
+				<p>Transfers: <strong>` + fmt.Sprint(bulkTransfer.RowCount) + `</strong></p>
+				<p>Total Amount: <strong>` + money.New(bulkTransfer.TotalAmount, bulkTransfer.Currency).String() + `</strong></p>
+				<p>Total Fee: <strong>` + money.New(bulkTransfer.TotalFee, bulkTransfer.Currency).String() + `</strong></p>` + approval + `
+				<p>If you did not request a bulk transfer, please ignore this email.</p>
+				<br>
+				<p>Best Regards,</p>
+			</body>
+	`
+
+	return s.accountService.sendMail(gomailer.Content{
+		Subject: "TEK Bank - Bulk Transfer Approval",
+		Body:    body,
+		To:      []string{senderAccount.Owner.Email},
+	})
+}
+
+// BulkTransferApproval approves the pending bulk transfer of the approval link token
+func (s *bulkTransferService) BulkTransferApproval(ctx context.Context, token string) error {
+	bulkTransfer, err := s.bulkTransferRepository.LockByTokenHash(hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.BulkTransferNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return s.approveBulkTransfer(bulkTransfer, time.Now())
+}
+
+// ApproveBulkTransfer approves the pending bulk transfer of the current user with its one-time code.
+// Wrong codes are counted, the bulk transfer is rejected after too many of them.
+func (s *bulkTransferService) ApproveBulkTransfer(ctx context.Context, request dto.ApproveTransferRequest) error {
+	bulkTransfer, err := s.ownedBulkTransfer(ctx, request.Id)
+	if err != nil {
+		return err
+	}
+
+	// Bulk transfers approved with a link have no code
+	if bulkTransfer.CodeHash == "" {
+		return errors.New(messages.InvalidTransferCode)
+	}
+
+	now := time.Now()
+	if bulkTransfer.Status == enum.BulkTransferPendingApproval && !isBulkTransferExpired(bulkTransfer, now) &&
+		subtle.ConstantTimeCompare([]byte(hashToken(request.Code)), []byte(bulkTransfer.CodeHash)) != 1 {
+		bulkTransfer.CodeAttempts++
+		if bulkTransfer.CodeAttempts < enum.TransferCodeMaxAttempts {
+			if err := s.bulkTransferRepository.Update(*bulkTransfer); err != nil {
+				return errors.New(messages.UnexpectedError)
+			}
+			return errors.New(messages.InvalidTransferCode)
+		}
+
+		bulkTransfer.FailureReason = messages.TransferCodeAttemptsExceeded
+		if err := s.endBulkTransfer(bulkTransfer, enum.BulkTransferRejected, now); err != nil {
+			return err
+		}
+		return errors.New(messages.TransferCodeAttemptsExceeded)
+	}
+
+	return s.approveBulkTransfer(bulkTransfer, now)
+}
+
+// approveBulkTransfer approves the pending bulk transfer and its rows, the rows are executed by the worker.
+// The expiry is recorded even though an error is returned, the caller should keep the changes of the transaction for it.
+func (s *bulkTransferService) approveBulkTransfer(bulkTransfer *models.BulkTransfer, now time.Time) error {
+	if bulkTransfer.Status == enum.BulkTransferPendingApproval && isBulkTransferExpired(bulkTransfer, now) {
+		if err := s.endBulkTransfer(bulkTransfer, enum.BulkTransferExpired, now); err != nil {
+			return err
+		}
+		return errors.New(messages.TransferExpired)
+	}
+
+	if bulkTransfer.Status != enum.BulkTransferPendingApproval {
+		return errors.New(messages.TransferNotPending)
+	}
+
+	if _, err := s.transferRepository.ApproveBulkRows(bulkTransfer.Id, now); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	bulkTransfer.Status = enum.BulkTransferProcessing
+	bulkTransfer.ApprovedAt = &now
+	if err := s.bulkTransferRepository.Update(*bulkTransfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// endBulkTransfer ends a pending bulk transfer with the status, its rows end with the same status
+// and the money held for them is available again
+func (s *bulkTransferService) endBulkTransfer(bulkTransfer *models.BulkTransfer, status string, now time.Time) error {
+	transfers, err := s.transferRepository.FindByBulkTransferId(bulkTransfer.Id)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	holdStatus := enum.HoldReleased
+	if status == enum.BulkTransferExpired {
+		holdStatus = enum.HoldExpired
+	}
+
+	for i := range transfers {
+		if transfers[i].Status != enum.TransferPendingApproval {
+			continue
+		}
+
+		transfers[i].FailureReason = bulkTransfer.FailureReason
+		if err := s.accountService.changeTransferStatus(&transfers[i], status, now); err != nil {
+			return err
+		}
+		if err := s.accountService.releaseTransferHold(&transfers[i], holdStatus, now); err != nil {
+			return err
+		}
+	}
+
+	bulkTransfer.Status = status
+	switch status {
+	case enum.BulkTransferRejected:
+		bulkTransfer.RejectedAt = &now
+	case enum.BulkTransferExpired:
+		bulkTransfer.ExpiredAt = &now
+	case enum.BulkTransferCancelled:
+		bulkTransfer.CancelledAt = &now
+	}
+
+	if err := s.bulkTransferRepository.Update(*bulkTransfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// ownedBulkTransfer locks the bulk transfer if it belongs to the current user,
+// bulk transfers of other users are not found
+func (s *bulkTransferService) ownedBulkTransfer(ctx context.Context, id string) (*models.BulkTransfer, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New(messages.BulkTransferNotFound)
+	}
+
+	bulkTransfer, err := s.bulkTransferRepository.LockById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && bulkTransfer.OwnerId != currentUser.Id) {
+		return nil, errors.New(messages.BulkTransferNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return bulkTransfer, nil
+}
+
+// CancelBulkTransfer cancels a bulk transfer of the current user which is waiting for approval
+func (s *bulkTransferService) CancelBulkTransfer(ctx context.Context, id string) error {
+	bulkTransfer, err := s.ownedBulkTransfer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if bulkTransfer.Status != enum.BulkTransferPendingApproval {
+		return errors.New(messages.TransferNotPending)
+	}
+
+	bulkTransfer.UpdatedBy = bulkTransfer.OwnerId
+	return s.endBulkTransfer(bulkTransfer, enum.BulkTransferCancelled, time.Now())
+}
+
+// GetBulkTransfer returns a bulk transfer of the current user with the result of every row
+func (s *bulkTransferService) GetBulkTransfer(ctx context.Context, id string) (*dto.BulkTransferItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New(messages.BulkTransferNotFound)
+	}
+
+	bulkTransfer, err := s.bulkTransferRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && bulkTransfer.OwnerId != currentUser.Id) {
+		return nil, errors.New(messages.BulkTransferNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	transfers, err := s.transferRepository.FindByBulkTransferId(bulkTransfer.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := bulkTransferItem(*bulkTransfer)
+	item.Rows = make([]dto.BulkTransferRow, 0, len(transfers))
+	for _, transfer := range transfers {
+		item.Rows = append(item.Rows, bulkTransferRow(transfer))
+	}
+
+	return &item, nil
+}
+
+// ListBulkTransfers returns the bulk transfers of the current user without their rows, the newest first
+func (s *bulkTransferService) ListBulkTransfers(ctx context.Context) ([]dto.BulkTransferItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	bulkTransfers, err := s.bulkTransferRepository.FindByOwnerId(currentUser.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	items := []dto.BulkTransferItem{}
+	for _, bulkTransfer := range bulkTransfers {
+		items = append(items, bulkTransferItem(bulkTransfer))
+	}
+
+	return items, nil
+}
+
+// ExpireBulkTransfers expires the bulk transfers which were not approved in time and releases the money of their rows
+func (s *bulkTransferService) ExpireBulkTransfers(now time.Time, limit int) (int64, error) {
+	bulkTransfers, err := s.bulkTransferRepository.LockExpired(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range bulkTransfers {
+		if err := s.endBulkTransfer(&bulkTransfers[i], enum.BulkTransferExpired, now); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(bulkTransfers)), nil
+}
+
+// FindBulkTransferRows returns the ids of the approved rows of bulk transfers which are not executed yet
+func (s *bulkTransferService) FindBulkTransferRows(limit int) ([]string, error) {
+	return s.transferRepository.FindApprovedBulkRows(limit)
+}
+
+// ExecuteBulkTransferRow executes an approved row of a bulk transfer. A row which can not be executed, like for the
+// balance, the limits or a closed account, is rejected with the reason and its money is released, the other rows go on.
+// Unexpected errors are returned, so the row is tried again. The last row completes the bulk transfer. The mails are
+// put into the outbox, a failed mail does not roll back the row.
+func (s *bulkTransferService) ExecuteBulkTransferRow(id string, now time.Time) error {
+	if s.tx == nil {
+		return errors.New(messages.TransactionFailed)
+	}
+
+	transfer, err := s.transferRepository.LockById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.TransferNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	if transfer.BulkTransferId == "" || transfer.Status != enum.TransferApproved {
+		return nil
+	}
+
+	// Lock the bulk transfer, so it is completed once by its last row
+	bulkTransfer, err := s.bulkTransferRepository.LockById(transfer.BulkTransferId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	// The row runs in a savepoint, so a rejected row leaves no money movement behind
+	var receiverAccount *models.Account
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		_, receiverAccount, err = s.accountService.WithTx(tx).executeTransfer(transfer)
+		return err
+	})
+	switch {
+	case err == nil:
+		if err := s.accountService.changeTransferStatus(transfer, enum.TransferExecuted, now); err != nil {
+			return err
+		}
+
+		err = s.notificationRepository.Create(models.Notification{
+			To:      receiverAccount.Owner.Email,
+			Subject: "TEK Bank - Transfer Received",
+			Body:    "You have received a new transfer.",
+		})
+		if err != nil {
+			return errors.New(messages.UnexpectedError)
+		}
+	case err.Error() == messages.UnexpectedError:
+		return err
+	default:
+		transfer.FailureReason = err.Error()
+		if err := s.accountService.changeTransferStatus(transfer, enum.TransferRejected, now); err != nil {
+			return err
+		}
+		if err := s.accountService.releaseTransferHold(transfer, enum.HoldReleased, now); err != nil {
+			return err
+		}
+	}
+
+	remaining, err := s.transferRepository.CountBulkRows(bulkTransfer.Id, enum.TransferApproved)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	if remaining > 0 {
+		return nil
+	}
+
+	return s.completeBulkTransfer(bulkTransfer, now)
+}
+
+// completeBulkTransfer records the results of the rows of the bulk transfer and sends the report to the sender
+func (s *bulkTransferService) completeBulkTransfer(bulkTransfer *models.BulkTransfer, now time.Time) error {
+	transfers, err := s.transferRepository.FindByBulkTransferId(bulkTransfer.Id)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	executedAmount := money.Zero
+	bulkTransfer.ExecutedCount = 0
+	bulkTransfer.FailedCount = 0
+	for _, transfer := range transfers {
+		if transfer.Status == enum.TransferExecuted {
+			bulkTransfer.ExecutedCount++
+			executedAmount += transfer.Amount
+		} else {
+			bulkTransfer.FailedCount++
+		}
+	}
+
+	bulkTransfer.Status = enum.BulkTransferCompleted
+	bulkTransfer.CompletedAt = &now
+	if err := s.bulkTransferRepository.Update(*bulkTransfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	owner, err := s.userRepository.FindByID(bulkTransfer.OwnerId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	err = s.notificationRepository.Create(models.Notification{
+		To:      owner.Email,
+		Subject: i18n.CreateMsgWithLanguage("", messages.BulkTransferReportMailSubject),
+		Body: i18n.CreateMsgWithLanguage("", messages.BulkTransferReportMailBody, map[string]string{
+			"FileName":      html.EscapeString(bulkTransfer.FileName),
+			"AccountNumber": fmt.Sprint(bulkTransfer.FromAccountNumber),
+			"Rows":          fmt.Sprint(bulkTransfer.RowCount),
+			"Executed":      fmt.Sprint(bulkTransfer.ExecutedCount),
+			"Failed":        fmt.Sprint(bulkTransfer.FailedCount),
+			"Amount":        money.New(executedAmount, bulkTransfer.Currency).String(),
+		}),
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
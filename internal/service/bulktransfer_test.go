@@ -0,0 +1,481 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestParseBulkTransferFile(t *testing.T) {
+	lines, err := parseBulkTransferFile([]byte("\xef\xbb\xbfAmount,To_Account_Number,note\n" +
+		"1500.50,1000000002,Salary\n" +
+		"abc,1000000003,\n" +
+		"10,,\n" +
+		"-5,1000000004,\n"))
+	assert.NoError(t, err)
+	assert.Len(t, lines, 4)
+
+	assert.Equal(t, bulkTransferLine{row: 2, toAccountNumber: 1000000002, amount: money.MustParse("1500.50"), note: "Salary"}, lines[0])
+	assert.Equal(t, 3, lines[1].row)
+	assert.Equal(t, messages.InvalidAmount, lines[1].err)
+	assert.Equal(t, messages.AccountNotFound, lines[2].err)
+	assert.Equal(t, messages.InvalidAmount, lines[3].err)
+
+	// The header has to name an amount and a receiver
+	_, err = parseBulkTransferFile([]byte("amount,note\n10,Salary\n"))
+	assert.EqualError(t, err, messages.InvalidBulkTransferFile)
+
+	_, err = parseBulkTransferFile([]byte("amount,to_iban,salary\n10,TR330006100519786457841326,1\n"))
+	assert.EqualError(t, err, messages.InvalidBulkTransferFile)
+
+	_, err = parseBulkTransferFile([]byte("amount,to_iban\n"))
+	assert.EqualError(t, err, messages.InvalidBulkTransferFile)
+
+	file := "amount,to_account_number\n" + strings.Repeat("10,1000000002\n", enum.BulkTransferMaxRows+1)
+	_, err = parseBulkTransferFile([]byte(file))
+	assert.EqualError(t, err, messages.BulkTransferTooManyRows)
+}
+
+func TestBulkTransferService_CreateBulkTransfer(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.CreateBulkTransferRequest{
+		FromAccountNumber: senderAccount.AccountNumber,
+		FileName:          `<a href="https://evil.example">payroll</a>.csv`,
+		File:              []byte("to_account_number,amount,note\n1000000002,20,Salary\n1000000002,30,Bonus\n"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockAccountData[1].AccountNumber).Return(&mockAccountData[1], nil).Times(2)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(2)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) (*models.BulkTransfer, error) {
+		assert.Equal(t, enum.BulkTransferPendingApproval, bulkTransfer.Status)
+		assert.Equal(t, hashToken("token"), bulkTransfer.TokenHash)
+		assert.Equal(t, 2, bulkTransfer.RowCount)
+		assert.Equal(t, money.MustParse("50"), bulkTransfer.TotalAmount)
+		assert.Equal(t, 2*mockFeeSchedule.FlatFee, bulkTransfer.TotalFee)
+		bulkTransfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40"
+		return &bulkTransfer, nil
+	}).Times(1)
+
+	// Every row is a pending transfer of the bulk transfer with its own hold
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40", transfer.BulkTransferId)
+		assert.Equal(t, enum.TransferPendingApproval, transfer.Status)
+		assert.Empty(t, transfer.TokenHash)
+		assert.Nil(t, transfer.ExpiresAt)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41"
+		return &transfer, nil
+	}).Times(2)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", hold.TransferId)
+		assert.Nil(t, hold.ExpiresAt)
+		return &hold, nil
+	}).Times(2)
+
+	// The file name of the client is escaped in the approval mail
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		assert.Contains(t, content.Body, "&lt;a href=&#34;https://evil.example&#34;&gt;payroll&lt;/a&gt;.csv")
+		assert.NotContains(t, content.Body, "evil.example\">")
+		return nil
+	}).Times(1)
+
+	response, err := bulkTransferService.CreateBulkTransfer(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40", response.Id)
+	assert.Equal(t, money.MustParse("50"), response.TotalAmount)
+	assert.Len(t, response.Rows, 2)
+	assert.Equal(t, 3, response.Rows[1].Row)
+	assert.Equal(t, enum.TransferPendingApproval, response.Rows[1].Status)
+}
+
+func TestBulkTransferService_CreateBulkTransfer_InvalidRows(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+
+	request := dto.CreateBulkTransferRequest{
+		FromAccountNumber: senderAccount.AccountNumber,
+		FileName:          "payroll.csv",
+		File:              []byte("to_account_number,amount\n1000000002,20\n1000000002,zero\n"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id).Return([]models.Account{senderAccount}, nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockAccountData[1].AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+
+	// Nothing is created, the preview shows the error of the row
+	response, err := bulkTransferService.CreateBulkTransfer(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.BulkTransferRowsInvalid)
+	assert.Len(t, response.Rows, 2)
+	assert.Empty(t, response.Rows[0].Error)
+	assert.Equal(t, mockFeeSchedule.FlatFee, response.Rows[0].TransactionFee)
+	assert.Equal(t, messages.InvalidAmount, response.Rows[1].Error)
+}
+
+// savepointDialector runs the savepoints of the test transaction without a database and counts their rollbacks
+type savepointDialector struct {
+	tests.DummyDialector
+	rollbacks *int
+}
+
+func (d savepointDialector) SavePoint(tx *gorm.DB, name string) error {
+	return nil
+}
+
+func (d savepointDialector) RollbackTo(tx *gorm.DB, name string) error {
+	*d.rollbacks++
+	return nil
+}
+
+// testConnPool is the connection of the test transaction, the repositories are mocks so it runs no statement
+type testConnPool struct {
+	gorm.ConnPool
+}
+
+func (testConnPool) Commit() error {
+	return nil
+}
+
+func (testConnPool) Rollback() error {
+	return nil
+}
+
+// withBulkTestTx returns the bulk transfer service running on a transaction the rows can take savepoints of,
+// the repositories stay the same mocks. The savepoints rolled back are counted.
+func withBulkTestTx(t *testing.T) (BulkTransferService, *int) {
+	rollbacks := 0
+	tx, err := gorm.Open(savepointDialector{rollbacks: &rollbacks}, &gorm.Config{ConnPool: testConnPool{}})
+	assert.NoError(t, err)
+
+	// The rows run on a session of the savepoint
+	accountRepoMock.EXPECT().WithTx(gomock.Any()).Return(accountRepoMock).AnyTimes()
+	userRepoMock.EXPECT().WithTx(gomock.Any()).Return(userRepoMock).AnyTimes()
+	transferRepoMock.EXPECT().WithTx(gomock.Any()).Return(transferRepoMock).AnyTimes()
+	ledgerRepoMock.EXPECT().WithTx(gomock.Any()).Return(ledgerRepoMock).AnyTimes()
+	exchangeRateRepoMock.EXPECT().WithTx(gomock.Any()).Return(exchangeRateRepoMock).AnyTimes()
+	transferRequestRepoMock.EXPECT().WithTx(gomock.Any()).Return(transferRequestRepoMock).AnyTimes()
+	transferLimitRepoMock.EXPECT().WithTx(gomock.Any()).Return(transferLimitRepoMock).AnyTimes()
+	feeScheduleRepoMock.EXPECT().WithTx(gomock.Any()).Return(feeScheduleRepoMock).AnyTimes()
+	accountEventRepoMock.EXPECT().WithTx(gomock.Any()).Return(accountEventRepoMock).AnyTimes()
+	holdRepoMock.EXPECT().WithTx(gomock.Any()).Return(holdRepoMock).AnyTimes()
+	interestRepoMock.EXPECT().WithTx(gomock.Any()).Return(interestRepoMock).AnyTimes()
+	bulkTransferRepoMock.EXPECT().WithTx(gomock.Any()).Return(bulkTransferRepoMock).AnyTimes()
+	beneficiaryRepoMock.EXPECT().WithTx(gomock.Any()).Return(beneficiaryRepoMock).AnyTimes()
+	notificationRepoMock.EXPECT().WithTx(gomock.Any()).Return(notificationRepoMock).AnyTimes()
+
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+	return bulkTransferService.WithTx(tx), &rollbacks
+}
+
+// mockBulkTransfer is a bulk transfer of John waiting for the approval
+func mockBulkTransfer(approvalMethod string, expiresAt time.Time) models.BulkTransfer {
+	return models.BulkTransfer{
+		Id:                "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40",
+		OwnerId:           mockData[0].Id,
+		FromAccountNumber: mockAccountData[0].AccountNumber,
+		FileName:          "payroll.csv",
+		Currency:          money.DefaultCurrency,
+		RowCount:          2,
+		TotalAmount:       money.MustParse("50"),
+		Status:            enum.BulkTransferPendingApproval,
+		ApprovalMethod:    approvalMethod,
+		ExpiresAt:         &expiresAt,
+	}
+}
+
+// mockBulkTransferRow is a row of the mock bulk transfer to Jane
+func mockBulkTransferRow(id string, amount string, status string) models.Transfer {
+	return models.Transfer{
+		Id:                id,
+		OwnerId:           mockData[0].Id,
+		FromAccountNumber: mockAccountData[0].AccountNumber,
+		ToAccountNumber:   mockAccountData[1].AccountNumber,
+		Amount:            money.MustParse(amount),
+		Currency:          money.DefaultCurrency,
+		ConvertedAmount:   money.MustParse(amount),
+		ConvertedCurrency: money.DefaultCurrency,
+		ExchangeRate:      money.OneRate,
+		BaseAmount:        money.MustParse(amount),
+		Status:            status,
+		BulkTransferId:    "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40",
+	}
+}
+
+func TestBulkTransferService_BulkTransferApproval(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailLink, time.Now().Add(enum.TransferApprovalTTL))
+	bulkTransfer.TokenHash = hashToken("token")
+
+	// The rows are approved with the bulk transfer and wait for the worker
+	bulkTransferRepoMock.EXPECT().LockByTokenHash(hashToken("token")).Return(&bulkTransfer, nil).Times(1)
+	transferRequestRepoMock.EXPECT().ApproveBulkRows(bulkTransfer.Id, gomock.Any()).Return(int64(2), nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferProcessing, bulkTransfer.Status)
+		assert.NotNil(t, bulkTransfer.ApprovedAt)
+		return nil
+	}).Times(1)
+
+	err := bulkTransferService.BulkTransferApproval(fiberCtx.Context(), "token")
+	assert.NoError(t, err)
+}
+
+func TestBulkTransferService_BulkTransferApproval_Expired(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailLink, time.Now().Add(-time.Minute))
+	bulkTransfer.TokenHash = hashToken("token")
+	rows := []models.Transfer{
+		mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", "20", enum.TransferPendingApproval),
+		mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b42", "30", enum.TransferPendingApproval),
+	}
+
+	// The expiry is recorded with the error, the handler keeps the changes of the transaction for it
+	bulkTransferRepoMock.EXPECT().LockByTokenHash(hashToken("token")).Return(&bulkTransfer, nil).Times(1)
+	transferRequestRepoMock.EXPECT().FindByBulkTransferId(bulkTransfer.Id).Return(rows, nil).Times(1)
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, enum.TransferExpired, transfer.Status)
+		assert.NotNil(t, transfer.ExpiredAt)
+		return nil
+	}).Times(2)
+	holdRepoMock.EXPECT().ReleaseByTransferId(rows[0].Id, enum.HoldExpired, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId(rows[1].Id, enum.HoldExpired, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferExpired, bulkTransfer.Status)
+		assert.NotNil(t, bulkTransfer.ExpiredAt)
+		assert.Nil(t, bulkTransfer.ApprovedAt)
+		return nil
+	}).Times(1)
+
+	err := bulkTransferService.BulkTransferApproval(fiberCtx.Context(), "token")
+	assert.EqualError(t, err, messages.TransferExpired)
+}
+
+func TestBulkTransferService_ApproveBulkTransfer_Code(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodSMSCode, time.Now().Add(enum.TransferCodeTTL))
+	bulkTransfer.CodeHash = hashToken("042137")
+
+	bulkTransferRepoMock.EXPECT().LockById(bulkTransfer.Id).Return(&bulkTransfer, nil).Times(1)
+	transferRequestRepoMock.EXPECT().ApproveBulkRows(bulkTransfer.Id, gomock.Any()).Return(int64(2), nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferProcessing, bulkTransfer.Status)
+		assert.Equal(t, 0, bulkTransfer.CodeAttempts)
+		return nil
+	}).Times(1)
+
+	err := bulkTransferService.ApproveBulkTransfer(fiberCtx.Context(), dto.ApproveTransferRequest{Id: bulkTransfer.Id, Code: "042137"})
+	assert.NoError(t, err)
+}
+
+func TestBulkTransferService_ApproveBulkTransfer_WrongCode(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailCode, time.Now().Add(enum.TransferCodeTTL))
+	bulkTransfer.CodeHash = hashToken("042137")
+
+	// The wrong code is counted, the rows are not approved
+	bulkTransferRepoMock.EXPECT().LockById(bulkTransfer.Id).Return(&bulkTransfer, nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferPendingApproval, bulkTransfer.Status)
+		assert.Equal(t, 1, bulkTransfer.CodeAttempts)
+		return nil
+	}).Times(1)
+
+	err := bulkTransferService.ApproveBulkTransfer(fiberCtx.Context(), dto.ApproveTransferRequest{Id: bulkTransfer.Id, Code: "000000"})
+	assert.EqualError(t, err, messages.InvalidTransferCode)
+}
+
+func TestBulkTransferService_ExecuteBulkTransferRow(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	bulkTransferService, rollbacks := withBulkTestTx(t)
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("100")
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailLink, time.Now())
+	bulkTransfer.Status = enum.BulkTransferProcessing
+	row := mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", "20", enum.TransferApproved)
+
+	transferRequestRepoMock.EXPECT().LockById(row.Id).Return(&row, nil).Times(1)
+	bulkTransferRepoMock.EXPECT().LockById(bulkTransfer.Id).Return(&bulkTransfer, nil).Times(1)
+
+	// The row is executed like a single transfer and its hold is captured
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockAccountData[1].AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id, mockAccountData[1].Id).Return([]models.Account{senderAccount, mockAccountData[1]}, nil).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId(row.Id, enum.HoldCaptured, "", gomock.Any()).Return(nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(senderAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(senderAccount.OwnerId, senderAccount.AccountNumber).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.FeeIncomeAccountCode, money.DefaultCurrency).Return(&mockCashAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, money.MustParse("-20"), journal.Postings[0].Amount)
+		assert.Equal(t, mockAccountData[1].Id, journal.Postings[1].AccountId)
+		assert.Equal(t, money.MustParse("20"), journal.Postings[1].Amount)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b43"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, enum.TransferExecuted, transfer.Status)
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b43", transfer.JournalId)
+		return nil
+	}).Times(1)
+
+	// The receiver is told through the outbox, the bulk transfer goes on with its other rows
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Equal(t, mockAccountData[1].Owner.Email, notification.To)
+		assert.Equal(t, "TEK Bank - Transfer Received", notification.Subject)
+		return nil
+	}).Times(1)
+	transferRequestRepoMock.EXPECT().CountBulkRows(bulkTransfer.Id, enum.TransferApproved).Return(int64(1), nil).Times(1)
+
+	err := bulkTransferService.ExecuteBulkTransferRow(row.Id, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *rollbacks)
+}
+
+func TestBulkTransferService_ExecuteBulkTransferRow_Rejected(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	bulkTransferService, rollbacks := withBulkTestTx(t)
+
+	senderAccount := mockAccountData[0]
+	senderAccount.Balance = money.MustParse("10")
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailLink, time.Now())
+	bulkTransfer.Status = enum.BulkTransferProcessing
+	row := mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b42", "30", enum.TransferApproved)
+
+	transferRequestRepoMock.EXPECT().LockById(row.Id).Return(&row, nil).Times(1)
+	bulkTransferRepoMock.EXPECT().LockById(bulkTransfer.Id).Return(&bulkTransfer, nil).Times(1)
+
+	// The balance is spent since the approval, the savepoint of the row is rolled back
+	accountRepoMock.EXPECT().FindByAccountNumber(senderAccount.AccountNumber).Return(&senderAccount, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(mockAccountData[1].AccountNumber).Return(&mockAccountData[1], nil).Times(1)
+	accountRepoMock.EXPECT().Lock(senderAccount.Id, mockAccountData[1].Id).Return([]models.Account{senderAccount, mockAccountData[1]}, nil).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId(row.Id, enum.HoldCaptured, "", gomock.Any()).Return(nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{senderAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+
+	// The row is rejected with the reason and its money is released
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, enum.TransferRejected, transfer.Status)
+		assert.Equal(t, messages.InSufficientBalance, transfer.FailureReason)
+		return nil
+	}).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId(row.Id, enum.HoldReleased, messages.InSufficientBalance, gomock.Any()).Return(nil).Times(1)
+
+	// It is the last row, the bulk transfer is completed with the results of its rows
+	transferRequestRepoMock.EXPECT().CountBulkRows(bulkTransfer.Id, enum.TransferApproved).Return(int64(0), nil).Times(1)
+	rejectedRow := row
+	rejectedRow.Status = enum.TransferRejected
+	transferRequestRepoMock.EXPECT().FindByBulkTransferId(bulkTransfer.Id).Return([]models.Transfer{
+		mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", "20", enum.TransferExecuted),
+		rejectedRow,
+	}, nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferCompleted, bulkTransfer.Status)
+		assert.NotNil(t, bulkTransfer.CompletedAt)
+		assert.Equal(t, 1, bulkTransfer.ExecutedCount)
+		assert.Equal(t, 1, bulkTransfer.FailedCount)
+		return nil
+	}).Times(1)
+	userRepoMock.EXPECT().FindByID(bulkTransfer.OwnerId).Return(&mockData[0], nil).Times(1)
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Equal(t, mockData[0].Email, notification.To)
+		assert.Equal(t, i18n.CreateMsgWithLanguage("", messages.BulkTransferReportMailBody, map[string]string{
+			"FileName":      "payroll.csv",
+			"AccountNumber": "1000000001",
+			"Rows":          "2",
+			"Executed":      "1",
+			"Failed":        "1",
+			"Amount":        money.New(money.MustParse("20"), money.DefaultCurrency).String(),
+		}), notification.Body)
+		return nil
+	}).Times(1)
+
+	err := bulkTransferService.ExecuteBulkTransferRow(row.Id, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *rollbacks)
+}
+
+func TestBulkTransferService_ExpireBulkTransfers(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	bulkTransferService := NewBulkTransferService(bulkTransferRepoMock, accountRepoMock, userRepoMock, transferRequestRepoMock, transferLimitRepoMock, holdRepoMock, notificationRepoMock, s, pkgSMSMock)
+
+	now := time.Now()
+	bulkTransfer := mockBulkTransfer(enum.ApprovalMethodEmailLink, now.Add(-time.Minute))
+	cancelledRow := mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b42", "30", enum.TransferCancelled)
+
+	// Only the pending rows end with the bulk transfer
+	bulkTransferRepoMock.EXPECT().LockExpired(now, 10).Return([]models.BulkTransfer{bulkTransfer}, nil).Times(1)
+	transferRequestRepoMock.EXPECT().FindByBulkTransferId(bulkTransfer.Id).Return([]models.Transfer{
+		mockBulkTransferRow("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", "20", enum.TransferPendingApproval),
+		cancelledRow,
+	}, nil).Times(1)
+	transferRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(transfer models.Transfer) error {
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", transfer.Id)
+		assert.Equal(t, enum.TransferExpired, transfer.Status)
+		return nil
+	}).Times(1)
+	holdRepoMock.EXPECT().ReleaseByTransferId("e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", enum.HoldExpired, gomock.Any(), now).Return(nil).Times(1)
+	bulkTransferRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(bulkTransfer models.BulkTransfer) error {
+		assert.Equal(t, enum.BulkTransferExpired, bulkTransfer.Status)
+		assert.Equal(t, &now, bulkTransfer.ExpiredAt)
+		return nil
+	}).Times(1)
+
+	count, err := bulkTransferService.ExpireBulkTransfers(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
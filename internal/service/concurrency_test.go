@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"tek-bank/internal/db/connection"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/mocks/crypto"
+	"tek-bank/mocks/gomailer"
+	"tek-bank/mocks/sms"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+// setupDatabaseTest connects to the PostgreSQL database given with the TEST_DB_* variables,
+// the races of the services can not be tested against mocks
+func setupDatabaseTest(t *testing.T) *gorm.DB {
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST is not set, this test needs a PostgreSQL database")
+	}
+
+	db := connection.PostgresSQLConnection(connection.DatabaseConfig{
+		Host:     os.Getenv("TEST_DB_HOST"),
+		Username: os.Getenv("TEST_DB_USER"),
+		Password: os.Getenv("TEST_DB_PASSWORD"),
+		DBName:   os.Getenv("TEST_DB_NAME"),
+		Port:     os.Getenv("TEST_DB_PORT"),
+		AppName:  "tekbank-test",
+		SSLMode:  "disable",
+		Timezone: "UTC",
+	})
+	require.NotNil(t, db)
+
+	return db
+}
+
+// newDatabaseAccountService returns the account service on the real repositories, only the mails,
+// the messages and the random numbers are mocked
+func newDatabaseAccountService(t *testing.T, db *gorm.DB) AccountService {
+	ct := gomock.NewController(t)
+
+	pkgMailer := gomailer.NewMockMailer(ct)
+	pkgMailer.EXPECT().Send(gomock.Any()).Return(nil).AnyTimes()
+
+	return NewAccountService(
+		repository.NewAccountRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewTransferHistoryRepository(db),
+		repository.NewLedgerRepository(db),
+		repository.NewExchangeRateRepository(db),
+		repository.NewTransferRepository(db),
+		repository.NewTransferLimitRepository(db),
+		repository.NewFeeScheduleRepository(db),
+		repository.NewAccountEventRepository(db),
+		repository.NewHoldRepository(db),
+		repository.NewInterestRepository(db),
+		repository.NewBeneficiaryRepository(db),
+		repository.NewNotificationRepository(db),
+		crypto.NewMockCrypto(ct),
+		pkgMailer,
+		sms.NewMockSender(ct),
+	)
+}
+
+// createDatabaseAccounts creates a customer with accounts of the balances, they are deleted with their money movements
+// at the end of the test
+func createDatabaseAccounts(t *testing.T, db *gorm.DB, balances ...money.Amount) []models.Account {
+	seed := rand.Int63n(1e9)
+
+	owner := models.User{
+		IdentityNumber: 8e10 + seed,
+		CustomerNumber: 8e10 + seed,
+		FirstName:      "Concurrency",
+		LastName:       "Test",
+		Email:          fmt.Sprintf("service-concurrency-%d@company.com", seed),
+		PhoneNumber:    uint64(8e10 + seed),
+		Password:       "!",
+	}
+	require.NoError(t, db.Create(&owner).Error)
+
+	var accounts []models.Account
+	for i, balance := range balances {
+		account := models.Account{
+			OwnerId:       owner.Id,
+			IBAN:          fmt.Sprintf("TEST-SERVICE-%d-%d", seed, i),
+			AccountNumber: 8e11 + seed*10 + int64(i),
+			Balance:       balance,
+			Currency:      money.DefaultCurrency,
+			IsActive:      true,
+			CreatedBy:     owner.Id,
+			UpdatedBy:     owner.Id,
+		}
+		require.NoError(t, db.Create(&account).Error)
+		accounts = append(accounts, account)
+	}
+
+	t.Cleanup(func() {
+		var ids []string
+		var numbers []int64
+		for _, account := range accounts {
+			ids = append(ids, account.Id)
+			numbers = append(numbers, account.AccountNumber)
+		}
+
+		db.Exec("DELETE FROM public.transfer_history WHERE \"from\" IN ? OR \"to\" IN ?", numbers, numbers)
+		db.Exec("DELETE FROM public.journals WHERE id IN (SELECT journal_id FROM public.postings WHERE account_id IN ?)", ids)
+		db.Exec("DELETE FROM public.transfers WHERE from_account_number IN ?", numbers)
+		db.Exec("DELETE FROM public.transfer_limits WHERE scope_id = ?", owner.Id)
+		db.Exec("DELETE FROM public.accounts WHERE id IN ?", ids)
+		db.Exec("DELETE FROM public.users WHERE id = ?", owner.Id)
+	})
+
+	return accounts
+}
+
+// createPendingTransfer creates a transfer waiting for its approval link without a hold, like a transfer
+// requested before the balance of the sender went down
+func createPendingTransfer(t *testing.T, db *gorm.DB, from, to models.Account, amount money.Amount, token string) {
+	expiresAt := time.Now().Add(enum.TransferApprovalTTL)
+	transfer := models.Transfer{
+		OwnerId:           from.OwnerId,
+		FromAccountNumber: from.AccountNumber,
+		ToAccountNumber:   to.AccountNumber,
+		Amount:            amount,
+		Currency:          from.Currency,
+		ConvertedAmount:   amount,
+		ConvertedCurrency: to.Currency,
+		ExchangeRate:      money.OneRate,
+		BaseAmount:        amount,
+		Status:            enum.TransferPendingApproval,
+		ApprovalMethod:    enum.ApprovalMethodEmailLink,
+		TokenHash:         hashToken(token),
+		ExpiresAt:         &expiresAt,
+		CreatedBy:         from.OwnerId,
+		UpdatedBy:         from.OwnerId,
+	}
+	require.NoError(t, db.Create(&transfer).Error)
+}
+
+// assertBalanceMatchesPostings checks the balance of the account against its initial balance and its postings
+func assertBalanceMatchesPostings(t *testing.T, db *gorm.DB, account models.Account) models.Account {
+	var reloaded models.Account
+	require.NoError(t, db.Where("id = ?", account.Id).First(&reloaded).Error)
+
+	var postings money.Amount
+	require.NoError(t, db.Model(&models.Posting{}).Select("COALESCE(SUM(amount), 0)").Where("account_id = ?", account.Id).Row().Scan(&postings))
+	assert.Equal(t, account.Balance+postings, reloaded.Balance)
+
+	return reloaded
+}
+
+func TestAccountService_ConcurrentApprovalsDoNotOverdraw(t *testing.T) {
+	db := setupDatabaseTest(t)
+	accountService := newDatabaseAccountService(t, db)
+
+	accounts := createDatabaseAccounts(t, db, money.MustParse("1000"), money.Zero)
+	sender, receiver := accounts[0], accounts[1]
+
+	// Twice the balance of the sender is waiting for the approval
+	const transfers = 20
+	amount := money.MustParse("100")
+	for i := 0; i < transfers; i++ {
+		createPendingTransfer(t, db, sender, receiver, amount, fmt.Sprintf("%s-%d", sender.IBAN, i))
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded, rejected int
+	var failures []error
+
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+
+			// Every approval reads the available balance and posts in its own request transaction
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return accountService.WithTx(tx).TransferApproval(context.Background(), token)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case err.Error() == messages.InSufficientBalance:
+				rejected++
+			default:
+				failures = append(failures, err)
+			}
+		}(fmt.Sprintf("%s-%d", sender.IBAN, i))
+	}
+
+	wg.Wait()
+
+	// Deadlocks or serialization errors would end up here
+	assert.Empty(t, failures)
+	assert.Equal(t, 10, succeeded)
+	assert.Equal(t, transfers-10, rejected)
+
+	reloadedSender := assertBalanceMatchesPostings(t, db, sender)
+	reloadedReceiver := assertBalanceMatchesPostings(t, db, receiver)
+	assert.True(t, reloadedSender.Balance.IsZero())
+	assert.Equal(t, amount*money.Amount(succeeded), reloadedReceiver.Balance)
+
+	var executed int64
+	require.NoError(t, db.Model(&models.Transfer{}).Where("from_account_number = ? AND status = ?", sender.AccountNumber, enum.TransferExecuted).Count(&executed).Error)
+	assert.Equal(t, int64(succeeded), executed)
+}
+
+func TestAccountService_ConcurrentApprovalsKeepCustomerLimit(t *testing.T) {
+	db := setupDatabaseTest(t)
+	accountService := newDatabaseAccountService(t, db)
+
+	accounts := createDatabaseAccounts(t, db, money.MustParse("1000"), money.MustParse("1000"))
+	receiver := createDatabaseAccounts(t, db, money.Zero)[0]
+
+	// The customer limit counts the transfers from both accounts, only one transfer fits into it
+	limit := models.TransferLimit{
+		Level:   enum.LimitLevelCustomer,
+		ScopeId: accounts[0].OwnerId,
+		Period:  enum.LimitPeriodDaily,
+		Amount:  money.MustParse("150"),
+	}
+	require.NoError(t, db.Create(&limit).Error)
+
+	const transfers = 10
+	amount := money.MustParse("100")
+	for i := 0; i < transfers; i++ {
+		createPendingTransfer(t, db, accounts[i%2], receiver, amount, fmt.Sprintf("%s-limit-%d", accounts[0].IBAN, i))
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded, exceeded int
+	var failures []error
+
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return accountService.WithTx(tx).TransferApproval(context.Background(), token)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case err.Error() == messages.TransferDailyLimitExceeded:
+				exceeded++
+			default:
+				failures = append(failures, err)
+			}
+		}(fmt.Sprintf("%s-limit-%d", accounts[0].IBAN, i))
+	}
+
+	wg.Wait()
+
+	assert.Empty(t, failures)
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, transfers-1, exceeded)
+
+	reloadedReceiver := assertBalanceMatchesPostings(t, db, receiver)
+	assert.Equal(t, amount, reloadedReceiver.Balance)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"os"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/money"
+)
+
+type ExchangeService interface {
+	GetRates(ctx context.Context) (*dto.GetExchangeRatesResponse, error)
+	UpdateRates(ctx context.Context, request dto.UpdateExchangeRatesRequest) error
+	LoadFromFile(path string) error
+
+	WithTx(trxHandle *gorm.DB) ExchangeService
+}
+
+type exchangeService struct {
+	exchangeRateRepository repository.ExchangeRateRepository
+}
+
+func NewExchangeService(exchangeRateRepository repository.ExchangeRateRepository) ExchangeService {
+	return &exchangeService{
+		exchangeRateRepository: exchangeRateRepository,
+	}
+}
+
+func (s *exchangeService) WithTx(trxHandle *gorm.DB) ExchangeService {
+	clone := *s
+	clone.exchangeRateRepository = s.exchangeRateRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// GetRates returns the rates of the bank, every rate is the value of one unit in the base currency
+func (s *exchangeService) GetRates(ctx context.Context) (*dto.GetExchangeRatesResponse, error) {
+	rates, err := s.exchangeRateRepository.FindAll()
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetExchangeRatesResponse{
+		BaseCurrency: money.DefaultCurrency.String(),
+		Rates:        []dto.ExchangeRateItem{},
+	}
+
+	for _, rate := range rates {
+		response.Rates = append(response.Rates, dto.ExchangeRateItem{
+			Currency: rate.Currency.String(),
+			Rate:     rate.Rate,
+		})
+	}
+
+	return response, nil
+}
+
+func (s *exchangeService) UpdateRates(ctx context.Context, request dto.UpdateExchangeRatesRequest) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	return s.saveRates(request.Rates, currentUser.Id)
+}
+
+// LoadFromFile loads the rates from a JSON file in the same format as the update request,
+// so the bank can work with its own rates without any connection to a rate provider
+func (s *exchangeService) LoadFromFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var request dto.UpdateExchangeRatesRequest
+	if err := json.Unmarshal(content, &request); err != nil {
+		return err
+	}
+
+	err = s.saveRates(request.Rates, "")
+	if err != nil {
+		return errors.New(messages.InvalidExchangeRate)
+	}
+
+	return nil
+}
+
+func (s *exchangeService) saveRates(items []dto.ExchangeRateItem, updatedBy string) error {
+	if len(items) == 0 {
+		return errors.New(messages.InvalidExchangeRate)
+	}
+
+	var rates []models.ExchangeRate
+	for _, item := range items {
+		currency, err := money.ParseCurrency(item.Currency)
+		if err != nil || currency == money.DefaultCurrency || item.Rate.IsZero() {
+			return errors.New(messages.InvalidExchangeRate)
+		}
+
+		rates = append(rates, models.ExchangeRate{
+			Currency:  currency,
+			Rate:      item.Rate,
+			UpdatedBy: updatedBy,
+		})
+	}
+
+	err := s.exchangeRateRepository.Upsert(rates)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
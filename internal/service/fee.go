@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"sort"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// maxRevenueDays is the longest period the fee revenue can be reported for at once
+const maxRevenueDays = 366
+
+// selectFeeSchedule returns the most specific of the matching schedules,
+// a schedule of the segment wins over a schedule of the account type
+func selectFeeSchedule(schedules []models.FeeSchedule) *models.FeeSchedule {
+	var selected *models.FeeSchedule
+	best := -1
+	for i := range schedules {
+		score := 0
+		if schedules[i].Segment != "" {
+			score += 2
+		}
+		if schedules[i].AccountType != "" {
+			score++
+		}
+		if score > best {
+			selected = &schedules[i]
+			best = score
+		}
+	}
+	return selected
+}
+
+// calculateFee returns the fee of the amount with the schedule and the rule it was calculated with,
+// the amount and the fee are in the base currency
+func calculateFee(schedule models.FeeSchedule, amount money.Amount) (money.Amount, string) {
+	var fee money.Amount
+	var rule string
+
+	switch schedule.Type {
+	case enum.FeeTypeFlat:
+		fee = schedule.FlatFee
+		rule = describeFee(schedule.FlatFee, money.Rate{})
+	case enum.FeeTypePercentage:
+		fee = amount.Mul(schedule.Rate.Rat(), money.HalfEven)
+		rule = describeFee(money.Zero, schedule.Rate)
+	case enum.FeeTypeTiered:
+		if len(schedule.Tiers) == 0 {
+			break
+		}
+		// The tiers are ordered by their upper bounds, the last one is used for amounts above all of them
+		tier := schedule.Tiers[len(schedule.Tiers)-1]
+		for _, t := range schedule.Tiers {
+			if t.UpTo.IsZero() || amount <= t.UpTo {
+				tier = t
+				break
+			}
+		}
+		fee = tier.FlatFee + amount.Mul(tier.Rate.Rat(), money.HalfEven)
+		rule = describeFee(tier.FlatFee, tier.Rate)
+		if tier.UpTo.IsPositive() {
+			rule += " for amounts up to " + money.New(tier.UpTo, money.DefaultCurrency).String()
+		}
+	}
+
+	var bounds []string
+	if schedule.MinFee.IsPositive() {
+		bounds = append(bounds, "min "+money.New(schedule.MinFee, money.DefaultCurrency).String())
+		if fee < schedule.MinFee {
+			fee = schedule.MinFee
+		}
+	}
+	if schedule.MaxFee.IsPositive() {
+		bounds = append(bounds, "max "+money.New(schedule.MaxFee, money.DefaultCurrency).String())
+		if fee > schedule.MaxFee {
+			fee = schedule.MaxFee
+		}
+	}
+	if len(bounds) > 0 {
+		rule += " (" + strings.Join(bounds, ", ") + ")"
+	}
+
+	return fee, schedule.Name + ": " + rule
+}
+
+// describeFee writes a flat fee and a rate of the amount as a text like "1.00 TRY + 0.1% of the amount"
+func describeFee(flatFee money.Amount, rate money.Rate) string {
+	var parts []string
+	if flatFee.IsPositive() || rate.IsZero() {
+		parts = append(parts, money.New(flatFee, money.DefaultCurrency).String())
+	}
+	if !rate.IsZero() {
+		percent := money.NewRate(new(big.Rat).Mul(rate.Rat(), big.NewRat(100, 1)))
+		parts = append(parts, percent.String()+"% of the amount")
+	}
+	return strings.Join(parts, " + ")
+}
+
+// ownAccountsFeeRule is the rule of a free transfer between the accounts of the same owner
+func ownAccountsFeeRule(schedule models.FeeSchedule) string {
+	return schedule.Name + ": free between own accounts"
+}
+
+// isValidFeeSchedule checks the scope, the type and the amounts of a schedule, a tiered schedule needs
+// tiers with rising upper bounds ending with an unbounded tier
+func isValidFeeSchedule(item dto.FeeScheduleItem) bool {
+	if strings.TrimSpace(item.Name) == "" || !isValidSegment(item.Segment, true) {
+		return false
+	}
+
+	if item.AccountType != "" && !isValidAccountType(item.AccountType) {
+		return false
+	}
+
+	if item.FlatFee.IsNegative() || item.MinFee.IsNegative() || item.MaxFee.IsNegative() || item.Rate.Rat().Sign() < 0 {
+		return false
+	}
+
+	if item.MinFee.IsPositive() && item.MaxFee.IsPositive() && item.MinFee > item.MaxFee {
+		return false
+	}
+
+	switch item.Type {
+	case enum.FeeTypeFlat:
+		return len(item.Tiers) == 0
+	case enum.FeeTypePercentage:
+		return !item.Rate.IsZero() && len(item.Tiers) == 0
+	case enum.FeeTypeTiered:
+		if len(item.Tiers) == 0 {
+			return false
+		}
+		for i, tier := range item.Tiers {
+			last := i == len(item.Tiers)-1
+			if tier.FlatFee.IsNegative() || tier.Rate.Rat().Sign() < 0 || tier.UpTo.IsNegative() {
+				return false
+			}
+			if last != tier.UpTo.IsZero() || (i > 0 && !last && tier.UpTo <= item.Tiers[i-1].UpTo) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// isValidSegment reports whether the segment is known, fee schedules may leave it empty to match all segments
+func isValidSegment(segment string, allowEmpty bool) bool {
+	switch segment {
+	case enum.SegmentRetail, enum.SegmentPremium, enum.SegmentBusiness:
+		return true
+	case "":
+		return allowEmpty
+	}
+	return false
+}
+
+func feeScheduleItem(schedule models.FeeSchedule) dto.FeeScheduleItem {
+	item := dto.FeeScheduleItem{
+		Id:              schedule.Id,
+		Name:            schedule.Name,
+		Segment:         schedule.Segment,
+		AccountType:     schedule.AccountType,
+		Type:            schedule.Type,
+		FlatFee:         schedule.FlatFee,
+		Rate:            schedule.Rate,
+		MinFee:          schedule.MinFee,
+		MaxFee:          schedule.MaxFee,
+		FreeOwnAccounts: schedule.FreeOwnAccounts,
+		Tiers:           []dto.FeeTierItem{},
+	}
+
+	for _, tier := range schedule.Tiers {
+		item.Tiers = append(item.Tiers, dto.FeeTierItem{
+			UpTo:    tier.UpTo,
+			FlatFee: tier.FlatFee,
+			Rate:    tier.Rate,
+		})
+	}
+
+	return item
+}
+
+type FeeService interface {
+	GetSchedules(ctx context.Context) (*dto.GetFeeSchedulesResponse, error)
+	UpdateSchedule(ctx context.Context, request dto.FeeScheduleItem) (*dto.FeeScheduleItem, error)
+	DeleteSchedule(ctx context.Context, id string) error
+	UpdateSegment(ctx context.Context, request dto.UpdateSegmentRequest) error
+	GetRevenue(ctx context.Context, request dto.GetFeeRevenueRequest) (*dto.GetFeeRevenueResponse, error)
+
+	WithTx(trxHandle *gorm.DB) FeeService
+}
+
+type feeService struct {
+	feeScheduleRepository repository.FeeScheduleRepository
+	userRepository        repository.UserRepository
+	ledgerRepository      repository.LedgerRepository
+}
+
+func NewFeeService(
+	feeScheduleRepository repository.FeeScheduleRepository,
+	userRepository repository.UserRepository,
+	ledgerRepository repository.LedgerRepository,
+) FeeService {
+	return &feeService{
+		feeScheduleRepository: feeScheduleRepository,
+		userRepository:        userRepository,
+		ledgerRepository:      ledgerRepository,
+	}
+}
+
+func (s *feeService) WithTx(trxHandle *gorm.DB) FeeService {
+	clone := *s
+	clone.feeScheduleRepository = s.feeScheduleRepository.WithTx(trxHandle)
+	clone.userRepository = s.userRepository.WithTx(trxHandle)
+	clone.ledgerRepository = s.ledgerRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// GetSchedules returns all fee schedules of the bank
+func (s *feeService) GetSchedules(ctx context.Context) (*dto.GetFeeSchedulesResponse, error) {
+	schedules, err := s.feeScheduleRepository.FindAll()
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetFeeSchedulesResponse{
+		BaseCurrency: money.DefaultCurrency.String(),
+		Schedules:    []dto.FeeScheduleItem{},
+	}
+
+	for _, schedule := range schedules {
+		response.Schedules = append(response.Schedules, feeScheduleItem(schedule))
+	}
+
+	return response, nil
+}
+
+// UpdateSchedule creates the schedule or overwrites the schedule of the same segment and account type
+func (s *feeService) UpdateSchedule(ctx context.Context, request dto.FeeScheduleItem) (*dto.FeeScheduleItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if !isValidFeeSchedule(request) {
+		return nil, errors.New(messages.InvalidFeeSchedule)
+	}
+
+	schedule := models.FeeSchedule{
+		Name:            strings.TrimSpace(request.Name),
+		Segment:         request.Segment,
+		AccountType:     request.AccountType,
+		Type:            request.Type,
+		FlatFee:         request.FlatFee,
+		Rate:            request.Rate,
+		MinFee:          request.MinFee,
+		MaxFee:          request.MaxFee,
+		FreeOwnAccounts: request.FreeOwnAccounts,
+		UpdatedBy:       currentUser.Id,
+	}
+
+	for _, tier := range request.Tiers {
+		schedule.Tiers = append(schedule.Tiers, models.FeeTier{
+			UpTo:    tier.UpTo,
+			FlatFee: tier.FlatFee,
+			Rate:    tier.Rate,
+		})
+	}
+
+	saved, err := s.feeScheduleRepository.Upsert(schedule)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := feeScheduleItem(*saved)
+	return &item, nil
+}
+
+// DeleteSchedule removes a schedule, its transfers fall back to a less specific schedule.
+// The default schedule matching all segments and account types can not be removed.
+func (s *feeService) DeleteSchedule(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.New(messages.FeeScheduleNotFound)
+	}
+
+	schedule, err := s.feeScheduleRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.FeeScheduleNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	if schedule.Segment == "" && schedule.AccountType == "" {
+		return errors.New(messages.DefaultFeeScheduleRequired)
+	}
+
+	err = s.feeScheduleRepository.Delete(id)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// UpdateSegment moves a customer to another segment, the next transfers of the customer are charged with its schedules
+func (s *feeService) UpdateSegment(ctx context.Context, request dto.UpdateSegmentRequest) error {
+	if !isValidSegment(request.Segment, false) {
+		return errors.New(messages.InvalidSegment)
+	}
+
+	if _, err := uuid.Parse(request.UserId); err != nil {
+		return errors.New(messages.UserNotFound)
+	}
+
+	err := s.userRepository.UpdateSegment(request.UserId, request.Segment)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.UserNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// GetRevenue returns the fees collected in the fee income accounts per day and currency.
+// The days are inclusive, the last 30 days are reported without them.
+func (s *feeService) GetRevenue(ctx context.Context, request dto.GetFeeRevenueRequest) (*dto.GetFeeRevenueResponse, error) {
+	to := calendar.StartOfDay(time.Now())
+	from := to.AddDate(0, 0, -29)
+
+	var err error
+	if request.To != "" {
+		to, err = time.ParseInLocation(dateLayout, request.To, time.Local)
+		if err != nil {
+			return nil, errors.New(messages.InvalidDateRange)
+		}
+		from = to.AddDate(0, 0, -29)
+	}
+	if request.From != "" {
+		from, err = time.ParseInLocation(dateLayout, request.From, time.Local)
+		if err != nil {
+			return nil, errors.New(messages.InvalidDateRange)
+		}
+	}
+
+	if from.After(to) || to.Sub(from) >= maxRevenueDays*24*time.Hour {
+		return nil, errors.New(messages.InvalidDateRange)
+	}
+
+	totals, err := s.ledgerRepository.SumInternalByDay(enum.FeeIncomeAccountCode, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetFeeRevenueResponse{
+		From:   from.Format(dateLayout),
+		To:     to.Format(dateLayout),
+		Days:   []dto.FeeRevenueItem{},
+		Totals: []dto.FeeRevenueTotal{},
+	}
+
+	sums := make(map[money.Currency]money.Amount)
+	for _, total := range totals {
+		response.Days = append(response.Days, dto.FeeRevenueItem{
+			Date:     total.Day.Format(dateLayout),
+			Currency: total.Currency.String(),
+			Amount:   total.Amount,
+		})
+		sums[total.Currency] += total.Amount
+	}
+
+	for currency, amount := range sums {
+		response.Totals = append(response.Totals, dto.FeeRevenueTotal{
+			Currency: currency.String(),
+			Amount:   amount,
+		})
+	}
+	sort.Slice(response.Totals, func(i, j int) bool {
+		return response.Totals[i].Currency < response.Totals[j].Currency
+	})
+
+	return response, nil
+}
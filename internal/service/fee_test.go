@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	repositoryMocks "tek-bank/internal/mocks/repository"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+func TestSelectFeeSchedule(t *testing.T) {
+	schedules := []models.FeeSchedule{
+		{Name: "Standard"},
+		{Name: "Current", AccountType: enum.AccountTypeCurrent},
+		{Name: "Premium", Segment: enum.SegmentPremium},
+	}
+
+	// The segment wins over the account type
+	assert.Equal(t, "Premium", selectFeeSchedule(schedules).Name)
+	assert.Equal(t, "Current", selectFeeSchedule(schedules[:2]).Name)
+	assert.Nil(t, selectFeeSchedule(nil))
+}
+
+func TestCalculateFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.FeeSchedule
+		amount   string
+		fee      string
+		rule     string
+	}{
+		{
+			name:     "flat",
+			schedule: models.FeeSchedule{Name: "Standard", Type: enum.FeeTypeFlat, FlatFee: money.MustParse("4.22")},
+			amount:   "1000",
+			fee:      "4.22",
+			rule:     "Standard: 4.22 TRY",
+		},
+		{
+			name:     "percentage below the minimum",
+			schedule: models.FeeSchedule{Name: "Premium", Type: enum.FeeTypePercentage, Rate: money.MustParseRate("0.001"), MinFee: money.MustParse("2"), MaxFee: money.MustParse("50")},
+			amount:   "1000",
+			fee:      "2.00",
+			rule:     "Premium: 0.1% of the amount (min 2.00 TRY, max 50.00 TRY)",
+		},
+		{
+			name:     "percentage above the maximum",
+			schedule: models.FeeSchedule{Name: "Premium", Type: enum.FeeTypePercentage, Rate: money.MustParseRate("0.001"), MaxFee: money.MustParse("50")},
+			amount:   "100000",
+			fee:      "50.00",
+			rule:     "Premium: 0.1% of the amount (max 50.00 TRY)",
+		},
+		{
+			name: "tiered",
+			schedule: models.FeeSchedule{Name: "Business", Type: enum.FeeTypeTiered, Tiers: []models.FeeTier{
+				{UpTo: money.MustParse("1000"), FlatFee: money.MustParse("1")},
+				{UpTo: money.MustParse("10000"), FlatFee: money.MustParse("2"), Rate: money.MustParseRate("0.0005")},
+				{FlatFee: money.MustParse("10")},
+			}},
+			amount: "5000",
+			fee:    "4.50",
+			rule:   "Business: 2.00 TRY + 0.05% of the amount for amounts up to 10000.00 TRY",
+		},
+		{
+			name: "tiered above all bounds",
+			schedule: models.FeeSchedule{Name: "Business", Type: enum.FeeTypeTiered, Tiers: []models.FeeTier{
+				{UpTo: money.MustParse("1000"), FlatFee: money.MustParse("1")},
+				{FlatFee: money.MustParse("10")},
+			}},
+			amount: "5000",
+			fee:    "10.00",
+			rule:   "Business: 10.00 TRY",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fee, rule := calculateFee(test.schedule, money.MustParse(test.amount))
+			assert.Equal(t, test.fee, fee.String())
+			assert.Equal(t, test.rule, rule)
+		})
+	}
+}
+
+func TestIsValidFeeSchedule(t *testing.T) {
+	tiered := dto.FeeScheduleItem{Name: "Business", Segment: enum.SegmentBusiness, Type: enum.FeeTypeTiered, Tiers: []dto.FeeTierItem{
+		{UpTo: money.MustParse("1000"), FlatFee: money.MustParse("1")},
+		{FlatFee: money.MustParse("10")},
+	}}
+	assert.True(t, isValidFeeSchedule(tiered))
+
+	// The last tier has to be unbounded
+	tiered.Tiers = tiered.Tiers[:1]
+	assert.False(t, isValidFeeSchedule(tiered))
+
+	assert.False(t, isValidFeeSchedule(dto.FeeScheduleItem{Name: "Premium", Type: enum.FeeTypePercentage}))
+	assert.False(t, isValidFeeSchedule(dto.FeeScheduleItem{Name: "Flat", Type: enum.FeeTypeFlat, MinFee: money.MustParse("5"), MaxFee: money.MustParse("1")}))
+	assert.False(t, isValidFeeSchedule(dto.FeeScheduleItem{Name: "Flat", Segment: "vip", Type: enum.FeeTypeFlat}))
+}
+
+func TestFeeService_GetRevenue(t *testing.T) {
+	ct := gomock.NewController(t)
+	ledgerRepository := repositoryMocks.NewMockLedgerRepository(ct)
+	feeService := NewFeeService(nil, nil, ledgerRepository)
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, time.March, 3, 0, 0, 0, 0, time.Local)
+
+	// The last day is included
+	ledgerRepository.EXPECT().SumInternalByDay(enum.FeeIncomeAccountCode, from, to.AddDate(0, 0, 1)).Return([]repository.DailyTotal{
+		{Day: from, Currency: "TRY", Amount: money.MustParse("8.44")},
+		{Day: from, Currency: "USD", Amount: money.MustParse("0.13")},
+		{Day: to, Currency: "TRY", Amount: money.MustParse("4.22")},
+	}, nil).Times(1)
+
+	response, err := feeService.GetRevenue(context.Background(), dto.GetFeeRevenueRequest{From: "2024-03-01", To: "2024-03-03"})
+	assert.NoError(t, err)
+	assert.Len(t, response.Days, 3)
+	assert.Equal(t, []dto.FeeRevenueTotal{
+		{Currency: "TRY", Amount: money.MustParse("12.66")},
+		{Currency: "USD", Amount: money.MustParse("0.13")},
+	}, response.Totals)
+
+	_, err = feeService.GetRevenue(context.Background(), dto.GetFeeRevenueRequest{From: "2024-03-04", To: "2024-03-03"})
+	assert.EqualError(t, err, messages.InvalidDateRange)
+}
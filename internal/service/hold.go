@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// isHoldActive reports whether the hold still reduces the available balance, expired holds may not be marked yet
+func isHoldActive(hold models.Hold, now time.Time) bool {
+	return hold.Status == enum.HoldActive && (hold.ExpiresAt == nil || now.Before(*hold.ExpiresAt))
+}
+
+func holdItem(hold models.Hold, now time.Time) dto.HoldItem {
+	status := hold.Status
+	if status == enum.HoldActive && !isHoldActive(hold, now) {
+		status = enum.HoldExpired
+	}
+
+	return dto.HoldItem{
+		Id:            hold.Id,
+		Type:          hold.Type,
+		Amount:        hold.Amount,
+		Currency:      hold.Currency.String(),
+		Reason:        hold.Reason,
+		TransferId:    hold.TransferId,
+		Status:        status,
+		ExpiresAt:     hold.ExpiresAt,
+		ReleasedAt:    hold.ReleasedAt,
+		ReleaseReason: hold.ReleaseReason,
+		CreatedAt:     hold.CreatedAt,
+	}
+}
+
+type HoldService interface {
+	Place(ctx context.Context, request dto.PlaceHoldRequest) (*dto.HoldItem, error)
+	Release(ctx context.Context, request dto.ReleaseHoldRequest) (*dto.HoldItem, error)
+	List(ctx context.Context, accountNumber int64) (*dto.GetHoldsResponse, error)
+
+	WithTx(trxHandle *gorm.DB) HoldService
+}
+
+type holdService struct {
+	holdRepository    repository.HoldRepository
+	accountRepository repository.AccountRepository
+}
+
+func NewHoldService(holdRepository repository.HoldRepository, accountRepository repository.AccountRepository) HoldService {
+	return &holdService{
+		holdRepository:    holdRepository,
+		accountRepository: accountRepository,
+	}
+}
+
+func (s *holdService) WithTx(trxHandle *gorm.DB) HoldService {
+	clone := *s
+	clone.holdRepository = s.holdRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// Place holds an amount of a customer account, like a legal block. The hold may be more than the balance,
+// the money coming in later is held then.
+func (s *holdService) Place(ctx context.Context, request dto.PlaceHoldRequest) (*dto.HoldItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	now := time.Now()
+	reason := strings.TrimSpace(request.Reason)
+	if !request.Amount.IsPositive() || reason == "" ||
+		(request.Type != enum.HoldTypeLegal && request.Type != enum.HoldTypeManual) ||
+		(request.ExpiresAt != nil && !request.ExpiresAt.After(now)) {
+		return nil, errors.New(messages.InvalidHold)
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
+	if err != nil || account.IsInternal {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	// Lock the account, so the hold is seen by the transfers and withdrawals running at the same time
+	if _, err := s.accountRepository.Lock(account.Id); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	hold, err := s.holdRepository.Create(models.Hold{
+		AccountId: account.Id,
+		Amount:    request.Amount,
+		Currency:  account.Currency,
+		Type:      request.Type,
+		Reason:    reason,
+		Status:    enum.HoldActive,
+		ExpiresAt: request.ExpiresAt,
+		CreatedBy: currentUser.Id,
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := holdItem(*hold, now)
+	return &item, nil
+}
+
+// Release ends an active hold, the held money is available again
+func (s *holdService) Release(ctx context.Context, request dto.ReleaseHoldRequest) (*dto.HoldItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return nil, errors.New(messages.AccountReasonRequired)
+	}
+
+	if _, err := uuid.Parse(request.Id); err != nil {
+		return nil, errors.New(messages.HoldNotFound)
+	}
+
+	hold, err := s.holdRepository.LockById(request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(messages.HoldNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	now := time.Now()
+	if !isHoldActive(*hold, now) {
+		return nil, errors.New(messages.HoldNotActive)
+	}
+
+	hold.Status = enum.HoldReleased
+	hold.ReleasedAt = &now
+	hold.ReleasedBy = currentUser.Id
+	hold.ReleaseReason = reason
+	if err := s.holdRepository.Update(*hold); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := holdItem(*hold, now)
+	return &item, nil
+}
+
+// List returns the holds of an account with its ledger and available balances. Customers see the holds of
+// their own accounts, admins of all customer accounts.
+func (s *holdService) List(ctx context.Context, accountNumber int64) (*dto.GetHoldsResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	if err != nil || account.IsInternal {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id && currentUser.Role != enum.RoleAdmin {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	holds, err := s.holdRepository.FindByAccountId(account.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	now := time.Now()
+	held := money.Zero
+	items := make([]dto.HoldItem, 0, len(holds))
+	for _, hold := range holds {
+		if isHoldActive(hold, now) {
+			held += hold.Amount
+		}
+		items = append(items, holdItem(hold, now))
+	}
+
+	return &dto.GetHoldsResponse{
+		AccountNumber:    account.AccountNumber,
+		Balance:          account.Balance,
+		AvailableBalance: account.Balance + account.OverdraftLimit - held,
+		HeldAmount:       held,
+		Currency:         account.Currency.String(),
+		Holds:            items,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestAccountService_Withdraw_HeldMoney(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("100")
+
+	// 80 of the 100 are held, so 30 can not be withdrawn
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{account.Id: money.MustParse("80")}, nil).Times(1)
+
+	_, err := s.Withdraw(fiberCtx.Context(), dto.WithdrawRequest{AccountNumber: account.AccountNumber, Amount: money.MustParse("30")})
+	assert.EqualError(t, err, messages.InSufficientBalance)
+}
+
+func TestHoldService_PlaceAndRelease(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id, Role: enum.RoleAdmin})
+	holdService := NewHoldService(holdRepoMock, accountRepoMock)
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("100")
+
+	// The hold may be more than the balance
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		assert.Equal(t, account.Id, hold.AccountId)
+		assert.Equal(t, enum.HoldActive, hold.Status)
+		assert.Equal(t, "Court order", hold.Reason)
+		assert.Equal(t, mockData[1].Id, hold.CreatedBy)
+		hold.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b50"
+		return &hold, nil
+	}).Times(1)
+
+	placed, err := holdService.Place(fiberCtx.Context(), dto.PlaceHoldRequest{AccountNumber: account.AccountNumber, Amount: money.MustParse("150"), Type: enum.HoldTypeLegal, Reason: " Court order "})
+	assert.NoError(t, err)
+	assert.Equal(t, enum.HoldActive, placed.Status)
+
+	// Holds of transfers are placed by the transfers only
+	_, err = holdService.Place(fiberCtx.Context(), dto.PlaceHoldRequest{AccountNumber: account.AccountNumber, Amount: money.MustParse("10"), Type: enum.HoldTypeTransfer, Reason: "Transfer"})
+	assert.EqualError(t, err, messages.InvalidHold)
+
+	hold := models.Hold{Id: placed.Id, AccountId: account.Id, Amount: placed.Amount, Type: enum.HoldTypeLegal, Status: enum.HoldActive}
+	holdRepoMock.EXPECT().LockById(hold.Id).Return(&hold, nil).Times(1)
+	holdRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(released models.Hold) error {
+		assert.Equal(t, enum.HoldReleased, released.Status)
+		assert.Equal(t, "Court order lifted", released.ReleaseReason)
+		assert.NotNil(t, released.ReleasedAt)
+		return nil
+	}).Times(1)
+
+	released, err := holdService.Release(fiberCtx.Context(), dto.ReleaseHoldRequest{Id: hold.Id, Reason: "Court order lifted"})
+	assert.NoError(t, err)
+	assert.Equal(t, enum.HoldReleased, released.Status)
+
+	// An expired hold can not be released anymore
+	expiredAt := time.Now().Add(-time.Minute)
+	expired := models.Hold{Id: hold.Id, Status: enum.HoldActive, ExpiresAt: &expiredAt}
+	holdRepoMock.EXPECT().LockById(hold.Id).Return(&expired, nil).Times(1)
+
+	_, err = holdService.Release(fiberCtx.Context(), dto.ReleaseHoldRequest{Id: hold.Id, Reason: "Again"})
+	assert.EqualError(t, err, messages.HoldNotActive)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math/big"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// isValidAccountType reports whether the type of a customer account is known
+func isValidAccountType(accountType string) bool {
+	switch accountType {
+	case enum.AccountTypeCurrent, enum.AccountTypeSavings, enum.AccountTypeTimeDeposit:
+		return true
+	}
+	return false
+}
+
+// accruedInterest returns the interest of the accruals. The exact interest of every day is summed and the sum is
+// rounded half even once, so no fraction of a cent is lost on the way.
+func accruedInterest(accruals []models.InterestAccrual) money.Amount {
+	total := new(big.Rat)
+	for _, accrual := range accruals {
+		total.Add(total, new(big.Rat).Mul(accrual.Balance.Rat(), accrual.Rate.Rat()))
+	}
+
+	total.Quo(total, big.NewRat(enum.InterestDaysInYear, 1))
+	return money.FromRat(total, money.HalfEven)
+}
+
+// startOfMonth returns midnight of the first day of the month in its location
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func interestRateItem(rate models.InterestRate) dto.InterestRateItem {
+	return dto.InterestRateItem{
+		Id:          rate.Id,
+		AccountType: rate.AccountType,
+		Currency:    rate.Currency.String(),
+		Rate:        rate.Rate,
+	}
+}
+
+type InterestService interface {
+	GetRates(ctx context.Context) (*dto.GetInterestRatesResponse, error)
+	UpdateRate(ctx context.Context, request dto.InterestRateItem) (*dto.InterestRateItem, error)
+	DeleteRate(ctx context.Context, id string) error
+	Accrue(now time.Time) (int64, error)
+	FindDue(now time.Time, limit int) ([]string, error)
+	FindOverdrawn(now time.Time, limit int) ([]repository.OverdrawnDay, error)
+
+	WithTx(trxHandle *gorm.DB) InterestService
+}
+
+type interestService struct {
+	interestRepository repository.InterestRepository
+}
+
+func NewInterestService(interestRepository repository.InterestRepository) InterestService {
+	return &interestService{
+		interestRepository: interestRepository,
+	}
+}
+
+func (s *interestService) WithTx(trxHandle *gorm.DB) InterestService {
+	clone := *s
+	clone.interestRepository = s.interestRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// GetRates returns the yearly interest rates of the account types
+func (s *interestService) GetRates(ctx context.Context) (*dto.GetInterestRatesResponse, error) {
+	rates, err := s.interestRepository.FindRates()
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetInterestRatesResponse{
+		DaysInYear: enum.InterestDaysInYear,
+		Rates:      []dto.InterestRateItem{},
+	}
+
+	for _, rate := range rates {
+		response.Rates = append(response.Rates, interestRateItem(rate))
+	}
+
+	return response, nil
+}
+
+// UpdateRate creates the rate or overwrites the rate of the same account type and currency. The new rate is used
+// from the day it is set on, the days before it keep the rate in force then.
+func (s *interestService) UpdateRate(ctx context.Context, request dto.InterestRateItem) (*dto.InterestRateItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if !isValidAccountType(request.AccountType) || request.Rate.IsZero() || request.Rate.Rat().Sign() < 0 {
+		return nil, errors.New(messages.InvalidInterestRate)
+	}
+
+	currency, err := money.ParseCurrency(request.Currency)
+	if err != nil {
+		return nil, errors.New(messages.UnsupportedCurrency)
+	}
+
+	saved, err := s.interestRepository.UpsertRate(models.InterestRate{
+		AccountType: request.AccountType,
+		Currency:    currency,
+		Rate:        request.Rate,
+		UpdatedBy:   currentUser.Id,
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := interestRateItem(*saved)
+	return &item, nil
+}
+
+// DeleteRate removes a rate, the accounts of its type and currency do not earn interest anymore
+func (s *interestService) DeleteRate(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.New(messages.InterestRateNotFound)
+	}
+
+	err := s.interestRepository.DeleteRate(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.InterestRateNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// Accrue stores the interest of the past days which are not accrued yet and returns how many accruals were stored.
+// The days are caught up from the last accrued day of the accounts, however long the worker was down.
+func (s *interestService) Accrue(now time.Time) (int64, error) {
+	today := calendar.StartOfDay(now)
+
+	start, err := s.interestRepository.FindAccrualStart()
+	if err != nil || start == nil {
+		return 0, err
+	}
+
+	var count int64
+	for day := *start; day.Before(today); day = day.AddDate(0, 0, 1) {
+		accrued, err := s.interestRepository.Accrue(day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return count, err
+		}
+		count += accrued
+	}
+
+	return count, nil
+}
+
+// FindDue returns the ids of the accounts with unpaid interest of the past months
+func (s *interestService) FindDue(now time.Time, limit int) ([]string, error) {
+	return s.interestRepository.FindDue(startOfMonth(now), limit)
+}
+
+// FindOverdrawn returns the past days accounts ended below zero and are not charged overdraft interest for,
+// oldest first. The days are caught up from the last charged day of the accounts like the accruals, a day is marked
+// as charged once no account is left to be charged for it and for the days before it.
+func (s *interestService) FindOverdrawn(now time.Time, limit int) ([]repository.OverdrawnDay, error) {
+	today := calendar.StartOfDay(now)
+
+	start, err := s.interestRepository.FindOverdraftStart()
+	if err != nil || start == nil {
+		return nil, err
+	}
+
+	var overdrawn []repository.OverdrawnDay
+	for day := *start; day.Before(today) && len(overdrawn) < limit; day = day.AddDate(0, 0, 1) {
+		found, err := s.interestRepository.FindOverdrawn(day, day.AddDate(0, 0, 1), limit-len(overdrawn))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(overdrawn) == 0 && len(found) == 0 {
+			if err := s.interestRepository.MarkOverdraftChecked(day, day.AddDate(0, 0, 1)); err != nil {
+				return nil, err
+			}
+		}
+		overdrawn = append(overdrawn, found...)
+	}
+
+	return overdrawn, nil
+}
+
+// PayInterest pays the interest the account earned before the current month
+func (s *accountService) PayInterest(accountId string, now time.Time) error {
+	accounts, err := s.accountRepository.Lock(accountId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	if len(accounts) == 0 {
+		return errors.New(messages.AccountNotFound)
+	}
+
+	_, err = s.payInterest(&accounts[0], startOfMonth(now), now)
+	return err
+}
+
+// payInterest posts the unpaid interest of the days before the given day to the locked account and returns it.
+// The interest is owed by the bank, so it is paid to frozen accounts too.
+func (s *accountService) payInterest(account *models.Account, before time.Time, now time.Time) (money.Amount, error) {
+	accruals, err := s.interestRepository.LockUnpaid(account.Id, before)
+	if err != nil {
+		return money.Zero, errors.New(messages.UnexpectedError)
+	}
+	if len(accruals) == 0 {
+		return money.Zero, nil
+	}
+
+	ids := make([]string, 0, len(accruals))
+	for _, accrual := range accruals {
+		ids = append(ids, accrual.Id)
+	}
+
+	// Interest rounded to zero is marked as paid without a journal
+	amount := accruedInterest(accruals)
+	journalId := ""
+	if amount.IsPositive() {
+		expenseAccount, err := s.internalAccount(enum.InterestExpenseAccountCode, account.Currency)
+		if err != nil {
+			return money.Zero, errors.New(messages.UnexpectedError)
+		}
+
+		journal, err := s.ledgerRepository.Post(models.Journal{
+			Type:        enum.JournalTypeInterest,
+			Description: "Interest",
+			CreatedBy:   expenseAccount.OwnerId,
+			UpdatedBy:   expenseAccount.OwnerId,
+			Postings: []models.Posting{
+				{AccountId: expenseAccount.Id, Amount: -amount, Currency: account.Currency},
+				{AccountId: account.Id, Amount: amount, Currency: account.Currency},
+			},
+		})
+		if err != nil {
+			return money.Zero, errors.New(messages.UnexpectedError)
+		}
+
+		err = s.transferHistoryRepository.Create([]models.TransferHistory{{
+			From:              expenseAccount.AccountNumber,
+			To:                account.AccountNumber,
+			Amount:            amount,
+			Currency:          account.Currency,
+			ConvertedAmount:   amount,
+			ConvertedCurrency: account.Currency,
+			ExchangeRate:      money.OneRate,
+			Note:              "Interest",
+			Type:              enum.JournalTypeInterest,
+			JournalId:         journal.Id,
+			CreatedBy:         expenseAccount.OwnerId,
+			UpdatedBy:         expenseAccount.OwnerId,
+		}})
+		if err != nil {
+			return money.Zero, errors.New(messages.UnexpectedError)
+		}
+
+		journalId = journal.Id
+	}
+
+	if err := s.interestRepository.MarkPaid(ids, journalId, now); err != nil {
+		return money.Zero, errors.New(messages.UnexpectedError)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestAccruedInterest(t *testing.T) {
+	days := func(count int, balance string, rate string) []models.InterestAccrual {
+		accruals := make([]models.InterestAccrual, count)
+		for i := range accruals {
+			accruals[i] = models.InterestAccrual{Balance: money.MustParse(balance), Rate: money.MustParseRate(rate)}
+		}
+		return accruals
+	}
+
+	// 1000 * 0.35 * 31 / 365 = 29.7260...
+	assert.Equal(t, money.MustParse("29.73"), accruedInterest(days(31, "1000", "0.35")))
+
+	// Every day earns 0.0027..., rounding every day would pay nothing
+	assert.Equal(t, money.MustParse("0.08"), accruedInterest(days(30, "10", "0.10")))
+
+	// The days with different balances and rates are summed exactly
+	mixed := append(days(15, "1000", "0.35"), days(15, "2000", "0.40")...)
+	assert.Equal(t, money.MustParse("47.26"), accruedInterest(mixed))
+
+	assert.True(t, accruedInterest(nil).IsZero())
+}
+
+func TestInterestService_Accrue(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	interestService := NewInterestService(interestRepoMock)
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.Local)
+
+	// The worker was down for a month, every day since the last accrued day is caught up
+	first := time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local)
+	interestRepoMock.EXPECT().FindAccrualStart().Return(&first, nil).Times(1)
+	for i := 0; i < 41; i++ {
+		day := first.AddDate(0, 0, i)
+		interestRepoMock.EXPECT().Accrue(day, day.AddDate(0, 0, 1)).Return(int64(i%2), nil).Times(1)
+	}
+
+	count, err := interestService.Accrue(now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), count)
+
+	// Nothing is accrued before the day is over
+	today := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	interestRepoMock.EXPECT().FindAccrualStart().Return(&today, nil).Times(1)
+
+	count, err = interestService.Accrue(now)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestInterestService_FindOverdrawn(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	interestService := NewInterestService(interestRepoMock)
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.Local)
+	first := time.Date(2024, 2, 10, 0, 0, 0, 0, time.Local)
+	overdrawn := dbrepository.OverdrawnDay{AccountId: mockAccountData[0].Id, Day: first.AddDate(0, 0, 2)}
+
+	// The days without overdrawn accounts are marked until the first overdrawn day, the later days are found
+	// but not marked before the overdrawn day is charged
+	interestRepoMock.EXPECT().FindOverdraftStart().Return(&first, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		day := first.AddDate(0, 0, i)
+		interestRepoMock.EXPECT().FindOverdrawn(day, day.AddDate(0, 0, 1), 2).Return(nil, nil).Times(1)
+		interestRepoMock.EXPECT().MarkOverdraftChecked(day, day.AddDate(0, 0, 1)).Return(nil).Times(1)
+	}
+	interestRepoMock.EXPECT().FindOverdrawn(overdrawn.Day, overdrawn.Day.AddDate(0, 0, 1), 2).Return([]dbrepository.OverdrawnDay{overdrawn}, nil).Times(1)
+	next := overdrawn.Day.AddDate(0, 0, 1)
+	interestRepoMock.EXPECT().FindOverdrawn(next, next.AddDate(0, 0, 1), 1).Return([]dbrepository.OverdrawnDay{{AccountId: overdrawn.AccountId, Day: next}}, nil).Times(1)
+
+	days, err := interestService.FindOverdrawn(now, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []dbrepository.OverdrawnDay{overdrawn, {AccountId: overdrawn.AccountId, Day: next}}, days)
+}
+
+func TestAccountService_PayInterest(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	account := mockAccountData[0]
+	account.Type = enum.AccountTypeSavings
+	account.Balance = money.MustParse("1000")
+
+	expenseAccount := mockCashAccount
+	expenseAccount.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b11"
+	expenseAccount.AccountNumber = 2
+	expenseAccount.InternalCode = enum.InterestExpenseAccountCode
+
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.Local)
+	accruals := []models.InterestAccrual{
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60", AccountId: account.Id, Balance: money.MustParse("1000"), Rate: money.MustParseRate("0.365")},
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b61", AccountId: account.Id, Balance: money.MustParse("3000"), Rate: money.MustParseRate("0.365")},
+	}
+
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	interestRepoMock.EXPECT().LockUnpaid(account.Id, time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)).Return(accruals, nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.InterestExpenseAccountCode, money.DefaultCurrency).Return(&expenseAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeInterest, journal.Type)
+		assert.Equal(t, []models.Posting{
+			{AccountId: expenseAccount.Id, Amount: -money.MustParse("4"), Currency: money.DefaultCurrency},
+			{AccountId: account.Id, Amount: money.MustParse("4"), Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b62"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(histories []models.TransferHistory) error {
+		assert.Len(t, histories, 1)
+		assert.Equal(t, enum.JournalTypeInterest, histories[0].Type)
+		assert.Equal(t, expenseAccount.AccountNumber, histories[0].From)
+		assert.Equal(t, account.AccountNumber, histories[0].To)
+		return nil
+	}).Times(1)
+	interestRepoMock.EXPECT().MarkPaid([]string{accruals[0].Id, accruals[1].Id}, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b62", now).Return(nil).Times(1)
+
+	err := s.PayInterest(account.Id, now)
+	assert.NoError(t, err)
+}
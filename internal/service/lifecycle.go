@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+// checkOpen makes sure the account is not closed
+func checkOpen(account *models.Account) error {
+	if !account.IsActive {
+		return errors.New(messages.AccountClosed)
+	}
+	return nil
+}
+
+// checkDebit makes sure money can be taken out of the account
+func checkDebit(account *models.Account) error {
+	if err := checkOpen(account); err != nil {
+		return err
+	}
+	if account.DebitsFrozen {
+		return errors.New(messages.AccountFrozen)
+	}
+	return nil
+}
+
+// checkCredit makes sure money can be put into the account
+func checkCredit(account *models.Account) error {
+	if err := checkOpen(account); err != nil {
+		return err
+	}
+	if account.CreditsFrozen {
+		return errors.New(messages.AccountFrozen)
+	}
+	return nil
+}
+
+// lifecycleReason returns the reason of a change of the account status, every change needs one
+func lifecycleReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", errors.New(messages.AccountReasonRequired)
+	}
+	return reason, nil
+}
+
+func accountStatus(account *models.Account) *dto.AccountStatusResponse {
+	return &dto.AccountStatusResponse{
+		AccountNumber: account.AccountNumber,
+		Status:        account.Status(),
+		DebitsFrozen:  account.DebitsFrozen,
+		CreditsFrozen: account.CreditsFrozen,
+		ClosedAt:      account.ClosedAt,
+		Balance:       account.Balance,
+		Currency:      account.Currency.String(),
+	}
+}
+
+// lifecycleAccount finds the customer account and locks it, so its status does not change during a transfer
+func (s *accountService) lifecycleAccount(accountNumber int64) (*models.Account, error) {
+	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	if err != nil || account.IsInternal {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if err := s.lockAccounts(account); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return account, nil
+}
+
+// changeAccountState saves the new state of the account and records the event of the change
+func (s *accountService) changeAccountState(account *models.Account, event models.AccountEvent) error {
+	if err := s.accountRepository.UpdateState(*account); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	event.AccountId = account.Id
+	event.DebitsFrozen = account.DebitsFrozen
+	event.CreditsFrozen = account.CreditsFrozen
+	if err := s.accountEventRepository.Create(event); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// FreezeAccount blocks the debits, the credits or both of an account. A frozen account can be frozen again
+// to change what is blocked.
+func (s *accountService) FreezeAccount(ctx context.Context, request dto.FreezeAccountRequest) (*dto.AccountStatusResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	reason, err := lifecycleReason(request.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.Debits && !request.Credits {
+		return nil, errors.New(messages.InvalidFreeze)
+	}
+
+	account, err := s.lifecycleAccount(request.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	account.DebitsFrozen = request.Debits
+	account.CreditsFrozen = request.Credits
+	account.UpdatedBy = currentUser.Id
+
+	err = s.changeAccountState(account, models.AccountEvent{
+		Action:    enum.AccountActionFreeze,
+		Reason:    reason,
+		CreatedBy: currentUser.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountStatus(account), nil
+}
+
+// UnfreezeAccount lets the money in and out of a frozen account again
+func (s *accountService) UnfreezeAccount(ctx context.Context, request dto.UnfreezeAccountRequest) (*dto.AccountStatusResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	reason, err := lifecycleReason(request.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.lifecycleAccount(request.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	if !account.DebitsFrozen && !account.CreditsFrozen {
+		return nil, errors.New(messages.AccountNotFrozen)
+	}
+
+	account.DebitsFrozen = false
+	account.CreditsFrozen = false
+	account.UpdatedBy = currentUser.Id
+
+	err = s.changeAccountState(account, models.AccountEvent{
+		Action:    enum.AccountActionUnfreeze,
+		Reason:    reason,
+		CreatedBy: currentUser.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountStatus(account), nil
+}
+
+// CloseAccount closes an account of the current user, admins can close any customer account. The balance has to be
+// zero or is swept to another open account of the owner. The owner is deactivated when the last account is closed.
+func (s *accountService) CloseAccount(ctx context.Context, request dto.CloseAccountRequest) (*dto.AccountStatusResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	reason, err := lifecycleReason(request.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
+	if err != nil || account.IsInternal {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id && currentUser.Role != enum.RoleAdmin {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	var sweepAccount *models.Account
+	if request.SweepToAccountNumber != 0 {
+		sweepAccount, err = s.accountRepository.FindByAccountNumber(request.SweepToAccountNumber)
+		if err != nil || sweepAccount.Id == account.Id || sweepAccount.OwnerId != account.OwnerId {
+			return nil, errors.New(messages.InvalidSweepAccount)
+		}
+
+		err = s.lockAccounts(account, sweepAccount)
+	} else {
+		err = s.lockAccounts(account)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	// The interest earned until today is paid before the balance is checked
+	now := time.Now()
+	interest, err := s.payInterest(account, calendar.StartOfDay(now).AddDate(0, 0, 1), now)
+	if err != nil {
+		return nil, err
+	}
+	account.Balance += interest
+
+	if account.Balance.IsNegative() || (account.Balance.IsPositive() && sweepAccount == nil) {
+		return nil, errors.New(messages.AccountBalanceNotZero)
+	}
+
+	// Held money can not be swept, the holds have to be released first
+	held, err := s.heldAmount(account, now)
+	if err != nil {
+		return nil, err
+	}
+	if held.IsPositive() {
+		return nil, errors.New(messages.AccountHasHolds)
+	}
+
+	event := models.AccountEvent{
+		Action:    enum.AccountActionClose,
+		Reason:    reason,
+		CreatedBy: currentUser.Id,
+	}
+
+	if account.Balance.IsPositive() {
+		// The sweep is a transfer out of the account, a freeze of the debits can not be bypassed by closing the account
+		if err := checkDebit(account); err != nil {
+			return nil, err
+		}
+		if checkCredit(sweepAccount) != nil {
+			return nil, errors.New(messages.InvalidSweepAccount)
+		}
+
+		journalId, err := s.sweepBalance(account, sweepAccount, currentUser.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		event.SweepAccountNumber = sweepAccount.AccountNumber
+		event.SweptAmount = account.Balance
+		event.JournalId = journalId
+		account.Balance = 0
+	}
+
+	account.IsActive = false
+	account.ClosedAt = &now
+	account.UpdatedBy = currentUser.Id
+
+	if err := s.changeAccountState(account, event); err != nil {
+		return nil, err
+	}
+
+	// The owner is deactivated with the last open account
+	accounts, err := s.accountRepository.FindByOwnerId(account.OwnerId)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	hasOpenAccount := false
+	for _, ownerAccount := range accounts {
+		if ownerAccount.Id != account.Id && ownerAccount.IsActive {
+			hasOpenAccount = true
+		}
+	}
+
+	if !hasOpenAccount {
+		if err := s.userRepository.SoftDelete(account.OwnerId); err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+	}
+
+	return accountStatus(account), nil
+}
+
+// sweepBalance moves the whole balance of the account to the other account of the owner with the current rate and
+// without a fee, the id of the journal is returned
+func (s *accountService) sweepBalance(account, sweepAccount *models.Account, userId string) (string, error) {
+	exchangeRate, err := s.exchangeRate(account.Currency, sweepAccount.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	amount := account.Balance
+	convertedAmount := exchangeRate.Convert(amount)
+
+	postings := []models.Posting{
+		{AccountId: account.Id, Amount: -amount, Currency: account.Currency},
+		{AccountId: sweepAccount.Id, Amount: convertedAmount, Currency: sweepAccount.Currency},
+	}
+
+	// The bank buys the currency of the closed account and sells the currency of the other account
+	if account.Currency != sweepAccount.Currency {
+		fromPosition, err := s.internalAccount(enum.FxPositionAccountCode, account.Currency)
+		if err != nil {
+			return "", errors.New(messages.UnexpectedError)
+		}
+
+		toPosition, err := s.internalAccount(enum.FxPositionAccountCode, sweepAccount.Currency)
+		if err != nil {
+			return "", errors.New(messages.UnexpectedError)
+		}
+
+		postings = append(postings,
+			models.Posting{AccountId: fromPosition.Id, Amount: amount, Currency: account.Currency},
+			models.Posting{AccountId: toPosition.Id, Amount: -convertedAmount, Currency: sweepAccount.Currency},
+		)
+	}
+
+	journal, err := s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeSweep,
+		Description: "Account closure",
+		CreatedBy:   userId,
+		UpdatedBy:   userId,
+		Postings:    postings,
+	})
+	if err != nil {
+		return "", errors.New(messages.UnexpectedError)
+	}
+
+	err = s.transferHistoryRepository.Create([]models.TransferHistory{{
+		From:              account.AccountNumber,
+		To:                sweepAccount.AccountNumber,
+		Amount:            amount,
+		Currency:          account.Currency,
+		ConvertedAmount:   convertedAmount,
+		ConvertedCurrency: sweepAccount.Currency,
+		ExchangeRate:      exchangeRate,
+		Note:              "Account closure",
+		Type:              enum.JournalTypeSweep,
+		JournalId:         journal.Id,
+		CreatedBy:         userId,
+		UpdatedBy:         userId,
+	}})
+	if err != nil {
+		return "", errors.New(messages.UnexpectedError)
+	}
+
+	return journal.Id, nil
+}
+
+// GetAccountEvents returns the status of a customer account and the audit trail of its lifecycle
+func (s *accountService) GetAccountEvents(ctx context.Context, accountNumber int64) (*dto.GetAccountEventsResponse, error) {
+	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	if err != nil || account.IsInternal {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	events, err := s.accountEventRepository.FindByAccountId(account.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	items := make([]dto.AccountEventItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, dto.AccountEventItem{
+			Id:                 event.Id,
+			Action:             event.Action,
+			Reason:             event.Reason,
+			DebitsFrozen:       event.DebitsFrozen,
+			CreditsFrozen:      event.CreditsFrozen,
+			SweepAccountNumber: event.SweepAccountNumber,
+			SweptAmount:        event.SweptAmount,
+			CreatedBy:          event.CreatedBy,
+			CreatedAt:          event.CreatedAt,
+		})
+	}
+
+	return &dto.GetAccountEventsResponse{
+		Status: *accountStatus(account),
+		Events: items,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"testing"
+)
+
+func TestAccountService_FreezeAccount_BlocksDebits(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id, Role: enum.RoleAdmin})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("100")
+
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	accountRepoMock.EXPECT().UpdateState(gomock.Any()).DoAndReturn(func(updated models.Account) error {
+		assert.True(t, updated.DebitsFrozen)
+		assert.False(t, updated.CreditsFrozen)
+		assert.Equal(t, mockData[1].Id, updated.UpdatedBy)
+		return nil
+	}).Times(1)
+	accountEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.AccountEvent) error {
+		assert.Equal(t, account.Id, event.AccountId)
+		assert.Equal(t, enum.AccountActionFreeze, event.Action)
+		assert.Equal(t, "Suspicious activity", event.Reason)
+		assert.True(t, event.DebitsFrozen)
+		return nil
+	}).Times(1)
+
+	response, err := s.FreezeAccount(fiberCtx.Context(), dto.FreezeAccountRequest{AccountNumber: account.AccountNumber, Debits: true, Reason: " Suspicious activity "})
+	assert.NoError(t, err)
+	assert.Equal(t, enum.AccountStatusFrozen, response.Status)
+
+	// The owner can not take money out of the frozen account anymore
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	frozen := account
+	frozen.DebitsFrozen = true
+
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{frozen}, nil).Times(1)
+
+	_, err = s.Withdraw(fiberCtx.Context(), dto.WithdrawRequest{AccountNumber: account.AccountNumber, Amount: money.MustParse("10")})
+	assert.EqualError(t, err, messages.AccountFrozen)
+
+	// A reason is required and something has to be frozen
+	_, err = s.FreezeAccount(fiberCtx.Context(), dto.FreezeAccountRequest{AccountNumber: account.AccountNumber, Debits: true})
+	assert.EqualError(t, err, messages.AccountReasonRequired)
+
+	_, err = s.FreezeAccount(fiberCtx.Context(), dto.FreezeAccountRequest{AccountNumber: account.AccountNumber, Reason: "Nothing"})
+	assert.EqualError(t, err, messages.InvalidFreeze)
+}
+
+func TestAccountService_CloseAccount_Sweep(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("75.50")
+
+	savings := mockAccountData[0]
+	savings.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b2b"
+	savings.AccountNumber = 1000000011
+
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(savings.AccountNumber).Return(&savings, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id, savings.Id).Return([]models.Account{account, savings}, nil).Times(1)
+	interestRepoMock.EXPECT().LockUnpaid(account.Id, gomock.Any()).Return(nil, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeSweep, journal.Type)
+		assert.Equal(t, []models.Posting{
+			{AccountId: account.Id, Amount: -money.MustParse("75.50"), Currency: money.DefaultCurrency},
+			{AccountId: savings.Id, Amount: money.MustParse("75.50"), Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b30"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(histories []models.TransferHistory) error {
+		assert.Len(t, histories, 1)
+		assert.Equal(t, enum.JournalTypeSweep, histories[0].Type)
+		assert.Equal(t, savings.AccountNumber, histories[0].To)
+		return nil
+	}).Times(1)
+	accountRepoMock.EXPECT().UpdateState(gomock.Any()).DoAndReturn(func(updated models.Account) error {
+		assert.False(t, updated.IsActive)
+		assert.NotNil(t, updated.ClosedAt)
+		return nil
+	}).Times(1)
+	accountEventRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(event models.AccountEvent) error {
+		assert.Equal(t, enum.AccountActionClose, event.Action)
+		assert.Equal(t, savings.AccountNumber, event.SweepAccountNumber)
+		assert.Equal(t, money.MustParse("75.50"), event.SweptAmount)
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b30", event.JournalId)
+		return nil
+	}).Times(1)
+
+	// The owner keeps the savings account, so the owner stays active
+	accountRepoMock.EXPECT().FindByOwnerId(account.OwnerId).Return([]models.Account{account, savings}, nil).Times(1)
+
+	response, err := s.CloseAccount(fiberCtx.Context(), dto.CloseAccountRequest{AccountNumber: account.AccountNumber, Reason: "Moving to savings", SweepToAccountNumber: savings.AccountNumber})
+	assert.NoError(t, err)
+	assert.Equal(t, enum.AccountStatusClosed, response.Status)
+	assert.True(t, response.Balance.IsZero())
+}
+
+func TestAccountService_CloseAccount_LastAccount(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Balance = money.MustParse("10")
+
+	// The balance has to be swept
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	interestRepoMock.EXPECT().LockUnpaid(account.Id, gomock.Any()).Return(nil, nil).Times(2)
+
+	_, err := s.CloseAccount(fiberCtx.Context(), dto.CloseAccountRequest{AccountNumber: account.AccountNumber, Reason: "Leaving"})
+	assert.EqualError(t, err, messages.AccountBalanceNotZero)
+
+	// The owner is deactivated with the last account
+	account.Balance = 0
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	accountRepoMock.EXPECT().UpdateState(gomock.Any()).Return(nil).Times(1)
+	accountEventRepoMock.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	accountRepoMock.EXPECT().FindByOwnerId(account.OwnerId).Return([]models.Account{account}, nil).Times(1)
+	userRepoMock.EXPECT().SoftDelete(account.OwnerId).Return(nil).Times(1)
+
+	_, err = s.CloseAccount(fiberCtx.Context(), dto.CloseAccountRequest{AccountNumber: account.AccountNumber, Reason: "Leaving"})
+	assert.NoError(t, err)
+
+	// Closed accounts can not receive money anymore
+	account.IsActive = false
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+
+	_, err = s.AddMoney(fiberCtx.Context(), dto.AddMoneyRequest{AccountNumber: account.AccountNumber, Amount: money.MustParse("10")})
+	assert.EqualError(t, err, messages.AccountClosed)
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strconv"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// limitExceededMessages are the errors of the limit periods, so the user knows which limit was hit
+var limitExceededMessages = map[string]string{
+	enum.LimitPeriodTransaction: messages.TransferTransactionLimitExceeded,
+	enum.LimitPeriodDaily:       messages.TransferDailyLimitExceeded,
+	enum.LimitPeriodMonthly:     messages.TransferMonthlyLimitExceeded,
+}
+
+// isLimitExceeded reports whether the error is one of the transfer limit errors
+func isLimitExceeded(err error) bool {
+	for _, message := range limitExceededMessages {
+		if err.Error() == message {
+			return true
+		}
+	}
+	return false
+}
+
+// limitUsage is a transfer limit with the amount used of it in its current period
+type limitUsage struct {
+	limit models.TransferLimit
+	used  money.Amount
+}
+
+func (u limitUsage) remaining() money.Amount {
+	if u.used > u.limit.Amount {
+		return money.Zero
+	}
+	return u.limit.Amount - u.used
+}
+
+// periodStart returns when the current period of the limit started, transaction limits have no usage
+func periodStart(period string, now time.Time) time.Time {
+	switch period {
+	case enum.LimitPeriodDaily:
+		return calendar.StartOfDay(now)
+	case enum.LimitPeriodMonthly:
+		year, month, _ := now.Date()
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	}
+	return now
+}
+
+// effectiveLimits drops the global limits of the periods the customer has its own limit for,
+// the limits of the account apply in addition to them
+func effectiveLimits(limits []models.TransferLimit) []models.TransferLimit {
+	customerPeriods := map[string]bool{}
+	for _, limit := range limits {
+		if limit.Level == enum.LimitLevelCustomer {
+			customerPeriods[limit.Period] = true
+		}
+	}
+
+	var effective []models.TransferLimit
+	for _, limit := range limits {
+		if limit.Level == enum.LimitLevelGlobal && customerPeriods[limit.Period] {
+			continue
+		}
+		effective = append(effective, limit)
+	}
+	return effective
+}
+
+// transferLimitUsages returns the limits of the transfers from the account with what is used of them until now.
+// Global and customer limits count the transfers from all accounts of the owner, account limits only the account.
+func transferLimitUsages(
+	transferLimitRepository repository.TransferLimitRepository,
+	transferRepository repository.TransferRepository,
+	account *models.Account,
+	now time.Time,
+) ([]limitUsage, error) {
+	limits, err := transferLimitRepository.FindApplicable(account.OwnerId, account.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []limitUsage
+	for _, limit := range effectiveLimits(limits) {
+		usage := limitUsage{limit: limit}
+		if limit.Period != enum.LimitPeriodTransaction {
+			since := periodStart(limit.Period, now)
+			if limit.Level == enum.LimitLevelAccount {
+				usage.used, err = transferRepository.SumExecutedByAccount(account.AccountNumber, since)
+			} else {
+				usage.used, err = transferRepository.SumExecutedByOwner(account.OwnerId, since)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+// exceededLimit returns the error of the first limit the amount does not fit into, the amount is in the base currency
+func exceededLimit(usages []limitUsage, amount money.Amount) error {
+	for _, usage := range usages {
+		if amount > usage.remaining() {
+			return errors.New(limitExceededMessages[usage.limit.Period])
+		}
+	}
+	return nil
+}
+
+// exceededBulkLimit returns the error of the first limit a row of a bulk transfer does not fit into. The transaction
+// limits are checked with the amount of the row, the period limits with the total of the rows up to it.
+func exceededBulkLimit(usages []limitUsage, amount money.Amount, total money.Amount) error {
+	for _, usage := range usages {
+		checked := total
+		if usage.limit.Period == enum.LimitPeriodTransaction {
+			checked = amount
+		}
+		if checked > usage.remaining() {
+			return errors.New(limitExceededMessages[usage.limit.Period])
+		}
+	}
+	return nil
+}
+
+type LimitService interface {
+	GetRemaining(ctx context.Context, accountNumber int64) (*dto.GetRemainingLimitsResponse, error)
+	GetLimits(ctx context.Context) (*dto.GetTransferLimitsResponse, error)
+	UpdateLimits(ctx context.Context, request dto.UpdateTransferLimitsRequest) error
+	DeleteLimit(ctx context.Context, id string) error
+
+	WithTx(trxHandle *gorm.DB) LimitService
+}
+
+type limitService struct {
+	transferLimitRepository repository.TransferLimitRepository
+	transferRepository      repository.TransferRepository
+	accountRepository       repository.AccountRepository
+}
+
+func NewLimitService(
+	transferLimitRepository repository.TransferLimitRepository,
+	transferRepository repository.TransferRepository,
+	accountRepository repository.AccountRepository,
+) LimitService {
+	return &limitService{
+		transferLimitRepository: transferLimitRepository,
+		transferRepository:      transferRepository,
+		accountRepository:       accountRepository,
+	}
+}
+
+func (s *limitService) WithTx(trxHandle *gorm.DB) LimitService {
+	clone := *s
+	clone.transferLimitRepository = s.transferLimitRepository.WithTx(trxHandle)
+	clone.transferRepository = s.transferRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// GetRemaining returns the transfer limits of an account of the current user and how much is left of them
+func (s *limitService) GetRemaining(ctx context.Context, accountNumber int64) (*dto.GetRemainingLimitsResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	usages, err := transferLimitUsages(s.transferLimitRepository, s.transferRepository, account, time.Now())
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetRemainingLimitsResponse{
+		AccountNumber: account.AccountNumber,
+		BaseCurrency:  money.DefaultCurrency.String(),
+		Limits:        []dto.RemainingLimitItem{},
+	}
+
+	for _, usage := range usages {
+		response.Limits = append(response.Limits, dto.RemainingLimitItem{
+			Level:     usage.limit.Level,
+			Period:    usage.limit.Period,
+			Limit:     usage.limit.Amount,
+			Used:      usage.used,
+			Remaining: usage.remaining(),
+		})
+	}
+
+	return response, nil
+}
+
+// GetLimits returns all transfer limits of the bank
+func (s *limitService) GetLimits(ctx context.Context) (*dto.GetTransferLimitsResponse, error) {
+	limits, err := s.transferLimitRepository.FindAll()
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetTransferLimitsResponse{
+		BaseCurrency: money.DefaultCurrency.String(),
+		Limits:       []dto.TransferLimitItem{},
+	}
+
+	for _, limit := range limits {
+		response.Limits = append(response.Limits, dto.TransferLimitItem{
+			Id:      limit.Id,
+			Level:   limit.Level,
+			ScopeId: limit.ScopeId,
+			Period:  limit.Period,
+			Amount:  limit.Amount,
+		})
+	}
+
+	return response, nil
+}
+
+// UpdateLimits creates or overwrites the given limits, the amounts are in the base currency.
+// The scope of a customer limit is the user id and the scope of an account limit is the account number.
+func (s *limitService) UpdateLimits(ctx context.Context, request dto.UpdateTransferLimitsRequest) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	if len(request.Limits) == 0 {
+		return errors.New(messages.InvalidTransferLimit)
+	}
+
+	var limits []models.TransferLimit
+	for _, item := range request.Limits {
+		if !isValidLimit(item) {
+			return errors.New(messages.InvalidTransferLimit)
+		}
+
+		limits = append(limits, models.TransferLimit{
+			Level:     item.Level,
+			ScopeId:   item.ScopeId,
+			Period:    item.Period,
+			Amount:    item.Amount,
+			UpdatedBy: currentUser.Id,
+		})
+	}
+
+	err = s.transferLimitRepository.Upsert(limits)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// isValidLimit checks the period, the amount and the scope of the level of a limit
+func isValidLimit(item dto.TransferLimitItem) bool {
+	if _, ok := limitExceededMessages[item.Period]; !ok || !item.Amount.IsPositive() {
+		return false
+	}
+
+	switch item.Level {
+	case enum.LimitLevelGlobal:
+		return item.ScopeId == ""
+	case enum.LimitLevelCustomer:
+		_, err := uuid.Parse(item.ScopeId)
+		return err == nil
+	case enum.LimitLevelAccount:
+		_, err := strconv.ParseInt(item.ScopeId, 10, 64)
+		return err == nil
+	}
+
+	return false
+}
+
+// DeleteLimit removes a limit, customers without their own limit fall back to the global limit
+func (s *limitService) DeleteLimit(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.New(messages.TransferLimitNotFound)
+	}
+
+	err := s.transferLimitRepository.Delete(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New(messages.TransferLimitNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
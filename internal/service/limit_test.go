@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+)
+
+func TestEffectiveLimits(t *testing.T) {
+	limits := []models.TransferLimit{
+		{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodDaily, Amount: money.MustParse("100000")},
+		{Level: enum.LimitLevelGlobal, Period: enum.LimitPeriodMonthly, Amount: money.MustParse("500000")},
+		{Level: enum.LimitLevelCustomer, Period: enum.LimitPeriodDaily, Amount: money.MustParse("1000")},
+		{Level: enum.LimitLevelAccount, Period: enum.LimitPeriodDaily, Amount: money.MustParse("2000")},
+	}
+
+	// The customer limit replaces only the global limit of its period, the account limit stays
+	effective := effectiveLimits(limits)
+	assert.Equal(t, []models.TransferLimit{limits[1], limits[2], limits[3]}, effective)
+}
+
+func TestExceededLimit(t *testing.T) {
+	usages := []limitUsage{
+		{limit: models.TransferLimit{Period: enum.LimitPeriodTransaction, Amount: money.MustParse("500")}},
+		{limit: models.TransferLimit{Period: enum.LimitPeriodMonthly, Amount: money.MustParse("1000")}, used: money.MustParse("1200")},
+	}
+
+	assert.EqualError(t, exceededLimit(usages, money.MustParse("600")), messages.TransferTransactionLimitExceeded)
+	assert.EqualError(t, exceededLimit(usages, money.MustParse("1")), messages.TransferMonthlyLimitExceeded)
+	assert.NoError(t, exceededLimit(usages[:1], money.MustParse("500")))
+	assert.Equal(t, money.Zero, usages[1].remaining())
+}
+
+func TestPeriodStart(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 14, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), periodStart(enum.LimitPeriodDaily, now))
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), periodStart(enum.LimitPeriodMonthly, now))
+}
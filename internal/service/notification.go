@@ -0,0 +1,63 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"tek-bank/internal/db/repository"
+	"tek-bank/pkg/gomailer"
+	"time"
+)
+
+type NotificationService interface {
+	SendPending(now time.Time, limit int) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) NotificationService
+}
+
+type notificationService struct {
+	notificationRepository repository.NotificationRepository
+	pkgMailer              gomailer.Mailer
+}
+
+func NewNotificationService(notificationRepository repository.NotificationRepository, pkgMailer gomailer.Mailer) NotificationService {
+	return &notificationService{
+		notificationRepository: notificationRepository,
+		pkgMailer:              pkgMailer,
+	}
+}
+
+func (s *notificationService) WithTx(trxHandle *gorm.DB) NotificationService {
+	clone := *s
+	clone.notificationRepository = s.notificationRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// SendPending sends the notifications of the outbox and returns how many were sent.
+// A failed mail is counted on the notification and tried again on the next run.
+func (s *notificationService) SendPending(now time.Time, limit int) (int64, error) {
+	notifications, err := s.notificationRepository.LockPending(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, notification := range notifications {
+		err := s.pkgMailer.Send(gomailer.Content{
+			Subject: notification.Subject,
+			Body:    notification.Body,
+			To:      []string{notification.To},
+		})
+		if err != nil {
+			if err := s.notificationRepository.MarkFailed(notification.Id, err.Error()); err != nil {
+				return count, err
+			}
+			continue
+		}
+
+		if err := s.notificationRepository.MarkSent(notification.Id, now); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/internal/db/models"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"testing"
+	"time"
+)
+
+func TestNotificationService_SendPending(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	notificationService := NewNotificationService(notificationRepoMock, pkgMailerMock)
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.Local)
+
+	notifications := []models.Notification{
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b80", To: mockData[0].Email, Subject: "Overdraft", Body: "Your account uses its overdraft."},
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b81", To: mockData[1].Email, Subject: "Overdraft", Body: "Your account uses its overdraft."},
+	}
+
+	// A failed mail is counted and tried again later, the others are still sent
+	notificationRepoMock.EXPECT().LockPending(10).Return(notifications, nil).Times(1)
+	pkgMailerMock.EXPECT().Send(pkgGomailer.Content{Subject: "Overdraft", Body: "Your account uses its overdraft.", To: []string{mockData[0].Email}}).Return(errors.New("smtp down")).Times(1)
+	notificationRepoMock.EXPECT().MarkFailed(notifications[0].Id, "smtp down").Return(nil).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+	notificationRepoMock.EXPECT().MarkSent(notifications[1].Id, now).Return(nil).Times(1)
+
+	count, err := notificationService.SendPending(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// How far an account uses its overdraft, the owner is notified when it goes up
+const (
+	overdraftNotUsed = iota
+	overdraftUsed
+	overdraftExceeded
+)
+
+// overdraftState returns how far a balance uses the overdraft of the given limit
+func overdraftState(balance money.Amount, limit money.Amount) int {
+	switch {
+	case balance < -limit:
+		return overdraftExceeded
+	case balance.IsNegative():
+		return overdraftUsed
+	}
+	return overdraftNotUsed
+}
+
+// notifyOverdraft tells the owner when the account starts using its overdraft or goes beyond its limit.
+// The account has the new balance and limit, previous is the overdraft state before the change. The mail is put
+// into the outbox of the transaction, it is sent after the commit and its failure does not roll back the postings.
+func (s *accountService) notifyOverdraft(account *models.Account, previous int, language string) error {
+	var subject, body string
+	switch state := overdraftState(account.Balance, account.OverdraftLimit); {
+	case state <= previous:
+		return nil
+	case state == overdraftExceeded:
+		subject, body = messages.OverdraftExceededMailSubject, messages.OverdraftExceededMailBody
+	default:
+		subject, body = messages.OverdraftStartedMailSubject, messages.OverdraftStartedMailBody
+	}
+
+	return s.notificationRepository.Create(models.Notification{
+		To:      account.Owner.Email,
+		Subject: i18n.CreateMsgWithLanguage(language, subject),
+		Body: i18n.CreateMsgWithLanguage(language, body, map[string]string{
+			"AccountNumber": fmt.Sprint(account.AccountNumber),
+			"Balance":       money.New(account.Balance, account.Currency).String(),
+			"Limit":         money.New(account.OverdraftLimit, account.Currency).String(),
+		}),
+	})
+}
+
+// SetOverdraft changes the overdraft limit and rate of a current account, a zero limit removes the overdraft.
+// A limit below the used overdraft does not take money back, the owner is notified to pay the difference in.
+func (s *accountService) SetOverdraft(ctx context.Context, request dto.SetOverdraftRequest) (*dto.OverdraftResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if request.Limit.IsNegative() || request.Rate.Rat().Sign() < 0 {
+		return nil, errors.New(messages.InvalidOverdraft)
+	}
+
+	account, err := s.lifecycleAccount(request.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOpen(account); err != nil {
+		return nil, err
+	}
+
+	if account.Type != enum.AccountTypeCurrent && request.Limit.IsPositive() {
+		return nil, errors.New(messages.OverdraftNotAllowed)
+	}
+
+	previous := overdraftState(account.Balance, account.OverdraftLimit)
+
+	account.OverdraftLimit = request.Limit
+	account.OverdraftRate = request.Rate
+	account.UpdatedBy = currentUser.Id
+	if err := s.accountRepository.UpdateOverdraft(*account); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if err := s.notifyOverdraft(account, previous, ""); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	available, err := s.availableBalance(account, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OverdraftResponse{
+		AccountNumber:    account.AccountNumber,
+		Balance:          account.Balance,
+		AvailableBalance: available,
+		OverdraftLimit:   account.OverdraftLimit,
+		OverdraftRate:    account.OverdraftRate,
+		Currency:         account.Currency.String(),
+	}, nil
+}
+
+// ChargeOverdraftInterest charges the interest of the negative balance the account had at the end of the day.
+// The charge is recorded as a paid overdraft accrual of the day, so a day is never charged twice and the charge is
+// never paid out as interest. The interest is owed to the bank, so it is charged to frozen accounts too and may take
+// the balance beyond the overdraft limit.
+func (s *accountService) ChargeOverdraftInterest(accountId string, day time.Time, now time.Time) error {
+	accounts, err := s.accountRepository.Lock(accountId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	if len(accounts) == 0 {
+		return errors.New(messages.AccountNotFound)
+	}
+	account := &accounts[0]
+
+	if account.OverdraftRate.IsZero() {
+		return nil
+	}
+
+	balance, err := s.ledgerRepository.BalanceAt(account.Id, day.AddDate(0, 0, 1))
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	if !balance.IsNegative() {
+		return nil
+	}
+
+	chargeId, err := s.interestRepository.CreateCharge(models.InterestAccrual{
+		AccountId: account.Id,
+		Day:       day,
+		Kind:      enum.AccrualKindOverdraft,
+		Balance:   balance,
+		Rate:      account.OverdraftRate,
+		PaidAt:    &now,
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	if chargeId == "" {
+		return nil
+	}
+
+	// Interest rounded to zero is recorded without a journal
+	amount := accruedInterest([]models.InterestAccrual{{Balance: -balance, Rate: account.OverdraftRate}})
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	incomeAccount, err := s.internalAccount(enum.InterestIncomeAccountCode, account.Currency)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	journal, err := s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeOverdraftInterest,
+		Description: "Overdraft interest",
+		CreatedBy:   incomeAccount.OwnerId,
+		UpdatedBy:   incomeAccount.OwnerId,
+		Postings: []models.Posting{
+			{AccountId: account.Id, Amount: -amount, Currency: account.Currency, AllowNegative: true},
+			{AccountId: incomeAccount.Id, Amount: amount, Currency: account.Currency},
+		},
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	err = s.transferHistoryRepository.Create([]models.TransferHistory{{
+		From:              account.AccountNumber,
+		To:                incomeAccount.AccountNumber,
+		Amount:            amount,
+		Currency:          account.Currency,
+		ConvertedAmount:   amount,
+		ConvertedCurrency: account.Currency,
+		ExchangeRate:      money.OneRate,
+		Note:              "Overdraft interest",
+		Type:              enum.JournalTypeOverdraftInterest,
+		JournalId:         journal.Id,
+		CreatedBy:         incomeAccount.OwnerId,
+		UpdatedBy:         incomeAccount.OwnerId,
+	}})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	if err := s.interestRepository.MarkPaid([]string{chargeId}, journal.Id, now); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	owner, err := s.userRepository.FindByID(account.OwnerId)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	account.Owner = *owner
+
+	previous := overdraftState(account.Balance, account.OverdraftLimit)
+	account.Balance -= amount
+	if err := s.notifyOverdraft(account, previous, ""); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
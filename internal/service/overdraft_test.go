@@ -0,0 +1,182 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestOverdraftState(t *testing.T) {
+	limit := money.MustParse("500")
+
+	assert.Equal(t, overdraftNotUsed, overdraftState(money.MustParse("10"), limit))
+	assert.Equal(t, overdraftNotUsed, overdraftState(money.Zero, limit))
+	assert.Equal(t, overdraftUsed, overdraftState(-money.MustParse("0.01"), limit))
+	assert.Equal(t, overdraftUsed, overdraftState(-money.MustParse("500"), limit))
+	assert.Equal(t, overdraftExceeded, overdraftState(-money.MustParse("500.01"), limit))
+	assert.Equal(t, overdraftExceeded, overdraftState(-money.MustParse("0.01"), money.Zero))
+}
+
+func TestAccountService_Withdraw_Overdraft(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	account := mockAccountData[0]
+	account.Type = enum.AccountTypeCurrent
+	account.Balance = money.MustParse("100")
+	account.OverdraftLimit = money.MustParse("500")
+
+	request := dto.WithdrawRequest{
+		AccountNumber: account.AccountNumber,
+		Language:      "en",
+		Amount:        money.MustParse("300"),
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	ledgerRepoMock.EXPECT().SumPostings(account.Id, enum.JournalTypeWithdrawal, gomock.Any()).Return(money.Zero, nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.CashAccountCode, money.DefaultCurrency).Return(&mockCashAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b70"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+
+	// The mail of the overdraft is put into the outbox, the withdrawal mail is sent at once
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Equal(t, account.Owner.Email, notification.To)
+		assert.Contains(t, notification.Body, "overdraft")
+		assert.Contains(t, notification.Body, "-200.00 TRY")
+		return nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).Return(nil).Times(1)
+
+	response, err := s.Withdraw(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, -money.MustParse("200"), response.Balance)
+
+	// The overdraft can not be passed
+	account.Balance = -money.MustParse("200")
+	accountRepoMock.EXPECT().FindByAccountNumber(request.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+
+	request.Amount = money.MustParse("300.01")
+	_, err = s.Withdraw(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.InSufficientBalance)
+}
+
+func TestAccountService_ChargeOverdraftInterest(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	account := mockAccountData[0]
+	account.Type = enum.AccountTypeCurrent
+	account.Balance = -money.MustParse("3000")
+	account.OverdraftLimit = money.MustParse("3000")
+	account.OverdraftRate = money.MustParseRate("0.365")
+
+	incomeAccount := mockCashAccount
+	incomeAccount.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b12"
+	incomeAccount.AccountNumber = 3
+	incomeAccount.InternalCode = enum.InterestIncomeAccountCode
+
+	day := time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, time.Local)
+
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	ledgerRepoMock.EXPECT().BalanceAt(account.Id, day.AddDate(0, 0, 1)).Return(-money.MustParse("1000"), nil).Times(1)
+	interestRepoMock.EXPECT().CreateCharge(gomock.Any()).DoAndReturn(func(charge models.InterestAccrual) (string, error) {
+		assert.Equal(t, day, charge.Day)
+		assert.Equal(t, enum.AccrualKindOverdraft, charge.Kind)
+		assert.Equal(t, -money.MustParse("1000"), charge.Balance)
+		assert.Equal(t, &now, charge.PaidAt)
+		return "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b71", nil
+	}).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.InterestIncomeAccountCode, money.DefaultCurrency).Return(&incomeAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeOverdraftInterest, journal.Type)
+		assert.Equal(t, []models.Posting{
+			{AccountId: account.Id, Amount: -money.MustParse("1"), Currency: money.DefaultCurrency, AllowNegative: true},
+			{AccountId: incomeAccount.Id, Amount: money.MustParse("1"), Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b72"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(histories []models.TransferHistory) error {
+		assert.Len(t, histories, 1)
+		assert.Equal(t, enum.JournalTypeOverdraftInterest, histories[0].Type)
+		assert.Equal(t, incomeAccount.AccountNumber, histories[0].To)
+		return nil
+	}).Times(1)
+	interestRepoMock.EXPECT().MarkPaid([]string{"e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b71"}, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b72", now).Return(nil).Times(1)
+	userRepoMock.EXPECT().FindByID(account.OwnerId).Return(&mockData[0], nil).Times(1)
+
+	// The interest takes the balance beyond the limit
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Equal(t, mockData[0].Email, notification.To)
+		assert.Contains(t, notification.Body, "-3001.00 TRY")
+		return nil
+	}).Times(1)
+
+	err := s.ChargeOverdraftInterest(account.Id, day, now)
+	assert.NoError(t, err)
+
+	// A day is charged once
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	ledgerRepoMock.EXPECT().BalanceAt(account.Id, day.AddDate(0, 0, 1)).Return(-money.MustParse("1000"), nil).Times(1)
+	interestRepoMock.EXPECT().CreateCharge(gomock.Any()).Return("", nil).Times(1)
+
+	err = s.ChargeOverdraftInterest(account.Id, day, now)
+	assert.NoError(t, err)
+}
+
+func TestAccountService_SetOverdraft(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id, Role: enum.RoleAdmin})
+
+	account := mockAccountData[0]
+	account.Balance = -money.MustParse("50")
+	account.OverdraftLimit = money.MustParse("100")
+
+	_, err := s.SetOverdraft(fiberCtx.Context(), dto.SetOverdraftRequest{AccountNumber: account.AccountNumber, Limit: -money.MustParse("1")})
+	assert.EqualError(t, err, messages.InvalidOverdraft)
+
+	// Only current accounts can have an overdraft
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+
+	_, err = s.SetOverdraft(fiberCtx.Context(), dto.SetOverdraftRequest{AccountNumber: account.AccountNumber, Limit: money.MustParse("100")})
+	assert.EqualError(t, err, messages.OverdraftNotAllowed)
+
+	// A lower limit than the used overdraft notifies the owner
+	account.Type = enum.AccountTypeCurrent
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(account.Id).Return([]models.Account{account}, nil).Times(1)
+	accountRepoMock.EXPECT().UpdateOverdraft(gomock.Any()).DoAndReturn(func(updated models.Account) error {
+		assert.Equal(t, money.MustParse("20"), updated.OverdraftLimit)
+		assert.Equal(t, mockData[1].Id, updated.UpdatedBy)
+		return nil
+	}).Times(1)
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Contains(t, notification.Body, "20.00 TRY")
+		return nil
+	}).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{account.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+
+	response, err := s.SetOverdraft(fiberCtx.Context(), dto.SetOverdraftRequest{AccountNumber: account.AccountNumber, Limit: money.MustParse("20"), Rate: money.MustParseRate("0.6")})
+	assert.NoError(t, err)
+	assert.Equal(t, -money.MustParse("30"), response.AvailableBalance)
+}
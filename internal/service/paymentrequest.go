@@ -0,0 +1,549 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+type PaymentRequestService interface {
+	Create(ctx context.Context, request dto.CreatePaymentRequestRequest) (*dto.PaymentRequestItem, error)
+	ListSent(ctx context.Context) ([]dto.PaymentRequestItem, error)
+	Inbox(ctx context.Context) ([]dto.PaymentRequestItem, error)
+	Get(ctx context.Context, id string) (*dto.PaymentRequestItem, error)
+	Accept(ctx context.Context, request dto.AcceptPaymentRequestRequest) (*dto.PaymentRequestItem, error)
+	Decline(ctx context.Context, request dto.DeclinePaymentRequestRequest) error
+	Cancel(ctx context.Context, id string) error
+
+	SettlePaying(limit int) (int64, error)
+	ExpirePending(now time.Time, limit int) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) PaymentRequestService
+}
+
+type paymentRequestService struct {
+	paymentRequestRepository repository.PaymentRequestRepository
+	accountRepository        repository.AccountRepository
+	userRepository           repository.UserRepository
+	notificationRepository   repository.NotificationRepository
+	accountService           AccountService
+}
+
+func NewPaymentRequestService(
+	paymentRequestRepository repository.PaymentRequestRepository,
+	accountRepository repository.AccountRepository,
+	userRepository repository.UserRepository,
+	notificationRepository repository.NotificationRepository,
+	accountService AccountService,
+) PaymentRequestService {
+	return &paymentRequestService{
+		paymentRequestRepository: paymentRequestRepository,
+		accountRepository:        accountRepository,
+		userRepository:           userRepository,
+		notificationRepository:   notificationRepository,
+		accountService:           accountService,
+	}
+}
+
+func (s *paymentRequestService) WithTx(trxHandle *gorm.DB) PaymentRequestService {
+	clone := *s
+	clone.paymentRequestRepository = s.paymentRequestRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	clone.userRepository = s.userRepository.WithTx(trxHandle)
+	clone.notificationRepository = s.notificationRepository.WithTx(trxHandle)
+	clone.accountService = s.accountService.WithTx(trxHandle)
+	return &clone
+}
+
+// notify puts the mail into the outbox of the transaction, one for every recipient. The mails are sent after
+// the commit, a failed mail does not roll back the payment requests.
+func (s *paymentRequestService) notify(content gomailer.Content) error {
+	for _, to := range content.To {
+		err := s.notificationRepository.Create(models.Notification{
+			To:      to,
+			Subject: content.Subject,
+			Body:    content.Body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fullName returns the name of the user shown to the other side of a payment request
+func fullName(user models.User) string {
+	return user.FirstName + " " + user.LastName
+}
+
+// Create asks another customer for money to an account of the current user, the payer is notified by e-mail.
+// A user can send enum.PaymentRequestLimit requests in enum.PaymentRequestLimitWindow.
+func (s *paymentRequestService) Create(ctx context.Context, request dto.CreatePaymentRequestRequest) (*dto.PaymentRequestItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if !request.Amount.IsPositive() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+
+	if (request.PayerAccountNumber == 0) == (request.PayerCustomerNumber == 0) {
+		return nil, errors.New(messages.InvalidPaymentRequest)
+	}
+
+	// Lock the requester, so concurrent requests can not go over the limit
+	if err := s.userRepository.Lock(currentUser.Id); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	count, err := s.paymentRequestRepository.CountByRequesterIdSince(currentUser.Id, time.Now().Add(-enum.PaymentRequestLimitWindow))
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+	if count >= enum.PaymentRequestLimit {
+		return nil, errors.New(messages.PaymentRequestLimitExceeded)
+	}
+
+	toAccount, err := s.accountRepository.FindByAccountNumber(request.ToAccountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if toAccount.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	// Freezes may be lifted until the request is paid, closed accounts stay closed
+	if err := checkOpen(toAccount); err != nil {
+		return nil, err
+	}
+
+	var payer *models.User
+	if request.PayerAccountNumber != 0 {
+		payerAccount, err := s.accountRepository.FindByAccountNumber(request.PayerAccountNumber)
+		if err != nil {
+			return nil, errors.New(messages.AccountNotFound)
+		}
+		if err := checkOpen(payerAccount); err != nil {
+			return nil, err
+		}
+		if payerAccount.Currency != toAccount.Currency {
+			return nil, errors.New(messages.PaymentRequestCurrencyMismatch)
+		}
+		payer = &payerAccount.Owner
+	} else {
+		payer, err = s.userRepository.FindByCustomerNumber(request.PayerCustomerNumber)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !payer.IsActive) {
+			return nil, errors.New(messages.UserNotFound)
+		}
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+	}
+
+	if payer.Id == currentUser.Id {
+		return nil, errors.New(messages.InvalidPaymentRequest)
+	}
+
+	paymentRequest, err := s.paymentRequestRepository.Create(models.PaymentRequest{
+		RequesterId:        currentUser.Id,
+		ToAccountNumber:    toAccount.AccountNumber,
+		Amount:             request.Amount,
+		Currency:           toAccount.Currency,
+		Note:               strings.TrimSpace(request.Note),
+		PayerId:            payer.Id,
+		PayerAccountNumber: request.PayerAccountNumber,
+		Status:             enum.PaymentRequestPending,
+		ExpiresAt:          time.Now().Add(enum.PaymentRequestTTL),
+		CreatedBy:          currentUser.Id,
+		UpdatedBy:          currentUser.Id,
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+	paymentRequest.Requester = toAccount.Owner
+	paymentRequest.Payer = *payer
+
+	note := paymentRequest.Note
+	if note == "" {
+		note = "-"
+	}
+
+	err = s.notify(gomailer.Content{
+		Subject: i18n.CreateMsgWithLanguage("", messages.PaymentRequestMailSubject),
+		Body: i18n.CreateMsgWithLanguage("", messages.PaymentRequestMailBody, map[string]string{
+			"Requester": fullName(paymentRequest.Requester),
+			"Amount":    money.New(paymentRequest.Amount, paymentRequest.Currency).String(),
+			"Note":      note,
+			"ExpiresAt": paymentRequest.ExpiresAt.Format(time.RFC1123),
+		}),
+		To: []string{payer.Email},
+	})
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := paymentRequestItem(*paymentRequest, currentUser.Id)
+	return &item, nil
+}
+
+// ListSent returns the payment requests of the current user with their answers, the newest first
+func (s *paymentRequestService) ListSent(ctx context.Context) ([]dto.PaymentRequestItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	paymentRequests, err := s.paymentRequestRepository.FindByRequesterId(currentUser.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return paymentRequestItems(paymentRequests, currentUser.Id), nil
+}
+
+// Inbox returns the payment requests waiting for an answer of the current user, the newest first
+func (s *paymentRequestService) Inbox(ctx context.Context) ([]dto.PaymentRequestItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	paymentRequests, err := s.paymentRequestRepository.FindPendingByPayerId(currentUser.Id, time.Now())
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return paymentRequestItems(paymentRequests, currentUser.Id), nil
+}
+
+// Get returns a payment request the current user sent or received
+func (s *paymentRequestService) Get(ctx context.Context, id string) (*dto.PaymentRequestItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New(messages.PaymentRequestNotFound)
+	}
+
+	paymentRequest, err := s.paymentRequestRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) ||
+		(err == nil && paymentRequest.RequesterId != currentUser.Id && paymentRequest.PayerId != currentUser.Id) {
+		return nil, errors.New(messages.PaymentRequestNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := paymentRequestItem(*paymentRequest, currentUser.Id)
+	return &item, nil
+}
+
+// Accept pays a payment request of the current user with a transfer to the requester. The transfer goes through
+// the approval of the payer like any other transfer, the request is paying until the transfer reaches a final status.
+func (s *paymentRequestService) Accept(ctx context.Context, request dto.AcceptPaymentRequestRequest) (*dto.PaymentRequestItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	paymentRequest, err := s.pendingPaymentRequest(request.Id, func(paymentRequest *models.PaymentRequest) bool {
+		return paymentRequest.PayerId == currentUser.Id
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fromAccountNumber := request.FromAccountNumber
+	if fromAccountNumber == 0 {
+		fromAccountNumber = paymentRequest.PayerAccountNumber
+	}
+	if fromAccountNumber == 0 {
+		return nil, errors.New(messages.BadRequest)
+	}
+	if paymentRequest.PayerAccountNumber != 0 && fromAccountNumber != paymentRequest.PayerAccountNumber {
+		return nil, errors.New(messages.PaymentRequestAccountMismatch)
+	}
+
+	fromAccount, err := s.accountRepository.FindByAccountNumber(fromAccountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+	if fromAccount.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	// The requester gets the requested amount, so the transfer is not converted
+	if fromAccount.Currency != paymentRequest.Currency {
+		return nil, errors.New(messages.PaymentRequestCurrencyMismatch)
+	}
+
+	transfer, err := s.accountService.TransferMoney(ctx, dto.TransferMoneyRequest{
+		Note:              paymentRequest.Note,
+		Amount:            paymentRequest.Amount,
+		FromAccountNumber: fromAccount.AccountNumber,
+		ToAccountNumber:   paymentRequest.ToAccountNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	paymentRequest.Status = enum.PaymentRequestPaying
+	paymentRequest.PayerAccountNumber = fromAccount.AccountNumber
+	paymentRequest.TransferId = transfer.Id
+	paymentRequest.AnsweredAt = &now
+	paymentRequest.UpdatedBy = currentUser.Id
+
+	// Transfers without an approval are executed at once, the other requests are settled by the worker
+	if transfer.Status == enum.TransferExecuted {
+		err = s.markPaid(paymentRequest)
+	} else {
+		err = s.paymentRequestRepository.Update(*paymentRequest)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := paymentRequestItem(*paymentRequest, currentUser.Id)
+	item.Transfer = transfer
+	return &item, nil
+}
+
+// Decline turns down a payment request of the current user, the requester is notified with the reason
+func (s *paymentRequestService) Decline(ctx context.Context, request dto.DeclinePaymentRequestRequest) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	paymentRequest, err := s.pendingPaymentRequest(request.Id, func(paymentRequest *models.PaymentRequest) bool {
+		return paymentRequest.PayerId == currentUser.Id
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	paymentRequest.Status = enum.PaymentRequestDeclined
+	paymentRequest.DeclineReason = strings.TrimSpace(request.Reason)
+	paymentRequest.AnsweredAt = &now
+	paymentRequest.UpdatedBy = currentUser.Id
+	if err := s.paymentRequestRepository.Update(*paymentRequest); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	err = s.notify(gomailer.Content{
+		Subject: i18n.CreateMsgWithLanguage("", messages.PaymentRequestDeclinedMailSubject),
+		Body: i18n.CreateMsgWithLanguage("", messages.PaymentRequestDeclinedMailBody, map[string]string{
+			"Payer":  fullName(paymentRequest.Payer),
+			"Amount": money.New(paymentRequest.Amount, paymentRequest.Currency).String(),
+			"Reason": paymentRequest.DeclineReason,
+		}),
+		To: []string{paymentRequest.Requester.Email},
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// Cancel withdraws a payment request of the current user which is not answered yet
+func (s *paymentRequestService) Cancel(ctx context.Context, id string) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	paymentRequest, err := s.pendingPaymentRequest(id, func(paymentRequest *models.PaymentRequest) bool {
+		return paymentRequest.RequesterId == currentUser.Id
+	})
+	if err != nil {
+		return err
+	}
+
+	paymentRequest.Status = enum.PaymentRequestCancelled
+	paymentRequest.UpdatedBy = currentUser.Id
+	if err := s.paymentRequestRepository.Update(*paymentRequest); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// SettlePaying settles the payment requests whose transfers reached a final status. The requester is notified of
+// the paid requests, the requests whose transfers were not executed wait for the answer of the payer again.
+func (s *paymentRequestService) SettlePaying(limit int) (int64, error) {
+	paid, err := s.paymentRequestRepository.LockPaying([]string{enum.TransferExecuted}, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range paid {
+		if err := s.markPaid(&paid[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	unpaid, err := s.paymentRequestRepository.LockPaying([]string{enum.TransferRejected, enum.TransferExpired, enum.TransferCancelled}, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, paymentRequest := range unpaid {
+		// Requests which ran out meanwhile are expired by the worker
+		paymentRequest.Status = enum.PaymentRequestPending
+		paymentRequest.TransferId = ""
+		paymentRequest.AnsweredAt = nil
+		if err := s.paymentRequestRepository.Update(paymentRequest); err != nil {
+			return 0, err
+		}
+
+		err = s.notify(gomailer.Content{
+			Subject: i18n.CreateMsgWithLanguage("", messages.PaymentRequestUnpaidMailSubject),
+			Body: i18n.CreateMsgWithLanguage("", messages.PaymentRequestUnpaidMailBody, map[string]string{
+				"Requester": fullName(paymentRequest.Requester),
+				"Amount":    money.New(paymentRequest.Amount, paymentRequest.Currency).String(),
+				"ExpiresAt": paymentRequest.ExpiresAt.Format(time.RFC1123),
+			}),
+			To: []string{paymentRequest.Payer.Email},
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(paid) + len(unpaid)), nil
+}
+
+// markPaid marks a payment request as paid by its executed transfer and notifies the requester
+func (s *paymentRequestService) markPaid(paymentRequest *models.PaymentRequest) error {
+	paymentRequest.Status = enum.PaymentRequestAccepted
+	if err := s.paymentRequestRepository.Update(*paymentRequest); err != nil {
+		return err
+	}
+
+	return s.notify(gomailer.Content{
+		Subject: i18n.CreateMsgWithLanguage("", messages.PaymentRequestAcceptedMailSubject),
+		Body: i18n.CreateMsgWithLanguage("", messages.PaymentRequestAcceptedMailBody, map[string]string{
+			"Payer":         fullName(paymentRequest.Payer),
+			"Amount":        money.New(paymentRequest.Amount, paymentRequest.Currency).String(),
+			"AccountNumber": fmt.Sprint(paymentRequest.ToAccountNumber),
+		}),
+		To: []string{paymentRequest.Requester.Email},
+	})
+}
+
+// ExpirePending expires the payment requests which were not answered in time and notifies both sides
+func (s *paymentRequestService) ExpirePending(now time.Time, limit int) (int64, error) {
+	paymentRequests, err := s.paymentRequestRepository.LockExpired(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, paymentRequest := range paymentRequests {
+		paymentRequest.Status = enum.PaymentRequestExpired
+		if err := s.paymentRequestRepository.Update(paymentRequest); err != nil {
+			return 0, err
+		}
+
+		body := i18n.CreateMsgWithLanguage("", messages.PaymentRequestExpiredMailBody, map[string]string{
+			"Requester": fullName(paymentRequest.Requester),
+			"Payer":     fullName(paymentRequest.Payer),
+			"Amount":    money.New(paymentRequest.Amount, paymentRequest.Currency).String(),
+		})
+
+		err = s.notify(gomailer.Content{
+			Subject: i18n.CreateMsgWithLanguage("", messages.PaymentRequestExpiredMailSubject),
+			Body:    body,
+			To:      []string{paymentRequest.Requester.Email, paymentRequest.Payer.Email},
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(paymentRequests)), nil
+}
+
+// pendingPaymentRequest locks a payment request waiting for an answer. Requests the current user can not answer,
+// as told by canAnswer, are not found.
+func (s *paymentRequestService) pendingPaymentRequest(id string, canAnswer func(paymentRequest *models.PaymentRequest) bool) (*models.PaymentRequest, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New(messages.PaymentRequestNotFound)
+	}
+
+	paymentRequest, err := s.paymentRequestRepository.LockById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !canAnswer(paymentRequest)) {
+		return nil, errors.New(messages.PaymentRequestNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if paymentRequest.Status != enum.PaymentRequestPending {
+		return nil, errors.New(messages.PaymentRequestNotPending)
+	}
+
+	// The worker expires the request and notifies both sides
+	if !time.Now().Before(paymentRequest.ExpiresAt) {
+		return nil, errors.New(messages.PaymentRequestExpired)
+	}
+
+	return paymentRequest, nil
+}
+
+// paymentRequestItem returns the payment request as the user sees it. The payer is shown to the requester once
+// the payer answers, so requests can not be used to look up the holders of accounts.
+func paymentRequestItem(paymentRequest models.PaymentRequest, userId string) dto.PaymentRequestItem {
+	item := dto.PaymentRequestItem{
+		Id:                      paymentRequest.Id,
+		RequesterName:           fullName(paymentRequest.Requester),
+		RequesterCustomerNumber: paymentRequest.Requester.CustomerNumber,
+		ToAccountNumber:         paymentRequest.ToAccountNumber,
+		PayerName:               fullName(paymentRequest.Payer),
+		PayerCustomerNumber:     paymentRequest.Payer.CustomerNumber,
+		PayerAccountNumber:      paymentRequest.PayerAccountNumber,
+		Amount:                  paymentRequest.Amount,
+		Currency:                paymentRequest.Currency.String(),
+		Note:                    paymentRequest.Note,
+		Status:                  paymentRequest.Status,
+		ExpiresAt:               paymentRequest.ExpiresAt,
+		TransferId:              paymentRequest.TransferId,
+		DeclineReason:           paymentRequest.DeclineReason,
+		AnsweredAt:              paymentRequest.AnsweredAt,
+		CreatedAt:               paymentRequest.CreatedAt,
+	}
+
+	if paymentRequest.RequesterId == userId && paymentRequest.AnsweredAt == nil {
+		item.PayerName = ""
+		item.PayerCustomerNumber = 0
+	}
+
+	return item
+}
+
+func paymentRequestItems(paymentRequests []models.PaymentRequest, userId string) []dto.PaymentRequestItem {
+	items := []dto.PaymentRequestItem{}
+	for _, paymentRequest := range paymentRequests {
+		items = append(items, paymentRequestItem(paymentRequest, userId))
+	}
+	return items
+}
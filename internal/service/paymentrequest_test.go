@@ -0,0 +1,281 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/internal/mocks/repository"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestPaymentRequestService_Create(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	paymentRequestRepoMock := repository.NewMockPaymentRequestRepository(gomock.NewController(t))
+	paymentRequestService := NewPaymentRequestService(paymentRequestRepoMock, accountRepoMock, userRepoMock, notificationRepoMock, s)
+
+	request := dto.CreatePaymentRequestRequest{
+		ToAccountNumber:     mockAccountData[0].AccountNumber,
+		PayerCustomerNumber: mockData[1].CustomerNumber,
+		Amount:              money.MustParse("25.50"),
+		Note:                " Dinner ",
+	}
+
+	userRepoMock.EXPECT().Lock(mockData[0].Id).Return(nil).Times(1)
+	paymentRequestRepoMock.EXPECT().CountByRequesterIdSince(mockData[0].Id, gomock.Any()).Return(int64(enum.PaymentRequestLimit-1), nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(request.ToAccountNumber).Return(&mockAccountData[0], nil).Times(1)
+	userRepoMock.EXPECT().FindByCustomerNumber(request.PayerCustomerNumber).Return(&mockData[1], nil).Times(1)
+	paymentRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(paymentRequest models.PaymentRequest) (*models.PaymentRequest, error) {
+		assert.Equal(t, mockData[0].Id, paymentRequest.RequesterId)
+		assert.Equal(t, mockData[1].Id, paymentRequest.PayerId)
+		assert.Equal(t, money.DefaultCurrency, paymentRequest.Currency)
+		assert.Equal(t, "Dinner", paymentRequest.Note)
+		assert.Equal(t, enum.PaymentRequestPending, paymentRequest.Status)
+		paymentRequest.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60"
+		return &paymentRequest, nil
+	}).Times(1)
+
+	// The payer is notified through the outbox
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		assert.Equal(t, mockData[1].Email, notification.To)
+		assert.Contains(t, notification.Body, "John Doe")
+		assert.Contains(t, notification.Body, "25.50 TRY")
+		return nil
+	}).Times(1)
+
+	response, err := paymentRequestService.Create(fiberCtx.Context(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60", response.Id)
+
+	// The payer is not shown to the requester until the payer answers
+	assert.Empty(t, response.PayerName)
+	assert.Zero(t, response.PayerCustomerNumber)
+
+	// The requester can not send more requests than the limit
+	userRepoMock.EXPECT().Lock(mockData[0].Id).Return(nil).Times(1)
+	paymentRequestRepoMock.EXPECT().CountByRequesterIdSince(mockData[0].Id, gomock.Any()).Return(int64(enum.PaymentRequestLimit), nil).Times(1)
+
+	_, err = paymentRequestService.Create(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.PaymentRequestLimitExceeded)
+
+	// The payer is given once and is another customer
+	request.PayerAccountNumber = mockAccountData[1].AccountNumber
+	_, err = paymentRequestService.Create(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.InvalidPaymentRequest)
+
+	request.PayerAccountNumber = 0
+	request.PayerCustomerNumber = mockData[0].CustomerNumber
+	userRepoMock.EXPECT().Lock(mockData[0].Id).Return(nil).Times(1)
+	paymentRequestRepoMock.EXPECT().CountByRequesterIdSince(mockData[0].Id, gomock.Any()).Return(int64(0), nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(request.ToAccountNumber).Return(&mockAccountData[0], nil).Times(1)
+	userRepoMock.EXPECT().FindByCustomerNumber(request.PayerCustomerNumber).Return(&mockData[0], nil).Times(1)
+
+	_, err = paymentRequestService.Create(fiberCtx.Context(), request)
+	assert.EqualError(t, err, messages.InvalidPaymentRequest)
+}
+
+func TestPaymentRequestService_Accept(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[1].Id})
+	paymentRequestRepoMock := repository.NewMockPaymentRequestRepository(gomock.NewController(t))
+	paymentRequestService := NewPaymentRequestService(paymentRequestRepoMock, accountRepoMock, userRepoMock, notificationRepoMock, s)
+
+	payerAccount := mockAccountData[1]
+	payerAccount.Balance = money.MustParse("100")
+
+	paymentRequest := models.PaymentRequest{
+		Id:              "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60",
+		RequesterId:     mockData[0].Id,
+		ToAccountNumber: mockAccountData[0].AccountNumber,
+		Amount:          money.MustParse("25.50"),
+		Currency:        money.DefaultCurrency,
+		Note:            "Dinner",
+		PayerId:         mockData[1].Id,
+		Status:          enum.PaymentRequestPending,
+		ExpiresAt:       time.Now().Add(time.Hour),
+		Requester:       mockData[0],
+		Payer:           mockData[1],
+	}
+
+	paymentRequestRepoMock.EXPECT().LockById(paymentRequest.Id).Return(&paymentRequest, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(payerAccount.AccountNumber).Return(&payerAccount, nil).Times(2)
+
+	// The transfer waits for the approval of the payer
+	accountRepoMock.EXPECT().FindByAccountNumber(paymentRequest.ToAccountNumber).Return(&mockAccountData[0], nil).Times(1)
+	feeScheduleRepoMock.EXPECT().FindApplicable(gomock.Any(), gomock.Any()).Return([]models.FeeSchedule{mockFeeSchedule}, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(payerAccount.Id).Return([]models.Account{payerAccount}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{payerAccount.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	userRepoMock.EXPECT().Lock(payerAccount.OwnerId).Return(nil).Times(1)
+	transferLimitRepoMock.EXPECT().FindApplicable(payerAccount.OwnerId, payerAccount.AccountNumber).Return(nil, nil).Times(1)
+	pkgCryptoMock.EXPECT().GenerateToken(32).Return("token", nil).Times(1)
+	transferRequestRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(transfer models.Transfer) (*models.Transfer, error) {
+		assert.Equal(t, paymentRequest.Amount, transfer.Amount)
+		assert.Equal(t, paymentRequest.ToAccountNumber, transfer.ToAccountNumber)
+		transfer.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b61"
+		return &transfer, nil
+	}).Times(1)
+	holdRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(hold models.Hold) (*models.Hold, error) {
+		return &hold, nil
+	}).Times(1)
+
+	paymentRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.PaymentRequest) error {
+		assert.Equal(t, enum.PaymentRequestPaying, updated.Status)
+		assert.Equal(t, "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b61", updated.TransferId)
+		assert.Equal(t, payerAccount.AccountNumber, updated.PayerAccountNumber)
+		assert.NotNil(t, updated.AnsweredAt)
+		return nil
+	}).Times(1)
+
+	// Only the approval of the transfer, the requester is notified when the transfer is executed
+	var recipients [][]string
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		recipients = append(recipients, content.To)
+		return nil
+	}).Times(1)
+
+	response, err := paymentRequestService.Accept(fiberCtx.Context(), dto.AcceptPaymentRequestRequest{Id: paymentRequest.Id, FromAccountNumber: payerAccount.AccountNumber})
+	assert.NoError(t, err)
+	assert.Equal(t, enum.PaymentRequestPaying, response.Status)
+	assert.Equal(t, "Jane Doe", response.PayerName)
+	assert.Equal(t, enum.TransferPendingApproval, response.Transfer.Status)
+	assert.Equal(t, [][]string{{mockData[1].Email}}, recipients)
+
+	// An answered request can not be answered again
+	paymentRequestRepoMock.EXPECT().LockById(paymentRequest.Id).Return(&paymentRequest, nil).Times(1)
+
+	err = paymentRequestService.Decline(fiberCtx.Context(), dto.DeclinePaymentRequestRequest{Id: paymentRequest.Id})
+	assert.EqualError(t, err, messages.PaymentRequestNotPending)
+}
+
+func TestPaymentRequestService_SettlePaying(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	paymentRequestRepoMock := repository.NewMockPaymentRequestRepository(gomock.NewController(t))
+	paymentRequestService := NewPaymentRequestService(paymentRequestRepoMock, accountRepoMock, userRepoMock, notificationRepoMock, s)
+
+	answeredAt := time.Now().Add(-time.Hour)
+	paid := models.PaymentRequest{
+		Id:              "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60",
+		ToAccountNumber: mockAccountData[0].AccountNumber,
+		Amount:          money.MustParse("25.50"),
+		Currency:        money.DefaultCurrency,
+		Status:          enum.PaymentRequestPaying,
+		ExpiresAt:       time.Now().Add(time.Hour),
+		TransferId:      "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b61",
+		AnsweredAt:      &answeredAt,
+		Requester:       mockData[0],
+		Payer:           mockData[1],
+	}
+	unpaid := paid
+	unpaid.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b62"
+	unpaid.TransferId = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b63"
+
+	paymentRequestRepoMock.EXPECT().LockPaying([]string{enum.TransferExecuted}, 10).Return([]models.PaymentRequest{paid}, nil).Times(1)
+	paymentRequestRepoMock.EXPECT().LockPaying([]string{enum.TransferRejected, enum.TransferExpired, enum.TransferCancelled}, 10).
+		Return([]models.PaymentRequest{unpaid}, nil).Times(1)
+
+	var updates []models.PaymentRequest
+	paymentRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.PaymentRequest) error {
+		updates = append(updates, updated)
+		return nil
+	}).Times(2)
+
+	// The notices are put into the outbox, they are sent after the commit
+	var recipients []string
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		recipients = append(recipients, notification.To)
+		return nil
+	}).Times(2)
+
+	count, err := paymentRequestService.SettlePaying(10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// The paid request is accepted, the requester is notified
+	assert.Equal(t, enum.PaymentRequestAccepted, updates[0].Status)
+	assert.Equal(t, paid.TransferId, updates[0].TransferId)
+
+	// The request whose transfer failed waits for the payer again
+	assert.Equal(t, enum.PaymentRequestPending, updates[1].Status)
+	assert.Empty(t, updates[1].TransferId)
+	assert.Nil(t, updates[1].AnsweredAt)
+	assert.Equal(t, []string{mockData[0].Email, mockData[1].Email}, recipients)
+}
+
+func TestPaymentRequestService_Answer_NotPayer(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	paymentRequestRepoMock := repository.NewMockPaymentRequestRepository(gomock.NewController(t))
+	paymentRequestService := NewPaymentRequestService(paymentRequestRepoMock, accountRepoMock, userRepoMock, notificationRepoMock, s)
+
+	paymentRequest := models.PaymentRequest{
+		Id:          "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60",
+		RequesterId: mockData[0].Id,
+		PayerId:     mockData[1].Id,
+		Status:      enum.PaymentRequestPending,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+
+	// The requester can not accept its own request
+	paymentRequestRepoMock.EXPECT().LockById(paymentRequest.Id).Return(&paymentRequest, nil).Times(1)
+
+	_, err := paymentRequestService.Accept(fiberCtx.Context(), dto.AcceptPaymentRequestRequest{Id: paymentRequest.Id})
+	assert.EqualError(t, err, messages.PaymentRequestNotFound)
+
+	// Expired requests are left to the worker
+	paymentRequestRepoMock.EXPECT().LockById(paymentRequest.Id).Return(&paymentRequest, nil).Times(1)
+
+	err = paymentRequestService.Cancel(fiberCtx.Context(), paymentRequest.Id)
+	assert.EqualError(t, err, messages.PaymentRequestExpired)
+}
+
+func TestPaymentRequestService_ExpirePending(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	paymentRequestRepoMock := repository.NewMockPaymentRequestRepository(gomock.NewController(t))
+	paymentRequestService := NewPaymentRequestService(paymentRequestRepoMock, accountRepoMock, userRepoMock, notificationRepoMock, s)
+
+	now := time.Now()
+	paymentRequest := models.PaymentRequest{
+		Id:        "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b60",
+		Amount:    money.MustParse("25.50"),
+		Currency:  money.DefaultCurrency,
+		Status:    enum.PaymentRequestPending,
+		ExpiresAt: now.Add(-time.Minute),
+		Requester: mockData[0],
+		Payer:     mockData[1],
+	}
+
+	paymentRequestRepoMock.EXPECT().LockExpired(now, 10).Return([]models.PaymentRequest{paymentRequest}, nil).Times(1)
+	paymentRequestRepoMock.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated models.PaymentRequest) error {
+		assert.Equal(t, enum.PaymentRequestExpired, updated.Status)
+		return nil
+	}).Times(1)
+
+	// Both sides get their own notice from the outbox, nothing is sent in the transaction
+	var recipients []string
+	notificationRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(notification models.Notification) error {
+		recipients = append(recipients, notification.To)
+		return nil
+	}).Times(2)
+
+	count, err := paymentRequestService.ExpirePending(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, []string{mockData[0].Email, mockData[1].Email}, recipients)
+}
@@ -2,34 +2,56 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"strings"
 	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
 	"tek-bank/internal/db/repository"
 	"tek-bank/internal/dto"
 	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
 )
 
 type ProfileService interface {
 	MyProfile(ctx context.Context) (*dto.GetProfileResponse, error)
-	MyTransferHistory(ctx context.Context, accountNumber int64) ([]dto.GetTransferHistoryResponse, error)
+	MyTransferHistory(ctx context.Context, request dto.GetTransferHistoryRequest) (*dto.GetTransferHistoryPageResponse, error)
+	UpdateApprovalMethod(ctx context.Context, request dto.UpdateApprovalMethodRequest) error
 }
 
 type profileService struct {
 	accountRepository  repository.AccountRepository
 	transferRepository repository.TransferHistoryRepository
 	userRepository     repository.UserRepository
+	ledgerRepository   repository.LedgerRepository
+	holdRepository     repository.HoldRepository
+	interestRepository repository.InterestRepository
 }
 
 func NewProfileService(
 	accountRepository repository.AccountRepository,
 	transferRepository repository.TransferHistoryRepository,
 	userRepository repository.UserRepository,
+	ledgerRepository repository.LedgerRepository,
+	holdRepository repository.HoldRepository,
+	interestRepository repository.InterestRepository,
 ) ProfileService {
 	return &profileService{
 		accountRepository:  accountRepository,
 		transferRepository: transferRepository,
 		userRepository:     userRepository,
+		ledgerRepository:   ledgerRepository,
+		holdRepository:     holdRepository,
+		interestRepository: interestRepository,
 	}
 }
 
@@ -49,35 +71,70 @@ func (s *profileService) MyProfile(ctx context.Context) (*dto.GetProfileResponse
 		return nil, errors.New(messages.UnexpectedError)
 	}
 
+	accountIds := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountIds = append(accountIds, account.Id)
+	}
+
+	held, err := s.holdRepository.SumActive(accountIds, time.Now())
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	accruals, err := s.interestRepository.FindUnpaid(accountIds)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	unpaid := make(map[string][]models.InterestAccrual)
+	for _, accrual := range accruals {
+		unpaid[accrual.AccountId] = append(unpaid[accrual.AccountId], accrual)
+	}
+
 	var accountItems []dto.AccountItem
 	for _, account := range accounts {
 		accountItems = append(accountItems, dto.AccountItem{
-			Id:            account.Id,
-			AccountNumber: account.AccountNumber,
-			IBAN:          account.IBAN,
-			Balance:       account.Balance,
+			Id:               account.Id,
+			AccountNumber:    account.AccountNumber,
+			IBAN:             account.IBAN,
+			Type:             account.Type,
+			Balance:          account.Balance,
+			AvailableBalance: account.Balance + account.OverdraftLimit - held[account.Id],
+			HeldAmount:       held[account.Id],
+			OverdraftLimit:   account.OverdraftLimit,
+			Currency:         account.Currency.String(),
+			Status:           account.Status(),
+			AccruedInterest:  accruedInterest(unpaid[account.Id]),
 		})
 	}
 
 	response := dto.GetProfileResponse{
-		Id:          user.Id,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		PhoneNumber: fmt.Sprintf("+%d", user.PhoneNumber),
-		Email:       user.Email,
-		AccountList: accountItems,
+		Id:             user.Id,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		PhoneNumber:    fmt.Sprintf("+%d", user.PhoneNumber),
+		Email:          user.Email,
+		ApprovalMethod: user.ApprovalMethod,
+		AccountList:    accountItems,
 	}
 
 	return &response, nil
 }
 
-func (s *profileService) MyTransferHistory(ctx context.Context, accountNumber int64) ([]dto.GetTransferHistoryResponse, error) {
+// MyTransferHistory returns a page of the history of an account of the current user, newest first.
+// The next page is requested with the cursor of the previous one and the same filters.
+func (s *profileService) MyTransferHistory(ctx context.Context, request dto.GetTransferHistoryRequest) (*dto.GetTransferHistoryPageResponse, error) {
 	currentUser, err := authware.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, errors.New(messages.Unauthorized)
 	}
 
-	account, err := s.accountRepository.FindByAccountNumber(accountNumber)
+	filter, err := historyFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
 	if err != nil {
 		return nil, errors.New(messages.AccountNotFound)
 	}
@@ -86,29 +143,185 @@ func (s *profileService) MyTransferHistory(ctx context.Context, accountNumber in
 		return nil, errors.New(messages.Unauthorized)
 	}
 
-	transferHistory, err := s.transferRepository.FetchByAccountNumber(accountNumber)
+	// One more entry than the page is fetched to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	transferHistory, err := s.transferRepository.FetchByAccountNumber(filter)
 	if err != nil {
-		return nil, errors.New(messages.AccountNotFound)
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	response := &dto.GetTransferHistoryPageResponse{Items: []dto.GetTransferHistoryResponse{}}
+	if len(transferHistory) > limit {
+		transferHistory = transferHistory[:limit]
+		response.NextCursor = encodeHistoryCursor(transferHistory[limit-1])
+	}
+
+	balances, err := historyBalances(s.ledgerRepository, account, transferHistory)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
 	}
 
-	var response []dto.GetTransferHistoryResponse
 	for _, transfer := range transferHistory {
+		item := historyItem(transfer, request.AccountNumber)
+		if balance, ok := balances[transfer.Id]; ok {
+			item.Balance = &balance
+		}
+		response.Items = append(response.Items, item)
+	}
 
-		if transfer.To == accountNumber && transfer.IsFee {
-			continue
+	return response, nil
+}
+
+// historyFilter validates the filters of the request and converts them to the filter of the repository
+func historyFilter(request dto.GetTransferHistoryRequest) (repository.TransferHistoryFilter, error) {
+	filter := repository.TransferHistoryFilter{
+		AccountNumber: request.AccountNumber,
+		Direction:     request.Direction,
+		Note:          strings.TrimSpace(request.Note),
+		Limit:         request.Limit,
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultHistoryPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxHistoryPageSize {
+		return filter, errors.New(messages.InvalidHistoryFilter)
+	}
+
+	switch filter.Direction {
+	case "", enum.HistoryDirectionIn, enum.HistoryDirectionOut:
+	default:
+		return filter, errors.New(messages.InvalidHistoryFilter)
+	}
+
+	var err error
+	if request.From != "" {
+		filter.From, err = time.ParseInLocation(dateLayout, request.From, time.Local)
+		if err != nil {
+			return filter, errors.New(messages.InvalidDateRange)
+		}
+	}
+	if request.To != "" {
+		to, err := time.ParseInLocation(dateLayout, request.To, time.Local)
+		if err != nil {
+			return filter, errors.New(messages.InvalidDateRange)
 		}
+		// The last day is inclusive
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.New(messages.InvalidDateRange)
+	}
 
-		if transfer.From == accountNumber {
-			transfer.Amount = -transfer.Amount
+	if request.MinAmount != "" {
+		filter.MinAmount, err = money.Parse(request.MinAmount)
+		if err != nil || filter.MinAmount.IsNegative() {
+			return filter, errors.New(messages.InvalidHistoryFilter)
+		}
+	}
+	if request.MaxAmount != "" {
+		filter.MaxAmount, err = money.Parse(request.MaxAmount)
+		if err != nil || !filter.MaxAmount.IsPositive() || filter.MaxAmount < filter.MinAmount {
+			return filter, errors.New(messages.InvalidHistoryFilter)
 		}
-		response = append(response, dto.GetTransferHistoryResponse{
-			Id:     transfer.Id,
-			From:   transfer.From,
-			To:     transfer.To,
-			Note:   transfer.Note,
-			Amount: transfer.Amount,
-		})
 	}
 
-	return response, nil
+	if request.Cursor != "" {
+		filter.CursorCreatedAt, filter.CursorId, err = decodeHistoryCursor(request.Cursor)
+		if err != nil {
+			return filter, errors.New(messages.InvalidCursor)
+		}
+	}
+
+	return filter, nil
+}
+
+// encodeHistoryCursor returns the cursor of the page after the entry, the cursor is opaque for the clients
+func encodeHistoryCursor(entry models.TransferHistory) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(entry.CreatedAt.Format(time.RFC3339Nano) + "|" + entry.Id))
+}
+
+func decodeHistoryCursor(cursor string) (time.Time, string, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	createdAt, id, found := strings.Cut(string(value), "|")
+	if !found {
+		return time.Time{}, "", errors.New("cursor without id")
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", err
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	return at, id, nil
+}
+
+// historyItem returns the entry as it is seen by the account, with the other side of the entry as the counterparty
+func historyItem(transfer models.TransferHistory, accountNumber int64) dto.GetTransferHistoryResponse {
+	item := dto.GetTransferHistoryResponse{
+		Id:        transfer.Id,
+		Type:      transfer.Type,
+		Direction: enum.HistoryDirectionIn,
+		From:      transfer.From,
+		To:        transfer.To,
+		Note:      transfer.Note,
+		Amount:    transfer.Amount,
+		Currency:  transfer.Currency.String(),
+		FeeRule:   transfer.FeeRule,
+		CreatedAt: transfer.CreatedAt,
+	}
+
+	counterparty := transfer.FromAccount
+
+	// The receiver sees the amount in its own currency
+	if transfer.From == accountNumber {
+		item.Direction = enum.HistoryDirectionOut
+		item.Amount = -transfer.Amount
+		counterparty = transfer.ToAccount
+	} else if transfer.ConvertedCurrency != "" {
+		item.Amount = transfer.ConvertedAmount
+		item.Currency = transfer.ConvertedCurrency.String()
+	}
+
+	if transfer.Currency != transfer.ConvertedCurrency && !transfer.ExchangeRate.IsZero() {
+		exchangeRate := transfer.ExchangeRate
+		item.ExchangeRate = &exchangeRate
+	}
+
+	item.CounterpartyAccountNumber = counterparty.AccountNumber
+	item.CounterpartyIBAN = counterparty.IBAN
+	item.CounterpartyName = strings.TrimSpace(counterparty.Owner.FirstName + " " + counterparty.Owner.LastName)
+
+	return item
+}
+
+// UpdateApprovalMethod changes how the next transfers of the current user are approved
+func (s *profileService) UpdateApprovalMethod(ctx context.Context, request dto.UpdateApprovalMethodRequest) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	switch request.ApprovalMethod {
+	case enum.ApprovalMethodEmailLink, enum.ApprovalMethodEmailCode, enum.ApprovalMethodSMSCode:
+	default:
+		return errors.New(messages.InvalidApprovalMethod)
+	}
+
+	err = s.userRepository.UpdateApprovalMethod(currentUser.Id, request.ApprovalMethod)
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestHistoryFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     dto.GetTransferHistoryRequest
+		expectedErr string
+	}{
+		{name: "no filters", request: dto.GetTransferHistoryRequest{AccountNumber: 1000000001}},
+		{name: "all filters", request: dto.GetTransferHistoryRequest{AccountNumber: 1000000001, Limit: 50, From: "2024-01-01", To: "2024-01-31", Direction: "out", MinAmount: "10", MaxAmount: "100.50", Note: "rent"}},
+		{name: "page too big", request: dto.GetTransferHistoryRequest{Limit: 101}, expectedErr: messages.InvalidHistoryFilter},
+		{name: "unknown direction", request: dto.GetTransferHistoryRequest{Direction: "sideways"}, expectedErr: messages.InvalidHistoryFilter},
+		{name: "invalid date", request: dto.GetTransferHistoryRequest{From: "01.01.2024"}, expectedErr: messages.InvalidDateRange},
+		{name: "from after to", request: dto.GetTransferHistoryRequest{From: "2024-02-01", To: "2024-01-31"}, expectedErr: messages.InvalidDateRange},
+		{name: "min above max", request: dto.GetTransferHistoryRequest{MinAmount: "100", MaxAmount: "10"}, expectedErr: messages.InvalidHistoryFilter},
+		{name: "negative amount", request: dto.GetTransferHistoryRequest{MinAmount: "-1"}, expectedErr: messages.InvalidHistoryFilter},
+		{name: "invalid cursor", request: dto.GetTransferHistoryRequest{Cursor: "not-a-cursor"}, expectedErr: messages.InvalidCursor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := historyFilter(test.request)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	filter, _ := historyFilter(dto.GetTransferHistoryRequest{To: "2024-01-31"})
+	assert.Equal(t, defaultHistoryPageSize, filter.Limit)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local), filter.To)
+}
+
+func TestProfileService_MyTransferHistory_Pages(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	profileService := NewProfileService(accountRepoMock, transferRepoMock, userRepoMock, ledgerRepoMock, holdRepoMock, interestRepoMock)
+
+	account := mockAccountData[0]
+	receiver := mockAccountData[1]
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 123456000, time.UTC)
+	entries := []models.TransferHistory{
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b41", From: account.AccountNumber, To: receiver.AccountNumber, Amount: money.MustParse("25"), Currency: money.DefaultCurrency, Type: enum.JournalTypeTransfer, CreatedAt: createdAt, ToAccount: receiver},
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b40", From: receiver.AccountNumber, To: account.AccountNumber, Amount: money.MustParse("10"), Currency: money.DefaultCurrency, Type: enum.JournalTypeTransfer, CreatedAt: createdAt, FromAccount: receiver},
+		{Id: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b39", From: receiver.AccountNumber, To: account.AccountNumber, Amount: money.MustParse("5"), Currency: money.DefaultCurrency, Type: enum.JournalTypeTransfer, CreatedAt: createdAt.Add(-time.Hour), FromAccount: receiver},
+	}
+
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(2)
+	transferRepoMock.EXPECT().FetchByAccountNumber(dbrepository.TransferHistoryFilter{AccountNumber: account.AccountNumber, Limit: 3}).Return(entries, nil).Times(1)
+
+	page, err := profileService.MyTransferHistory(fiberCtx.Context(), dto.GetTransferHistoryRequest{AccountNumber: account.AccountNumber, Limit: 2})
+	if err != nil {
+		t.Errorf("Error was not expected: %v", err)
+	}
+	assert.Len(t, page.Items, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	assert.Equal(t, enum.HistoryDirectionOut, page.Items[0].Direction)
+	assert.Equal(t, -money.MustParse("25"), page.Items[0].Amount)
+	assert.Equal(t, receiver.IBAN, page.Items[0].CounterpartyIBAN)
+	assert.Equal(t, "Jane Doe", page.Items[0].CounterpartyName)
+	assert.Equal(t, enum.HistoryDirectionIn, page.Items[1].Direction)
+	assert.Equal(t, receiver.AccountNumber, page.Items[1].CounterpartyAccountNumber)
+	assert.Equal(t, createdAt, page.Items[1].CreatedAt)
+
+	// The next page starts after the last entry of the page
+	transferRepoMock.EXPECT().FetchByAccountNumber(gomock.Any()).DoAndReturn(func(filter dbrepository.TransferHistoryFilter) ([]models.TransferHistory, error) {
+		assert.True(t, createdAt.Equal(filter.CursorCreatedAt))
+		assert.Equal(t, entries[1].Id, filter.CursorId)
+		return entries[2:], nil
+	}).Times(1)
+
+	page, err = profileService.MyTransferHistory(fiberCtx.Context(), dto.GetTransferHistoryRequest{AccountNumber: account.AccountNumber, Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Errorf("Error was not expected: %v", err)
+	}
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"os"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"time"
+)
+
+// reversalForceAllowed reports whether the policy of the bank lets admins force reversals,
+// a forced reversal takes the money back even if the balance of the receiver goes below zero
+func reversalForceAllowed() bool {
+	return os.Getenv("REVERSAL_ALLOW_FORCE") == "true"
+}
+
+// reversalPart returns the amounts of a reversal of the transfer entry in the currencies of the sender and the receiver.
+// The original rate is used, the last reversal takes back exactly what is left for the receiver.
+func reversalPart(original models.TransferHistory, reversals []models.TransferHistory, amount money.Amount) (money.Amount, money.Amount, error) {
+	convertedAmount := original.ConvertedAmount
+	if original.ConvertedCurrency == "" {
+		convertedAmount = original.Amount
+	}
+
+	// A reversal gives back the converted amount to the sender, see ReverseTransfer
+	var reversed, reversedConverted money.Amount
+	for _, reversal := range reversals {
+		reversed += reversal.ConvertedAmount
+		reversedConverted += reversal.Amount
+	}
+
+	remaining := original.Amount - reversed
+	if !remaining.IsPositive() {
+		return money.Zero, money.Zero, errors.New(messages.TransferAlreadyReversed)
+	}
+
+	if amount.IsZero() {
+		amount = remaining
+	}
+
+	if amount.IsNegative() || amount > remaining {
+		return money.Zero, money.Zero, errors.New(messages.InvalidReversalAmount)
+	}
+
+	if amount == remaining {
+		return amount, convertedAmount - reversedConverted, nil
+	}
+
+	rate := original.ExchangeRate
+	if rate.IsZero() {
+		rate = money.OneRate
+	}
+
+	return amount, rate.Convert(amount), nil
+}
+
+// ReverseTransfer gives back the whole or a part of an executed transfer to the sender, optionally with its fee.
+// The money is moved with compensating postings and the history entries point to the reversed entries.
+// A reversal needs a reason and fails if the receiver does not have the money without its overdraft and its holds,
+// unless it is forced. It also needs a reference of the admin, an entry is reversed once with a reference, so a
+// retried request can not give back twice.
+func (s *accountService) ReverseTransfer(ctx context.Context, request dto.ReverseTransferRequest) (*dto.ReverseTransferResponse, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return nil, errors.New(messages.ReversalReasonRequired)
+	}
+
+	reference := strings.TrimSpace(request.Reference)
+	if reference == "" {
+		return nil, errors.New(messages.ReversalReferenceRequired)
+	}
+
+	if request.Force && !reversalForceAllowed() {
+		return nil, errors.New(messages.ReversalForceNotAllowed)
+	}
+
+	if _, err := uuid.Parse(request.TransferHistoryId); err != nil {
+		return nil, errors.New(messages.TransferNotFound)
+	}
+
+	// Lock the entry, so concurrent reversals can not give back more than the transfer
+	original, err := s.transferHistoryRepository.LockById(request.TransferHistoryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New(messages.TransferNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	if original.Type != enum.JournalTypeTransfer || original.IsFee || original.ReversalOf != "" {
+		return nil, errors.New(messages.InvalidReversal)
+	}
+
+	reversals, err := s.transferHistoryRepository.FindReversals(original.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	for _, reversal := range reversals {
+		if reversal.Reference == reference {
+			return nil, errors.New(messages.ReversalReferenceUsed)
+		}
+	}
+
+	amount, convertedAmount, err := reversalPart(*original, reversals, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var feeEntry *models.TransferHistory
+	if request.RefundFee {
+		feeEntry, err = s.refundableFee(original)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	senderAccount, err := s.accountRepository.FindByAccountNumber(original.From)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	receiverAccount, err := s.accountRepository.FindByAccountNumber(original.To)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	err = s.lockAccounts(senderAccount, receiverAccount)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// Reversals are allowed on frozen accounts, but a closed account can not get or give money anymore
+	if err := checkOpen(senderAccount); err != nil {
+		return nil, err
+	}
+	if err := checkOpen(receiverAccount); err != nil {
+		return nil, err
+	}
+
+	// Unless it is forced, the reversal only takes the money the receiver has. The overdraft of the receiver is not
+	// used and the money held for its pending transfers or by an admin stays with it.
+	if !request.Force {
+		held, err := s.heldAmount(receiverAccount, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if receiverAccount.Balance-held < convertedAmount {
+			return nil, errors.New(messages.ReceiverInsufficientBalance)
+		}
+	}
+
+	postings := []models.Posting{
+		{AccountId: receiverAccount.Id, Amount: -convertedAmount, Currency: receiverAccount.Currency, AllowNegative: request.Force},
+		{AccountId: senderAccount.Id, Amount: amount, Currency: senderAccount.Currency},
+	}
+
+	// The bank sells back the currency of the sender and buys back the currency of the receiver
+	if senderAccount.Currency != receiverAccount.Currency {
+		receiverPosition, err := s.internalAccount(enum.FxPositionAccountCode, receiverAccount.Currency)
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		senderPosition, err := s.internalAccount(enum.FxPositionAccountCode, senderAccount.Currency)
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		postings = append(postings,
+			models.Posting{AccountId: receiverPosition.Id, Amount: convertedAmount, Currency: receiverAccount.Currency},
+			models.Posting{AccountId: senderPosition.Id, Amount: -amount, Currency: senderAccount.Currency},
+		)
+	}
+
+	var feeAccount *models.Account
+	if feeEntry != nil {
+		feeAccount, err = s.internalAccount(enum.FeeIncomeAccountCode, feeEntry.Currency)
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+
+		postings = append(postings,
+			models.Posting{AccountId: feeAccount.Id, Amount: -feeEntry.Amount, Currency: feeEntry.Currency},
+			models.Posting{AccountId: senderAccount.Id, Amount: feeEntry.Amount, Currency: feeEntry.Currency},
+		)
+	}
+
+	journal, err := s.ledgerRepository.Post(models.Journal{
+		Type:        enum.JournalTypeReversal,
+		Description: reason,
+		CreatedBy:   currentUser.Id,
+		UpdatedBy:   currentUser.Id,
+		Postings:    postings,
+	})
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		return nil, errors.New(messages.ReceiverInsufficientBalance)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// The reversal goes from the receiver to the sender, so the currencies are the other way round
+	exchangeRate := money.OneRate
+	if !original.ExchangeRate.IsZero() {
+		exchangeRate = money.OneRate.Div(original.ExchangeRate)
+	}
+
+	transferHistories := []models.TransferHistory{{
+		From:              receiverAccount.AccountNumber,
+		To:                senderAccount.AccountNumber,
+		Amount:            convertedAmount,
+		Currency:          receiverAccount.Currency,
+		ConvertedAmount:   amount,
+		ConvertedCurrency: senderAccount.Currency,
+		ExchangeRate:      exchangeRate,
+		Note:              "Reversal",
+		Type:              enum.JournalTypeReversal,
+		JournalId:         journal.Id,
+		ReversalOf:        original.Id,
+		Reason:            reason,
+		Reference:         reference,
+		CreatedBy:         currentUser.Id,
+		UpdatedBy:         currentUser.Id,
+	}}
+
+	refundedFee := money.Zero
+	if feeEntry != nil {
+		refundedFee = feeEntry.Amount
+		transferHistories = append(transferHistories, models.TransferHistory{
+			From:              feeAccount.AccountNumber,
+			To:                senderAccount.AccountNumber,
+			Amount:            feeEntry.Amount,
+			Currency:          feeEntry.Currency,
+			ConvertedAmount:   feeEntry.Amount,
+			ConvertedCurrency: feeEntry.Currency,
+			ExchangeRate:      money.OneRate,
+			Note:              "Transaction Fee Refund",
+			IsFee:             true,
+			FeeRule:           feeEntry.FeeRule,
+			Type:              enum.JournalTypeReversal,
+			JournalId:         journal.Id,
+			ReversalOf:        feeEntry.Id,
+			Reason:            reason,
+			Reference:         reference,
+			CreatedBy:         currentUser.Id,
+			UpdatedBy:         currentUser.Id,
+		})
+	}
+
+	err = s.transferHistoryRepository.Create(transferHistories)
+	if errors.Is(err, repository.ErrDuplicateReversal) {
+		return nil, errors.New(messages.ReversalReferenceUsed)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	err = s.notifyReversal(senderAccount, receiverAccount, amount, convertedAmount, reason, request.Language)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	// Taking the money back may put the receiver into the overdraft, or beyond it when forced
+	previous := overdraftState(receiverAccount.Balance, receiverAccount.OverdraftLimit)
+	receiverAccount.Balance -= convertedAmount
+	if err := s.notifyOverdraft(receiverAccount, previous, request.Language); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	remaining, _, _ := reversalPart(*original, append(reversals, transferHistories[0]), money.Zero)
+
+	return &dto.ReverseTransferResponse{
+		TransferHistoryId: original.Id,
+		JournalId:         journal.Id,
+		Amount:            amount,
+		Currency:          senderAccount.Currency.String(),
+		ConvertedAmount:   convertedAmount,
+		ConvertedCurrency: receiverAccount.Currency.String(),
+		RefundedFee:       refundedFee,
+		RemainingAmount:   remaining,
+		Forced:            request.Force,
+		Reference:         reference,
+	}, nil
+}
+
+// refundableFee returns the fee entry of the transfer entry, a fee is only refunded once
+func (s *accountService) refundableFee(original *models.TransferHistory) (*models.TransferHistory, error) {
+	if original.JournalId == "" {
+		return nil, errors.New(messages.InvalidFeeRefund)
+	}
+
+	entries, err := s.transferHistoryRepository.FindByJournalId(original.JournalId)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsFee || !entry.Amount.IsPositive() {
+			continue
+		}
+
+		refunds, err := s.transferHistoryRepository.FindReversals(entry.Id)
+		if err != nil {
+			return nil, errors.New(messages.UnexpectedError)
+		}
+		if len(refunds) > 0 {
+			return nil, errors.New(messages.InvalidFeeRefund)
+		}
+
+		return &entry, nil
+	}
+
+	return nil, errors.New(messages.InvalidFeeRefund)
+}
+
+// notifyReversal tells the sender and the receiver how much of the transfer was reversed in their currencies
+func (s *accountService) notifyReversal(senderAccount, receiverAccount *models.Account, amount, convertedAmount money.Amount, reason string, language string) error {
+	content := func(account *models.Account, amount money.Amount) gomailer.Content {
+		return gomailer.Content{
+			Subject: i18n.CreateMsgWithLanguage(language, messages.TransferReversedMailSubject),
+			Body: i18n.CreateMsgWithLanguage(language, messages.TransferReversedMailBody, map[string]string{
+				"From":   fmt.Sprint(senderAccount.AccountNumber),
+				"To":     fmt.Sprint(receiverAccount.AccountNumber),
+				"Amount": money.New(amount, account.Currency).String(),
+				"Reason": reason,
+			}),
+			To: []string{account.Owner.Email},
+		}
+	}
+
+	return s.sendMail(content(senderAccount, amount), content(receiverAccount, convertedAmount))
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"testing"
+)
+
+var mockTransferEntry = models.TransferHistory{
+	Id:        "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b30",
+	From:      1000000001,
+	To:        1000000002,
+	Amount:    money.MustParse("100"),
+	Currency:  money.DefaultCurrency,
+	Type:      enum.JournalTypeTransfer,
+	JournalId: "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b31",
+}
+
+func TestReversalPart(t *testing.T) {
+	rate, _ := money.ParseRate("0.03")
+	crossCurrency := models.TransferHistory{
+		Amount:            money.MustParse("100"),
+		Currency:          money.DefaultCurrency,
+		ConvertedAmount:   money.MustParse("3.33"),
+		ConvertedCurrency: "USD",
+		ExchangeRate:      rate,
+	}
+
+	tests := []struct {
+		name             string
+		original         models.TransferHistory
+		reversals        []models.TransferHistory
+		amount           money.Amount
+		expectedAmount   money.Amount
+		expectedReceiver money.Amount
+		expectedErr      string
+	}{
+		{
+			name:             "full reversal",
+			original:         mockTransferEntry,
+			expectedAmount:   money.MustParse("100"),
+			expectedReceiver: money.MustParse("100"),
+		},
+		{
+			name:             "partial reversal",
+			original:         mockTransferEntry,
+			amount:           money.MustParse("40"),
+			expectedAmount:   money.MustParse("40"),
+			expectedReceiver: money.MustParse("40"),
+		},
+		{
+			name:             "rest after a partial reversal",
+			original:         mockTransferEntry,
+			reversals:        []models.TransferHistory{{Amount: money.MustParse("40"), ConvertedAmount: money.MustParse("40")}},
+			expectedAmount:   money.MustParse("60"),
+			expectedReceiver: money.MustParse("60"),
+		},
+		{
+			name:        "more than the rest",
+			original:    mockTransferEntry,
+			reversals:   []models.TransferHistory{{Amount: money.MustParse("40"), ConvertedAmount: money.MustParse("40")}},
+			amount:      money.MustParse("60.01"),
+			expectedErr: messages.InvalidReversalAmount,
+		},
+		{
+			name:        "negative amount",
+			original:    mockTransferEntry,
+			amount:      -money.MustParse("1"),
+			expectedErr: messages.InvalidReversalAmount,
+		},
+		{
+			name:        "already reversed",
+			original:    mockTransferEntry,
+			reversals:   []models.TransferHistory{{Amount: money.MustParse("100"), ConvertedAmount: money.MustParse("100")}},
+			expectedErr: messages.TransferAlreadyReversed,
+		},
+		{
+			name:             "partial reversal with the original rate",
+			original:         crossCurrency,
+			amount:           money.MustParse("50"),
+			expectedAmount:   money.MustParse("50"),
+			expectedReceiver: money.MustParse("1.50"),
+		},
+		{
+			name:             "last reversal takes back what is left",
+			original:         crossCurrency,
+			reversals:        []models.TransferHistory{{Amount: money.MustParse("1.50"), ConvertedAmount: money.MustParse("50")}},
+			expectedAmount:   money.MustParse("50"),
+			expectedReceiver: money.MustParse("1.83"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			amount, receiverAmount, err := reversalPart(test.original, test.reversals, test.amount)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedAmount, amount)
+			assert.Equal(t, test.expectedReceiver, receiverAmount)
+		})
+	}
+}
+
+func TestAccountService_ReverseTransfer_PartialWithFee(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	sender := mockAccountData[0]
+	receiver := mockAccountData[1]
+	receiver.Balance = money.MustParse("100")
+	feeEntry := models.TransferHistory{
+		Id:        "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b32",
+		From:      sender.AccountNumber,
+		To:        2,
+		Amount:    money.MustParse("4.22"),
+		Currency:  money.DefaultCurrency,
+		IsFee:     true,
+		FeeRule:   "Standard: 4.22 TRY flat",
+		Type:      enum.JournalTypeTransfer,
+		JournalId: mockTransferEntry.JournalId,
+	}
+	feeAccount := models.Account{
+		Id:            "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b11",
+		AccountNumber: 2,
+		Currency:      money.DefaultCurrency,
+		IsInternal:    true,
+		InternalCode:  enum.FeeIncomeAccountCode,
+	}
+
+	request := dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Language:          "en",
+		Amount:            money.MustParse("40"),
+		RefundFee:         true,
+		Reason:            "Wrong receiver",
+		Reference:         "TICKET-4711",
+	}
+
+	transferRepoMock.EXPECT().LockById(mockTransferEntry.Id).Return(&mockTransferEntry, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(mockTransferEntry.Id).Return(nil, nil).Times(1)
+	transferRepoMock.EXPECT().FindByJournalId(mockTransferEntry.JournalId).Return([]models.TransferHistory{mockTransferEntry, feeEntry}, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(feeEntry.Id).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(sender.AccountNumber).Return(&sender, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(receiver.AccountNumber).Return(&receiver, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(sender.Id, receiver.Id).Return([]models.Account{sender, receiver}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{receiver.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	accountRepoMock.EXPECT().FindInternal(enum.FeeIncomeAccountCode, money.DefaultCurrency).Return(&feeAccount, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(journal models.Journal) (*models.Journal, error) {
+		assert.Equal(t, enum.JournalTypeReversal, journal.Type)
+		assert.Equal(t, request.Reason, journal.Description)
+		assert.Equal(t, []models.Posting{
+			{AccountId: receiver.Id, Amount: -request.Amount, Currency: money.DefaultCurrency},
+			{AccountId: sender.Id, Amount: request.Amount, Currency: money.DefaultCurrency},
+			{AccountId: feeAccount.Id, Amount: -feeEntry.Amount, Currency: money.DefaultCurrency},
+			{AccountId: sender.Id, Amount: feeEntry.Amount, Currency: money.DefaultCurrency},
+		}, journal.Postings)
+		journal.Id = "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b33"
+		return &journal, nil
+	}).Times(1)
+	transferRepoMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(histories []models.TransferHistory) error {
+		assert.Len(t, histories, 2)
+		assert.Equal(t, receiver.AccountNumber, histories[0].From)
+		assert.Equal(t, sender.AccountNumber, histories[0].To)
+		assert.Equal(t, mockTransferEntry.Id, histories[0].ReversalOf)
+		assert.Equal(t, feeEntry.Id, histories[1].ReversalOf)
+		assert.True(t, histories[1].IsFee)
+		assert.Equal(t, request.Reference, histories[0].Reference)
+		assert.Equal(t, request.Reference, histories[1].Reference)
+		return nil
+	}).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		assert.Contains(t, content.Body, "40.00 TRY")
+		assert.Contains(t, content.Body, request.Reason)
+		return nil
+	}).Times(2)
+
+	response, err := s.ReverseTransfer(fiberCtx.Context(), request)
+	if err != nil {
+		t.Errorf("Error was not expected: %v", err)
+	}
+	assert.Equal(t, money.MustParse("40"), response.Amount)
+	assert.Equal(t, money.MustParse("4.22"), response.RefundedFee)
+	assert.Equal(t, money.MustParse("60"), response.RemainingAmount)
+}
+
+func TestAccountService_ReverseTransfer_ReceiverInsufficientBalance(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	sender := mockAccountData[0]
+	receiver := mockAccountData[1]
+	receiver.Balance = money.MustParse("100")
+
+	// The ledger checks the balance of the receiver again when the money is moved
+	transferRepoMock.EXPECT().LockById(mockTransferEntry.Id).Return(&mockTransferEntry, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(mockTransferEntry.Id).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(sender.AccountNumber).Return(&sender, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(receiver.AccountNumber).Return(&receiver, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(sender.Id, receiver.Id).Return([]models.Account{sender, receiver}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{receiver.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+	ledgerRepoMock.EXPECT().Post(gomock.Any()).Return(nil, dbrepository.ErrInsufficientBalance).Times(1)
+
+	_, err := s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "Fraud",
+		Reference:         "TICKET-4711",
+	})
+	assert.EqualError(t, err, messages.ReceiverInsufficientBalance)
+}
+
+func TestAccountService_ReverseTransfer_ReceiverHeld(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	sender := mockAccountData[0]
+	receiver := mockAccountData[1]
+	receiver.Balance = money.MustParse("150")
+
+	// 60 of the balance is held by a legal hold, the rest is not enough for the reversal
+	transferRepoMock.EXPECT().LockById(mockTransferEntry.Id).Return(&mockTransferEntry, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(mockTransferEntry.Id).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(sender.AccountNumber).Return(&sender, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(receiver.AccountNumber).Return(&receiver, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(sender.Id, receiver.Id).Return([]models.Account{sender, receiver}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{receiver.Id}, gomock.Any()).Return(map[string]money.Amount{receiver.Id: money.MustParse("60")}, nil).Times(1)
+
+	_, err := s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "Fraud",
+		Reference:         "TICKET-4711",
+	})
+	assert.EqualError(t, err, messages.ReceiverInsufficientBalance)
+}
+
+func TestAccountService_ReverseTransfer_ReceiverOverdrawn(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	sender := mockAccountData[0]
+	receiver := mockAccountData[1]
+	receiver.Balance = money.MustParse("-5")
+	receiver.OverdraftLimit = money.MustParse("500")
+
+	// The ledger would allow the reversal within the overdraft, the receiver does not have the money though
+	transferRepoMock.EXPECT().LockById(mockTransferEntry.Id).Return(&mockTransferEntry, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(mockTransferEntry.Id).Return(nil, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(sender.AccountNumber).Return(&sender, nil).Times(1)
+	accountRepoMock.EXPECT().FindByAccountNumber(receiver.AccountNumber).Return(&receiver, nil).Times(1)
+	accountRepoMock.EXPECT().Lock(sender.Id, receiver.Id).Return([]models.Account{sender, receiver}, nil).Times(1)
+	holdRepoMock.EXPECT().SumActive([]string{receiver.Id}, gomock.Any()).Return(map[string]money.Amount{}, nil).Times(1)
+
+	_, err := s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "Fraud",
+		Amount:            money.MustParse("10"),
+		Reference:         "TICKET-4711",
+	})
+	assert.EqualError(t, err, messages.ReceiverInsufficientBalance)
+}
+
+func TestAccountService_ReverseTransfer_ForceNotAllowed(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	t.Setenv("REVERSAL_ALLOW_FORCE", "false")
+
+	_, err := s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "Fraud",
+		Reference:         "TICKET-4711",
+		Force:             true,
+	})
+	assert.EqualError(t, err, messages.ReversalForceNotAllowed)
+
+	_, err = s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "  ",
+	})
+	assert.EqualError(t, err, messages.ReversalReasonRequired)
+
+	_, err = s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Reason:            "Fraud",
+	})
+	assert.EqualError(t, err, messages.ReversalReferenceRequired)
+}
+
+func TestAccountService_ReverseTransfer_ReferenceUsed(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+
+	// A retry of a partial reversal without the idempotency key does not give back the money again
+	reversal := models.TransferHistory{
+		Id:              "e7e1b1b0-7f46-4b6d-8b0d-3b6f1b4f1b34",
+		Amount:          money.MustParse("40"),
+		ConvertedAmount: money.MustParse("40"),
+		Type:            enum.JournalTypeReversal,
+		ReversalOf:      mockTransferEntry.Id,
+		Reference:       "TICKET-4711",
+	}
+
+	transferRepoMock.EXPECT().LockById(mockTransferEntry.Id).Return(&mockTransferEntry, nil).Times(1)
+	transferRepoMock.EXPECT().FindReversals(mockTransferEntry.Id).Return([]models.TransferHistory{reversal}, nil).Times(1)
+
+	_, err := s.ReverseTransfer(fiberCtx.Context(), dto.ReverseTransferRequest{
+		TransferHistoryId: mockTransferEntry.Id,
+		Amount:            money.MustParse("40"),
+		Reason:            "Wrong receiver",
+		Reference:         " TICKET-4711 ",
+	})
+	assert.EqualError(t, err, messages.ReversalReferenceUsed)
+}
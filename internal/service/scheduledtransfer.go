@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/calendar"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+// dateLayout is the layout of the dates of scheduled transfers in requests and responses
+const dateLayout = "2006-01-02"
+
+type ScheduledTransferService interface {
+	Create(ctx context.Context, request dto.CreateScheduledTransferRequest) (*dto.ScheduledTransferItem, error)
+	List(ctx context.Context) ([]dto.ScheduledTransferItem, error)
+	Get(ctx context.Context, id string) (*dto.ScheduledTransferItem, error)
+	Update(ctx context.Context, request dto.UpdateScheduledTransferRequest) (*dto.ScheduledTransferItem, error)
+	Cancel(ctx context.Context, id string) error
+
+	FindDue(now time.Time, limit int) ([]string, error)
+	Run(ctx context.Context, id string, now time.Time) error
+
+	WithTx(trxHandle *gorm.DB) ScheduledTransferService
+}
+
+type scheduledTransferService struct {
+	scheduledTransferRepository repository.ScheduledTransferRepository
+	accountRepository           repository.AccountRepository
+	accountService              AccountService
+
+	// Transaction of the service, Run executes the transfer in a savepoint of it
+	tx *gorm.DB
+}
+
+func NewScheduledTransferService(
+	scheduledTransferRepository repository.ScheduledTransferRepository,
+	accountRepository repository.AccountRepository,
+	accountService AccountService,
+) ScheduledTransferService {
+	return &scheduledTransferService{
+		scheduledTransferRepository: scheduledTransferRepository,
+		accountRepository:           accountRepository,
+		accountService:              accountService,
+	}
+}
+
+func (s *scheduledTransferService) WithTx(trxHandle *gorm.DB) ScheduledTransferService {
+	clone := *s
+	clone.scheduledTransferRepository = s.scheduledTransferRepository.WithTx(trxHandle)
+	clone.accountRepository = s.accountRepository.WithTx(trxHandle)
+	clone.accountService = s.accountService.WithTx(trxHandle)
+	clone.tx = trxHandle
+	return &clone
+}
+
+// Create schedules a transfer from an account of the current user, the first occurrence is on the start date
+func (s *scheduledTransferService) Create(ctx context.Context, request dto.CreateScheduledTransferRequest) (*dto.ScheduledTransferItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	if !request.Amount.IsPositive() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+
+	switch request.Frequency {
+	case enum.FrequencyOnce, enum.FrequencyDaily, enum.FrequencyWeekly, enum.FrequencyMonthly:
+	default:
+		return nil, errors.New(messages.InvalidSchedule)
+	}
+
+	if request.NonBusinessDay == "" {
+		request.NonBusinessDay = enum.NonBusinessDayShift
+	}
+	if request.NonBusinessDay != enum.NonBusinessDayShift && request.NonBusinessDay != enum.NonBusinessDaySkip {
+		return nil, errors.New(messages.InvalidSchedule)
+	}
+
+	startDate, err := time.ParseInLocation(dateLayout, request.StartDate, time.Local)
+	if err != nil || startDate.Before(calendar.StartOfDay(time.Now())) {
+		return nil, errors.New(messages.InvalidSchedule)
+	}
+
+	var endDate *time.Time
+	if request.EndDate != "" {
+		date, err := time.ParseInLocation(dateLayout, request.EndDate, time.Local)
+		if err != nil || date.Before(startDate) {
+			return nil, errors.New(messages.InvalidSchedule)
+		}
+		endDate = &date
+	}
+
+	senderAccount, err := s.accountRepository.FindByAccountNumber(request.FromAccountNumber)
+	if err != nil {
+		return nil, errors.New(messages.AccountNotFound)
+	}
+
+	if senderAccount.OwnerId != currentUser.Id {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	receiverAccount, err := findReceiverAccount(s.accountRepository, request.ToAccountNumber, request.ToIBAN)
+	if err != nil {
+		return nil, err
+	}
+
+	// Freezes may be lifted until the transfer runs, closed accounts stay closed
+	if err := checkOpen(senderAccount); err != nil {
+		return nil, err
+	}
+	if err := checkOpen(receiverAccount); err != nil {
+		return nil, err
+	}
+
+	scheduledTransfer := models.ScheduledTransfer{
+		OwnerId:           currentUser.Id,
+		FromAccountNumber: senderAccount.AccountNumber,
+		ToAccountNumber:   receiverAccount.AccountNumber,
+		Amount:            request.Amount,
+		Note:              request.Note,
+		Frequency:         request.Frequency,
+		DayOfMonth:        startDate.Day(),
+		NonBusinessDay:    request.NonBusinessDay,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Status:            enum.ScheduledTransferActive,
+		CreatedBy:         currentUser.Id,
+		UpdatedBy:         currentUser.Id,
+	}
+	planRun(&scheduledTransfer, startDate)
+
+	// A skipped single transfer or a schedule without a business day before its end never runs
+	if scheduledTransfer.Status != enum.ScheduledTransferActive {
+		return nil, errors.New(messages.InvalidSchedule)
+	}
+
+	created, err := s.scheduledTransferRepository.Create(scheduledTransfer)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := scheduledTransferItem(*created)
+	return &item, nil
+}
+
+// List returns the scheduled transfers of the current user including the finished ones
+func (s *scheduledTransferService) List(ctx context.Context) ([]dto.ScheduledTransferItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	scheduledTransfers, err := s.scheduledTransferRepository.FindByOwnerId(currentUser.Id)
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	items := []dto.ScheduledTransferItem{}
+	for _, scheduledTransfer := range scheduledTransfers {
+		items = append(items, scheduledTransferItem(scheduledTransfer))
+	}
+
+	return items, nil
+}
+
+func (s *scheduledTransferService) Get(ctx context.Context, id string) (*dto.ScheduledTransferItem, error) {
+	scheduledTransfer, err := s.ownedScheduledTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	item := scheduledTransferItem(*scheduledTransfer)
+	return &item, nil
+}
+
+// Update changes the amount, the note or the end date of an active scheduled transfer
+func (s *scheduledTransferService) Update(ctx context.Context, request dto.UpdateScheduledTransferRequest) (*dto.ScheduledTransferItem, error) {
+	scheduledTransfer, err := s.ownedScheduledTransfer(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduledTransfer.Status != enum.ScheduledTransferActive {
+		return nil, errors.New(messages.ScheduledTransferNotActive)
+	}
+
+	if request.Amount.IsNegative() {
+		return nil, errors.New(messages.InvalidAmount)
+	}
+	if request.Amount.IsPositive() {
+		scheduledTransfer.Amount = request.Amount
+	}
+
+	if request.Note != nil {
+		scheduledTransfer.Note = *request.Note
+	}
+
+	if request.EndDate != nil {
+		scheduledTransfer.EndDate = nil
+		if *request.EndDate != "" {
+			endDate, err := time.ParseInLocation(dateLayout, *request.EndDate, time.Local)
+			if err != nil || endDate.Before(localDate(scheduledTransfer.StartDate)) {
+				return nil, errors.New(messages.InvalidSchedule)
+			}
+			scheduledTransfer.EndDate = &endDate
+		}
+
+		// The planned occurrence may be after the new end date
+		if scheduledTransfer.EndDate != nil && localDate(scheduledTransfer.NextRunDate).After(*scheduledTransfer.EndDate) {
+			scheduledTransfer.Status = enum.ScheduledTransferCompleted
+		}
+	}
+
+	currentUser, _ := authware.GetCurrentUser(ctx)
+	scheduledTransfer.UpdatedBy = currentUser.Id
+
+	if err := s.scheduledTransferRepository.Update(*scheduledTransfer); err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	item := scheduledTransferItem(*scheduledTransfer)
+	return &item, nil
+}
+
+// Cancel stops an active scheduled transfer, the transfers which already ran are not touched
+func (s *scheduledTransferService) Cancel(ctx context.Context, id string) error {
+	scheduledTransfer, err := s.ownedScheduledTransfer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if scheduledTransfer.Status != enum.ScheduledTransferActive {
+		return errors.New(messages.ScheduledTransferNotActive)
+	}
+
+	currentUser, _ := authware.GetCurrentUser(ctx)
+	scheduledTransfer.Status = enum.ScheduledTransferCancelled
+	scheduledTransfer.UpdatedBy = currentUser.Id
+
+	if err := s.scheduledTransferRepository.Update(*scheduledTransfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// FindDue returns the ids of the scheduled transfers which should run until now
+func (s *scheduledTransferService) FindDue(now time.Time, limit int) ([]string, error) {
+	return s.scheduledTransferRepository.FindDueIds(now, limit)
+}
+
+// Run executes the due occurrence of the scheduled transfer and plans the next one. It must be called
+// with a transaction, the transfer runs in a savepoint so a failed attempt is recorded without its changes.
+// A transfer which is not due anymore or is being run by another worker is skipped.
+func (s *scheduledTransferService) Run(ctx context.Context, id string, now time.Time) error {
+	if s.tx == nil {
+		return errors.New(messages.TransactionFailed)
+	}
+
+	scheduledTransfer, err := s.scheduledTransferRepository.LockDue(id, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	request := dto.TransferMoneyRequest{
+		Note:              scheduledTransfer.Note,
+		Amount:            scheduledTransfer.Amount,
+		FromAccountNumber: scheduledTransfer.FromAccountNumber,
+		ToAccountNumber:   scheduledTransfer.ToAccountNumber,
+	}
+
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		return s.accountService.WithTx(tx).ExecuteTransfer(ctx, scheduledTransfer.OwnerId, request)
+	})
+
+	recordRun(scheduledTransfer, now, err)
+
+	return s.scheduledTransferRepository.Update(*scheduledTransfer)
+}
+
+// ownedScheduledTransfer returns the scheduled transfer if it belongs to the current user,
+// scheduled transfers of other users are not found
+func (s *scheduledTransferService) ownedScheduledTransfer(ctx context.Context, id string) (*models.ScheduledTransfer, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	scheduledTransfer, err := s.scheduledTransferRepository.FindById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && scheduledTransfer.OwnerId != currentUser.Id) {
+		return nil, errors.New(messages.ScheduledTransferNotFound)
+	}
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	return scheduledTransfer, nil
+}
+
+// recordRun records the result of an attempt. A missing balance, a frozen account or an unexpected error is tried
+// again later until the attempts run out, other errors like a closed account mean the transfer can never succeed.
+func recordRun(scheduledTransfer *models.ScheduledTransfer, now time.Time, err error) {
+	scheduledTransfer.LastRunAt = &now
+	scheduledTransfer.Attempts++
+
+	switch {
+	case err == nil:
+		scheduledTransfer.LastError = ""
+		planNextRun(scheduledTransfer)
+	case err.Error() == messages.InSufficientBalance || err.Error() == messages.UnexpectedError || isLimitExceeded(err) ||
+		err.Error() == messages.AccountFrozen:
+		// The balance, the limits or the freeze may allow the transfer later, a recurring transfer is not stopped by them
+		scheduledTransfer.LastError = err.Error()
+		if scheduledTransfer.Attempts < enum.ScheduledTransferMaxAttempts {
+			scheduledTransfer.NextRunAt = now.Add(enum.ScheduledTransferRetryInterval)
+		} else if scheduledTransfer.Frequency == enum.FrequencyOnce {
+			scheduledTransfer.Status = enum.ScheduledTransferFailed
+		} else {
+			// The occurrence is given up, the recurring transfer goes on with the next one
+			planNextRun(scheduledTransfer)
+		}
+	default:
+		scheduledTransfer.LastError = err.Error()
+		scheduledTransfer.Status = enum.ScheduledTransferFailed
+	}
+}
+
+// planNextRun plans the occurrence after the current one, the scheduled transfer is completed if there is none
+func planNextRun(scheduledTransfer *models.ScheduledTransfer) {
+	next, ok := calendar.Next(scheduledTransfer.Frequency, scheduledTransfer.NextRunDate, scheduledTransfer.DayOfMonth)
+	if !ok {
+		scheduledTransfer.Status = enum.ScheduledTransferCompleted
+		return
+	}
+	planRun(scheduledTransfer, next)
+}
+
+// planRun plans the occurrence on the date. An occurrence on a weekend is shifted to the next business day
+// or skipped for the following occurrence, the scheduled transfer is completed when the end date is passed.
+func planRun(scheduledTransfer *models.ScheduledTransfer, date time.Time) {
+	date = localDate(date)
+
+	for {
+		if scheduledTransfer.EndDate != nil && date.After(localDate(*scheduledTransfer.EndDate)) {
+			scheduledTransfer.Status = enum.ScheduledTransferCompleted
+			return
+		}
+
+		if calendar.IsBusinessDay(date) || scheduledTransfer.NonBusinessDay == enum.NonBusinessDayShift {
+			break
+		}
+
+		next, ok := calendar.Next(scheduledTransfer.Frequency, date, scheduledTransfer.DayOfMonth)
+		if !ok {
+			scheduledTransfer.Status = enum.ScheduledTransferCompleted
+			return
+		}
+		date = next
+	}
+
+	scheduledTransfer.Status = enum.ScheduledTransferActive
+	scheduledTransfer.NextRunDate = date
+	scheduledTransfer.NextRunAt = calendar.NextBusinessDay(date)
+	scheduledTransfer.Attempts = 0
+}
+
+// localDate returns midnight of the date in the location of the bank, dates are read from the database in UTC
+func localDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func scheduledTransferItem(scheduledTransfer models.ScheduledTransfer) dto.ScheduledTransferItem {
+	item := dto.ScheduledTransferItem{
+		Id:                scheduledTransfer.Id,
+		FromAccountNumber: scheduledTransfer.FromAccountNumber,
+		ToAccountNumber:   scheduledTransfer.ToAccountNumber,
+		Amount:            scheduledTransfer.Amount,
+		Note:              scheduledTransfer.Note,
+		Frequency:         scheduledTransfer.Frequency,
+		NonBusinessDay:    scheduledTransfer.NonBusinessDay,
+		StartDate:         scheduledTransfer.StartDate.Format(dateLayout),
+		Status:            scheduledTransfer.Status,
+		Attempts:          scheduledTransfer.Attempts,
+		LastRunAt:         scheduledTransfer.LastRunAt,
+		LastError:         scheduledTransfer.LastError,
+	}
+
+	if scheduledTransfer.EndDate != nil {
+		item.EndDate = scheduledTransfer.EndDate.Format(dateLayout)
+	}
+
+	if scheduledTransfer.Status == enum.ScheduledTransferActive {
+		item.NextRunAt = &scheduledTransfer.NextRunAt
+	}
+
+	return item
+}
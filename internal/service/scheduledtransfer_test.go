@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestPlanRun_ShiftsWeekendToMonday(t *testing.T) {
+	scheduledTransfer := &models.ScheduledTransfer{Frequency: enum.FrequencyMonthly, DayOfMonth: 1, NonBusinessDay: enum.NonBusinessDayShift}
+
+	// 1 June 2024 is a Saturday
+	planRun(scheduledTransfer, date(2024, time.June, 1))
+
+	assert.Equal(t, enum.ScheduledTransferActive, scheduledTransfer.Status)
+	assert.Equal(t, date(2024, time.June, 1), scheduledTransfer.NextRunDate)
+	assert.Equal(t, date(2024, time.June, 3), scheduledTransfer.NextRunAt)
+}
+
+func TestPlanRun_SkipsWeekend(t *testing.T) {
+	scheduledTransfer := &models.ScheduledTransfer{Frequency: enum.FrequencyMonthly, DayOfMonth: 1, NonBusinessDay: enum.NonBusinessDaySkip}
+
+	planRun(scheduledTransfer, date(2024, time.June, 1))
+
+	assert.Equal(t, enum.ScheduledTransferActive, scheduledTransfer.Status)
+	assert.Equal(t, date(2024, time.July, 1), scheduledTransfer.NextRunDate)
+	assert.Equal(t, date(2024, time.July, 1), scheduledTransfer.NextRunAt)
+}
+
+func TestPlanRun_CompletesAfterEndDate(t *testing.T) {
+	endDate := date(2024, time.June, 30)
+	scheduledTransfer := &models.ScheduledTransfer{Frequency: enum.FrequencyMonthly, DayOfMonth: 1, EndDate: &endDate}
+
+	planRun(scheduledTransfer, date(2024, time.July, 1))
+
+	assert.Equal(t, enum.ScheduledTransferCompleted, scheduledTransfer.Status)
+}
+
+func TestRecordRun_PlansNextOccurrence(t *testing.T) {
+	now := date(2024, time.January, 31).Add(time.Minute)
+	scheduledTransfer := &models.ScheduledTransfer{
+		Frequency:      enum.FrequencyMonthly,
+		DayOfMonth:     31,
+		NonBusinessDay: enum.NonBusinessDayShift,
+		Status:         enum.ScheduledTransferActive,
+		NextRunDate:    date(2024, time.January, 31),
+		Attempts:       1,
+	}
+
+	recordRun(scheduledTransfer, now, nil)
+
+	// February is shorter, the transfer runs on its last day
+	assert.Equal(t, enum.ScheduledTransferActive, scheduledTransfer.Status)
+	assert.Equal(t, date(2024, time.February, 29), scheduledTransfer.NextRunDate)
+	assert.Equal(t, 0, scheduledTransfer.Attempts)
+	assert.Equal(t, &now, scheduledTransfer.LastRunAt)
+}
+
+func TestRecordRun_RetriesInsufficientBalance(t *testing.T) {
+	now := date(2024, time.January, 31).Add(time.Minute)
+	scheduledTransfer := &models.ScheduledTransfer{
+		Frequency:   enum.FrequencyOnce,
+		Status:      enum.ScheduledTransferActive,
+		NextRunDate: date(2024, time.January, 31),
+	}
+
+	recordRun(scheduledTransfer, now, errors.New(messages.InSufficientBalance))
+
+	assert.Equal(t, enum.ScheduledTransferActive, scheduledTransfer.Status)
+	assert.Equal(t, now.Add(enum.ScheduledTransferRetryInterval), scheduledTransfer.NextRunAt)
+	assert.Equal(t, messages.InSufficientBalance, scheduledTransfer.LastError)
+
+	// The single transfer is given up after the last attempt
+	scheduledTransfer.Attempts = enum.ScheduledTransferMaxAttempts - 1
+	recordRun(scheduledTransfer, now, errors.New(messages.InSufficientBalance))
+
+	assert.Equal(t, enum.ScheduledTransferFailed, scheduledTransfer.Status)
+}
+
+func TestRecordRun_FailsOnPermanentError(t *testing.T) {
+	scheduledTransfer := &models.ScheduledTransfer{Frequency: enum.FrequencyDaily, Status: enum.ScheduledTransferActive}
+
+	recordRun(scheduledTransfer, time.Now(), errors.New(messages.AccountNotFound))
+
+	assert.Equal(t, enum.ScheduledTransferFailed, scheduledTransfer.Status)
+	assert.Equal(t, messages.AccountNotFound, scheduledTransfer.LastError)
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"tek-bank/pkg/pdf"
+	"time"
+	"unicode/utf8"
+)
+
+// maxStatementDays is the longest period a statement can be generated for
+const maxStatementDays = 366
+
+// statementTimeLayout is how the times of the statement lines are written
+const statementTimeLayout = "2006-01-02 15:04"
+
+// statementTypes are the labels of the journal types on statements
+var statementTypes = map[string]string{
+	enum.JournalTypeDeposit:           messages.StatementDeposit,
+	enum.JournalTypeTransfer:          messages.StatementTransfer,
+	enum.JournalTypeWithdrawal:        messages.StatementWithdrawal,
+	enum.JournalTypeReversal:          messages.StatementReversal,
+	enum.JournalTypeSweep:             messages.StatementSweep,
+	enum.JournalTypeInterest:          messages.StatementInterest,
+	enum.JournalTypeOverdraftInterest: messages.StatementOverdraftInterest,
+}
+
+var statementContentTypes = map[string]string{
+	enum.StatementFormatCSV: "text/csv",
+	enum.StatementFormatPDF: "application/pdf",
+}
+
+// statementLine is a posting of the account with the balance after it
+type statementLine struct {
+	Time         time.Time
+	Type         string
+	Description  string
+	Counterparty string
+	Amount       money.Amount
+	Balance      money.Amount
+	IsFee        bool
+}
+
+// statement is an account statement for the days between From and To, both are inclusive
+type statement struct {
+	Account        models.Account
+	From           time.Time
+	To             time.Time
+	OpeningBalance money.Amount
+	ClosingBalance money.Amount
+	Lines          []statementLine
+}
+
+// buildStatement writes a line for every posting of the account with the running balance. The lines are described with
+// the history entries of their journals, postings without an entry like deposits are described by their journal.
+func buildStatement(account models.Account, from, to time.Time, openingBalance money.Amount, postings []repository.AccountPosting, entries []models.TransferHistory, language string) statement {
+	result := statement{
+		Account:        account,
+		From:           from,
+		To:             to,
+		OpeningBalance: openingBalance,
+		Lines:          []statementLine{},
+	}
+
+	used := make(map[string]bool)
+	balance := openingBalance
+	for _, posting := range postings {
+		balance += posting.Amount
+
+		line := statementLine{
+			Time:        posting.CreatedAt,
+			Type:        posting.JournalType,
+			Description: posting.Description,
+			Amount:      posting.Amount,
+			Balance:     balance,
+		}
+		if key, ok := statementTypes[posting.JournalType]; ok {
+			line.Type = i18n.CreateMsgWithLanguage(language, key)
+		}
+
+		if entry := matchHistoryEntry(posting, entries, account.AccountNumber, used); entry != nil {
+			item := historyItem(*entry, account.AccountNumber)
+			line.Counterparty = item.CounterpartyName
+			if item.CounterpartyAccountNumber != 0 {
+				line.Counterparty = fmt.Sprintf("%s (%d)", item.CounterpartyName, item.CounterpartyAccountNumber)
+			}
+
+			switch {
+			case entry.IsFee && entry.ReversalOf != "":
+				line.Type = i18n.CreateMsgWithLanguage(language, messages.StatementFeeRefund)
+				line.Description = entry.FeeRule
+				line.IsFee = true
+			case entry.IsFee:
+				line.Type = i18n.CreateMsgWithLanguage(language, messages.StatementFee)
+				line.Description = entry.FeeRule
+				line.IsFee = true
+			case entry.ReversalOf != "":
+				line.Description = entry.Reason
+			case entry.Note != "":
+				line.Description = entry.Note
+			}
+		}
+
+		result.Lines = append(result.Lines, line)
+	}
+
+	result.ClosingBalance = balance
+	return result
+}
+
+// statementCSV writes the statement as CSV, the first rows are the account and the period
+func statementCSV(s statement, language string) ([]byte, error) {
+	label := func(key string) string {
+		return i18n.CreateMsgWithLanguage(language, key)
+	}
+
+	rows := [][]string{
+		{label(messages.StatementTitle)},
+		{label(messages.StatementAccount), fmt.Sprint(s.Account.AccountNumber), s.Account.IBAN},
+		{label(messages.StatementCurrency), s.Account.Currency.String()},
+		{label(messages.StatementPeriod), s.From.Format(dateLayout), s.To.Format(dateLayout)},
+		{},
+		{label(messages.StatementDate), label(messages.StatementType), label(messages.StatementDescription), label(messages.StatementCounterparty), label(messages.StatementAmount), label(messages.StatementBalance)},
+		{s.From.Format(dateLayout), "", label(messages.StatementOpeningBalance), "", "", s.OpeningBalance.String()},
+	}
+
+	for _, line := range s.Lines {
+		rows = append(rows, []string{line.Time.Format(statementTimeLayout), line.Type, line.Description, line.Counterparty, line.Amount.String(), line.Balance.String()})
+	}
+
+	rows = append(rows, []string{s.To.Format(dateLayout), "", label(messages.StatementClosingBalance), "", "", s.ClosingBalance.String()})
+
+	var buffer bytes.Buffer
+	if err := csv.NewWriter(&buffer).WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// statementPDF writes the statement as a PDF table, the columns are aligned with the monospaced font
+func statementPDF(s statement, language string) []byte {
+	label := func(key string) string {
+		return i18n.CreateMsgWithLanguage(language, key)
+	}
+
+	row := func(date, lineType, description, counterparty, amount, balance string) string {
+		return strings.Join([]string{
+			padRight(date, 16), padRight(lineType, 11), padRight(description, 22), padRight(counterparty, 17), padLeft(amount, 12), padLeft(balance, 12),
+		}, " ")
+	}
+
+	document := pdf.New()
+	document.BoldLine(label(messages.StatementTitle))
+	document.Line("")
+	document.Line(fmt.Sprintf("%s: %d  %s", label(messages.StatementAccount), s.Account.AccountNumber, s.Account.IBAN))
+	document.Line(fmt.Sprintf("%s: %s", label(messages.StatementCurrency), s.Account.Currency))
+	document.Line(fmt.Sprintf("%s: %s - %s", label(messages.StatementPeriod), s.From.Format(dateLayout), s.To.Format(dateLayout)))
+	document.Line("")
+
+	document.BoldLine(row(label(messages.StatementDate), label(messages.StatementType), label(messages.StatementDescription), label(messages.StatementCounterparty), label(messages.StatementAmount), label(messages.StatementBalance)))
+	document.Line(row(s.From.Format(dateLayout), "", label(messages.StatementOpeningBalance), "", "", s.OpeningBalance.String()))
+
+	for _, line := range s.Lines {
+		document.Line(row(line.Time.Format(statementTimeLayout), line.Type, line.Description, line.Counterparty, line.Amount.String(), line.Balance.String()))
+	}
+
+	document.BoldLine(row(s.To.Format(dateLayout), "", label(messages.StatementClosingBalance), "", "", s.ClosingBalance.String()))
+
+	return document.Bytes()
+}
+
+// padRight cuts the text to the width or fills it up with spaces
+func padRight(text string, width int) string {
+	if utf8.RuneCountInString(text) > width {
+		return string([]rune(text)[:width])
+	}
+	return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+}
+
+// padLeft right-aligns the text in the width
+func padLeft(text string, width int) string {
+	if utf8.RuneCountInString(text) >= width {
+		return text
+	}
+	return strings.Repeat(" ", width-utf8.RuneCountInString(text)) + text
+}
+
+// statementPeriod returns the days of the statement, the last month is used without them
+func statementPeriod(request dto.StatementRequest, now time.Time) (time.Time, time.Time, error) {
+	year, month, _ := now.Date()
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	from := firstOfMonth.AddDate(0, -1, 0)
+	to := firstOfMonth.AddDate(0, 0, -1)
+
+	var err error
+	if request.From != "" {
+		from, err = time.ParseInLocation(dateLayout, request.From, time.Local)
+		if err != nil {
+			return from, to, errors.New(messages.InvalidDateRange)
+		}
+	}
+	if request.To != "" {
+		to, err = time.ParseInLocation(dateLayout, request.To, time.Local)
+		if err != nil {
+			return from, to, errors.New(messages.InvalidDateRange)
+		}
+	}
+
+	if from.After(to) || to.Sub(from) >= maxStatementDays*24*time.Hour {
+		return from, to, errors.New(messages.InvalidDateRange)
+	}
+
+	return from, to, nil
+}
+
+type StatementService interface {
+	Download(ctx context.Context, request dto.StatementRequest) (*dto.StatementFile, error)
+	Email(ctx context.Context, request dto.StatementRequest) error
+}
+
+type statementService struct {
+	accountRepository         repository.AccountRepository
+	transferHistoryRepository repository.TransferHistoryRepository
+	ledgerRepository          repository.LedgerRepository
+	pkgMailer                 gomailer.Mailer
+}
+
+func NewStatementService(
+	accountRepository repository.AccountRepository,
+	transferHistoryRepository repository.TransferHistoryRepository,
+	ledgerRepository repository.LedgerRepository,
+	pkgMailer gomailer.Mailer,
+) StatementService {
+	return &statementService{
+		accountRepository:         accountRepository,
+		transferHistoryRepository: transferHistoryRepository,
+		ledgerRepository:          ledgerRepository,
+		pkgMailer:                 pkgMailer,
+	}
+}
+
+// Download returns the statement of an account of the current user as CSV or PDF
+func (s *statementService) Download(ctx context.Context, request dto.StatementRequest) (*dto.StatementFile, error) {
+	file, _, err := s.generate(ctx, request)
+	return file, err
+}
+
+// Email sends the statement of an account of the current user to the e-mail address of the user as an attachment
+func (s *statementService) Email(ctx context.Context, request dto.StatementRequest) error {
+	file, account, err := s.generate(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	// The mailer attaches files, so the statement is written to a temporary directory until it is sent
+	dir, err := os.MkdirTemp("", "statement")
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, file.Name)
+	if err := os.WriteFile(path, file.Content, 0600); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	from, to, _ := statementPeriod(request, time.Now())
+	err = s.pkgMailer.Send(gomailer.Content{
+		Subject: i18n.CreateMsgWithLanguage(request.Language, messages.StatementMailSubject),
+		Body: i18n.CreateMsgWithLanguage(request.Language, messages.StatementMailBody, map[string]string{
+			"AccountNumber": fmt.Sprint(account.AccountNumber),
+			"From":          from.Format(dateLayout),
+			"To":            to.Format(dateLayout),
+		}),
+		To:          []string{account.Owner.Email},
+		Attachments: []string{path},
+	})
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+func (s *statementService) generate(ctx context.Context, request dto.StatementRequest) (*dto.StatementFile, *models.Account, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, nil, errors.New(messages.Unauthorized)
+	}
+
+	if request.Format == "" {
+		request.Format = enum.StatementFormatPDF
+	}
+	contentType, ok := statementContentTypes[request.Format]
+	if !ok {
+		return nil, nil, errors.New(messages.InvalidStatementFormat)
+	}
+
+	from, to, err := statementPeriod(request, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.accountRepository.FindByAccountNumber(request.AccountNumber)
+	if err != nil {
+		return nil, nil, errors.New(messages.AccountNotFound)
+	}
+
+	if account.OwnerId != currentUser.Id {
+		return nil, nil, errors.New(messages.Unauthorized)
+	}
+
+	openingBalance, err := s.ledgerRepository.BalanceAt(account.Id, from)
+	if err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
+	}
+
+	postings, err := s.ledgerRepository.FindPostings(account.Id, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
+	}
+
+	journalIds := []string{}
+	seen := make(map[string]bool)
+	for _, posting := range postings {
+		if !seen[posting.JournalId] {
+			seen[posting.JournalId] = true
+			journalIds = append(journalIds, posting.JournalId)
+		}
+	}
+
+	entries, err := s.transferHistoryRepository.FindByJournalIds(journalIds)
+	if err != nil {
+		return nil, nil, errors.New(messages.UnexpectedError)
+	}
+
+	result := buildStatement(*account, from, to, openingBalance, postings, entries, request.Language)
+
+	file := &dto.StatementFile{
+		Name:        fmt.Sprintf("statement-%d-%s-%s.%s", account.AccountNumber, from.Format(dateLayout), to.Format(dateLayout), request.Format),
+		ContentType: contentType,
+	}
+
+	if request.Format == enum.StatementFormatCSV {
+		file.Content, err = statementCSV(result, request.Language)
+		if err != nil {
+			return nil, nil, errors.New(messages.UnexpectedError)
+		}
+	} else {
+		file.Content = statementPDF(result, request.Language)
+	}
+
+	return file, account, nil
+}
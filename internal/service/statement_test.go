@@ -0,0 +1,123 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"os"
+	"strings"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	dbrepository "tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	pkgGomailer "tek-bank/pkg/gomailer"
+	"tek-bank/pkg/money"
+	"testing"
+	"time"
+)
+
+var mockStatementPostings = []dbrepository.AccountPosting{
+	{Id: "p1", JournalId: "j1", JournalType: enum.JournalTypeDeposit, Description: "Deposit", Amount: money.MustParse("100"), Currency: money.DefaultCurrency, CreatedAt: time.Date(2024, 1, 2, 9, 0, 0, 0, time.Local)},
+	{Id: "p2", JournalId: "j2", JournalType: enum.JournalTypeTransfer, Description: "Transfer", Amount: -money.MustParse("30"), Currency: money.DefaultCurrency, CreatedAt: time.Date(2024, 1, 5, 14, 30, 0, 0, time.Local)},
+	{Id: "p3", JournalId: "j2", JournalType: enum.JournalTypeTransfer, Description: "Transfer", Amount: -money.MustParse("4.22"), Currency: money.DefaultCurrency, CreatedAt: time.Date(2024, 1, 5, 14, 30, 0, 0, time.Local)},
+}
+
+var mockStatementEntries = []models.TransferHistory{
+	{Id: "h1", From: 1000000001, To: 1000000002, Amount: money.MustParse("30"), Currency: money.DefaultCurrency, Note: "Rent", Type: enum.JournalTypeTransfer, JournalId: "j2", ToAccount: mockAccountData[1]},
+	{Id: "h2", From: 1000000001, To: 2, Amount: money.MustParse("4.22"), Currency: money.DefaultCurrency, IsFee: true, FeeRule: "Standard: 4.22 TRY flat", Type: enum.JournalTypeTransfer, JournalId: "j2"},
+}
+
+func TestBuildStatement(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local)
+
+	result := buildStatement(mockAccountData[0], from, to, money.MustParse("50"), mockStatementPostings, mockStatementEntries, "en")
+
+	assert.Equal(t, money.MustParse("50"), result.OpeningBalance)
+	assert.Equal(t, money.MustParse("115.78"), result.ClosingBalance)
+	assert.Len(t, result.Lines, 3)
+
+	assert.Equal(t, "Deposit", result.Lines[0].Type)
+	assert.Equal(t, money.MustParse("150"), result.Lines[0].Balance)
+
+	assert.Equal(t, "Transfer", result.Lines[1].Type)
+	assert.Equal(t, "Rent", result.Lines[1].Description)
+	assert.Equal(t, "Jane Doe (1000000002)", result.Lines[1].Counterparty)
+	assert.Equal(t, money.MustParse("120"), result.Lines[1].Balance)
+
+	assert.Equal(t, "Fee", result.Lines[2].Type)
+	assert.Equal(t, "Standard: 4.22 TRY flat", result.Lines[2].Description)
+	assert.True(t, result.Lines[2].IsFee)
+	assert.Equal(t, money.MustParse("115.78"), result.Lines[2].Balance)
+
+	// The labels of the lines are in the language of the statement too
+	result = buildStatement(mockAccountData[0], from, to, money.MustParse("50"), mockStatementPostings, mockStatementEntries, "tr")
+	content, err := statementCSV(result, "tr")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, "Tarih,İşlem,Açıklama,Karşı taraf,Tutar,Bakiye", lines[5])
+	assert.Equal(t, "2024-01-01,,Açılış bakiyesi,,,50.00", lines[6])
+	assert.Equal(t, "2024-01-05 14:30,Havale,Rent,Jane Doe (1000000002),-30.00,120.00", lines[8])
+	assert.Equal(t, "2024-01-31,,Kapanış bakiyesi,,,115.78", lines[10])
+
+	assert.True(t, strings.HasPrefix(string(statementPDF(result, "en")), "%PDF-"))
+}
+
+func TestStatementPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.Local)
+
+	from, to, err := statementPeriod(dto.StatementRequest{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local), from)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), to)
+
+	_, _, err = statementPeriod(dto.StatementRequest{From: "2024-02-01", To: "2024-01-01"}, now)
+	assert.EqualError(t, err, messages.InvalidDateRange)
+
+	_, _, err = statementPeriod(dto.StatementRequest{From: "2022-12-31", To: "2024-01-01"}, now)
+	assert.EqualError(t, err, messages.InvalidDateRange)
+}
+
+func TestStatementService_Email(t *testing.T) {
+	teardown := setupAccountTest(t)
+	defer teardown()
+
+	authware.SetCurrentUser(fiberCtx, authware.CurrentUser{Id: mockData[0].Id})
+	statementService := NewStatementService(accountRepoMock, transferRepoMock, ledgerRepoMock, pkgMailerMock)
+
+	account := mockAccountData[0]
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+
+	accountRepoMock.EXPECT().FindByAccountNumber(account.AccountNumber).Return(&account, nil).Times(1)
+	ledgerRepoMock.EXPECT().BalanceAt(account.Id, from).Return(money.MustParse("50"), nil).Times(1)
+	ledgerRepoMock.EXPECT().FindPostings(account.Id, from, to).Return(mockStatementPostings, nil).Times(1)
+	transferRepoMock.EXPECT().FindByJournalIds([]string{"j1", "j2"}).Return(mockStatementEntries, nil).Times(1)
+	pkgMailerMock.EXPECT().Send(gomock.Any()).DoAndReturn(func(content pkgGomailer.Content) error {
+		assert.Equal(t, []string{mockData[0].Email}, content.To)
+		assert.Contains(t, content.Body, "2024-01-31")
+		assert.Len(t, content.Attachments, 1)
+		assert.True(t, strings.HasSuffix(content.Attachments[0], "statement-1000000001-2024-01-01-2024-01-31.csv"))
+
+		file, err := os.ReadFile(content.Attachments[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(file), "Closing balance")
+		return nil
+	}).Times(1)
+
+	err := statementService.Email(fiberCtx.Context(), dto.StatementRequest{
+		AccountNumber: account.AccountNumber,
+		Language:      "en",
+		From:          "2024-01-01",
+		To:            "2024-01-31",
+		Format:        "csv",
+	})
+	assert.NoError(t, err)
+
+	_, err = statementService.Download(fiberCtx.Context(), dto.StatementRequest{AccountNumber: account.AccountNumber, Format: "xlsx"})
+	assert.EqualError(t, err, messages.InvalidStatementFormat)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gorm.io/gorm"
+	"tek-bank/cmd/api/middleware/authware"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/db/repository"
+	"tek-bank/internal/dto"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+// transferTransitions are the statuses a transfer can move to from its current status,
+// executed, rejected, expired and cancelled transfers are final
+var transferTransitions = map[string][]string{
+	enum.TransferPendingApproval: {enum.TransferApproved, enum.TransferRejected, enum.TransferExpired, enum.TransferCancelled},
+	enum.TransferApproved:        {enum.TransferExecuted, enum.TransferRejected},
+}
+
+// transitionTransfer moves the transfer to the status and records the time of the change
+func transitionTransfer(transfer *models.Transfer, status string, now time.Time) error {
+	allowed := false
+	for _, next := range transferTransitions[transfer.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New(messages.TransferNotPending)
+	}
+
+	transfer.Status = status
+	switch status {
+	case enum.TransferApproved:
+		transfer.ApprovedAt = &now
+	case enum.TransferExecuted:
+		transfer.ExecutedAt = &now
+	case enum.TransferRejected:
+		transfer.RejectedAt = &now
+	case enum.TransferExpired:
+		transfer.ExpiredAt = &now
+	case enum.TransferCancelled:
+		transfer.CancelledAt = &now
+	}
+
+	return nil
+}
+
+// isTransferExpired reports whether the time to approve the transfer is over
+func isTransferExpired(transfer *models.Transfer, now time.Time) bool {
+	return transfer.ExpiresAt != nil && !now.Before(*transfer.ExpiresAt)
+}
+
+// hashToken returns the hash of an approval token or code, only the hash is stored
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func transferItem(transfer models.Transfer) dto.TransferItem {
+	return dto.TransferItem{
+		Id:                transfer.Id,
+		FromAccountNumber: transfer.FromAccountNumber,
+		ToAccountNumber:   transfer.ToAccountNumber,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency.String(),
+		ConvertedAmount:   transfer.ConvertedAmount,
+		ConvertedCurrency: transfer.ConvertedCurrency.String(),
+		ExchangeRate:      transfer.ExchangeRate,
+		TransactionFee:    transfer.TransactionFee,
+		FeeRule:           transfer.FeeRule,
+		Note:              transfer.Note,
+		Status:            transfer.Status,
+		ApprovalMethod:    transfer.ApprovalMethod,
+		BeneficiaryId:     transfer.BeneficiaryId,
+		ExpiresAt:         transfer.ExpiresAt,
+		CreatedAt:         transfer.CreatedAt,
+	}
+}
+
+type TransferService interface {
+	ListPending(ctx context.Context) ([]dto.TransferItem, error)
+	Cancel(ctx context.Context, id string) error
+	ExpirePending(now time.Time) (int64, error)
+
+	WithTx(trxHandle *gorm.DB) TransferService
+}
+
+type transferService struct {
+	transferRepository repository.TransferRepository
+	holdRepository     repository.HoldRepository
+}
+
+func NewTransferService(transferRepository repository.TransferRepository, holdRepository repository.HoldRepository) TransferService {
+	return &transferService{
+		transferRepository: transferRepository,
+		holdRepository:     holdRepository,
+	}
+}
+
+func (s *transferService) WithTx(trxHandle *gorm.DB) TransferService {
+	clone := *s
+	clone.transferRepository = s.transferRepository.WithTx(trxHandle)
+	clone.holdRepository = s.holdRepository.WithTx(trxHandle)
+	return &clone
+}
+
+// ListPending returns the transfers of the current user which are waiting for approval
+func (s *transferService) ListPending(ctx context.Context) ([]dto.TransferItem, error) {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.New(messages.Unauthorized)
+	}
+
+	transfers, err := s.transferRepository.FindPendingByOwnerId(currentUser.Id, time.Now())
+	if err != nil {
+		return nil, errors.New(messages.UnexpectedError)
+	}
+
+	items := []dto.TransferItem{}
+	for _, transfer := range transfers {
+		items = append(items, transferItem(transfer))
+	}
+
+	return items, nil
+}
+
+// Cancel cancels a transfer of the current user which is waiting for approval, its link can not be used anymore
+func (s *transferService) Cancel(ctx context.Context, id string) error {
+	currentUser, err := authware.GetCurrentUser(ctx)
+	if err != nil {
+		return errors.New(messages.Unauthorized)
+	}
+
+	// The rows of bulk transfers are cancelled with their bulk transfer
+	transfer, err := s.transferRepository.LockById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (transfer.OwnerId != currentUser.Id || transfer.BulkTransferId != "")) {
+		return errors.New(messages.TransferNotFound)
+	}
+	if err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	now := time.Now()
+	if err := transitionTransfer(transfer, enum.TransferCancelled, now); err != nil {
+		return err
+	}
+
+	transfer.UpdatedBy = currentUser.Id
+	if err := s.transferRepository.Update(*transfer); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	// The held money is available again
+	if err := s.holdRepository.ReleaseByTransferId(transfer.Id, enum.HoldReleased, "", now); err != nil {
+		return errors.New(messages.UnexpectedError)
+	}
+
+	return nil
+}
+
+// ExpirePending expires the transfers which were not approved in time. The holds which ran out, like the holds of
+// the expired transfers, are marked as expired too, they do not count for the available balances anymore anyway.
+// It runs in the transaction of the caller, so a transfer is expired together with its hold.
+func (s *transferService) ExpirePending(now time.Time) (int64, error) {
+	count, err := s.transferRepository.ExpirePending(now)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := s.holdRepository.ReleaseExpired(now); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tek-bank/internal/db/models"
+	"tek-bank/internal/i18n/messages"
+	"tek-bank/pkg/enum"
+)
+
+func TestTransitionTransfer_ValidChanges(t *testing.T) {
+	now := time.Now()
+	transfer := &models.Transfer{Status: enum.TransferPendingApproval}
+
+	assert.NoError(t, transitionTransfer(transfer, enum.TransferApproved, now))
+	assert.NoError(t, transitionTransfer(transfer, enum.TransferExecuted, now))
+
+	assert.Equal(t, enum.TransferExecuted, transfer.Status)
+	assert.Equal(t, &now, transfer.ApprovedAt)
+	assert.Equal(t, &now, transfer.ExecutedAt)
+}
+
+func TestTransitionTransfer_InvalidChanges(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+	}{
+		{enum.TransferPendingApproval, enum.TransferExecuted},
+		{enum.TransferExecuted, enum.TransferCancelled},
+		{enum.TransferExpired, enum.TransferApproved},
+		{enum.TransferCancelled, enum.TransferApproved},
+		{enum.TransferApproved, enum.TransferCancelled},
+	}
+
+	for _, test := range tests {
+		transfer := &models.Transfer{Status: test.from}
+		err := transitionTransfer(transfer, test.to, time.Now())
+
+		assert.EqualError(t, err, messages.TransferNotPending, "%s -> %s", test.from, test.to)
+		assert.Equal(t, test.from, transfer.Status)
+	}
+}
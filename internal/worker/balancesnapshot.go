@@ -0,0 +1,23 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewBalanceSnapshotWorker creates the worker which takes the snapshots of the balances at midnight,
+// the accounts which already have the snapshot of the last midnight are left out
+func NewBalanceSnapshotWorker(balanceService service.BalanceService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := balanceService.CreateSnapshots(now)
+		if err != nil {
+			log.Error("Balance snapshots could not be created", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d balance snapshots created", count)
+		}
+	})
+}
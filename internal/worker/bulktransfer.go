@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewBulkTransferWorker creates the worker which expires the bulk transfers that were not approved in time
+// and executes the rows of the approved ones, every row in its own transaction
+func NewBulkTransferWorker(db *gorm.DB, bulkTransferService service.BulkTransferService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := expireBulkTransfers(db, bulkTransferService, now)
+		if err != nil {
+			log.Error("Pending bulk transfers could not be expired", err)
+		} else if count > 0 {
+			log.Infof("%d pending bulk transfers expired", count)
+		}
+
+		ids, err := bulkTransferService.FindBulkTransferRows(batchSize)
+		if err != nil {
+			log.Error("Approved bulk transfer rows could not be found", err)
+			return
+		}
+
+		for _, id := range ids {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if err := executeBulkTransferRow(db, bulkTransferService, id, now); err != nil {
+				log.Errorf("Bulk transfer row %s could not be executed: %v", id, err)
+			}
+		}
+	})
+}
+
+func expireBulkTransfers(db *gorm.DB, bulkTransferService service.BulkTransferService, now time.Time) (int64, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	count, err := bulkTransferService.WithTx(tx).ExpireBulkTransfers(now, batchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return count, tx.Commit().Error
+}
+
+func executeBulkTransferRow(db *gorm.DB, bulkTransferService service.BulkTransferService, id string, now time.Time) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := bulkTransferService.WithTx(tx).ExecuteBulkTransferRow(id, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
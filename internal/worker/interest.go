@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewInterestWorker creates the worker which accrues the interest of the past days, pays the interest
+// of the past months and charges the overdraft interest of the past days. The payment and the charge
+// of every account run in their own transaction.
+func NewInterestWorker(db *gorm.DB, interestService service.InterestService, accountService service.AccountService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := interestService.Accrue(now)
+		if err != nil {
+			log.Error("Interest could not be accrued", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d interest accruals created", count)
+		}
+
+		ids, err := interestService.FindDue(now, batchSize)
+		if err != nil {
+			log.Error("Accounts with due interest could not be found", err)
+			return
+		}
+
+		for _, id := range ids {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if err := payInterest(db, accountService, id, now); err != nil {
+				log.Errorf("Interest of the account %s could not be paid: %v", id, err)
+			}
+		}
+
+		overdrawn, err := interestService.FindOverdrawn(now, batchSize)
+		if err != nil {
+			log.Error("Overdrawn accounts could not be found", err)
+			return
+		}
+
+		for _, day := range overdrawn {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if err := chargeOverdraftInterest(db, accountService, day.AccountId, day.Day, now); err != nil {
+				log.Errorf("Overdraft interest of the account %s could not be charged: %v", day.AccountId, err)
+			}
+		}
+	})
+}
+
+func payInterest(db *gorm.DB, accountService service.AccountService, accountId string, now time.Time) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := accountService.WithTx(tx).PayInterest(accountId, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func chargeOverdraftInterest(db *gorm.DB, accountService service.AccountService, accountId string, day time.Time, now time.Time) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := accountService.WithTx(tx).ChargeOverdraftInterest(accountId, day, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
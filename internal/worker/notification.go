@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewNotificationWorker creates the worker which sends the e-mails of the outbox, they are stored by the
+// transactions of the changes and sent only after the commit
+func NewNotificationWorker(db *gorm.DB, notificationService service.NotificationService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := sendNotifications(db, notificationService, now)
+		if err != nil {
+			log.Error("Notifications could not be sent", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d notifications sent", count)
+		}
+	})
+}
+
+func sendNotifications(db *gorm.DB, notificationService service.NotificationService, now time.Time) (int64, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	count, err := notificationService.WithTx(tx).SendPending(now, batchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return count, tx.Commit().Error
+}
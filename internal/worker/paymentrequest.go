@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewPaymentRequestExpiryWorker creates the worker which expires the payment requests that were not answered in time
+func NewPaymentRequestExpiryWorker(db *gorm.DB, paymentRequestService service.PaymentRequestService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := expirePaymentRequests(db, paymentRequestService, now)
+		if err != nil {
+			log.Error("Pending payment requests could not be expired", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d pending payment requests expired", count)
+		}
+	})
+}
+
+func expirePaymentRequests(db *gorm.DB, paymentRequestService service.PaymentRequestService, now time.Time) (int64, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	count, err := paymentRequestService.WithTx(tx).ExpirePending(now, batchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return count, tx.Commit().Error
+}
+
+// NewPaymentRequestSettlementWorker creates the worker which settles the paying payment requests once their transfers
+// are executed or failed
+func NewPaymentRequestSettlementWorker(db *gorm.DB, paymentRequestService service.PaymentRequestService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := settlePaymentRequests(db, paymentRequestService)
+		if err != nil {
+			log.Error("Paying payment requests could not be settled", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d paying payment requests settled", count)
+		}
+	})
+}
+
+func settlePaymentRequests(db *gorm.DB, paymentRequestService service.PaymentRequestService) (int64, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	count, err := paymentRequestService.WithTx(tx).SettlePaying(batchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return count, tx.Commit().Error
+}
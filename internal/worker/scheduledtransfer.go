@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// batchSize is the maximum number of scheduled transfers run in one tick
+const batchSize = 100
+
+// NewScheduledTransferWorker creates the worker which runs the due scheduled transfers,
+// every transfer in its own transaction
+func NewScheduledTransferWorker(db *gorm.DB, scheduledTransferService service.ScheduledTransferService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		ids, err := scheduledTransferService.FindDue(now, batchSize)
+		if err != nil {
+			log.Error("Due scheduled transfers could not be found", err)
+			return
+		}
+
+		for _, id := range ids {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if err := runScheduledTransfer(db, scheduledTransferService, id, now); err != nil {
+				log.Errorf("Scheduled transfer %s could not be run: %v", id, err)
+			}
+		}
+	})
+}
+
+func runScheduledTransfer(db *gorm.DB, scheduledTransferService service.ScheduledTransferService, id string, now time.Time) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := scheduledTransferService.WithTx(tx).Run(context.Background(), id, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
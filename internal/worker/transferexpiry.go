@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+	"tek-bank/internal/service"
+	"time"
+)
+
+// NewTransferExpiryWorker creates the worker which expires the transfers that were not approved in time
+func NewTransferExpiryWorker(db *gorm.DB, transferService service.TransferService, interval time.Duration) *Worker {
+	return New(interval, func(stop <-chan struct{}, now time.Time) {
+		count, err := expireTransfers(db, transferService, now)
+		if err != nil {
+			log.Error("Pending transfers could not be expired", err)
+			return
+		}
+
+		if count > 0 {
+			log.Infof("%d pending transfers expired", count)
+		}
+	})
+}
+
+func expireTransfers(db *gorm.DB, transferService service.TransferService, now time.Time) (int64, error) {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+
+	count, err := transferService.WithTx(tx).ExpirePending(now)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return count, tx.Commit().Error
+}
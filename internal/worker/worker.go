@@ -0,0 +1,66 @@
+// Package worker contains the background jobs of the bank which run next to the API
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Job is the work of one tick of a worker, it should return soon after stop is closed
+type Job func(stop <-chan struct{}, now time.Time)
+
+// Worker runs its job periodically in the background, the first run is right after the start
+type Worker struct {
+	interval time.Duration
+	job      Job
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func New(interval time.Duration, job Job) *Worker {
+	return &Worker{
+		interval: interval,
+		job:      job,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called
+func (w *Worker) Start() {
+	go w.loop()
+}
+
+// Stop lets the running job finish and waits for the worker, or returns when the context is done
+func (w *Worker) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) loop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.job(w.stop, time.Now())
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -82,12 +82,28 @@ func (mr *MockCryptoMockRecorder) HashPassword(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockCrypto)(nil).HashPassword), arg0)
 }
 
+// RandomCode mocks base method.
+func (m *MockCrypto) RandomCode(arg0 int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RandomCode", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RandomCode indicates an expected call of RandomCode.
+func (mr *MockCryptoMockRecorder) RandomCode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomCode", reflect.TypeOf((*MockCrypto)(nil).RandomCode), arg0)
+}
+
 // RandomIBAN mocks base method.
-func (m *MockCrypto) RandomIBAN(arg0 string) string {
+func (m *MockCrypto) RandomIBAN(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RandomIBAN", arg0)
 	ret0, _ := ret[0].(string)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // RandomIBAN indicates an expected call of RandomIBAN.
@@ -0,0 +1,48 @@
+// Package calendar computes the dates of recurring transfers and the business days of the bank.
+//
+// Business days are Monday to Friday, public holidays are not known to the bank yet.
+package calendar
+
+import (
+	"tek-bank/pkg/enum"
+	"time"
+)
+
+// StartOfDay returns midnight of the day in its location
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// IsBusinessDay reports whether the bank works on the day
+func IsBusinessDay(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// NextBusinessDay returns the day itself if it is a business day, otherwise the first business day after it
+func NextBusinessDay(t time.Time) time.Time {
+	for !IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// Next returns the occurrence after the date for the frequency, false is returned if there is none.
+// Monthly occurrences keep the day of month, in shorter months the last day of the month is used.
+func Next(frequency string, date time.Time, dayOfMonth int) (time.Time, bool) {
+	switch frequency {
+	case enum.FrequencyDaily:
+		return date.AddDate(0, 0, 1), true
+	case enum.FrequencyWeekly:
+		return date.AddDate(0, 0, 7), true
+	case enum.FrequencyMonthly:
+		// The first day of the next month never overflows
+		firstOfMonth := time.Date(date.Year(), date.Month(), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location()).AddDate(0, 1, 0)
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		if dayOfMonth > lastDay {
+			dayOfMonth = lastDay
+		}
+		return firstOfMonth.AddDate(0, 0, dayOfMonth-1), true
+	default:
+		return time.Time{}, false
+	}
+}
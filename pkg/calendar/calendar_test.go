@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"tek-bank/pkg/enum"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		frequency  string
+		date       time.Time
+		dayOfMonth int
+		next       time.Time
+		ok         bool
+	}{
+		{"daily", enum.FrequencyDaily, date(2024, 2, 28), 28, date(2024, 2, 29), true},
+		{"weekly", enum.FrequencyWeekly, date(2024, 12, 30), 30, date(2025, 1, 6), true},
+		{"monthly", enum.FrequencyMonthly, date(2024, 1, 1), 1, date(2024, 2, 1), true},
+		{"monthly end of month", enum.FrequencyMonthly, date(2024, 1, 31), 31, date(2024, 2, 29), true},
+		{"monthly back to the day", enum.FrequencyMonthly, date(2024, 2, 29), 31, date(2024, 3, 31), true},
+		{"monthly over the year", enum.FrequencyMonthly, date(2024, 12, 15), 15, date(2025, 1, 15), true},
+		{"once", enum.FrequencyOnce, date(2024, 1, 1), 1, time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, ok := Next(test.frequency, test.date, test.dayOfMonth)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.next, next)
+		})
+	}
+}
+
+func TestNextBusinessDay(t *testing.T) {
+	// 2024-06-01 is a Saturday
+	assert.Equal(t, date(2024, 6, 3), NextBusinessDay(date(2024, 6, 1)))
+	assert.Equal(t, date(2024, 6, 3), NextBusinessDay(date(2024, 6, 2)))
+	assert.Equal(t, date(2024, 6, 4), NextBusinessDay(date(2024, 6, 4)))
+	assert.False(t, IsBusinessDay(date(2024, 6, 1)))
+	assert.True(t, IsBusinessDay(date(2024, 6, 3)))
+}
@@ -20,15 +20,19 @@ func SuccessResponse(ctx *fiber.Ctx, status int, data interface{}, msg ...string
 	})
 }
 
+// ErrorResponse responds with the error message, a single data value is returned as it is
 func ErrorResponse(ctx *fiber.Ctx, status int, msg string, data ...interface{}) error {
-	if len(data) == 0 {
-		data = nil
+	var responseData interface{}
+	if len(data) == 1 {
+		responseData = data[0]
+	} else if len(data) > 1 {
+		responseData = data
 	}
 
 	return ctx.Status(status).JSON(BaseResponse{
 		Success: false,
 		Message: msg,
-		Data:    data,
+		Data:    responseData,
 	})
 }
 
@@ -1,24 +1,58 @@
 package crypto
 
 import (
+	cryptorand "crypto/rand"
+	"github.com/gofiber/fiber/v2/log"
 	"golang.org/x/crypto/bcrypt"
+	"math/big"
 	"math/rand"
+	"os"
+	"tek-bank/pkg/iban"
 )
 
+// defaultBankCodes is used when IBAN_BANK_CODES is not set, the bank only has accounts in Turkey then
+const defaultBankCodes = "TR:00099"
+
 //go:generate mockgen -destination=../../mocks/crypto/crypto_mock.go -package=crypto tek-bank/pkg/crypto Crypto
 type Crypto interface {
 	HashPassword(password string) (string, error)
 	CheckPasswordHash(password, hashedPassword string) bool
 	RandomNumber() int64
 	RandomPassword() string
-	RandomIBAN(isoCode string) string
+	RandomIBAN(isoCode string) (string, error)
 	GenerateToken(length int) (string, error)
+	RandomCode(digits int) (string, error)
 }
 
-type crypto struct{}
+type crypto struct {
+	ibanGenerator *iban.Generator
+}
 
+// NewCrypto creates the crypto package, the bank codes of the IBANs are read from IBAN_BANK_CODES like "TR:00099,DE:10020030"
 func NewCrypto() Crypto {
-	return &crypto{}
+	bankCodes := os.Getenv("IBAN_BANK_CODES")
+	if bankCodes == "" {
+		bankCodes = defaultBankCodes
+	}
+
+	generator, err := newIBANGenerator(bankCodes)
+	if err != nil {
+		log.Error("Invalid IBAN_BANK_CODES, using the default bank codes: ", err)
+		generator, _ = newIBANGenerator(defaultBankCodes)
+	}
+
+	return &crypto{
+		ibanGenerator: generator,
+	}
+}
+
+func newIBANGenerator(value string) (*iban.Generator, error) {
+	bankCodes, err := iban.ParseBankCodes(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return iban.NewGenerator(bankCodes)
 }
 
 func (c *crypto) HashPassword(password string) (string, error) {
@@ -31,9 +65,30 @@ func (c *crypto) CheckPasswordHash(password, hashedPassword string) bool {
 	return err == nil
 }
 
-// RandomNumber generates a random customer number with 12 digits
+// RandomNumber generates a random customer or account number with 12 digits,
+// the last digit is a Luhn check digit so mistyped numbers can be noticed
 func (c *crypto) RandomNumber() int64 {
-	return rand.Int63n(1e12)
+	number := 1e10 + rand.Int63n(9e10)
+	return number*10 + luhnCheckDigit(number)
+}
+
+// luhnCheckDigit computes the digit which makes the number followed by it valid for the Luhn algorithm
+func luhnCheckDigit(number int64) int64 {
+	var sum int64
+	double := true
+	for ; number > 0; number /= 10 {
+		digit := number % 10
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return (10 - sum%10) % 10
 }
 
 func (c *crypto) RandomPassword() string {
@@ -52,15 +107,10 @@ func (c *crypto) RandomPassword() string {
 	return string(d)
 }
 
-func (c *crypto) RandomIBAN(isoCode string) string {
-	digitRunes := []rune("0123456789")
-
-	d := make([]rune, 28)
-	for i := range d {
-		d[i] = digitRunes[rand.Intn(len(digitRunes))]
-	}
-
-	return isoCode + "00 " + string(d[0:4]) + " " + string(d[4:8]) + " " + string(d[8:12]) + " " + string(d[12:16]) + " " + string(d[16:20]) + " " + string(d[20:24]) + " " + string(d[24:28])
+// RandomIBAN generates a random IBAN of the bank in the country, iban.ErrUnsupportedCountry is returned
+// if the bank has no bank code for the country
+func (c *crypto) RandomIBAN(isoCode string) (string, error) {
+	return c.ibanGenerator.Generate(isoCode)
 }
 
 func (c *crypto) GenerateToken(length int) (string, error) {
@@ -78,3 +128,18 @@ func (c *crypto) GenerateToken(length int) (string, error) {
 
 	return string(buffer), nil
 }
+
+// RandomCode generates a numeric one-time code with the number of digits, leading zeros are kept.
+// The code is read from a cryptographically secure source because it approves money movements.
+func (c *crypto) RandomCode(digits int) (string, error) {
+	code := make([]byte, digits)
+	for i := range code {
+		digit, err := cryptorand.Int(cryptorand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + digit.Int64())
+	}
+
+	return string(code), nil
+}
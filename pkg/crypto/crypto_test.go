@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"tek-bank/pkg/iban"
+)
+
+// luhnValid checks a number with its check digit
+func luhnValid(number int64) bool {
+	return luhnCheckDigit(number/10) == number%10
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	// 7992739871 is the usual example of the Luhn algorithm with the check digit 3
+	assert.Equal(t, int64(3), luhnCheckDigit(7992739871))
+	assert.True(t, luhnValid(79927398713))
+	assert.False(t, luhnValid(79927398710))
+}
+
+func TestRandomNumber(t *testing.T) {
+	c := NewCrypto()
+	for i := 0; i < 1000; i++ {
+		number := c.RandomNumber()
+		assert.GreaterOrEqual(t, number, int64(1e11))
+		assert.Less(t, number, int64(1e12))
+		assert.True(t, luhnValid(number), number)
+	}
+}
+
+func TestRandomIBAN(t *testing.T) {
+	t.Setenv("IBAN_BANK_CODES", "TR:00123,GB:TEKB")
+	c := NewCrypto()
+
+	value, err := c.RandomIBAN("tr")
+	assert.NoError(t, err)
+	assert.NoError(t, iban.Validate(value))
+	assert.Len(t, value, 26)
+
+	_, err = c.RandomIBAN("US")
+	assert.ErrorIs(t, err, iban.ErrUnsupportedCountry)
+}
+
+func TestRandomCode(t *testing.T) {
+	c := NewCrypto()
+
+	for i := 0; i < 100; i++ {
+		code, err := c.RandomCode(6)
+		assert.NoError(t, err)
+		assert.Regexp(t, `^[0-9]{6}$`, code)
+	}
+}
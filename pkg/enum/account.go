@@ -0,0 +1,32 @@
+package enum
+
+// Types of customer accounts, savings and time deposit accounts earn interest when a rate is set for them
+const (
+	AccountTypeCurrent     = "current"
+	AccountTypeSavings     = "savings"
+	AccountTypeTimeDeposit = "time_deposit"
+)
+
+// Actions of the account lifecycle, every action is recorded as an account event with its reason
+const (
+	AccountActionFreeze   = "freeze"
+	AccountActionUnfreeze = "unfreeze"
+	AccountActionClose    = "close"
+)
+
+// Statuses of accounts as shown to the clients
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+// InterestDaysInYear is the day count of the yearly interest rates (actual/365), a day earns 1/365 of the rate
+const InterestDaysInYear = 365
+
+// Kinds of interest accruals, interest is earned on a positive balance and paid by the bank, an overdraft charge is
+// the interest of a negative balance the account pays to the bank
+const (
+	AccrualKindInterest  = "interest"
+	AccrualKindOverdraft = "overdraft"
+)
@@ -0,0 +1,11 @@
+package enum
+
+// Actions on the trust of a beneficiary, every action is recorded as a beneficiary event. Trust is only given
+// when the owner approves it with the approval method of its transfers.
+const (
+	BeneficiaryActionTrustRequested = "trust_requested"
+	BeneficiaryActionTrustApproved  = "trust_approved"
+	BeneficiaryActionTrustRejected  = "trust_rejected"
+	BeneficiaryActionTrustExpired   = "trust_expired"
+	BeneficiaryActionTrustRevoked   = "trust_revoked"
+)
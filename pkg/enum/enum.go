@@ -1,3 +1,12 @@
 package enum
 
-var TransferFee float64 = 4.22
+import "tek-bank/pkg/money"
+
+// DefaultCountryCode is used for the IBAN of a new account when no country is given
+const DefaultCountryCode = "TR"
+
+// NumberAttempts is how many times a generated number is generated again when it is already used
+const NumberAttempts = 5
+
+// DailyWithdrawalLimit is the default daily withdrawal limit of an account in the base currency
+var DailyWithdrawalLimit = money.MustParse("10000")
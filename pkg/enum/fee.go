@@ -0,0 +1,18 @@
+package enum
+
+// Types of fee schedules
+const (
+	// FeeTypeFlat charges the same fee for every transfer
+	FeeTypeFlat = "flat"
+	// FeeTypePercentage charges a part of the amount
+	FeeTypePercentage = "percentage"
+	// FeeTypeTiered charges the flat fee and the part of the tier the amount falls into
+	FeeTypeTiered = "tiered"
+)
+
+// Segments of customers, the fee of a transfer can depend on the segment of the sender
+const (
+	SegmentRetail   = "retail"
+	SegmentPremium  = "premium"
+	SegmentBusiness = "business"
+)
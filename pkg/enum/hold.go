@@ -0,0 +1,16 @@
+package enum
+
+// Types of holds, transfer holds reserve the money of a pending transfer, the others are placed by admins
+const (
+	HoldTypeTransfer = "transfer"
+	HoldTypeLegal    = "legal"
+	HoldTypeManual   = "manual"
+)
+
+// Statuses of holds, only active holds reduce the available balance
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+)
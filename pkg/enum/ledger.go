@@ -0,0 +1,31 @@
+package enum
+
+// Journal types
+const (
+	JournalTypeDeposit    = "deposit"
+	JournalTypeTransfer   = "transfer"
+	JournalTypeWithdrawal = "withdrawal"
+	JournalTypeReversal   = "reversal"
+	JournalTypeSweep      = "sweep"
+	JournalTypeInterest   = "interest"
+
+	JournalTypeOverdraftInterest = "overdraft_interest"
+)
+
+// Internal account codes, there is one internal account per code and currency
+const (
+	CashAccountCode            = "cash"
+	FeeIncomeAccountCode       = "fee_income"
+	FxPositionAccountCode      = "fx_position"
+	InterestExpenseAccountCode = "interest_expense"
+	InterestIncomeAccountCode  = "interest_income"
+)
+
+// SystemUserEmail is the e-mail of the user which owns the internal accounts of the bank
+const SystemUserEmail = "system@tekbank.internal"
+
+// Directions of the transfer history entries of an account
+const (
+	HistoryDirectionIn  = "in"
+	HistoryDirectionOut = "out"
+)
@@ -0,0 +1,18 @@
+package enum
+
+// Levels of transfer limits
+const (
+	// LimitLevelGlobal limits every customer which has no limit of its own for the period
+	LimitLevelGlobal = "global"
+	// LimitLevelCustomer limits all accounts of one customer together
+	LimitLevelCustomer = "customer"
+	// LimitLevelAccount limits one account in addition to the limit of its owner
+	LimitLevelAccount = "account"
+)
+
+// Periods of transfer limits
+const (
+	LimitPeriodTransaction = "transaction"
+	LimitPeriodDaily       = "daily"
+	LimitPeriodMonthly     = "monthly"
+)
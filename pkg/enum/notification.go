@@ -0,0 +1,4 @@
+package enum
+
+// NotificationMaxAttempts is how many times a notification of the outbox is sent before it is given up
+const NotificationMaxAttempts = 5
@@ -0,0 +1,24 @@
+package enum
+
+import "time"
+
+// Statuses of payment requests, a request waits for the answer of the payer until it expires. An accepted
+// request is paying until its transfer is executed, it is accepted then or pending again if the transfer failed.
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestPaying    = "paying"
+	PaymentRequestAccepted  = "accepted"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+)
+
+// PaymentRequestTTL is how long the payer can answer a payment request
+const PaymentRequestTTL = 7 * 24 * time.Hour
+
+// PaymentRequestLimit is how many payment requests a user can send in PaymentRequestLimitWindow. Cancelled requests
+// count too, every request sends an e-mail to the payer.
+const (
+	PaymentRequestLimit       = 20
+	PaymentRequestLimitWindow = 24 * time.Hour
+)
@@ -0,0 +1,8 @@
+package enum
+
+// User roles
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+	RoleSystem   = "system"
+)
@@ -0,0 +1,33 @@
+package enum
+
+import "time"
+
+// Frequencies of scheduled transfers
+const (
+	FrequencyOnce    = "once"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// Statuses of scheduled transfers
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferCompleted = "completed"
+	ScheduledTransferCancelled = "cancelled"
+	ScheduledTransferFailed    = "failed"
+)
+
+// What happens to a scheduled transfer which falls on a weekend
+const (
+	// NonBusinessDayShift runs the transfer on the next business day
+	NonBusinessDayShift = "shift"
+	// NonBusinessDaySkip leaves the transfer out and waits for the next occurrence
+	NonBusinessDaySkip = "skip"
+)
+
+// ScheduledTransferMaxAttempts is how many times an occurrence is tried when the balance is not enough
+const ScheduledTransferMaxAttempts = 3
+
+// ScheduledTransferRetryInterval is the time between two attempts of an occurrence
+const ScheduledTransferRetryInterval = time.Hour
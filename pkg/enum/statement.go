@@ -0,0 +1,7 @@
+package enum
+
+// Formats of account statements
+const (
+	StatementFormatCSV = "csv"
+	StatementFormatPDF = "pdf"
+)
@@ -0,0 +1,57 @@
+package enum
+
+import "time"
+
+// Statuses of transfers, a transfer waits for the approval of the sender until it expires
+const (
+	TransferPendingApproval = "pending_approval"
+	TransferApproved        = "approved"
+	TransferExecuted        = "executed"
+	TransferRejected        = "rejected"
+	TransferExpired         = "expired"
+	TransferCancelled       = "cancelled"
+)
+
+// TransferApprovalTTL is how long a transfer can be approved after it is requested
+const TransferApprovalTTL = time.Hour
+
+// Approval methods of transfers, every user chooses one for all of its transfers
+const (
+	// ApprovalMethodEmailLink sends a link to approve the transfer via e-mail
+	ApprovalMethodEmailLink = "email_link"
+	// ApprovalMethodEmailCode sends a one-time code via e-mail, the user approves with the code while logged in
+	ApprovalMethodEmailCode = "email_code"
+	// ApprovalMethodSMSCode sends a one-time code via SMS, the user approves with the code while logged in
+	ApprovalMethodSMSCode = "sms_code"
+)
+
+// ApprovalTrustedBeneficiary is recorded as the approval method of the transfers to trusted beneficiaries
+// which were executed without an approval of the sender
+const ApprovalTrustedBeneficiary = "trusted_beneficiary"
+
+// TrustedBeneficiaryLimitPeriod is the period over which the transfers to trusted beneficiaries without an approval
+// are added up for the limit of the bank
+const TrustedBeneficiaryLimitPeriod = LimitPeriodDaily
+
+// TransferCodeLength is the number of digits of a one-time approval code
+const TransferCodeLength = 6
+
+// TransferCodeTTL is how long a transfer can be approved with its one-time code
+const TransferCodeTTL = 5 * time.Minute
+
+// TransferCodeMaxAttempts is how many wrong codes are accepted before the transfer is rejected
+const TransferCodeMaxAttempts = 3
+
+// Statuses of bulk transfers, the rows of an approved bulk transfer are processed in the background until it is completed.
+// The rows are transfers with the statuses of the bulk transfer until it is approved.
+const (
+	BulkTransferPendingApproval = TransferPendingApproval
+	BulkTransferProcessing      = "processing"
+	BulkTransferCompleted       = "completed"
+	BulkTransferRejected        = TransferRejected
+	BulkTransferExpired         = TransferExpired
+	BulkTransferCancelled       = TransferCancelled
+)
+
+// BulkTransferMaxRows is how many rows a bulk transfer file can have
+const BulkTransferMaxRows = 1000
@@ -7,6 +7,21 @@ import (
 	"os"
 )
 
+//go:generate mockgen -destination=../../mocks/gomailer/gomailer_mock.go -package=gomailer tek-bank/pkg/gomailer Mailer
+type Mailer interface {
+	Send(content Content) error
+}
+
+type gomailer struct{}
+
+func NewMailer() Mailer {
+	return &gomailer{}
+}
+
+func (g *gomailer) Send(content Content) error {
+	return SendMail(content)
+}
+
 type Content struct {
 	Subject     string
 	Body        string
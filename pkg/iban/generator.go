@@ -0,0 +1,139 @@
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	ErrUnsupportedCountry = errors.New("iban can not be generated for the country")
+	ErrInvalidBankCode    = errors.New("bank code does not match the account structure of the country")
+)
+
+// part is a fixed length part of a national account number (BBAN)
+type part struct {
+	length int
+	// kind is 'n' for digits, 'a' for upper case letters, 'c' for both and '0' for zeros
+	kind byte
+}
+
+// structures of the national account numbers the bank can generate, see the IBAN registry.
+// The first part is always the bank code, the other parts are filled randomly.
+var structures = map[string][]part{
+	"AT": {{5, 'n'}, {11, 'n'}},
+	"CH": {{5, 'n'}, {12, 'c'}},
+	"DE": {{8, 'n'}, {10, 'n'}},
+	"GB": {{4, 'a'}, {6, 'n'}, {8, 'n'}},
+	"IE": {{4, 'a'}, {6, 'n'}, {8, 'n'}},
+	"LU": {{3, 'n'}, {13, 'c'}},
+	"NL": {{4, 'a'}, {10, 'n'}},
+	"TR": {{5, 'n'}, {1, '0'}, {16, 'c'}},
+}
+
+// CheckDigits computes the two check digits of the IBAN of the country and the national account number
+func CheckDigits(country string, bban string) string {
+	return fmt.Sprintf("%02d", 98-mod97(Normalize(bban)+strings.ToUpper(country)+"00"))
+}
+
+// Generator generates IBANs of the bank with random account numbers
+type Generator struct {
+	bankCodes map[string]string
+}
+
+// NewGenerator creates a generator for the countries of the bank codes, the bank codes are
+// validated against the account structure of their country
+func NewGenerator(bankCodes map[string]string) (*Generator, error) {
+	codes := make(map[string]string, len(bankCodes))
+	for country, bankCode := range bankCodes {
+		country = strings.ToUpper(country)
+		bankCode = Normalize(bankCode)
+
+		structure, ok := structures[country]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedCountry, country)
+		}
+
+		if !structure[0].matches(bankCode) {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidBankCode, country, bankCode)
+		}
+
+		codes[country] = bankCode
+	}
+
+	return &Generator{bankCodes: codes}, nil
+}
+
+// ParseBankCodes parses the bank codes of the countries like "TR:00099,DE:10020030"
+func ParseBankCodes(value string) (map[string]string, error) {
+	bankCodes := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		country, bankCode, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBankCode, entry)
+		}
+
+		bankCodes[strings.ToUpper(strings.TrimSpace(country))] = strings.TrimSpace(bankCode)
+	}
+
+	return bankCodes, nil
+}
+
+// Generate returns a random IBAN of the bank in the country in the electronic format without spaces
+func (g *Generator) Generate(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+
+	bankCode, ok := g.bankCodes[country]
+	if !ok {
+		return "", ErrUnsupportedCountry
+	}
+
+	var bban strings.Builder
+	bban.WriteString(bankCode)
+	for _, p := range structures[country][1:] {
+		bban.WriteString(p.random())
+	}
+
+	return country + CheckDigits(country, bban.String()) + bban.String(), nil
+}
+
+func (p part) matches(value string) bool {
+	if len(value) != p.length {
+		return false
+	}
+
+	for _, r := range value {
+		isDigit := r >= '0' && r <= '9'
+		isLetter := r >= 'A' && r <= 'Z'
+		switch {
+		case p.kind == 'n' && !isDigit,
+			p.kind == 'a' && !isLetter,
+			p.kind == 'c' && !isDigit && !isLetter,
+			p.kind == '0' && r != '0':
+			return false
+		}
+	}
+
+	return true
+}
+
+// random fills the part, alphanumeric parts are filled with digits so account numbers stay readable
+func (p part) random() string {
+	value := make([]byte, p.length)
+	for i := range value {
+		switch p.kind {
+		case 'a':
+			value[i] = byte('A' + rand.Intn(26))
+		case '0':
+			value[i] = '0'
+		default:
+			value[i] = byte('0' + rand.Intn(10))
+		}
+	}
+	return string(value)
+}
@@ -0,0 +1,106 @@
+// Package iban validates International Bank Account Numbers (ISO 13616).
+//
+// An IBAN is the country code, two check digits and the national account number (BBAN)
+// of the country. The check digits are valid if the number, with the first four characters
+// moved to the end and the letters replaced by numbers (A = 10, ..., Z = 35), is 1 mod 97.
+package iban
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidCharacters = errors.New("iban has invalid characters")
+	ErrUnknownCountry    = errors.New("iban country is not known")
+	ErrInvalidLength     = errors.New("iban length does not match its country")
+	ErrInvalidChecksum   = errors.New("iban check digits are wrong")
+)
+
+// lengths is the length of the IBAN of every country in the IBAN registry
+var lengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31,
+	"SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// Normalize removes the spaces of the print format and upper cases the IBAN
+func Normalize(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// Validate checks the characters, the length of the country and the check digits of the IBAN,
+// the IBAN may be given in the print format with spaces
+func Validate(value string) error {
+	value = Normalize(value)
+
+	if len(value) < 4 {
+		return ErrInvalidLength
+	}
+
+	for i, r := range value {
+		switch {
+		case i < 2 && (r < 'A' || r > 'Z'):
+			return ErrInvalidCharacters
+		case i >= 2 && i < 4 && (r < '0' || r > '9'):
+			return ErrInvalidCharacters
+		case !isAlphanumeric(r):
+			return ErrInvalidCharacters
+		}
+	}
+
+	length, ok := lengths[value[:2]]
+	if !ok {
+		return ErrUnknownCountry
+	}
+
+	if len(value) != length {
+		return ErrInvalidLength
+	}
+
+	if mod97(value[4:]+value[:4]) != 1 {
+		return ErrInvalidChecksum
+	}
+
+	return nil
+}
+
+// Format prints the IBAN in groups of four characters like "TR33 0006 1005 1978 6457 8413 26"
+func Format(value string) string {
+	value = Normalize(value)
+
+	var builder strings.Builder
+	for i, r := range value {
+		if i > 0 && i%4 == 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// mod97 returns the remainder of the number, letters count as two digits (A = 10, ..., Z = 35)
+func mod97(value string) int {
+	remainder := 0
+	for _, r := range value {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z')
+}
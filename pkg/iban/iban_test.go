@@ -0,0 +1,94 @@
+package iban
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"turkey", "TR330006100519786457841326", nil},
+		{"germany print format", "DE89 3704 0044 0532 0130 00", nil},
+		{"united kingdom with letters", "GB82 WEST 1234 5698 7654 32", nil},
+		{"lower case", "nl91abna0417164300", nil},
+		{"wrong check digits", "GB82 WEST 1234 5698 7654 33", ErrInvalidChecksum},
+		{"swapped digits", "TR330006100519786457841362", ErrInvalidChecksum},
+		{"too short for the country", "DE89 3704 0044 0532 0130 0", ErrInvalidLength},
+		{"too long for the country", "TR3300061005197864578413260", ErrInvalidLength},
+		{"unknown country", "XX89 3704 0044 0532 0130 00", ErrUnknownCountry},
+		{"invalid character", "DE89-3704-0044-0532-0130-00", ErrInvalidCharacters},
+		{"letters as check digits", "DEAB370400440532013000", ErrInvalidCharacters},
+		{"empty", "", ErrInvalidLength},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, Validate(test.value))
+		})
+	}
+}
+
+func TestNormalizeAndFormat(t *testing.T) {
+	assert.Equal(t, "TR330006100519786457841326", Normalize(" tr33 0006 1005 1978 6457 8413 26 "))
+	assert.Equal(t, "TR33 0006 1005 1978 6457 8413 26", Format("TR330006100519786457841326"))
+}
+
+func TestCheckDigits(t *testing.T) {
+	assert.Equal(t, "33", CheckDigits("TR", "0006100519786457841326"))
+	assert.Equal(t, "82", CheckDigits("GB", "WEST12345698765432"))
+	assert.Equal(t, "89", CheckDigits("DE", "370400440532013000"))
+}
+
+func TestStructuresMatchLengths(t *testing.T) {
+	for country, structure := range structures {
+		length := 4
+		for _, p := range structure {
+			length += p.length
+		}
+		assert.Equal(t, lengths[country], length, country)
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	generator, err := NewGenerator(map[string]string{"tr": "00099", "GB": "tekb", "DE": "10020030"})
+	assert.NoError(t, err)
+
+	for _, country := range []string{"TR", "gb", "DE"} {
+		for i := 0; i < 100; i++ {
+			value, err := generator.Generate(country)
+			assert.NoError(t, err)
+			assert.NoError(t, Validate(value), value)
+		}
+	}
+
+	value, _ := generator.Generate("TR")
+	assert.Equal(t, "000990", value[4:10])
+
+	_, err = generator.Generate("NL")
+	assert.ErrorIs(t, err, ErrUnsupportedCountry)
+}
+
+func TestNewGenerator_InvalidBankCode(t *testing.T) {
+	_, err := NewGenerator(map[string]string{"TR": "0009"})
+	assert.ErrorIs(t, err, ErrInvalidBankCode)
+
+	_, err = NewGenerator(map[string]string{"GB": "1234"})
+	assert.ErrorIs(t, err, ErrInvalidBankCode)
+
+	_, err = NewGenerator(map[string]string{"US": "1234"})
+	assert.ErrorIs(t, err, ErrUnsupportedCountry)
+}
+
+func TestParseBankCodes(t *testing.T) {
+	bankCodes, err := ParseBankCodes(" tr:00099, DE:10020030,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"TR": "00099", "DE": "10020030"}, bankCodes)
+
+	_, err = ParseBankCodes("TR00099")
+	assert.ErrorIs(t, err, ErrInvalidBankCode)
+}
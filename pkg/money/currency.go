@@ -0,0 +1,85 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code like "TRY" or "EUR"
+type Currency string
+
+// DefaultCurrency is used when no currency is given
+const DefaultCurrency Currency = "TRY"
+
+// ParseCurrency validates and normalizes a currency code
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+
+	return Currency(code), nil
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Value stores the currency as a plain string column
+func (c Currency) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan reads the currency from a string column
+func (c *Currency) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*c = ""
+	case []byte:
+		*c = Currency(strings.TrimSpace(string(v)))
+	case string:
+		*c = Currency(strings.TrimSpace(v))
+	default:
+		return fmt.Errorf("money: can not scan %T into Currency", src)
+	}
+	return nil
+}
+
+// Money is an amount together with its currency
+type Money struct {
+	Amount   Amount   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// New creates money in the given currency
+func New(amount Amount, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add adds two amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub subtracts two amounts of the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// String formats the money like "12.50 TRY"
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
@@ -0,0 +1,271 @@
+// Package money keeps money amounts exact.
+//
+// An Amount is stored in minor units (cents), so additions and comparisons never drift.
+// Rounding only happens when an amount is multiplied by a rate (fees, exchange rates,
+// interest) and the caller always chooses the RoundingMode explicitly. Amounts coming
+// from clients are never rounded, more than two decimals are rejected instead.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimals kept for every amount
+const Scale = 2
+
+const minorUnits = 100
+
+var (
+	ErrInvalidAmount    = errors.New("invalid money amount")
+	ErrTooManyDecimals  = errors.New("money amount has more than two decimals")
+	ErrInvalidCurrency  = errors.New("invalid currency code")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// RoundingMode decides what happens to the part of a result below one minor unit
+type RoundingMode int
+
+const (
+	// HalfEven rounds to the nearest cent and ties to the even cent (banker's rounding)
+	HalfEven RoundingMode = iota
+	// HalfUp rounds to the nearest cent and ties away from zero
+	HalfUp
+	// Down truncates towards zero
+	Down
+	// Up rounds away from zero
+	Up
+)
+
+// Amount is a money amount in minor units, 1050 means 10.50
+type Amount int64
+
+// Zero is the zero amount
+const Zero Amount = 0
+
+// FromMinor creates an amount from minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse parses a decimal string like "10", "-3.5" or "1250.75" without any rounding
+func Parse(value string) (Amount, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Zero, ErrInvalidAmount
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	if whole == "" && (!hasFraction || fraction == "") {
+		return Zero, ErrInvalidAmount
+	}
+
+	// Trailing zeros do not add precision
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > Scale {
+		return Zero, ErrTooManyDecimals
+	}
+
+	if !isDigits(whole) || !isDigits(fraction) {
+		return Zero, ErrInvalidAmount
+	}
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<63-1-(minorUnits-1))/minorUnits {
+		return Zero, ErrInvalidAmount
+	}
+
+	fraction += strings.Repeat("0", Scale-len(fraction))
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+
+	minor := units*minorUnits + cents
+	if negative {
+		minor = -minor
+	}
+
+	return Amount(minor), nil
+}
+
+// MustParse is like Parse but panics on an invalid amount, it is meant for constants
+func MustParse(value string) Amount {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// FromRat converts a rational number to an amount with the given rounding mode
+func FromRat(value *big.Rat, mode RoundingMode) Amount {
+	scaled := new(big.Rat).Mul(value, big.NewRat(minorUnits, 1))
+	return Amount(roundRat(scaled, mode))
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Rat returns the amount as an exact rational number
+func (a Amount) Rat() *big.Rat {
+	return big.NewRat(int64(a), minorUnits)
+}
+
+// Mul multiplies the amount with a rate like an exchange rate or a percentage
+func (a Amount) Mul(rate *big.Rat, mode RoundingMode) Amount {
+	return FromRat(new(big.Rat).Mul(a.Rat(), rate), mode)
+}
+
+// Abs returns the absolute value of the amount
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// String formats the amount with exactly two decimals like "-12.05"
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+	}
+
+	// Work on the unsigned value so the smallest int64 can be printed too
+	abs := uint64(minor)
+	if minor < 0 {
+		abs = uint64(-(minor + 1)) + 1
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, abs/minorUnits, abs%minorUnits)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimals
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads the amount from a JSON number or string without going through float64
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), "\"")
+	if value == "null" {
+		return nil
+	}
+
+	amount, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*a = amount
+	return nil
+}
+
+// Value stores the amount as a numeric column
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads the amount from a numeric column
+func (a *Amount) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+		*a = Zero
+		return nil
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case int64:
+		*a = Amount(v * minorUnits)
+		return nil
+	case float64:
+		// Only happens for float columns, the value is rounded to the nearest cent
+		rat, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		if !ok {
+			return ErrInvalidAmount
+		}
+		*a = FromRat(rat, HalfEven)
+		return nil
+	default:
+		return fmt.Errorf("money: can not scan %T into Amount", src)
+	}
+
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return ErrInvalidAmount
+	}
+
+	*a = FromRat(rat, HalfEven)
+	return nil
+}
+
+// roundRat rounds the rational number to an integer with the given mode
+func roundRat(value *big.Rat, mode RoundingMode) int64 {
+	num := new(big.Int).Set(value.Num())
+	den := value.Denom()
+
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	if remainder.Sign() != 0 {
+		switch mode {
+		case Up:
+			quotient.Add(quotient, big.NewInt(1))
+		case HalfUp, HalfEven:
+			twice := new(big.Int).Mul(remainder, big.NewInt(2))
+			cmp := twice.Cmp(den)
+			if cmp > 0 || (cmp == 0 && (mode == HalfUp || quotient.Bit(0) == 1)) {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+
+	if negative {
+		quotient.Neg(quotient)
+	}
+
+	return quotient.Int64()
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+		err      error
+	}{
+		{input: "0", expected: 0},
+		{input: "10", expected: 1000},
+		{input: "4.22", expected: 422},
+		{input: "4.2", expected: 420},
+		{input: "4.220", expected: 422},
+		{input: ".5", expected: 50},
+		{input: "5.", expected: 500},
+		{input: "-0.01", expected: -1},
+		{input: "+7.07", expected: 707},
+		{input: "0.105", err: ErrTooManyDecimals},
+		{input: "", err: ErrInvalidAmount},
+		{input: "-", err: ErrInvalidAmount},
+		{input: "1e3", err: ErrInvalidAmount},
+		{input: "1,5", err: ErrInvalidAmount},
+		{input: "99999999999999999999", err: ErrInvalidAmount},
+	}
+
+	for _, test := range tests {
+		amount, err := Parse(test.input)
+		assert.Equal(t, test.err, err, test.input)
+		assert.Equal(t, test.expected, amount, test.input)
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "0.00", Zero.String())
+	assert.Equal(t, "4.22", Amount(422).String())
+	assert.Equal(t, "-0.05", Amount(-5).String())
+	assert.Equal(t, "-12.30", Amount(-1230).String())
+}
+
+func TestAmount_AdditionIsExact(t *testing.T) {
+	// 0.1 + 0.2 is the classic float64 drift
+	sum := MustParse("0.1") + MustParse("0.2")
+	assert.Equal(t, MustParse("0.3"), sum)
+
+	// The old float64 comparison of amount + fee against the balance
+	balance := MustParse("104.22")
+	total := MustParse("100") + MustParse("4.22")
+	assert.False(t, balance < total)
+}
+
+func TestAmount_MulRounding(t *testing.T) {
+	tests := []struct {
+		amount   string
+		rate     string
+		mode     RoundingMode
+		expected string
+	}{
+		// 0.125 is exactly half a cent
+		{amount: "12.50", rate: "0.01", mode: HalfEven, expected: "0.12"},
+		{amount: "12.50", rate: "0.01", mode: HalfUp, expected: "0.13"},
+		{amount: "13.50", rate: "0.01", mode: HalfEven, expected: "0.14"},
+		{amount: "-12.50", rate: "0.01", mode: HalfUp, expected: "-0.13"},
+		{amount: "-12.50", rate: "0.01", mode: HalfEven, expected: "-0.12"},
+		{amount: "10.00", rate: "1/3", mode: Down, expected: "3.33"},
+		{amount: "10.00", rate: "1/3", mode: Up, expected: "3.34"},
+		{amount: "10.00", rate: "2/3", mode: HalfEven, expected: "6.67"},
+		{amount: "100.00", rate: "34.5678", mode: HalfUp, expected: "3456.78"},
+	}
+
+	for _, test := range tests {
+		rate, ok := new(big.Rat).SetString(test.rate)
+		assert.True(t, ok)
+
+		result := MustParse(test.amount).Mul(rate, test.mode)
+		assert.Equal(t, test.expected, result.String(), test.amount+" * "+test.rate)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var request struct {
+		Amount Amount `json:"amount"`
+	}
+
+	err := json.Unmarshal([]byte(`{"amount": 100.10}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(10010), request.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount": "7.5"}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(750), request.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount": 0.001}`), &request)
+	assert.Error(t, err)
+
+	data, err := json.Marshal(request)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":7.50}`, string(data))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	var amount Amount
+
+	assert.NoError(t, amount.Scan([]byte("1250.75")))
+	assert.Equal(t, Amount(125075), amount)
+
+	assert.NoError(t, amount.Scan("-3.1"))
+	assert.Equal(t, Amount(-310), amount)
+
+	assert.NoError(t, amount.Scan(float64(4.22)))
+	assert.Equal(t, Amount(422), amount)
+
+	value, err := Amount(-1).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.01", value)
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := New(100, "TRY").Add(New(50, "TRY"))
+	assert.NoError(t, err)
+	assert.Equal(t, New(150, "TRY"), sum)
+
+	_, err = New(100, "TRY").Add(New(50, "EUR"))
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := ParseCurrency(" eur ")
+	assert.NoError(t, err)
+	assert.Equal(t, Currency("EUR"), currency)
+
+	_, err = ParseCurrency("EURO")
+	assert.Equal(t, ErrInvalidCurrency, err)
+}
+
+func TestRate_Convert(t *testing.T) {
+	eur, err := ParseRate("36.4512")
+	assert.NoError(t, err)
+
+	usd, err := ParseRate("32.1")
+	assert.NoError(t, err)
+
+	// 100 EUR in TRY
+	assert.Equal(t, "3645.12", eur.Convert(MustParse("100")).String())
+
+	// 100 EUR in USD goes over the base currency and keeps ten decimals
+	cross := eur.Div(usd)
+	assert.Equal(t, "1.1355514019", cross.String())
+	assert.Equal(t, "113.56", cross.Convert(MustParse("100")).String())
+
+	_, err = ParseRate("-1")
+	assert.Equal(t, ErrInvalidRate, err)
+
+	_, err = ParseRate("1e3")
+	assert.Equal(t, ErrInvalidRate, err)
+}
@@ -0,0 +1,142 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RateScale is the number of decimals kept when a rate is stored or printed
+const RateScale = 10
+
+var ErrInvalidRate = errors.New("invalid rate")
+
+// Rate is an exact positive decimal ratio like an exchange rate
+type Rate struct {
+	value *big.Rat
+}
+
+// OneRate is the rate between a currency and itself
+var OneRate = Rate{value: big.NewRat(1, 1)}
+
+// ParseRate parses a positive decimal rate like "36.4512"
+func ParseRate(value string) (Rate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, "eE/") {
+		return Rate{}, ErrInvalidRate
+	}
+
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok || rat.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	return Rate{value: rat}, nil
+}
+
+// MustParseRate is like ParseRate but panics on an invalid rate, it is meant for constants
+func MustParseRate(value string) Rate {
+	rate, err := ParseRate(value)
+	if err != nil {
+		panic(err)
+	}
+	return rate
+}
+
+// NewRate creates a rate from a rational number
+func NewRate(value *big.Rat) Rate {
+	return Rate{value: new(big.Rat).Set(value)}
+}
+
+// Rat returns a copy of the exact value of the rate
+func (r Rate) Rat() *big.Rat {
+	if r.value == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.value)
+}
+
+// Div returns r / other, used to cross two rates of the same base currency
+func (r Rate) Div(other Rate) Rate {
+	return Rate{value: new(big.Rat).Quo(r.Rat(), other.Rat())}
+}
+
+func (r Rate) IsZero() bool {
+	return r.value == nil || r.value.Sign() == 0
+}
+
+// String formats the rate with at most ten decimals, the last one is rounded half up
+func (r Rate) String() string {
+	if r.value == nil {
+		return "0"
+	}
+
+	value := r.value.FloatString(RateScale)
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+	return value
+}
+
+// Convert converts the amount with the rate and rounds the result half even
+func (r Rate) Convert(amount Amount) Amount {
+	return amount.Mul(r.Rat(), HalfEven)
+}
+
+// MarshalJSON writes the rate as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	if r.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads the rate from a JSON number or string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), "\"")
+	if value == "null" {
+		return nil
+	}
+
+	rate, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
+
+// Value stores the rate as a numeric column, an empty rate is stored as null
+func (r Rate) Value() (driver.Value, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+// Scan reads the rate from a numeric column
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	default:
+		return fmt.Errorf("money: can not scan %T into Rate", src)
+	}
+}
+
+func (r *Rate) scanString(value string) error {
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return ErrInvalidRate
+	}
+	*r = Rate{value: rat}
+	return nil
+}
@@ -0,0 +1,151 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page in points, the text is written with the monospaced Courier fonts so columns can be aligned with spaces
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 40.0
+	fontSize   = 9.0
+	lineHeight = 12.0
+
+	// linesPerPage fit between the margins, (pageHeight - 2*margin) / lineHeight
+	linesPerPage = 63
+)
+
+// LineWidth is the number of characters which fit on a line, a Courier character is 0.6 times the font size wide
+const LineWidth = 95
+
+type line struct {
+	text string
+	bold bool
+}
+
+// Document is a text document of lines, new pages are started when a page is full
+type Document struct {
+	pages [][]line
+}
+
+func New() *Document {
+	return &Document{pages: [][]line{{}}}
+}
+
+// Line writes a line of text, the text is cut after LineWidth characters
+func (d *Document) Line(text string) {
+	d.add(line{text: text})
+}
+
+// BoldLine writes a line of text in bold
+func (d *Document) BoldLine(text string) {
+	d.add(line{text: text, bold: true})
+}
+
+func (d *Document) add(l line) {
+	if len(d.pages[len(d.pages)-1]) == linesPerPage {
+		d.pages = append(d.pages, []line{})
+	}
+	d.pages[len(d.pages)-1] = append(d.pages[len(d.pages)-1], l)
+}
+
+// Pages returns the number of pages of the document
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Bytes returns the PDF file of the document
+func (d *Document) Bytes() []byte {
+	var buffer bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buffer.WriteString("%PDF-1.4\n")
+
+	// 1: catalog, 2: page tree, 3 and 4: fonts, then a page and its content per page
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+
+		content := pageContent(lines)
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buffer.Bytes()
+}
+
+func pageContent(lines []line) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT\n")
+	fmt.Fprintf(&content, "%.2f TL\n%.2f %.2f Td\n", lineHeight, margin, pageHeight-margin-fontSize)
+
+	for _, l := range lines {
+		font := "F1"
+		if l.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&content, "/%s %.0f Tf\n(%s) Tj T*\n", font, fontSize, encode(l.text))
+	}
+
+	content.WriteString("ET")
+	return content.Bytes()
+}
+
+// transliterations are the letters which are not in WinAnsiEncoding, they are written without their accents
+var transliterations = map[rune]byte{
+	'ğ': 'g', 'Ğ': 'G', 'ş': 's', 'Ş': 'S', 'ı': 'i', 'İ': 'I',
+}
+
+// encode converts the text to a WinAnsiEncoding PDF string, unknown characters are written as '?'
+func encode(text string) string {
+	var encoded strings.Builder
+	count := 0
+	for _, r := range text {
+		if count == LineWidth {
+			break
+		}
+		count++
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			encoded.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 and WinAnsiEncoding are the same in this range
+			fmt.Fprintf(&encoded, "\\%03o", r)
+		default:
+			if b, ok := transliterations[r]; ok {
+				encoded.WriteByte(b)
+			} else {
+				encoded.WriteByte('?')
+			}
+		}
+	}
+	return encoded.String()
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"ascii", "Opening balance 10.00", "Opening balance 10.00"},
+		{"escaped", `Fee (flat) \ 4.22`, `Fee \(flat\) \\ 4.22`},
+		{"latin-1 and turkish", "Açılış", `A\347ilis`},
+		{"unknown", "5 €", "5 ?"},
+		{"cut", strings.Repeat("a", LineWidth+5), strings.Repeat("a", LineWidth)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, encode(test.text))
+		})
+	}
+}
+
+func TestDocument_Bytes(t *testing.T) {
+	document := New()
+	document.BoldLine("Statement")
+	for i := 0; i < linesPerPage; i++ {
+		document.Line(fmt.Sprintf("Line %d", i))
+	}
+
+	assert.Equal(t, 2, document.Pages())
+
+	file := document.Bytes()
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(file, []byte("%%EOF\n")))
+	assert.Contains(t, string(file), "/Count 2")
+	assert.Contains(t, string(file), "(Statement) Tj")
+
+	// The cross reference table points to the objects
+	xref := bytes.Index(file, []byte("\nxref\n")) + 1
+	assert.Contains(t, string(file), fmt.Sprintf("startxref\n%d\n", xref))
+	assert.Contains(t, string(file[xref:]), "0 9\n")
+}
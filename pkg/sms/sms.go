@@ -0,0 +1,30 @@
+package sms
+
+import (
+	"github.com/gofiber/fiber/v2/log"
+	"os"
+)
+
+//go:generate mockgen -destination=../../mocks/sms/sms_mock.go -package=sms tek-bank/pkg/sms Sender
+type Sender interface {
+	Send(phoneNumber uint64, message string) error
+}
+
+// NewSender creates the sender of the provider in SMS_PROVIDER. Only the fake provider,
+// which is the default, is available until the bank works with an SMS provider.
+func NewSender() Sender {
+	provider := os.Getenv("SMS_PROVIDER")
+	if provider != "" && provider != "fake" {
+		log.Errorf("Unknown SMS_PROVIDER %s, the fake provider is used", provider)
+	}
+
+	return &fakeSender{}
+}
+
+// fakeSender writes the messages to the log instead of sending them, it is meant for local development
+type fakeSender struct{}
+
+func (s *fakeSender) Send(phoneNumber uint64, message string) error {
+	log.Infof("SMS to +%d: %s", phoneNumber, message)
+	return nil
+}